@@ -84,6 +84,18 @@ var API_RATE_LIMIT_CONFIGURATION = map[string]types.RateLimit{
 		Tokens:   2,
 		Interval: 1 * time.Minute,
 	},
+	"coupon/deposit-bonus/opt-in": {
+		Tokens:   5,
+		Interval: 1 * time.Minute,
+	},
+	"chat/ignore": {
+		Tokens:   10,
+		Interval: time.Minute,
+	},
+	"chat/direct-message": {
+		Tokens:   20,
+		Interval: time.Minute,
+	},
 }
 
 var WEBSOCKET_RATE_LIMIT_CONFIGURATION = map[string]types.RateLimit{
@@ -202,6 +214,55 @@ var CRASH_MIN_CASH_OUT_AT = float64(1.01)
 var CRASH_SEED_CHAIN_LENGTH = uint(2500000)
 var CRASH_MAX_CASH_OUT = int64(1000 * ONE_CHIP_WITH_DECIMALS)
 var CRASH_START_ON_SERVER_STARTUP = false
+var CRASH_HOUSE_EDGE_MIN = int64(100)  // 1%
+var CRASH_HOUSE_EDGE_MAX = int64(1000) // 10%
+var GAME_SETTINGS_MAX_FEE = int64(20)  // 20%
+var GAME_SETTINGS_HISTORY_LIMIT = 50
+
+var MAINTENANCE_COUNTDOWN_NOTICES = []time.Duration{
+	30 * time.Minute,
+	10 * time.Minute,
+	5 * time.Minute,
+	time.Minute,
+	30 * time.Second,
+	10 * time.Second,
+}
+
+var SHUTDOWN_ROUND_DRAIN_TIMEOUT = 90 * time.Second // Unfinished crash round is refunded on next boot
+var SHUTDOWN_EVENT_FLUSH_TIMEOUT = 5 * time.Second
+var SHUTDOWN_HTTP_TIMEOUT = 10 * time.Second
+var SHUTDOWN_POLL_INTERVAL = 100 * time.Millisecond
+var RECOVERY_MATCH_WINDOW = time.Minute // Game records created later than this are not matched to a pending transaction
+var PENDING_TX_JANITOR_SCHEDULE = "*/5 * * * *"
+var PENDING_TX_TTL = map[models.TransactionType]time.Duration{ // Older pending transactions are closed by janitor
+	models.TxCrashBet:        10 * time.Minute,
+	models.TxCrashProfit:     10 * time.Minute,
+	models.TxCoinflipBet:     10 * time.Minute,
+	models.TxJackpotBet:      10 * time.Minute,
+	models.TxGrandJackpotBet: 10 * time.Minute,
+	models.TxDreamtowerBet:   10 * time.Minute,
+	models.TxDreamtowerFee:   10 * time.Minute,
+}
+var PENDING_COUPON_TX_TTL = map[models.CouponTransactionType]time.Duration{
+	models.CpTxCoinflipBet:   10 * time.Minute,
+	models.CpTxDreamtowerBet: 10 * time.Minute,
+	models.CpTxCrashBet:      10 * time.Minute,
+	models.CpTxCrashProfit:   10 * time.Minute,
+}
+
+var DB_TX_TIMEOUT = 30 * time.Second            // Deadline of a unit of work whose context has none
+var DB_TX_STATEMENT_TIMEOUT = 10 * time.Second  // Postgres statement_timeout inside a unit of work
+var DB_TX_MAX_RETRIES = 3                       // Retries on deadlock or serialization failure
+var DB_TX_RETRY_BACKOFF = 50 * time.Millisecond // Multiplied by attempt number
+var DB_SESSION_LEAK_THRESHOLD = time.Minute     // Open sessions older than this, or units of work past their deadline, are reported
+var DB_SESSION_LEAK_ROLLBACK = 30 * time.Second // Units of work open this long past their deadline are rolled back
+var DB_SESSION_LEAK_CHECK_INTERVAL = 30 * time.Second
+
+var DEPOSIT_WATCHER_ENABLED = true
+var DEPOSIT_WATCHER_INTERVAL = 15 * time.Second
+var DEPOSIT_WATCHER_PAGE_LIMIT = 100 // Signatures per getSignaturesForAddress call, at most 1000
+var DEPOSIT_MEMO_LENGTH = 10
+var DEPOSIT_MEMO_ALPHABET = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // No look-alike letters, memos are typed into exchanges
 
 var BASE_RAKEBACK_RATE = uint(5)       // 5 %
 var ADDITIONAL_RAKEBACK_RATE = uint(0) // 0 %
@@ -215,6 +276,15 @@ var CHAT_RAIN_MIN_WAGER = int64(100 * ONE_CHIP_WITH_DECIMALS) // 100 usd
 
 var WITHDRAW_MIN_LIMIT = int64(ONE_CHIP_WITH_DECIMALS)                           // 1 usd
 var WITHDRAW_FEE_PER_SPL = int64(float64(0.1) * float64(ONE_CHIP_WITH_DECIMALS)) // 0.1 usd
+var WITHDRAW_POLL_INTERVAL = 5 * time.Second
+var WITHDRAW_QUEUED_TIMEOUT = time.Minute                  // Queued withdrawals not sent by then are refunded
+var WITHDRAW_MAX_BROADCASTS = uint(3)                      // Expired withdrawals are rebroadcast, then refunded
+var WITHDRAW_COMPUTE_UNITS_PER_INSTRUCTION = uint32(40000) // Compute unit limit per instruction, pNFT transfers aside
+var WITHDRAW_PNFT_COMPUTE_UNITS = uint32(250000)           // Per programmable NFT transfer instruction
+var WITHDRAW_PRIORITY_FEE_PERCENTILE = 75                  // Of recent treasury priority fees, in %
+var WITHDRAW_PRIORITY_FEE_MIN = uint64(1000)               // Micro lamports per compute unit
+var WITHDRAW_PRIORITY_FEE_MAX = uint64(1000000)            // Micro lamports per compute unit
+var WITHDRAW_PRIORITY_FEE_ESCALATION = uint64(2)           // Fee multiplier per rebroadcast
 
 const DREAMTOWER_HEIGHT = uint(9)
 
@@ -238,6 +308,9 @@ var COUPON_EXCHANGE_CHIP_RATE = 100                                   // 100 %
 var COUPON_MAXIMUM_EXCHANGE = 50 * ONE_CHIP_WITH_DECIMALS             // Maximum exchange amount
 var COUPON_MAXIMUM_FIRST_DEPOSIT_BONUS = 100 * ONE_CHIP_WITH_DECIMALS // Maximum bonus balance on first deposit
 var COUPON_TEMP_ID = uint(100001)
+var COUPON_CAMPAIGN_MAXIMUM_CODE_COUNT = uint(50000)
+var COUPON_CAMPAIGN_CODE_BATCH_SIZE = 1000
+var COUPON_DEPOSIT_BONUS_MAXIMUM_MATCH_PERCENT = uint(200) // 200 %
 
 var HIDDEN_USERS = []string{"DuelBot"}
 var ACCOUNT_LIMIT_PER_IP = uint(20)
@@ -245,7 +318,7 @@ var ACCOUNT_LIMIT_FOR_SPECIFIC_IP = map[string]uint{}
 
 var DAILY_RACE_TEMP_ID = uint(100004)
 
-var WEEKLY_RAFFLE_TEMP_ID = uint(100005)                                // maximum reserved user_id flag here
+var WEEKLY_RAFFLE_TEMP_ID = uint(100005)
 var WEEKLY_RAFFLE_MAXIMUM_TICKET_ID = uint(9999999)                     // Currently set as infinite.
 var WEEKLY_RAFFLE_MAXIMUM_TICKET_PER_USER = uint(9999999)               // Currently set as infinite
 var WEEKLY_RAFFLE_MAXIMUM_PARTICIPANTS = uint(9999999)                  // Currently set as infinite
@@ -259,6 +332,51 @@ var WEEKLY_RAFFLE_DEFAULT_PRIZES = []int64{                             // One t
 }
 var WEEKLY_RAFFLE_OPEN = true
 
+var QUEST_TEMP_ID = uint(100006)
+var QUEST_DAILY_COUNT = uint(3)   // 3 daily quests per day
+var QUEST_WEEKLY_COUNT = uint(3)  // 3 weekly quests per week
+var QUEST_WAGER_QUEUE_SIZE = 4096 // Wager events buffered for quest progress
+
+var HOUSE_RAIN_TEMP_ID = uint(100007)                              // maximum reserved user_id flag here
+var HOUSE_RAIN_DAILY_BUDGET = int64(1000 * ONE_CHIP_WITH_DECIMALS) // 1000 chips per day until set by admin
+var HOUSE_RAIN_COUNTDOWN_NOTICES = []uint{60, 30, 10}              // Remaining seconds to announce countdown
+var VIP_LEVEL_WAGER_THRESHOLDS = []int64{                          // Total wagered to reach each VIP level
+	1000 * ONE_CHIP_WITH_DECIMALS,
+	10000 * ONE_CHIP_WITH_DECIMALS,
+	50000 * ONE_CHIP_WITH_DECIMALS,
+	250000 * ONE_CHIP_WITH_DECIMALS,
+	1000000 * ONE_CHIP_WITH_DECIMALS,
+}
+
+var FINANCIAL_REPORT_AGGREGATE_SPEC = "10 0 * * *" // Nightly at 00:10 UTC
+var FINANCIAL_REPORT_BACKFILL_DAYS = 31            // Missing days materialized on start up
+var FINANCIAL_REPORT_MAX_RANGE_DAYS = 366          // Maximum days per report query
+
+var LOG_DEFAULT_SINKS = "console" // Used when LOG_SINKS is empty
+var LOG_DEFAULT_LEVEL = "info"    // Used when LOG_LEVELS has no default entry
+var LOG_FILE_DEFAULT_PATH = "logs/duelana.log"
+var LOG_FILE_MAX_AGE_DAYS = 14 // Rotated log files kept on disk
+var LOG_CLOUDWATCH_BATCH_INTERVAL = 5 * time.Second
+var LOG_CORRELATION_ID_MAX_LENGTH = 64  // Longer X-Request-ID headers are replaced
+var LOG_REDACTED_FIELD_KEYS = []string{ // Matched against lower cased field keys without '_' and '-'
+	"privatekey",
+	"prikey",
+	"secretkey",
+	"secret",
+	"password",
+	"pwd",
+	"apikey",
+	"accesskey",
+	"accesstoken",
+	"apitoken",
+	"authorization",
+	"mnemonic",
+	"seedphrase",
+}
+var LOG_REDACTED_EXACT_FIELD_KEYS = []string{ // Unrevealed server seeds, e.g. running crash round
+	"seed",
+}
+
 var DREAMTOWER_DIFFICULTIES = map[string]models.DreamTowerDifficulty{
 	"Easy": {
 		Level:       models.LevelEasy,
@@ -291,6 +409,10 @@ var TIP_MIN_AMOUNT = int64(float64(0.01) * float64(ONE_CHIP_WITH_DECIMALS))
 var TIP_MAX_AMOUNT = int64(10000 * ONE_CHIP_WITH_DECIMALS)
 var MUTE_DURATION = time.Duration(15) * time.Minute
 
+var ADMIN_AUDIT_LOG_MAX_FIELD_LENGTH = 4096 // bytes of parameters and result kept per entry
+var ADMIN_AUDIT_LOG_MAX_QUERY_LIMIT = 500   // entries per audit log query
+var ADMIN_OPERATOR_TOKEN_BYTES = 32         // random bytes of operator api token
+
 var CHAT_COMMANDS = []types.ChatCommand{
 	{Pattern: "/mute user", RegExp: `/\/mute \w+/`, Role: models.ModeratorRole},
 	{Pattern: "/unmute user", RegExp: `/\/unmute \w+/`, Role: models.ModeratorRole},
@@ -314,3 +436,42 @@ var BONK_SPL_ADDRESS = "DezXAZ8z7PnrnRJjz3wXBoRgixCa6xjnB7YaB1pPB263"
 var USDC_SPL_ADDRESS = "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v"
 var SOL_SPL_ADDRESS = "So11111111111111111111111111111111111111112"
 var BOKU_SPL_ADDRESS = "CN7qFa5iYkHz99PTctvT4xXUHnxwjQ5MHxCuTJtPN5uS"
+var TOKEN_2022_PROGRAM_ADDRESS = "TokenzQdBNbLqP5VEhdkAS6EPFLC1PGnBNVLgjxoXq1"
+
+var SPL_TOKEN_CACHE_TTL = time.Minute                    // Registry reload interval of supported spl tokens
+var SPL_TOKEN_DEFAULT_PRICE_SOURCE = "coingecko,jupiter" // Comma separated price sources of tokens created without one
+var SPL_TOKEN_MAX_DECIMALS = 18
+
+var PRICE_ORACLE_CACHE_TTL = 30 * time.Second
+var PRICE_ORACLE_MAX_STALENESS = 5 * time.Minute // Older quotes are ignored
+var PRICE_ORACLE_MAX_DEVIATION = int64(200)      // Of a quote from median, in bps
+var PRICE_ORACLE_MIN_QUOTES = 2                  // Capped to price source count of the token
+var PRICE_ORACLE_REQUEST_TIMEOUT = 5 * time.Second
+var PRICE_ORACLE_QUEUE_RETRY_INTERVAL = time.Minute // Of deposits queued at unsafe price
+var PRICE_ORACLE_JUPITER_PRICE_API = "https://api.jup.ag/price/v2"
+
+var NFT_FLOOR_UPDATE_SCHEDULE = "@every 30m"
+var NFT_FLOOR_TWAP_WINDOW = 6 * time.Hour // Floor applied is time weighted over this window
+var NFT_FLOOR_MAX_CHANGE = int64(1000)    // Per update, in bps of current floor
+var NFT_FLOOR_HISTORY_LIMIT = 200
+var NFT_FLOOR_MAGIC_EDEN_API = "https://api-mainnet.magiceden.dev/v2"
+
+var NFT_RARITY_CACHE_TTL = 6 * time.Hour
+var NFT_RARITY_TIERS = []types.RarityTier{ // Rarest first, top in bps of supply, multiplier in bps of floor
+	{Top: 100, Multiplier: 30000},
+	{Top: 500, Multiplier: 20000},
+	{Top: 1000, Multiplier: 15000},
+	{Top: 2500, Multiplier: 12000},
+}
+var NFT_RARITY_HOWRARE_API = "https://api.howrare.is/v0.1"
+var NFT_RARITY_MOONRANK_API = "https://moonrank.app"
+var NFT_SALES_CACHE_TTL = 10 * time.Minute
+var NFT_SALES_LIMIT = 500
+var NFT_VALUATION_SALES_WINDOW = 7 * 24 * time.Hour // Older sales are ignored
+var NFT_VALUATION_MIN_SALES = 3                     // Of the rarity tier to value by sales
+var NFT_VALUATION_MIN_MULTIPLE = int64(10000)       // Of floor, in bps
+var NFT_VALUATION_MAX_MULTIPLE = int64(30000)       // Of floor, in bps
+
+var TREASURY_MONITOR_SCHEDULE = "@every 5m"
+var TREASURY_SWEEP_TARGET = int64(5000) // Hot balance left by a sweep, in bps from min to max threshold
+var TREASURY_SWEEP_HISTORY_LIMIT = 200
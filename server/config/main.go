@@ -24,6 +24,7 @@ type Config struct {
 	Network               string `mapstructure:"NETWORK"`
 	MasterWalletPubKey    string `mapstructure:"MASTER_WALLET_PUB_KEY"`
 	MasterWalletPriKey    string `mapstructure:"MASTER_WALLET_PRI_KEY"`
+	ColdWalletPubKey      string `mapstructure:"COLD_WALLET_PUB_KEY"`
 	ProgramKey            string `mapstructure:"PROGRAM_KEY"`
 	TokenPriceApi         string `mapstructure:"TOKEN_PRICE_API"`
 	TatumBroadcastApi     string `mapstructure:"TATUM_BROADCAST_CONFIRM"`
@@ -45,6 +46,12 @@ type Config struct {
 	RedisUrl              string `mapstructure:"REDIS_URL"`
 	RedisPwd              string `mapstructure:"REDIS_PWD"`
 	WeeklyRaffleRandomKey string `mapstructure:"WEEKLY_RAFFLE_RANDOM_KEY"`
+	LogSinks              string `mapstructure:"LOG_SINKS"`
+	LogLevels             string `mapstructure:"LOG_LEVELS"`
+	LogFilePath           string `mapstructure:"LOG_FILE_PATH"`
+	MetricsAccessToken    string `mapstructure:"METRICS_ACCESS_TOKEN"`
+	TrustedProxies        string `mapstructure:"TRUSTED_PROXIES"`
+	ClientIPHeader        string `mapstructure:"CLIENT_IP_HEADER"`
 }
 
 var config Config
@@ -94,6 +101,7 @@ func loadEnv() (conf Config, err error) {
 		Network:               viper.GetString("network"),
 		MasterWalletPubKey:    viper.GetString("master_wallet_pub_key"),
 		MasterWalletPriKey:    viper.GetString("master_wallet_pri_key"),
+		ColdWalletPubKey:      viper.GetString("cold_wallet_pub_key"),
 		ProgramKey:            viper.GetString("program_key"),
 		TokenPriceApi:         viper.GetString("TOKEN_PRICE_API"),
 		TatumBroadcastApi:     viper.GetString("tatum_broadcast_confirm"),
@@ -114,6 +122,12 @@ func loadEnv() (conf Config, err error) {
 		AdminApiAccessToken:   viper.GetString("ADMIN_API_ACCESS_TOKEN"),
 		RedisUrl:              viper.GetString("REDIS_URL"),
 		WeeklyRaffleRandomKey: viper.GetString("WEEKLY_RAFFLE_RANDOM_KEY"),
+		LogSinks:              viper.GetString("LOG_SINKS"),
+		LogLevels:             viper.GetString("LOG_LEVELS"),
+		LogFilePath:           viper.GetString("LOG_FILE_PATH"),
+		MetricsAccessToken:    viper.GetString("METRICS_ACCESS_TOKEN"),
+		TrustedProxies:        viper.GetString("TRUSTED_PROXIES"),
+		ClientIPHeader:        viper.GetString("CLIENT_IP_HEADER"),
 	}
 	if conf.Network == "mainnet" {
 		conf.SolanaRpcUrl = viper.GetString("mainnet_rpc_url")
@@ -165,3 +179,29 @@ func GetAPIRateConfig(key string) types.RateLimit {
 func GetWebsocketRateConfig(key string) types.RateLimit {
 	return websocketRateConfig[key]
 }
+
+/**
+* Returns reserved house user ids. Chips of these users are
+* internal movements and not owed to players.
+ */
+func GetHouseUserIDs() []uint {
+	return []uint{
+		JACKPOT_TEMP_ID,
+		JACKPOT_FEE_ID,
+		GRAND_JACKPOT_TEMP_ID,
+		GRAND_JACKPOT_FEE_ID,
+		COINFLIP_TEMP_ID,
+		COINFLIP_FEE_ID,
+		COINFLIP_BOT_ID,
+		DREAMTOWER_TEMP_ID,
+		DREAMTOWER_FEE_ID,
+		CRASH_TEMP_ID,
+		CRASH_FEE_ID,
+		DUEL_BOT_STAKE_ID,
+		COUPON_TEMP_ID,
+		DAILY_RACE_TEMP_ID,
+		WEEKLY_RAFFLE_TEMP_ID,
+		QUEST_TEMP_ID,
+		HOUSE_RAIN_TEMP_ID,
+	}
+}
@@ -0,0 +1,209 @@
+package admin
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Duelana-Team/duelana-v1/controllers/coupon"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"github.com/gin-gonic/gin"
+)
+
+func CreateCouponCampaignHandler(ctx *gin.Context) {
+	var params coupon.CreateCampaignRequest
+	if err := ctx.BindJSON(&params); err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+	params.Balance = utils.ConvertChipToBalance(params.Balance)
+	params.MaxBetWhileActive = utils.ConvertChipToBalance(params.MaxBetWhileActive)
+
+	campaign, err := coupon.CreateCampaign(params)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if utils.IsErrorCode(err, coupon.ErrCodeInvalidParameter) ||
+			utils.IsErrorCode(err, coupon.ErrCodeCampaignNameDuplicated) {
+			status = http.StatusBadRequest
+		}
+		ctx.AbortWithStatusJSON(
+			status,
+			gin.H{
+				"message": "failed to create coupon campaign",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"message":  "succeed to create coupon campaign",
+			"campaign": campaign,
+		},
+	)
+}
+
+func GetCouponCampaignCodesHandler(ctx *gin.Context) {
+	campaignID, err := strconv.ParseUint(ctx.Query("campaignId"), 10, 32)
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	codes, err := coupon.GetCampaignCodes(uint(campaignID))
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to get coupon campaign codes",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"campaignId": campaignID,
+			"codes":      codes,
+		},
+	)
+}
+
+func GetCouponCampaignStatsHandler(ctx *gin.Context) {
+	stats, err := coupon.GetCampaignStats()
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to get coupon campaign stats",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"campaigns": stats,
+		},
+	)
+}
+
+func CreateDepositBonusCampaignHandler(ctx *gin.Context) {
+	var params coupon.CreateDepositBonusCampaignRequest
+	if err := ctx.BindJSON(&params); err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+	params.MaxBonus = utils.ConvertChipToBalance(params.MaxBonus)
+	params.MinDeposit = utils.ConvertChipToBalance(params.MinDeposit)
+	params.MaxBetWhileActive = utils.ConvertChipToBalance(params.MaxBetWhileActive)
+
+	campaign, err := coupon.CreateDepositBonusCampaign(params)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if utils.IsErrorCode(err, coupon.ErrCodeInvalidParameter) ||
+			utils.IsErrorCode(err, coupon.ErrCodeCampaignNameDuplicated) {
+			status = http.StatusBadRequest
+		}
+		ctx.AbortWithStatusJSON(
+			status,
+			gin.H{
+				"message": "failed to create deposit bonus campaign",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"message":  "succeed to create deposit bonus campaign",
+			"campaign": campaign,
+		},
+	)
+}
+
+func SetDepositBonusCampaignEnabledHandler(ctx *gin.Context) {
+	var params struct {
+		CampaignID uint `json:"campaignId"`
+		Enabled    bool `json:"enabled"`
+	}
+	if err := ctx.BindJSON(&params); err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	if err := coupon.SetDepositBonusCampaignEnabled(
+		params.CampaignID,
+		params.Enabled,
+	); err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to set deposit bonus campaign status",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"message":    "succeed to set deposit bonus campaign status",
+			"campaignId": params.CampaignID,
+			"enabled":    params.Enabled,
+		},
+	)
+}
+
+func GetDepositBonusCampaignsHandler(ctx *gin.Context) {
+	campaigns, err := coupon.GetAllDepositBonusCampaigns()
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to get deposit bonus campaigns",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"campaigns": campaigns,
+		},
+	)
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/Duelana-Team/duelana-v1/db"
+	"github.com/Duelana-Team/duelana-v1/models"
 	"github.com/Duelana-Team/duelana-v1/utils"
 )
 
@@ -154,3 +155,213 @@ on coinflip_state.name = dream_tower_state.name`
 
 	return &userState, nil
 }
+
+// Returns persisted feature switches.
+// Returns empty list when db is not initialized.
+func getFeatureSwitches() ([]models.FeatureSwitch, error) {
+	db := db.GetDB()
+	if db == nil {
+		return []models.FeatureSwitch{}, nil
+	}
+
+	switches := []models.FeatureSwitch{}
+	if err := db.Find(&switches).Error; err != nil {
+		return nil, utils.MakeError(
+			"admin_db_aggregator",
+			"getFeatureSwitches",
+			"failed to retrieve feature switches",
+			err,
+		)
+	}
+	return switches, nil
+}
+
+// Saves block state of the feature.
+// Skipped when db is not initialized.
+func saveFeatureSwitch(name string, blocked bool) error {
+	db := db.GetDB()
+	if db == nil {
+		return nil
+	}
+
+	if err := db.Save(&models.FeatureSwitch{
+		Name:    name,
+		Blocked: blocked,
+	}).Error; err != nil {
+		return utils.MakeError(
+			"admin_db_aggregator",
+			"saveFeatureSwitch",
+			"failed to save feature switch",
+			fmt.Errorf(
+				"name: %s, blocked: %v, err: %v",
+				name, blocked, err,
+			),
+		)
+	}
+	return nil
+}
+
+// Returns all admin operators.
+func getAdminOperators() ([]models.AdminOperator, error) {
+	database := db.GetDB()
+	if database == nil {
+		return nil, utils.MakeError(
+			"admin_db_aggregator",
+			"getAdminOperators",
+			"failed to retrieve db pointer",
+			errors.New("retrieved db pointer is nil"),
+		)
+	}
+
+	operators := []models.AdminOperator{}
+	if err := database.Order("id").Find(&operators).Error; err != nil {
+		return nil, utils.MakeError(
+			"admin_db_aggregator",
+			"getAdminOperators",
+			"failed to retrieve operators",
+			err,
+		)
+	}
+	return operators, nil
+}
+
+// Returns audit logs matching the filter, latest first,
+// with total count of matching logs.
+func getAuditLogs(filter AuditLogFilter) ([]models.AdminAuditLog, int64, error) {
+	database := db.GetDB()
+	if database == nil {
+		return nil, 0, utils.MakeError(
+			"admin_db_aggregator",
+			"getAuditLogs",
+			"failed to retrieve db pointer",
+			errors.New("retrieved db pointer is nil"),
+		)
+	}
+
+	query := database.Model(&models.AdminAuditLog{})
+	if filter.OperatorName != "" {
+		query = query.Where("operator_name = ?", filter.OperatorName)
+	}
+	if filter.Route != "" {
+		query = query.Where("route = ?", filter.Route)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, utils.MakeError(
+			"admin_db_aggregator",
+			"getAuditLogs",
+			"failed to count audit logs",
+			fmt.Errorf(
+				"filter: %v, err: %v",
+				filter, err,
+			),
+		)
+	}
+
+	logs := []models.AdminAuditLog{}
+	if err := query.Order(
+		"id desc",
+	).Limit(
+		filter.Limit,
+	).Offset(
+		filter.Offset,
+	).Find(&logs).Error; err != nil {
+		return nil, 0, utils.MakeError(
+			"admin_db_aggregator",
+			"getAuditLogs",
+			"failed to retrieve audit logs",
+			fmt.Errorf(
+				"filter: %v, err: %v",
+				filter, err,
+			),
+		)
+	}
+
+	return logs, total, nil
+}
+
+// Returns admin operator by id.
+func getAdminOperator(id uint) (*models.AdminOperator, error) {
+	database := db.GetDB()
+	if database == nil {
+		return nil, utils.MakeError(
+			"admin_db_aggregator",
+			"getAdminOperator",
+			"failed to retrieve db pointer",
+			errors.New("retrieved db pointer is nil"),
+		)
+	}
+
+	operator := models.AdminOperator{}
+	if err := database.First(&operator, id).Error; err != nil {
+		return nil, utils.MakeError(
+			"admin_db_aggregator",
+			"getAdminOperator",
+			"failed to retrieve operator",
+			fmt.Errorf(
+				"id: %d, err: %v",
+				id, err,
+			),
+		)
+	}
+	return &operator, nil
+}
+
+// Creates a new admin operator.
+func createAdminOperator(operator *models.AdminOperator) error {
+	database := db.GetDB()
+	if database == nil {
+		return utils.MakeError(
+			"admin_db_aggregator",
+			"createAdminOperator",
+			"failed to retrieve db pointer",
+			errors.New("retrieved db pointer is nil"),
+		)
+	}
+
+	if err := database.Create(operator).Error; err != nil {
+		return utils.MakeError(
+			"admin_db_aggregator",
+			"createAdminOperator",
+			"failed to create operator",
+			fmt.Errorf(
+				"name: %s, err: %v",
+				operator.Name, err,
+			),
+		)
+	}
+	return nil
+}
+
+// Saves role, enabled state and token hash of admin operator.
+func saveAdminOperator(operator *models.AdminOperator) error {
+	database := db.GetDB()
+	if database == nil {
+		return utils.MakeError(
+			"admin_db_aggregator",
+			"saveAdminOperator",
+			"failed to retrieve db pointer",
+			errors.New("retrieved db pointer is nil"),
+		)
+	}
+
+	if err := database.Save(operator).Error; err != nil {
+		return utils.MakeError(
+			"admin_db_aggregator",
+			"saveAdminOperator",
+			"failed to save operator",
+			fmt.Errorf(
+				"id: %d, err: %v",
+				operator.ID, err,
+			),
+		)
+	}
+	return nil
+}
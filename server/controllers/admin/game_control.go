@@ -50,6 +50,16 @@ func (c *GameController) Init() {
 	for _, gameName := range c.gameNames {
 		c.gameBlocked.Store(gameName, false)
 	}
+	if err := c.loadBlocked(); err != nil {
+		log.LogMessage(
+			"admin_game_controller",
+			"failed to load persisted block states",
+			"error",
+			logrus.Fields{
+				"error": err.Error(),
+			},
+		)
+	}
 	log.LogMessage(
 		"admin_game_controller",
 		"successfully initialized",
@@ -96,10 +106,32 @@ func (c *GameController) BlockGame(gameName string, block bool) error {
 		)
 	}
 
+	if err := saveFeatureSwitch(gameName, block); err != nil {
+		return utils.MakeError(
+			"admin-game-control",
+			"BlockGame",
+			"failed to persist block state",
+			err,
+		)
+	}
 	c.gameBlocked.Store(gameName, block)
 	return nil
 }
 
+// Restores block states persisted before restart.
+func (c *GameController) loadBlocked() error {
+	switches, err := getFeatureSwitches()
+	if err != nil {
+		return err
+	}
+	for _, featureSwitch := range switches {
+		if _, prs := c.gameBlocked.Load(featureSwitch.Name); prs {
+			c.gameBlocked.Store(featureSwitch.Name, featureSwitch.Blocked)
+		}
+	}
+	return nil
+}
+
 func (c *GameController) GetGameBlocked(gameName string) bool {
 	blocked, prs := c.gameBlocked.Load(gameName)
 	isBlocked, ok := blocked.(bool)
@@ -150,3 +182,12 @@ func GameControllerMiddleware(gameName string) gin.HandlerFunc {
 func GetGameBlocked(gameName string) bool {
 	return gameController.GetGameBlocked(gameName)
 }
+
+// Blocks a game on behalf of other modules, e.g. withdrawals on
+// low treasury liquidity. An already blocked game is left as is.
+func BlockGame(gameName string) error {
+	if gameController.GetGameBlocked(gameName) {
+		return nil
+	}
+	return gameController.BlockGame(gameName, true)
+}
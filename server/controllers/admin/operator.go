@@ -0,0 +1,283 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/middlewares"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type AuditLogFilter struct {
+	OperatorName string
+	Route        string
+	From         *time.Time
+	To           *time.Time
+	Limit        int
+	Offset       int
+}
+
+/**
+* @Internal
+* Checks whether the role is one of operator roles.
+ */
+func isValidOperatorRole(role models.AdminOperatorRole) bool {
+	switch role {
+	case models.AdminFinanceRole,
+		models.AdminGamesRole,
+		models.AdminPromotionsRole,
+		models.AdminSupportRole,
+		models.AdminSuperRole:
+		return true
+	}
+	return false
+}
+
+/**
+* @Internal
+* Generates a new api token for operator.
+* Returns plain token to be delivered once and its hash to be saved.
+ */
+func generateOperatorToken() (string, string, error) {
+	token, err := utils.GenerateClientSeed(config.ADMIN_OPERATOR_TOKEN_BYTES)
+	if err != nil {
+		return "", "", err
+	}
+	return token, middlewares.HashAdminApiToken(token), nil
+}
+
+/**
+* @Internal
+* Parses audit log query parameters.
+ */
+func buildAuditLogFilter(ctx *gin.Context) (*AuditLogFilter, error) {
+	filter := AuditLogFilter{
+		OperatorName: ctx.Query("operator"),
+		Route:        ctx.Query("route"),
+		Limit:        100,
+	}
+
+	if limit := ctx.Query("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil ||
+			value <= 0 ||
+			value > config.ADMIN_AUDIT_LOG_MAX_QUERY_LIMIT {
+			return nil, fmt.Errorf("invalid limit: %s", limit)
+		}
+		filter.Limit = value
+	}
+	if offset := ctx.Query("offset"); offset != "" {
+		value, err := strconv.Atoi(offset)
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("invalid offset: %s", offset)
+		}
+		filter.Offset = value
+	}
+	if from := ctx.Query("from"); from != "" {
+		value, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from: %s", from)
+		}
+		filter.From = &value
+	}
+	if to := ctx.Query("to"); to != "" {
+		value, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to: %s", to)
+		}
+		filter.To = &value
+	}
+
+	return &filter, nil
+}
+
+func GetAdminOperatorsHandler(ctx *gin.Context) {
+	operators, err := getAdminOperators()
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to get operators",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"operators": operators,
+		},
+	)
+}
+
+func CreateAdminOperatorHandler(ctx *gin.Context) {
+	var params struct {
+		Name string                   `json:"name"`
+		Role models.AdminOperatorRole `json:"role"`
+	}
+	if err := ctx.BindJSON(&params); err != nil ||
+		params.Name == "" ||
+		params.Name == middlewares.ROOT_ADMIN_OPERATOR_NAME ||
+		!isValidOperatorRole(params.Role) {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+			},
+		)
+		return
+	}
+
+	token, tokenHash, err := generateOperatorToken()
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to generate token",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	operator := models.AdminOperator{
+		Name:      params.Name,
+		Role:      params.Role,
+		TokenHash: tokenHash,
+		Enabled:   true,
+	}
+	if err := createAdminOperator(&operator); err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "failed to create operator",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	// Token is delivered only once and must not be kept in audit log.
+	ctx.Set(middlewares.ADMIN_AUDIT_OMIT_RESULT_KEY, true)
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"operator": operator,
+			"token":    token,
+		},
+	)
+}
+
+func UpdateAdminOperatorHandler(ctx *gin.Context) {
+	var params struct {
+		ID          uint                     `json:"id"`
+		Role        models.AdminOperatorRole `json:"role"`
+		Enabled     *bool                    `json:"enabled"`
+		RotateToken bool                     `json:"rotateToken"`
+	}
+	if err := ctx.BindJSON(&params); err != nil ||
+		params.ID == 0 ||
+		(params.Role != "" && !isValidOperatorRole(params.Role)) {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+			},
+		)
+		return
+	}
+
+	operator, err := getAdminOperator(params.ID)
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusNotFound,
+			gin.H{
+				"message": "operator not found",
+			},
+		)
+		return
+	}
+
+	if params.Role != "" {
+		operator.Role = params.Role
+	}
+	if params.Enabled != nil {
+		operator.Enabled = *params.Enabled
+	}
+	token := ""
+	if params.RotateToken {
+		var tokenHash string
+		if token, tokenHash, err = generateOperatorToken(); err != nil {
+			ctx.AbortWithStatusJSON(
+				http.StatusInternalServerError,
+				gin.H{
+					"message": "failed to generate token",
+					"error":   err.Error(),
+				},
+			)
+			return
+		}
+		operator.TokenHash = tokenHash
+		ctx.Set(middlewares.ADMIN_AUDIT_OMIT_RESULT_KEY, true)
+	}
+
+	if err := saveAdminOperator(operator); err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to update operator",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	response := gin.H{
+		"operator": operator,
+	}
+	if token != "" {
+		response["token"] = token
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+func GetAuditLogsHandler(ctx *gin.Context) {
+	filter, err := buildAuditLogFilter(ctx)
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	logs, total, err := getAuditLogs(*filter)
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to get audit logs",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"logs":  logs,
+			"total": total,
+		},
+	)
+}
@@ -0,0 +1,187 @@
+package chat
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Duelana-Team/duelana-v1/middlewares"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"github.com/gin-gonic/gin"
+)
+
+func (c *Controller) GetIgnoredUsersHandler(ctx *gin.Context) {
+	userID := middlewares.GetAuthUserID(ctx, true)
+	if userID == 0 {
+		return
+	}
+
+	users, err := c.GetIgnoredUsers(userID)
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to retrieve ignored users",
+			},
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"users": users,
+	})
+}
+
+func (c *Controller) IgnoreUserHandler(ctx *gin.Context) {
+	userID := middlewares.GetAuthUserID(ctx, true)
+	if userID == 0 {
+		return
+	}
+
+	var params struct {
+		UserID uint `json:"userId"`
+	}
+	if err := ctx.BindJSON(&params); err != nil || params.UserID == 0 {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+			},
+		)
+		return
+	}
+
+	if err := c.IgnoreUser(userID, params.UserID); err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "failed to ignore user",
+			},
+		)
+		return
+	}
+
+	c.GetIgnoredUsersHandler(ctx)
+}
+
+func (c *Controller) UnignoreUserHandler(ctx *gin.Context) {
+	userID := middlewares.GetAuthUserID(ctx, true)
+	if userID == 0 {
+		return
+	}
+
+	var params struct {
+		UserID uint `json:"userId"`
+	}
+	if err := ctx.BindJSON(&params); err != nil || params.UserID == 0 {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+			},
+		)
+		return
+	}
+
+	if err := c.UnignoreUser(userID, params.UserID); err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to unignore user",
+			},
+		)
+		return
+	}
+
+	c.GetIgnoredUsersHandler(ctx)
+}
+
+func (c *Controller) SendDirectMessageHandler(ctx *gin.Context) {
+	userID := middlewares.GetAuthUserID(ctx, true)
+	if userID == 0 {
+		return
+	}
+
+	var params struct {
+		Recipient uint   `json:"recipient"`
+		Message   string `json:"message"`
+	}
+	if err := ctx.BindJSON(&params); err != nil || params.Recipient == 0 {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+			},
+		)
+		return
+	}
+
+	directMessage, err := c.SendDirectMessage(
+		userID,
+		params.Recipient,
+		params.Message,
+	)
+	if utils.IsErrorCode(err, ErrCodeIgnoredByRecipient) {
+		ctx.AbortWithStatusJSON(
+			http.StatusForbidden,
+			gin.H{
+				"message": "recipient is not accepting your messages",
+			},
+		)
+		return
+	} else if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "failed to send direct message",
+			},
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": directMessage,
+	})
+}
+
+func (c *Controller) GetDirectMessagesHandler(ctx *gin.Context) {
+	userID := middlewares.GetAuthUserID(ctx, true)
+	if userID == 0 {
+		return
+	}
+
+	otherID, err := strconv.Atoi(ctx.Query("userId"))
+	if err != nil || otherID <= 0 {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+			},
+		)
+		return
+	}
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 || limit > 100 {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+			},
+		)
+		return
+	}
+
+	messages, err := c.GetDirectMessages(userID, uint(otherID), limit)
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to retrieve direct messages",
+			},
+		)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"messages": messages,
+	})
+}
@@ -0,0 +1,6 @@
+package chat
+
+// Error code range: #115xxx
+const ErrCodeBase = "#115"
+const ErrCodeInvalidParameter = ErrCodeBase + "000"
+const ErrCodeIgnoredByRecipient = ErrCodeBase + "001"
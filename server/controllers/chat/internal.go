@@ -63,6 +63,11 @@ func (c *Controller) detectAndHandleCommand(user models.User, content string) (s
 			return content, c.setChatCooldown(user, param1)
 		}
 		return content, true
+	case "tip":
+		if ok, err := regexp.MatchString(`^/tip\s\w+\s\d+$`, content); err == nil && ok {
+			return c.tip(user, param1, param2, content)
+		}
+		return content, true
 	case "rain":
 		if ok, err := regexp.MatchString(`^/rain\s\d+\s\d+$`, content); err == nil && ok {
 			return c.rain(user, param1, param2, content)
@@ -250,6 +255,86 @@ func (c *Controller) setChatCooldown(user models.User, param string) bool {
 	return false
 }
 
+func (c *Controller) tip(user models.User, param1 string, param2 string, content string) (string, bool) {
+	var amount int64
+	n, err := fmt.Sscanf(param2, "%d", &amount)
+	if n != 1 || err != nil {
+		return content, true
+	}
+
+	var recipient models.User
+	if err := db.GetDB().Where("name = ?", param1).First(&recipient).Error; err != nil {
+		b, _ := json.Marshal(types.WSMessage{
+			Room:      string(types.Chat),
+			EventType: "error",
+			Payload:   types.ErrorMessagePayload{Message: "Invalid userName : " + param1}})
+		c.EventEmitter <- types.WSEvent{Users: []uint{user.ID}, Message: b}
+		return content, false
+	}
+
+	if payload := validateTip(user, recipient, amount); payload != nil {
+		b, _ := json.Marshal(types.WSMessage{
+			Room:      string(types.Chat),
+			EventType: "error",
+			Payload:   *payload})
+		c.EventEmitter <- types.WSEvent{Users: []uint{user.ID}, Message: b}
+		return content, false
+	}
+
+	_, err = transaction.Transfer(&transaction.TransactionRequest{
+		FromUser: (*db_aggregator.User)(&user.ID),
+		ToUser:   (*db_aggregator.User)(&recipient.ID),
+		Balance: db_aggregator.BalanceLoad{
+			ChipBalance: &amount,
+		},
+		Type:          models.TxTip,
+		ToBeConfirmed: true,
+		OwnerID:       user.ID,
+		OwnerType:     models.TransactionUserReferenced,
+	})
+	if err != nil {
+		log.LogMessage("tip handler", "failed to transfer tip", "error", logrus.Fields{"error": err.Error()})
+		return content, false
+	}
+
+	b, _ := json.Marshal(types.WSMessage{
+		EventType: "balance_update",
+		Payload: types.BalanceUpdatePayload{
+			UpdateType:  types.Decrease,
+			Balance:     amount,
+			BalanceType: models.ChipBalanceForGame,
+			Delay:       0,
+		}})
+	c.EventEmitter <- types.WSEvent{Users: []uint{user.ID}, Message: b}
+
+	b, _ = json.Marshal(types.WSMessage{
+		EventType: "balance_update",
+		Payload: types.BalanceUpdatePayload{
+			UpdateType:  types.Increase,
+			Balance:     amount,
+			BalanceType: models.ChipBalanceForGame,
+			Delay:       0,
+		}})
+	c.EventEmitter <- types.WSEvent{Users: []uint{recipient.ID}, Message: b}
+
+	to, _ := json.Marshal(utils.GetUserDataWithPermissions(recipient, nil, 0))
+	return fmt.Sprintf(`$ %d %s`, amount, string(to)), true
+}
+
+// Returns error to be sent to the tipper, or nil for a valid tip.
+func validateTip(user models.User, recipient models.User, amount int64) *types.ErrorMessagePayload {
+	if recipient.ID == user.ID ||
+		amount < config.TIP_MIN_AMOUNT ||
+		amount > config.TIP_MAX_AMOUNT {
+		return &types.ErrorMessagePayload{Message: "Invalid tip"}
+	}
+	if user.Wallet.Balance.ChipBalance == nil ||
+		user.Wallet.Balance.ChipBalance.Balance < amount {
+		return &types.ErrorMessagePayload{ErrorCode: types.ErrNotEnoughChip}
+	}
+	return nil
+}
+
 func (c *Controller) rain(user models.User, param1 string, param2 string, content string) (string, bool) {
 	var split int
 	var amount int64
@@ -0,0 +1,69 @@
+package chat
+
+import (
+	"testing"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/types"
+	"gorm.io/gorm"
+)
+
+func TestValidateTip(t *testing.T) {
+	user := models.User{
+		Model: gorm.Model{ID: 1},
+		Wallet: models.Wallet{
+			Balance: models.Balance{
+				ChipBalance: &models.ChipBalance{
+					Balance: 100 * config.ONE_CHIP_WITH_DECIMALS,
+				},
+			},
+		},
+	}
+	recipient := models.User{Model: gorm.Model{ID: 2}}
+
+	if payload := validateTip(
+		user,
+		user,
+		config.ONE_CHIP_WITH_DECIMALS,
+	); payload == nil || payload.Message != "Invalid tip" {
+		t.Fatalf("should not tip yourself: %v", payload)
+	}
+	if payload := validateTip(
+		user,
+		recipient,
+		config.TIP_MIN_AMOUNT-1,
+	); payload == nil || payload.Message != "Invalid tip" {
+		t.Fatalf("should not tip under min amount: %v", payload)
+	}
+	if payload := validateTip(
+		user,
+		recipient,
+		config.TIP_MAX_AMOUNT+1,
+	); payload == nil || payload.Message != "Invalid tip" {
+		t.Fatalf("should not tip over max amount: %v", payload)
+	}
+	if payload := validateTip(
+		user,
+		recipient,
+		100*config.ONE_CHIP_WITH_DECIMALS+1,
+	); payload == nil || payload.ErrorCode != types.ErrNotEnoughChip {
+		t.Fatalf("should not tip over balance: %v", payload)
+	}
+	if payload := validateTip(
+		models.User{Model: gorm.Model{ID: 3}},
+		recipient,
+		config.TIP_MIN_AMOUNT,
+	); payload == nil || payload.ErrorCode != types.ErrNotEnoughChip {
+		t.Fatalf("should not tip without chip balance: %v", payload)
+	}
+
+	for _, amount := range []int64{
+		config.TIP_MIN_AMOUNT,
+		100 * config.ONE_CHIP_WITH_DECIMALS,
+	} {
+		if payload := validateTip(user, recipient, amount); payload != nil {
+			t.Fatalf("should tip %d: %v", amount, payload)
+		}
+	}
+}
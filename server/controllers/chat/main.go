@@ -2,6 +2,7 @@ package chat
 
 import (
 	"encoding/json"
+	"sync"
 	"time"
 
 	"github.com/Duelana-Team/duelana-v1/config"
@@ -26,6 +27,8 @@ type Controller struct {
 	rainMinWager int64
 	chatCooldown int
 	isMuted      syncmap.Map
+	ignoredBy    syncmap.Map
+	ignoreMutex  sync.Mutex
 	Index        uint
 	EventEmitter chan types.WSEvent
 }
@@ -38,12 +41,17 @@ func (c *Controller) Init(maxCount int, maxLength uint, wagerLimit int64, chatCo
 	c.isMuted = syncmap.Map{}
 	c.activeUsers = syncmap.Map{}
 	c.rainMinWager = rainMinWager
+	c.loadIgnores()
 }
 
 func (c *Controller) ServeChatContents(conn *websocket.Conn, users []uint, viewerID *uint) {
 	length := len(c.chatContents)
 	contents := []types.ChatContent{}
 	for i := c.currentIndex; i < c.currentIndex+length; i++ {
+		if viewerID != nil &&
+			c.isIgnoring(*viewerID, c.chatContents[i%length].Author.ID) {
+			continue
+		}
 		contents = append(contents, c.chatContents[i%length])
 	}
 	b, _ := json.Marshal(types.WSMessage{
@@ -158,7 +166,7 @@ func (c *Controller) ChatBroadcastMessage(eventType string, chatContent types.Ch
 		EventType: eventType,
 		Payload:   chatContent,
 	})
-	c.EventEmitter <- types.WSEvent{Room: types.Chat, Message: b}
+	c.EventEmitter <- types.WSEvent{Room: types.Chat, Excludes: c.getIgnorers(chatContent.Author.ID), Message: b}
 }
 
 func (c *Controller) ActivateUser(userID uint) {
@@ -293,3 +301,8 @@ func (c *Controller) IsMuted(userID uint) bool {
 	}
 	return false
 }
+
+func (c *Controller) IsActiveUser(userID uint) bool {
+	_, ok := c.activeUsers.Load(userID)
+	return ok
+}
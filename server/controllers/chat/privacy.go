@@ -0,0 +1,338 @@
+package chat
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Duelana-Team/duelana-v1/db"
+	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/types"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm/clause"
+)
+
+/**
+* @Internal
+* Loads ignore lists from db into memory.
+* `ignoredBy` maps each user to ids of users ignoring them.
+ */
+func (c *Controller) loadIgnores() {
+	ignores := []models.ChatIgnore{}
+	if err := db.GetDB().Find(&ignores).Error; err != nil {
+		log.LogMessage(
+			"chat_loadIgnores",
+			"failed to load ignore lists",
+			"error",
+			logrus.Fields{
+				"error": err.Error(),
+			},
+		)
+		return
+	}
+
+	c.ignoreMutex.Lock()
+	defer c.ignoreMutex.Unlock()
+	for _, ignore := range ignores {
+		c.addIgnorer(ignore.IgnoredUserID, ignore.UserID)
+	}
+}
+
+/**
+* @Internal
+* Adds the user to ignorers of the author in memory.
+* Should be called holding `ignoreMutex`.
+ */
+func (c *Controller) addIgnorer(authorID uint, userID uint) {
+	ignorers := c.getIgnorers(authorID)
+	if !slices.Contains(ignorers, userID) {
+		c.ignoredBy.Store(authorID, append(ignorers, userID))
+	}
+}
+
+/**
+* @Internal
+* Removes the user from ignorers of the author in memory.
+* Should be called holding `ignoreMutex`.
+ */
+func (c *Controller) removeIgnorer(authorID uint, userID uint) {
+	ignorers := []uint{}
+	for _, ignorer := range c.getIgnorers(authorID) {
+		if ignorer != userID {
+			ignorers = append(ignorers, ignorer)
+		}
+	}
+	c.ignoredBy.Store(authorID, ignorers)
+}
+
+/**
+* @Internal
+* Returns ids of users ignoring the author.
+ */
+func (c *Controller) getIgnorers(authorID uint) []uint {
+	if ignorers, ok := c.ignoredBy.Load(authorID); ok {
+		return append([]uint{}, ignorers.([]uint)...)
+	}
+	return []uint{}
+}
+
+/**
+* @Internal
+* Checks whether the user is ignoring the author.
+ */
+func (c *Controller) isIgnoring(userID uint, authorID uint) bool {
+	return slices.Contains(c.getIgnorers(authorID), userID)
+}
+
+/**
+* @External
+* Returns users ignored by the user.
+ */
+func (c *Controller) GetIgnoredUsers(userID uint) ([]types.User, error) {
+	ignores := []models.ChatIgnore{}
+	if err := db.GetDB().Preload(
+		"IgnoredUser",
+	).Where(
+		"user_id = ?",
+		userID,
+	).Order(
+		"id",
+	).Find(&ignores).Error; err != nil {
+		return nil, utils.MakeError(
+			"chat_privacy",
+			"GetIgnoredUsers",
+			"failed to retrieve ignores",
+			fmt.Errorf(
+				"userID: %d, err: %v",
+				userID, err,
+			),
+		)
+	}
+
+	users := []types.User{}
+	for _, ignore := range ignores {
+		users = append(
+			users,
+			utils.GetUserDataWithPermissions(
+				ignore.IgnoredUser,
+				nil,
+				0,
+			),
+		)
+	}
+	return users, nil
+}
+
+/**
+* @External
+* Adds the target to the ignore list of the user.
+* Messages of the target are hidden from the user since then.
+ */
+func (c *Controller) IgnoreUser(userID uint, targetID uint) error {
+	if userID == targetID {
+		return utils.MakeErrorWithCode(
+			"chat_privacy",
+			"IgnoreUser",
+			"invalid parameter",
+			ErrCodeInvalidParameter,
+			errors.New("can not ignore yourself"),
+		)
+	}
+
+	var target models.User
+	if err := db.GetDB().First(&target, targetID).Error; err != nil {
+		return utils.MakeError(
+			"chat_privacy",
+			"IgnoreUser",
+			"failed to retrieve target",
+			fmt.Errorf(
+				"targetID: %d, err: %v",
+				targetID, err,
+			),
+		)
+	}
+
+	c.ignoreMutex.Lock()
+	defer c.ignoreMutex.Unlock()
+	if err := db.GetDB().Clauses(
+		clause.OnConflict{DoNothing: true},
+	).Create(&models.ChatIgnore{
+		UserID:        userID,
+		IgnoredUserID: targetID,
+	}).Error; err != nil {
+		return utils.MakeError(
+			"chat_privacy",
+			"IgnoreUser",
+			"failed to create ignore",
+			fmt.Errorf(
+				"userID: %d, targetID: %d, err: %v",
+				userID, targetID, err,
+			),
+		)
+	}
+
+	c.addIgnorer(targetID, userID)
+	return nil
+}
+
+/**
+* @External
+* Removes the target from the ignore list of the user.
+ */
+func (c *Controller) UnignoreUser(userID uint, targetID uint) error {
+	c.ignoreMutex.Lock()
+	defer c.ignoreMutex.Unlock()
+	if err := db.GetDB().Unscoped().Where(
+		"user_id = ?",
+		userID,
+	).Where(
+		"ignored_user_id = ?",
+		targetID,
+	).Delete(&models.ChatIgnore{}).Error; err != nil {
+		return utils.MakeError(
+			"chat_privacy",
+			"UnignoreUser",
+			"failed to delete ignore",
+			fmt.Errorf(
+				"userID: %d, targetID: %d, err: %v",
+				userID, targetID, err,
+			),
+		)
+	}
+
+	c.removeIgnorer(targetID, userID)
+	return nil
+}
+
+/**
+* @External
+* Persists direct message and sends it to both sender and recipient.
+* Returns error when the recipient is ignoring the sender.
+ */
+func (c *Controller) SendDirectMessage(
+	senderID uint,
+	recipientID uint,
+	message string,
+) (*models.DirectMessage, error) {
+	// 1. Validate parameter.
+	message = strings.TrimSpace(message)
+	if senderID == recipientID ||
+		message == "" ||
+		len(message) > int(c.maxLength) {
+		return nil, utils.MakeErrorWithCode(
+			"chat_privacy",
+			"SendDirectMessage",
+			"invalid parameter",
+			ErrCodeInvalidParameter,
+			fmt.Errorf(
+				"senderID: %d, recipientID: %d, length: %d",
+				senderID, recipientID, len(message),
+			),
+		)
+	}
+
+	// 2. Check recipient is not ignoring the sender.
+	if c.isIgnoring(recipientID, senderID) {
+		return nil, utils.MakeErrorWithCode(
+			"chat_privacy",
+			"SendDirectMessage",
+			"recipient is ignoring sender",
+			ErrCodeIgnoredByRecipient,
+			fmt.Errorf(
+				"senderID: %d, recipientID: %d",
+				senderID, recipientID,
+			),
+		)
+	}
+
+	// 3. Check sender and recipient.
+	var sender, recipient models.User
+	if err := db.GetDB().First(&sender, senderID).Error; err != nil {
+		return nil, utils.MakeError(
+			"chat_privacy",
+			"SendDirectMessage",
+			"failed to retrieve sender",
+			err,
+		)
+	}
+	if sender.Banned {
+		return nil, utils.MakeError(
+			"chat_privacy",
+			"SendDirectMessage",
+			"sender is banned",
+			fmt.Errorf("senderID: %d", senderID),
+		)
+	}
+	if err := db.GetDB().First(&recipient, recipientID).Error; err != nil {
+		return nil, utils.MakeError(
+			"chat_privacy",
+			"SendDirectMessage",
+			"failed to retrieve recipient",
+			err,
+		)
+	}
+
+	// 4. Persist message.
+	directMessage := models.DirectMessage{
+		SenderID:    senderID,
+		RecipientID: recipientID,
+		Message:     message,
+	}
+	if err := db.GetDB().Create(&directMessage).Error; err != nil {
+		return nil, utils.MakeError(
+			"chat_privacy",
+			"SendDirectMessage",
+			"failed to create direct message",
+			err,
+		)
+	}
+
+	// 5. Send to both sides.
+	b, _ := json.Marshal(types.WSMessage{
+		Room:      string(types.Chat),
+		EventType: "direct_message",
+		Payload: types.DirectMessagePayload{
+			ID:        directMessage.ID,
+			Sender:    utils.GetUserDataWithPermissions(sender, nil, 0),
+			Recipient: utils.GetUserDataWithPermissions(recipient, nil, 0),
+			Message:   directMessage.Message,
+			Time:      uint64(directMessage.CreatedAt.UnixMilli()),
+		},
+	})
+	c.EventEmitter <- types.WSEvent{Users: []uint{senderID, recipientID}, Message: b}
+
+	return &directMessage, nil
+}
+
+/**
+* @External
+* Returns latest direct messages between two users, latest first.
+ */
+func (c *Controller) GetDirectMessages(
+	userID uint,
+	otherID uint,
+	limit int,
+) ([]models.DirectMessage, error) {
+	messages := []models.DirectMessage{}
+	if err := db.GetDB().Where(
+		"(sender_id = ? and recipient_id = ?) or (sender_id = ? and recipient_id = ?)",
+		userID, otherID, otherID, userID,
+	).Order(
+		"id desc",
+	).Limit(limit).Find(&messages).Error; err != nil {
+		return nil, utils.MakeError(
+			"chat_privacy",
+			"GetDirectMessages",
+			"failed to retrieve direct messages",
+			fmt.Errorf(
+				"userID: %d, otherID: %d, err: %v",
+				userID, otherID, err,
+			),
+		)
+	}
+	return messages, nil
+}
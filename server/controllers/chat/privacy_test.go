@@ -0,0 +1,78 @@
+package chat
+
+import (
+	"testing"
+
+	"github.com/Duelana-Team/duelana-v1/types"
+	"github.com/Duelana-Team/duelana-v1/utils"
+)
+
+func TestIgnorers(t *testing.T) {
+	c := Controller{}
+
+	c.addIgnorer(1, 2)
+	c.addIgnorer(1, 3)
+	c.addIgnorer(1, 2)
+	if ignorers := c.getIgnorers(1); len(ignorers) != 2 ||
+		ignorers[0] != 2 ||
+		ignorers[1] != 3 {
+		t.Fatalf("unexpected ignorers after ignore: %v", ignorers)
+	}
+	if !c.isIgnoring(2, 1) ||
+		!c.isIgnoring(3, 1) ||
+		c.isIgnoring(1, 2) ||
+		c.isIgnoring(4, 1) {
+		t.Fatal("unexpected ignoring after ignore")
+	}
+
+	c.removeIgnorer(1, 2)
+	if ignorers := c.getIgnorers(1); len(ignorers) != 1 ||
+		ignorers[0] != 3 {
+		t.Fatalf("unexpected ignorers after unignore: %v", ignorers)
+	}
+	if c.isIgnoring(2, 1) ||
+		!c.isIgnoring(3, 1) {
+		t.Fatal("unexpected ignoring after unignore")
+	}
+
+	// Returned ignorers should not alias the stored list.
+	ignorers := c.getIgnorers(1)
+	ignorers[0] = 5
+	if !c.isIgnoring(3, 1) {
+		t.Fatal("stored ignorers modified through returned list")
+	}
+
+	if ignorers := c.getIgnorers(6); len(ignorers) != 0 {
+		t.Fatalf("unexpected ignorers of unknown author: %v", ignorers)
+	}
+}
+
+func TestSendDirectMessageToIgnorer(t *testing.T) {
+	c := Controller{
+		maxLength:    200,
+		EventEmitter: make(chan types.WSEvent, 1),
+	}
+	c.addIgnorer(1, 2)
+
+	if _, err := c.SendDirectMessage(1, 2, "hello"); !utils.IsErrorCode(
+		err,
+		ErrCodeIgnoredByRecipient,
+	) {
+		t.Fatalf("should reject sender ignored by recipient: %v", err)
+	}
+	if _, err := c.SendDirectMessage(1, 1, "hello"); !utils.IsErrorCode(
+		err,
+		ErrCodeInvalidParameter,
+	) {
+		t.Fatalf("should reject message to yourself: %v", err)
+	}
+	if _, err := c.SendDirectMessage(1, 2, "  "); !utils.IsErrorCode(
+		err,
+		ErrCodeInvalidParameter,
+	) {
+		t.Fatalf("should reject empty message: %v", err)
+	}
+	if len(c.EventEmitter) != 0 {
+		t.Fatal("should not send rejected message")
+	}
+}
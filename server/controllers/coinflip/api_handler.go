@@ -3,7 +3,6 @@ package coinflip
 import (
 	"net/http"
 
-	"github.com/Duelana-Team/duelana-v1/config"
 	"github.com/Duelana-Team/duelana-v1/db"
 	"github.com/Duelana-Team/duelana-v1/log"
 	"github.com/Duelana-Team/duelana-v1/models"
@@ -14,11 +13,12 @@ import (
 )
 
 func (c *Controller) GetMeta() gin.H {
+	settings := c.settings.Load()
 	return gin.H{
-		"createRoundLimit": config.COINFLIP_ROUND_LIMIT,
-		"minBetAmount":     config.COINFLIP_MIN_AMOUNT,
-		"maxBetAmount":     config.COINFLIP_MAX_AMOUNT,
-		"fee":              config.COINFLIP_FEE,
+		"createRoundLimit": settings.roundLimit,
+		"minBetAmount":     settings.minAmount,
+		"maxBetAmount":     settings.maxAmount,
+		"fee":              settings.fee,
 	}
 }
 
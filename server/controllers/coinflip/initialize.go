@@ -3,6 +3,7 @@ package coinflip
 import (
 	"errors"
 
+	"github.com/Duelana-Team/duelana-v1/controllers/game_settings"
 	"github.com/Duelana-Team/duelana-v1/db"
 	"github.com/Duelana-Team/duelana-v1/log"
 	"github.com/Duelana-Team/duelana-v1/models"
@@ -43,9 +44,9 @@ func (c *Controller) initLastRounds() error {
 				"Coinflip Controller",
 				"failed to get pending rounds",
 				"error",
-				logrus.Fields{
-					"roundId": round.ID,
-				},
+				log.WithRoundID(logrus.Fields{
+					"round": round.ID,
+				}, "coinflip", round.ID),
 			)
 			return errors.New("failed to save creator of a round")
 		}
@@ -55,13 +56,16 @@ func (c *Controller) initLastRounds() error {
 }
 
 func (c *Controller) Init(roundLimit uint, minBetAmount int64, maxBetAmount int64, fee int64) {
-	c.roundLimit = roundLimit
-	c.minAmount = minBetAmount
-	c.maxAmount = maxBetAmount
-	c.fee = fee
+	c.settings.Store(&settingsSnapshot{
+		roundLimit: roundLimit,
+		minAmount:  minBetAmount,
+		maxAmount:  maxBetAmount,
+		fee:        fee,
+	})
 	c.activeRounds = syncmap.Map{}
 	c.round2Creator = syncmap.Map{}
 	c.isRoundPending = syncmap.Map{}
+	c.reloadSettings()
 
 	if err := c.initLastRounds(); err != nil {
 		c.activeRounds.Range(func(key, value any) bool {
@@ -74,3 +78,30 @@ func (c *Controller) Init(roundLimit uint, minBetAmount int64, maxBetAmount int6
 		})
 	}
 }
+
+/**
+* @Internal
+* Applies runtime settings when a new version has been saved,
+* and returns the snapshot to be used for the whole new round.
+* Called before accepting a new round, so running rounds keep
+* the prize calculated on creation.
+ */
+func (c *Controller) reloadSettings() *settingsSnapshot {
+	current := c.settings.Load()
+	settings, version := game_settings.GetCoinflipSettings()
+	if current != nil && version == current.version {
+		return current
+	}
+	next := &settingsSnapshot{
+		roundLimit: settings.RoundLimit,
+		minAmount:  settings.MinBetAmount,
+		maxAmount:  settings.MaxBetAmount,
+		fee:        settings.Fee,
+		version:    version,
+	}
+	if !c.settings.CompareAndSwap(current, next) {
+		// Reloaded by another client meanwhile.
+		return c.settings.Load()
+	}
+	return next
+}
@@ -55,10 +55,11 @@ func (c *Controller) ServeGameData(conn *websocket.Conn) {
 }
 
 func (c *Controller) Create(userID uint, eventParam EventParam) {
-	if !c.validateAmount(userID, eventParam) {
+	settings := c.reloadSettings()
+	if !c.validateAmount(userID, eventParam, settings) {
 		return
 	}
-	if !c.validateCount(userID, eventParam) {
+	if !c.validateCount(userID, eventParam, settings) {
 		return
 	}
 
@@ -189,7 +190,7 @@ func (c *Controller) Create(userID uint, eventParam EventParam) {
 		TailsUserID: tailsUserID,
 		HeadsUserID: headsUserID,
 		Amount:      eventParam.Amount,
-		Prize:       eventParam.Amount * 2 * (100 - c.fee) / 100,
+		Prize:       eventParam.Amount * 2 * (100 - settings.fee) / 100,
 		TicketID:    ticketID,
 	}
 	if result := db.Create(&round); result.Error != nil {
@@ -251,7 +252,7 @@ func (c *Controller) Create(userID uint, eventParam EventParam) {
 			CreatorID: userInfo.ID,
 		}})
 	c.EventEmitter <- types.WSEvent{Room: types.Coinflip, Message: b}
-	log.LogMessage("coinflip controller", "new round created", "success", logrus.Fields{"round": round.ID, "user": userID, "side": eventParam.Side, "amount": eventParam.Amount})
+	log.LogMessage("coinflip controller", "new round created", "success", log.WithRoundID(logrus.Fields{"round": round.ID, "user": userID, "side": eventParam.Side, "amount": eventParam.Amount}, "coinflip", round.ID))
 }
 
 func (c *Controller) Join(userID uint, roundID uint) {
@@ -289,6 +290,8 @@ func (c *Controller) Join(userID uint, roundID uint) {
 		},
 		Type:          models.TxCoinflipBet,
 		ToBeConfirmed: false,
+		OwnerID:       roundID,
+		OwnerType:     models.TransactionCoinflipReferenced,
 	})
 	if err != nil {
 		b, _ := json.Marshal(types.WSMessage{
@@ -424,7 +427,7 @@ func (c *Controller) Join(userID uint, roundID uint) {
 		OwnerType:     models.TransactionCoinflipReferenced,
 	})
 	if err != nil {
-		log.LogMessage("coinflip controller", "failed to transfer profit to winner", "error", logrus.Fields{"round": roundID, "error": err.Error()})
+		log.LogMessage("coinflip controller", "failed to transfer profit to winner", "error", log.WithRoundID(logrus.Fields{"round": roundID, "error": err.Error()}, "coinflip", roundID))
 		return
 	}
 
@@ -441,7 +444,7 @@ func (c *Controller) Join(userID uint, roundID uint) {
 		OwnerType:     models.TransactionCoinflipReferenced,
 	})
 	if err != nil {
-		log.LogMessage("coinflip controller", "failed to transfer house fee", "error", logrus.Fields{"round": roundID, "error": err.Error()})
+		log.LogMessage("coinflip controller", "failed to transfer house fee", "error", log.WithRoundID(logrus.Fields{"round": roundID, "error": err.Error()}, "coinflip", roundID))
 		return
 	}
 
@@ -514,7 +517,7 @@ func (c *Controller) Join(userID uint, roundID uint) {
 			Delay:       5.5,
 		}})
 	c.EventEmitter <- types.WSEvent{Users: []uint{winnerId}, Message: b}
-	log.LogMessage("coinflip controller", "joined", "success", logrus.Fields{"round": round.ID, "user": userID, "winner": map[string]any{"id": winner.ID, "name": winner.Name}})
+	log.LogMessage("coinflip controller", "joined", "success", log.WithRoundID(logrus.Fields{"round": round.ID, "user": userID, "winner": map[string]any{"id": winner.ID, "name": winner.Name}}, "coinflip", round.ID))
 }
 
 func (c *Controller) Cancel(userID uint, roundID uint) {
@@ -583,14 +586,15 @@ func (c *Controller) Cancel(userID uint, roundID uint) {
 			Delay:       0,
 		}})
 	c.EventEmitter <- types.WSEvent{Users: []uint{userID}, Message: b}
-	log.LogMessage("coinflip controller", "cancelled", "success", logrus.Fields{"round": round.ID, "user": userID})
+	log.LogMessage("coinflip controller", "cancelled", "success", log.WithRoundID(logrus.Fields{"round": round.ID, "user": userID}, "coinflip", round.ID))
 }
 
 func (c *Controller) BetAgainstBot(userID uint, eventParam EventParam) {
-	if !c.validateAmount(userID, eventParam) {
+	settings := c.reloadSettings()
+	if !c.validateAmount(userID, eventParam, settings) {
 		return
 	}
-	if !c.validateCount(userID, eventParam) {
+	if !c.validateCount(userID, eventParam, settings) {
 		return
 	}
 
@@ -600,9 +604,9 @@ func (c *Controller) BetAgainstBot(userID uint, eventParam EventParam) {
 		Type:    models.CpTxCoinflipBet,
 	})
 	if result == coupon.CouponBetUnavailable && eventParam.PaidBalanceType == models.ChipBalanceForGame {
-		c.betAgainstBotWithChips(userID, eventParam)
+		c.betAgainstBotWithChips(userID, eventParam, settings)
 	} else if result == coupon.CouponBetSucceed && eventParam.PaidBalanceType == models.CouponBalanceForGame {
-		c.betAgainstBotWithCoupon(userID, eventParam, tx)
+		c.betAgainstBotWithCoupon(userID, eventParam, tx, settings)
 	} else {
 		if tx != 0 {
 			coupon.Decline(tx)
@@ -624,7 +628,7 @@ func (c *Controller) BetAgainstBot(userID uint, eventParam EventParam) {
 	}
 }
 
-func (c *Controller) betAgainstBotWithChips(userID uint, eventParam EventParam) {
+func (c *Controller) betAgainstBotWithChips(userID uint, eventParam EventParam, settings *settingsSnapshot) {
 	tx1, err := transaction.Transfer(&transaction.TransactionRequest{
 		FromUser: (*db_aggregator.User)(&userID),
 		ToUser:   (*db_aggregator.User)(&config.COINFLIP_TEMP_ID),
@@ -753,7 +757,7 @@ func (c *Controller) betAgainstBotWithChips(userID uint, eventParam EventParam)
 		TailsUserID: tailsUserID,
 		HeadsUserID: headsUserID,
 		Amount:      eventParam.Amount,
-		Prize:       eventParam.Amount * 2 * (100 - c.fee) / 100,
+		Prize:       eventParam.Amount * 2 * (100 - settings.fee) / 100,
 		TicketID:    ticketID,
 	}
 
@@ -825,7 +829,7 @@ func (c *Controller) betAgainstBotWithChips(userID uint, eventParam EventParam)
 		OwnerType:     models.TransactionCoinflipReferenced,
 	})
 	if err != nil {
-		log.LogMessage("coinflip controller", "failed to transfer profit to winner", "error", logrus.Fields{"round": round.ID, "error": err.Error()})
+		log.LogMessage("coinflip controller", "failed to transfer profit to winner", "error", log.WithRoundID(logrus.Fields{"round": round.ID, "error": err.Error()}, "coinflip", round.ID))
 		return
 	}
 
@@ -851,7 +855,7 @@ func (c *Controller) betAgainstBotWithChips(userID uint, eventParam EventParam)
 		},
 	})
 	if err != nil {
-		log.LogMessage("coinflip controller", "failed to transfer house fee", "error", logrus.Fields{"round": round.ID, "error": err.Error()})
+		log.LogMessage("coinflip controller", "failed to transfer house fee", "error", log.WithRoundID(logrus.Fields{"round": round.ID, "error": err.Error()}, "coinflip", round.ID))
 		return
 	}
 
@@ -876,10 +880,10 @@ func (c *Controller) betAgainstBotWithChips(userID uint, eventParam EventParam)
 				"coinflip controller",
 				"failed to perform refill transaction",
 				"error",
-				logrus.Fields{
+				log.WithRoundID(logrus.Fields{
 					"round": round.ID,
 					"error": err.Error(),
-				})
+				}, "coinflip", round.ID))
 			return
 		}
 	}
@@ -962,10 +966,10 @@ func (c *Controller) betAgainstBotWithChips(userID uint, eventParam EventParam)
 		c.EventEmitter <- types.WSEvent{Users: []uint{winnerId}, Message: b}
 	}
 
-	log.LogMessage("coinflip controller", "play against bot", "success", logrus.Fields{"round": round.ID, "user": userID, "winner": winnerId})
+	log.LogMessage("coinflip controller", "play against bot", "success", log.WithRoundID(logrus.Fields{"round": round.ID, "user": userID, "winner": winnerId}, "coinflip", round.ID))
 }
 
-func (c *Controller) betAgainstBotWithCoupon(userID uint, eventParam EventParam, txID uint) {
+func (c *Controller) betAgainstBotWithCoupon(userID uint, eventParam EventParam, txID uint, settings *settingsSnapshot) {
 	var tailsUserID, headsUserID *uint
 	var tailsUser, headsUser, userInfo models.User
 
@@ -1024,7 +1028,7 @@ func (c *Controller) betAgainstBotWithCoupon(userID uint, eventParam EventParam,
 		TailsUserID:     tailsUserID,
 		HeadsUserID:     headsUserID,
 		Amount:          eventParam.Amount,
-		Prize:           eventParam.Amount * 2 * (100 - c.fee) / 100,
+		Prize:           eventParam.Amount * 2 * (100 - settings.fee) / 100,
 		TicketID:        ticketID,
 		PaidBalanceType: models.CouponBalanceForGame,
 	}
@@ -1085,7 +1089,7 @@ func (c *Controller) betAgainstBotWithCoupon(userID uint, eventParam EventParam,
 			Type:          models.CpTxCoinflipProfit,
 			ToBeConfirmed: true,
 		}); err != nil {
-			log.LogMessage("coinflip controller", "failed to transfer profit to winner", "error", logrus.Fields{"round": round.ID, "error": err.Error()})
+			log.LogMessage("coinflip controller", "failed to transfer profit to winner", "error", log.WithRoundID(logrus.Fields{"round": round.ID, "error": err.Error()}, "coinflip", round.ID))
 		}
 	}
 
@@ -1140,5 +1144,5 @@ func (c *Controller) betAgainstBotWithCoupon(userID uint, eventParam EventParam,
 		c.EventEmitter <- types.WSEvent{Users: []uint{winnerId}, Message: b}
 	}
 
-	log.LogMessage("coinflip controller", "play against bot", "success", logrus.Fields{"round": round.ID, "user": userID, "winner": winnerId})
+	log.LogMessage("coinflip controller", "play against bot", "success", log.WithRoundID(logrus.Fields{"round": round.ID, "user": userID, "winner": winnerId}, "coinflip", round.ID))
 }
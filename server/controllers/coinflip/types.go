@@ -1,6 +1,8 @@
 package coinflip
 
 import (
+	"sync/atomic"
+
 	"github.com/Duelana-Team/duelana-v1/models"
 	"github.com/Duelana-Team/duelana-v1/types"
 	"golang.org/x/sync/syncmap"
@@ -22,13 +24,21 @@ type EventParam struct {
 	PaidBalanceType models.PaidBalanceForGame `json:"paidBalanceType"`
 }
 
+// Snapshot of runtime settings, replaced as a whole on reload
+// and never modified once stored.
+type settingsSnapshot struct {
+	roundLimit uint
+	minAmount  int64
+	maxAmount  int64
+	fee        int64
+	// Version of runtime settings applied.
+	version uint
+}
+
 type Controller struct {
 	activeRounds   syncmap.Map
 	round2Creator  syncmap.Map
 	isRoundPending syncmap.Map
-	roundLimit     uint
-	minAmount      int64
-	maxAmount      int64
-	fee            int64
+	settings       atomic.Pointer[settingsSnapshot]
 	EventEmitter   chan types.WSEvent
 }
@@ -23,8 +23,8 @@ func (c *Controller) refundPaidBalance(userID uint, amount int64, paidBalanceTyp
 	c.EventEmitter <- types.WSEvent{Users: []uint{userID}, Message: b}
 }
 
-func (c *Controller) validateAmount(userID uint, eventParam EventParam) bool {
-	if eventParam.Amount < c.minAmount || eventParam.Amount > c.maxAmount {
+func (c *Controller) validateAmount(userID uint, eventParam EventParam, settings *settingsSnapshot) bool {
+	if eventParam.Amount < settings.minAmount || eventParam.Amount > settings.maxAmount {
 		b, _ := json.Marshal(types.WSMessage{
 			Room:      string(types.Coinflip),
 			EventType: "message",
@@ -37,7 +37,7 @@ func (c *Controller) validateAmount(userID uint, eventParam EventParam) bool {
 	return true
 }
 
-func (c *Controller) validateCount(userID uint, eventParam EventParam) bool {
+func (c *Controller) validateCount(userID uint, eventParam EventParam, settings *settingsSnapshot) bool {
 	roundCount := uint(0)
 	c.round2Creator.Range(func(key, value any) bool {
 		if value.(uint) == userID {
@@ -45,7 +45,7 @@ func (c *Controller) validateCount(userID uint, eventParam EventParam) bool {
 		}
 		return true
 	})
-	if roundCount == c.roundLimit {
+	if roundCount >= settings.roundLimit {
 		b, _ := json.Marshal(types.WSMessage{
 			Room:      string(types.Coinflip),
 			EventType: "message",
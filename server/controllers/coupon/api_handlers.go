@@ -30,7 +30,7 @@ func RedeemHandler(ctx *gin.Context) {
 		return
 	}
 
-	claimed, err := Claim(userID, params.Code)
+	claimed, err := ClaimFromIP(userID, params.Code, ctx.ClientIP())
 	if err != nil {
 		log.LogMessage(
 			"coupon_api_redeem_handler",
@@ -80,6 +80,14 @@ func RedeemHandler(ctx *gin.Context) {
 				"errorCode": ErrResponseCouponClaimLimitExceed,
 			},
 		)
+	} else if utils.IsErrorCode(err, ErrCodeCouponClaimReachedIPLimit) {
+		ctx.AbortWithStatusJSON(
+			http.StatusNotAcceptable,
+			gin.H{
+				"message":   "Redeem limit exceed for this network",
+				"errorCode": ErrResponseCouponIPLimitExceed,
+			},
+		)
 	} else if utils.IsErrorCode(err, ErrCodeMissingRequiredAffiliate) {
 		ctx.AbortWithStatusJSON(
 			http.StatusNotAcceptable,
@@ -204,3 +212,108 @@ func GetActiveHandler(ctx *gin.Context) {
 		},
 	)
 }
+
+// Get deposit bonus campaigns available to opt in and user's opt-in status.
+func GetDepositBonusesHandler(ctx *gin.Context) {
+	userID := middlewares.GetAuthUserID(ctx, true)
+	if userID == 0 {
+		return
+	}
+
+	bonuses, err := GetDepositBonuses(userID)
+	if err != nil {
+		log.LogMessage(
+			"coupon_api_get_deposit_bonuses_handler",
+			"failed to get deposit bonuses",
+			"error",
+			logrus.Fields{
+				"userID": userID,
+				"error":  err.Error(),
+			},
+		)
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "Failed to get deposit bonuses",
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"depositBonuses": bonuses,
+		},
+	)
+}
+
+// Opts in deposit bonus campaign to be applied on the next deposit.
+func OptInDepositBonusHandler(ctx *gin.Context) {
+	userID := middlewares.GetAuthUserID(ctx, true)
+	if userID == 0 {
+		return
+	}
+
+	var params struct {
+		CampaignID uint `json:"campaignId"`
+	}
+	err := ctx.BindJSON(&params)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"message": "invalid parameter",
+		})
+		return
+	}
+
+	err = OptInDepositBonus(userID, params.CampaignID)
+	if err != nil {
+		log.LogMessage(
+			"coupon_api_opt_in_deposit_bonus_handler",
+			"failed to opt in deposit bonus",
+			"error",
+			logrus.Fields{
+				"userID":     userID,
+				"campaignID": params.CampaignID,
+				"error":      err.Error(),
+			},
+		)
+	}
+	if err == nil {
+		ctx.JSON(http.StatusOK, gin.H{
+			"campaignId": params.CampaignID,
+			"optedIn":    true,
+		})
+	} else if utils.IsErrorCode(err, ErrCodeDepositBonusNotFound) {
+		ctx.AbortWithStatusJSON(
+			http.StatusNotAcceptable,
+			gin.H{
+				"message":   "Deposit bonus not found",
+				"errorCode": ErrResponseDepositBonusNotFound,
+			},
+		)
+	} else if utils.IsErrorCode(err, ErrCodeDepositBonusAlreadyOptedIn) {
+		ctx.AbortWithStatusJSON(
+			http.StatusNotAcceptable,
+			gin.H{
+				"message":   "Already opted in deposit bonus",
+				"errorCode": ErrResponseDepositBonusAlreadyOptedIn,
+			},
+		)
+	} else if utils.IsErrorCode(err, ErrCodeDepositBonusNotAllowed) {
+		ctx.AbortWithStatusJSON(
+			http.StatusNotAcceptable,
+			gin.H{
+				"message":   "Not allowed deposit bonus for referred users",
+				"errorCode": ErrResponseDepositBonusNotAllowed,
+			},
+		)
+	} else {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "Failed to opt in deposit bonus",
+			},
+		)
+	}
+}
@@ -0,0 +1,386 @@
+package coupon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/controllers/transaction/db_aggregator"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"github.com/lib/pq"
+)
+
+/**
+* @External
+* For admin.
+* Creates a coupon campaign and generates `CodeCount` unique single-use
+* codes belonging to it in bulk.
+* Returns created campaign record.
+* Returns error on
+*  - Invalid parameter. `ErrCodeInvalidParameter`
+*  - Campaign name is already used. `ErrCodeCampaignNameDuplicated`
+ */
+func CreateCampaign(request CreateCampaignRequest) (*models.CouponCampaign, error) {
+	// 1. Validate parameter.
+	campaign, err := buildCampaignFromRequest(request)
+	if err != nil {
+		return nil, utils.MakeErrorWithCode(
+			"coupon_campaign",
+			"CreateCampaign",
+			"invalid parameter",
+			ErrCodeInvalidParameter,
+			err,
+		)
+	}
+
+	// 2. Check whether the campaign name is already used.
+	if existing, err := retrieveCampaignByName(
+		campaign.Name,
+	); err != nil {
+		return nil, utils.MakeError(
+			"coupon_campaign",
+			"CreateCampaign",
+			"failed to check campaign name",
+			err,
+		)
+	} else if existing != nil {
+		return nil, utils.MakeErrorWithCode(
+			"coupon_campaign",
+			"CreateCampaign",
+			"campaign name duplicated",
+			ErrCodeCampaignNameDuplicated,
+			fmt.Errorf("name: %s", campaign.Name),
+		)
+	}
+
+	// 3. Create campaign and generate codes in a unit of work.
+	// A retried attempt starts from a fresh copy of the campaign.
+	built := *campaign
+	if err := db_aggregator.WithTx(
+		context.Background(),
+		func(ctx context.Context) error {
+			*campaign = built
+			if err := createCampaignWithCodesUnchecked(
+				campaign,
+				db_aggregator.SessionIdFromContext(ctx),
+			); err != nil {
+				return utils.MakeError(
+					"coupon_campaign",
+					"CreateCampaign",
+					"failed to create campaign with codes",
+					err,
+				)
+			}
+			return nil
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	return campaign, nil
+}
+
+/**
+* @External
+* For admin.
+* Returns all generated codes of the campaign with claimed status.
+ */
+func GetCampaignCodes(campaignID uint) ([]CampaignCode, error) {
+	if campaignID == 0 {
+		return nil, utils.MakeErrorWithCode(
+			"coupon_campaign",
+			"GetCampaignCodes",
+			"invalid parameter",
+			ErrCodeInvalidParameter,
+			errors.New("provided campaign id is zero"),
+		)
+	}
+
+	codes, err := retrieveCampaignCodes(campaignID)
+	if err != nil {
+		return nil, utils.MakeError(
+			"coupon_campaign",
+			"GetCampaignCodes",
+			"failed to retrieve campaign codes",
+			fmt.Errorf(
+				"campaignID: %d, err: %v",
+				campaignID, err,
+			),
+		)
+	}
+
+	return codes, nil
+}
+
+/**
+* @External
+* For admin.
+* Returns redemption statistics of all campaigns, latest first.
+ */
+func GetCampaignStats() ([]CampaignStats, error) {
+	campaigns, err := retrieveAllCampaigns()
+	if err != nil {
+		return nil, utils.MakeError(
+			"coupon_campaign",
+			"GetCampaignStats",
+			"failed to retrieve campaigns",
+			err,
+		)
+	}
+
+	redemptions, err := retrieveCampaignRedemptions()
+	if err != nil {
+		return nil, utils.MakeError(
+			"coupon_campaign",
+			"GetCampaignStats",
+			"failed to retrieve campaign redemptions",
+			err,
+		)
+	}
+
+	stats := []CampaignStats{}
+	for _, campaign := range campaigns {
+		stat := CampaignStats{
+			CampaignID: campaign.ID,
+			Name:       campaign.Name,
+			CodeCount:  campaign.CodeCount,
+			ExpiresAt:  campaign.ExpiresAt,
+		}
+		if redemption, ok := redemptions[campaign.ID]; ok {
+			stat.Redeemed = redemption.Redeemed
+			stat.RedeemedIPs = redemption.RedeemedIPs
+			stat.Exchanged = redemption.Exchanged
+			stat.ClaimedBalance = redemption.ClaimedBalance
+			stat.ExchangedBalance = redemption.ExchangedBalance
+			stat.Wagered = redemption.Wagered
+		}
+		stats = append(stats, stat)
+	}
+
+	return stats, nil
+}
+
+/**
+* @Internal
+* Validates campaign creation request and builds campaign record
+* filling omitted settings with global defaults.
+ */
+func buildCampaignFromRequest(request CreateCampaignRequest) (*models.CouponCampaign, error) {
+	if request.Name == "" {
+		return nil, errors.New("campaign name is empty")
+	}
+	if request.CodeCount == 0 ||
+		request.CodeCount > config.COUPON_CAMPAIGN_MAXIMUM_CODE_COUNT {
+		return nil, fmt.Errorf(
+			"code count should be in 1 ~ %d, provided: %d",
+			config.COUPON_CAMPAIGN_MAXIMUM_CODE_COUNT,
+			request.CodeCount,
+		)
+	}
+	if request.Balance <= 0 {
+		return nil, fmt.Errorf("invalid balance: %d", request.Balance)
+	}
+
+	return buildCampaignRules(
+		models.CouponCampaign{
+			Name:            request.Name,
+			CodeCount:       request.CodeCount,
+			BonusBalance:    request.Balance,
+			ClaimLimitPerIP: request.ClaimLimitPerIP,
+		},
+		request,
+	)
+}
+
+/**
+* @Internal
+* Validates and applies bonus balance rules of the request to the campaign.
+* Rules are shared by code campaigns and deposit bonus campaigns.
+ */
+func buildCampaignRules(
+	campaign models.CouponCampaign,
+	request CreateCampaignRequest,
+) (*models.CouponCampaign, error) {
+	if !request.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("expiry is not in future: %v", request.ExpiresAt)
+	}
+	if request.MaxBetWhileActive < 0 {
+		return nil, fmt.Errorf(
+			"invalid max bet while active: %d",
+			request.MaxBetWhileActive,
+		)
+	}
+
+	campaign.ExpiresAt = request.ExpiresAt
+	campaign.WagerTimes = request.WagerTimes
+	campaign.BalanceLifeTimeInHours = request.BalanceLifeTimeInHours
+	campaign.MaxBetWhileActive = request.MaxBetWhileActive
+	campaign.AllowedGames = pq.StringArray{}
+	if campaign.WagerTimes == 0 {
+		campaign.WagerTimes = uint(config.COUPON_REQUIRED_WAGER_TIMES)
+	}
+	if campaign.BalanceLifeTimeInHours == 0 {
+		campaign.BalanceLifeTimeInHours = uint(config.COUPON_BALANCE_LIFE_TIME_IN_HOURS)
+	}
+
+	games := request.AllowedGames
+	if len(games) == 0 {
+		games = getCouponSupportedGames()
+	}
+	for _, game := range games {
+		if getCouponBetTxType(game) == "" {
+			return nil, fmt.Errorf("game not supported by coupon: %s", game)
+		}
+		campaign.AllowedGames = append(campaign.AllowedGames, string(game))
+	}
+
+	return &campaign, nil
+}
+
+/**
+* @Internal
+* Returns games available to be played with coupon balance.
+ */
+func getCouponSupportedGames() []models.GameType {
+	return []models.GameType{
+		models.Crash,
+		models.Dreamtower,
+		models.Coinflip,
+	}
+}
+
+/**
+* @Internal
+* Returns coupon bet transaction type of the game.
+* Returns empty string for games not supported by coupon.
+ */
+func getCouponBetTxType(game models.GameType) models.CouponTransactionType {
+	switch game {
+	case models.Crash:
+		return models.CpTxCrashBet
+	case models.Dreamtower:
+		return models.CpTxDreamtowerBet
+	case models.Coinflip:
+		return models.CpTxCoinflipBet
+	}
+	return ""
+}
+
+/**
+* @Internal
+* Returns wager times required to exchange the coupon.
+* Campaign coupons use their own multiplier instead of the global one.
+ */
+func getRequiredWagerTimes(coupon *models.Coupon) int64 {
+	if coupon != nil &&
+		coupon.Campaign != nil &&
+		coupon.Campaign.WagerTimes > 0 {
+		return int64(coupon.Campaign.WagerTimes)
+	}
+	return int64(config.COUPON_REQUIRED_WAGER_TIMES)
+}
+
+/**
+* @Internal
+* Returns the time when active claimed coupon expires.
+* Claimed coupons without explicit expiry live for
+* `COUPON_BALANCE_LIFE_TIME_IN_HOURS` after claim.
+ */
+func getActiveCouponExpiresAt(claimedCoupon *models.ClaimedCoupon) time.Time {
+	if claimedCoupon.ExpiresAt != nil {
+		return *claimedCoupon.ExpiresAt
+	}
+	return claimedCoupon.CreatedAt.Add(
+		time.Hour * time.Duration(config.COUPON_BALANCE_LIFE_TIME_IN_HOURS),
+	)
+}
+
+/**
+* @Internal
+* Returns expiry of the balance claimed from coupon now.
+* Returns nil for non-campaign coupons to follow global life time.
+ */
+func getClaimExpiresAt(coupon *models.Coupon) *time.Time {
+	if coupon == nil ||
+		coupon.Campaign == nil ||
+		coupon.Campaign.BalanceLifeTimeInHours == 0 {
+		return nil
+	}
+	expiresAt := time.Now().Add(
+		time.Hour * time.Duration(coupon.Campaign.BalanceLifeTimeInHours),
+	)
+	return &expiresAt
+}
+
+/**
+* @Internal
+* Checks whether the campaign of the coupon is expired.
+ */
+func isCampaignExpired(coupon *models.Coupon) bool {
+	return coupon != nil &&
+		coupon.Campaign != nil &&
+		!coupon.Campaign.ExpiresAt.After(time.Now())
+}
+
+/**
+* @Internal
+* Checks campaign restrictions of the active coupon for a bet transaction.
+* Returns error on
+*  - Game is not allowed by campaign. `ErrCodeCouponNotAllowedForGame`
+*  - Bet amount is exceeding campaign max bet. `ErrCodeExceedingCouponMaxBet`
+ */
+func checkCampaignBetRestriction(
+	coupon *models.Coupon,
+	request CouponTransactionRequest,
+) error {
+	if coupon == nil ||
+		coupon.Campaign == nil ||
+		!isWagerTransaction(request.Type) {
+		return nil
+	}
+
+	if len(coupon.Campaign.AllowedGames) > 0 {
+		isAllowed := false
+		for _, game := range coupon.Campaign.AllowedGames {
+			if getCouponBetTxType(models.GameType(game)) == request.Type {
+				isAllowed = true
+				break
+			}
+		}
+		if !isAllowed {
+			return utils.MakeErrorWithCode(
+				"coupon_campaign",
+				"checkCampaignBetRestriction",
+				"game not allowed by campaign",
+				ErrCodeCouponNotAllowedForGame,
+				fmt.Errorf(
+					"campaignID: %d, allowedGames: %v, txType: %s",
+					coupon.Campaign.ID,
+					coupon.Campaign.AllowedGames,
+					request.Type,
+				),
+			)
+		}
+	}
+
+	if coupon.Campaign.MaxBetWhileActive > 0 &&
+		request.Balance > coupon.Campaign.MaxBetWhileActive {
+		return utils.MakeErrorWithCode(
+			"coupon_campaign",
+			"checkCampaignBetRestriction",
+			"bet amount exceeding campaign max bet",
+			ErrCodeExceedingCouponMaxBet,
+			fmt.Errorf(
+				"campaignID: %d, maxBet: %d, bet: %d",
+				coupon.Campaign.ID,
+				coupon.Campaign.MaxBetWhileActive,
+				request.Balance,
+			),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package coupon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"github.com/lib/pq"
+)
+
+func TestBuildCampaignFromRequest(t *testing.T) {
+	request := CreateCampaignRequest{
+		Name:      "launch",
+		CodeCount: 1000,
+		Balance:   100000,
+		ExpiresAt: time.Now().Add(time.Hour * 24),
+	}
+
+	campaign, err := buildCampaignFromRequest(request)
+	if err != nil {
+		t.Fatalf("failed to build campaign: %v", err)
+	}
+	if campaign.WagerTimes != uint(config.COUPON_REQUIRED_WAGER_TIMES) ||
+		campaign.BalanceLifeTimeInHours != uint(config.COUPON_BALANCE_LIFE_TIME_IN_HOURS) ||
+		len(campaign.AllowedGames) != len(getCouponSupportedGames()) {
+		t.Fatalf("defaults are not applied: %v", *campaign)
+	}
+
+	request.AllowedGames = []models.GameType{models.Crash}
+	request.WagerTimes = 10
+	campaign, err = buildCampaignFromRequest(request)
+	if err != nil {
+		t.Fatalf("failed to build campaign: %v", err)
+	}
+	if campaign.WagerTimes != 10 ||
+		len(campaign.AllowedGames) != 1 ||
+		campaign.AllowedGames[0] != string(models.Crash) {
+		t.Fatalf("custom settings are not applied: %v", *campaign)
+	}
+
+	invalids := []CreateCampaignRequest{
+		{CodeCount: 1, Balance: 1, ExpiresAt: request.ExpiresAt},
+		{Name: "a", Balance: 1, ExpiresAt: request.ExpiresAt},
+		{Name: "a", CodeCount: config.COUPON_CAMPAIGN_MAXIMUM_CODE_COUNT + 1, Balance: 1, ExpiresAt: request.ExpiresAt},
+		{Name: "a", CodeCount: 1, ExpiresAt: request.ExpiresAt},
+		{Name: "a", CodeCount: 1, Balance: 1, ExpiresAt: time.Now().Add(-time.Minute)},
+		{Name: "a", CodeCount: 1, Balance: 1, ExpiresAt: request.ExpiresAt, MaxBetWhileActive: -1},
+		{Name: "a", CodeCount: 1, Balance: 1, ExpiresAt: request.ExpiresAt, AllowedGames: []models.GameType{models.Jackpot}},
+	}
+	for i, invalid := range invalids {
+		if _, err := buildCampaignFromRequest(invalid); err == nil {
+			t.Fatalf("should fail for invalid request #%d: %v", i, invalid)
+		}
+	}
+}
+
+func TestCheckCampaignBetRestriction(t *testing.T) {
+	coupon := models.Coupon{
+		Campaign: &models.CouponCampaign{
+			MaxBetWhileActive: 500000,
+			AllowedGames:      pq.StringArray{string(models.Crash), string(models.Coinflip)},
+		},
+	}
+
+	if err := checkCampaignBetRestriction(
+		&coupon,
+		CouponTransactionRequest{Type: models.CpTxCrashBet, Balance: 500000},
+	); err != nil {
+		t.Fatalf("should allow crash bet within max bet: %v", err)
+	}
+	if err := checkCampaignBetRestriction(
+		&coupon,
+		CouponTransactionRequest{Type: models.CpTxDreamtowerBet, Balance: 100000},
+	); !utils.IsErrorCode(err, ErrCodeCouponNotAllowedForGame) {
+		t.Fatalf("should restrict dreamtower bet: %v", err)
+	}
+	if err := checkCampaignBetRestriction(
+		&coupon,
+		CouponTransactionRequest{Type: models.CpTxCoinflipBet, Balance: 500001},
+	); !utils.IsErrorCode(err, ErrCodeExceedingCouponMaxBet) {
+		t.Fatalf("should restrict bet exceeding max bet: %v", err)
+	}
+	if err := checkCampaignBetRestriction(
+		&coupon,
+		CouponTransactionRequest{Type: models.CpTxDreamtowerProfit, Balance: 1000000},
+	); err != nil {
+		t.Fatalf("should not restrict profit transactions: %v", err)
+	}
+	if err := checkCampaignBetRestriction(
+		&models.Coupon{},
+		CouponTransactionRequest{Type: models.CpTxDreamtowerBet, Balance: 1000000},
+	); err != nil {
+		t.Fatalf("should not restrict non-campaign coupons: %v", err)
+	}
+
+	if getRequiredWagerTimes(&models.Coupon{}) != int64(config.COUPON_REQUIRED_WAGER_TIMES) {
+		t.Fatal("should use global wager times for non-campaign coupons")
+	}
+	coupon.Campaign.WagerTimes = 5
+	if getRequiredWagerTimes(&coupon) != 5 {
+		t.Fatal("should use campaign wager times")
+	}
+
+	claimedAt := time.Now().Add(-time.Hour)
+	claimed := models.ClaimedCoupon{CreatedAt: claimedAt}
+	if !getActiveCouponExpiresAt(&claimed).Equal(
+		claimedAt.Add(time.Hour * time.Duration(config.COUPON_BALANCE_LIFE_TIME_IN_HOURS)),
+	) {
+		t.Fatal("should use global life time without explicit expiry")
+	}
+}
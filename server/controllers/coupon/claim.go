@@ -18,9 +18,24 @@ import (
 //   - Provided code is already claimed by this user. ErrCode: ErrCodeCouponAlreadyClaimed
 //   - Provided code is not allowed by this user to be claimed. ErrCode: ErrCodeCouponNotAllowedToClaim
 //   - Provided code is already reached Claim limit. ErrCode: ErrCodeCouponClaimReachedLimit
-//   - For the expired coupon codes(14 days passed after creation) will be handled by
-//     ErrCodeCouponCodeNotFound error.
+//   - Campaign claim limit per ip is reached. ErrCode: ErrCodeCouponClaimReachedIPLimit
+//   - For the expired coupon codes(14 days passed after creation, or campaign expired)
+//     will be handled by ErrCodeCouponCodeNotFound error.
 func Claim(userID uint, codeLike string) (int64, error) {
+	return claim(userID, codeLike, "")
+}
+
+// @External
+// For client.
+// Claims coupon code same as `Claim`, recording ip address of the client.
+// Claimed ip address is required for campaign codes limiting claims per ip.
+func ClaimFromIP(userID uint, codeLike string, claimedIP string) (int64, error) {
+	return claim(userID, codeLike, claimedIP)
+}
+
+// @Internal
+// Claims coupon code from the ip address, empty if not known.
+func claim(userID uint, codeLike string, claimedIP string) (int64, error) {
 	// 1. Validate parameter.
 	if userID == 0 ||
 		codeLike == "" {
@@ -173,10 +188,54 @@ func Claim(userID uint, codeLike string) (int64, error) {
 		)
 	}
 
-	// 8. Create a new claimed coupon record.
+	// 8. Check for campaign claim limit per ip.
+	if coupon.Campaign != nil &&
+		coupon.Campaign.ClaimLimitPerIP > 0 {
+		if claimedIP == "" {
+			return 0, utils.MakeErrorWithCode(
+				"coupon_claim",
+				"Claim",
+				"missing claimed ip for campaign code",
+				ErrCodeInvalidParameter,
+				fmt.Errorf(
+					"campaignID: %d, userID: %d",
+					coupon.Campaign.ID, userID,
+				),
+			)
+		}
+		claimedFromIP, err := lockCampaignAndCountClaimsFromIP(
+			coupon.Campaign.ID,
+			claimedIP,
+			sessionId,
+		)
+		if err != nil {
+			return 0, utils.MakeError(
+				"coupon_claim",
+				"Claim",
+				"failed to count campaign claims from ip",
+				err,
+			)
+		}
+		if claimedFromIP >= int64(coupon.Campaign.ClaimLimitPerIP) {
+			return 0, utils.MakeErrorWithCode(
+				"coupon_claim",
+				"Claim",
+				"campaign claim reached limit per ip",
+				ErrCodeCouponClaimReachedIPLimit,
+				fmt.Errorf(
+					"campaignID: %d, ip: %s, claimed: %d",
+					coupon.Campaign.ID, claimedIP, claimedFromIP,
+				),
+			)
+		}
+	}
+
+	// 9. Create a new claimed coupon record.
 	newActiveCoupon := models.ClaimedCoupon{
 		CouponID:      code,
 		ClaimedUserID: userID,
+		ClaimedIP:     claimedIP,
+		ExpiresAt:     getClaimExpiresAt(coupon),
 	}
 	if err := createClaimedCouponUnchecked(
 		&newActiveCoupon,
@@ -193,7 +252,7 @@ func Claim(userID uint, codeLike string) (int64, error) {
 		)
 	}
 
-	// 9. Update balance and record transaction.
+	// 10. Update balance and record transaction.
 	if _, err := performTransactionInSession(
 		CouponTransactionRequest{
 			Type:          models.CpTxClaimCode,
@@ -211,7 +270,7 @@ func Claim(userID uint, codeLike string) (int64, error) {
 		)
 	}
 
-	// 10. Commit session.
+	// 11. Commit session.
 	if err := db_aggregator.CommitSession(sessionId); err != nil {
 		return 0, utils.MakeError(
 			"coupon_claim",
@@ -138,3 +138,64 @@ func Create(createRequest CreateCouponRequest) (uuid.UUID, error) {
 
 	return *newCoupon.Code, nil
 }
+
+// @External
+// Creates coupon code only for the user inside the provided session,
+// so that the coupon is issued atomically with what rewards it.
+// Returns newly generated coupon code.
+func CreateForUser(
+	userID uint,
+	balance int64,
+	sessionId db_aggregator.UUID,
+) (uuid.UUID, error) {
+	// 1. Validate parameters
+	if userID == 0 || balance <= 0 {
+		return uuid.Nil, utils.MakeErrorWithCode(
+			"coupon",
+			"CreateForUser",
+			"invalid parameter",
+			ErrCodeInvalidBalanceForCreate,
+			fmt.Errorf(
+				"userID: %d, balance: %d",
+				userID, balance,
+			),
+		)
+	}
+
+	// 2. Retrieve session
+	session, err := db_aggregator.GetSession(sessionId)
+	if err != nil {
+		return uuid.Nil, utils.MakeError(
+			"coupon",
+			"CreateForUser",
+			"failed to retrieve session",
+			err,
+		)
+	}
+
+	// 3. Create a code and return
+	newCoupon := models.Coupon{
+		Type:            models.CouponForSpecUsers,
+		AccessUserIDs:   pq.Int64Array{int64(userID)},
+		AccessUserLimit: 1,
+		BonusBalance:    balance,
+	}
+	if result := session.Create(&newCoupon); result.Error != nil {
+		return uuid.Nil, utils.MakeError(
+			"coupon",
+			"CreateForUser",
+			"failed to create new coupon",
+			result.Error,
+		)
+	}
+	if newCoupon.Code == nil {
+		return uuid.Nil, utils.MakeError(
+			"coupon",
+			"CreateForUser",
+			"default uuid is not assigned",
+			errors.New("coupon code is nil pointer"),
+		)
+	}
+
+	return *newCoupon.Code, nil
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/Duelana-Team/duelana-v1/models"
 	"github.com/Duelana-Team/duelana-v1/utils"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -20,7 +21,8 @@ import (
 // Lock and retrieve unexpired coupon record by code.
 // Should lock and retrieve where
 //   - Code is equal to provided one
-//   - CreatedAt is greater than 14 days before now
+//   - CreatedAt is greater than 14 days before now, or
+//     campaign of the code is not expired
 func lockAndRetrieveCoupon(
 	code uuid.UUID,
 	sessionId db_aggregator.UUID,
@@ -57,11 +59,13 @@ func lockAndRetrieveCoupon(
 		"ClaimedCoupons",
 	).Preload(
 		"RequiredAffiliate.ActiveAffiliates",
+	).Preload(
+		"Campaign",
 	).Where(
 		"code = ?",
 		code,
 	).Where(
-		"created_at > ?",
+		"(campaign_id is not null or created_at > ?)",
 		time.Unix(time.Now().Unix()-int64(time.Hour.Seconds())*24*int64(config.COUPON_CODE_LIFE_TIME_IN_DAYS), 0),
 	).Order(
 		"created_at",
@@ -82,6 +86,20 @@ func lockAndRetrieveCoupon(
 		)
 	}
 
+	// 4. Campaign codes are expiring with their campaign.
+	if isCampaignExpired(&coupon) {
+		return nil, utils.MakeErrorWithCode(
+			"coupon_db",
+			"lockAndRetrieveCoupon",
+			"campaign of the code is expired",
+			ErrCodeCouponCodeNotFound,
+			fmt.Errorf(
+				"code: %v, campaignExpiresAt: %v",
+				code, coupon.Campaign.ExpiresAt,
+			),
+		)
+	}
+
 	return &coupon, nil
 }
 
@@ -89,7 +107,8 @@ func lockAndRetrieveCoupon(
 // Lock and retrieve user's active claimed coupon record.
 // Should lock and retrieve where
 //   - ClaimedUserID is equal to userID
-//   - CreatedAt is greater than 8 hours before than now
+//   - CreatedAt is greater than 8 hours before than now, or
+//     ExpiresAt is set and greater than now
 //   - Exchanged is 0
 func lockAndRetrieveActiveCoupon(
 	userID uint,
@@ -124,13 +143,14 @@ func lockAndRetrieveActiveCoupon(
 			Strength: "UPDATE",
 		},
 	).Preload(
-		"Coupon",
+		"Coupon.Campaign",
 	).Where(
 		"claimed_user_id = ?",
 		userID,
 	).Where(
-		"created_at > ?",
+		"((expires_at is null and created_at > ?) or expires_at > ?)",
 		getActiveCouponLifeTimeBeforeNow(),
+		time.Now(),
 	).Where(
 		"exchanged = 0",
 	).Order(
@@ -715,13 +735,14 @@ func GetActiveUserCoupon(userID uint) *ActiveUserCouponMeta {
 			Strength: "UPDATE",
 		},
 	).Preload(
-		"Coupon",
+		"Coupon.Campaign",
 	).Where(
 		"claimed_user_id = ?",
 		userID,
 	).Where(
-		"created_at > ?",
+		"((expires_at is null and created_at > ?) or expires_at > ?)",
 		getActiveCouponLifeTimeBeforeNow(),
+		time.Now(),
 	).Order(
 		"created_at",
 	).First(&activeCoupon).Error; err != nil {
@@ -742,16 +763,20 @@ func GetActiveUserCoupon(userID uint) *ActiveUserCouponMeta {
 		return nil
 	}
 
-	return &ActiveUserCouponMeta{
-		Code:       *activeCoupon.Coupon.Code,
-		Balance:    activeCoupon.Balance,
-		Claimed:    activeCoupon.Coupon.BonusBalance,
-		Wagered:    activeCoupon.Wagered,
-		WagerLimit: activeCoupon.Coupon.BonusBalance * int64(config.COUPON_REQUIRED_WAGER_TIMES),
-		RemainingTime: time.Until(time.Unix(
-			activeCoupon.CreatedAt.Unix()+int64(time.Hour.Seconds())*int64(config.COUPON_BALANCE_LIFE_TIME_IN_HOURS), 0,
-		)).Milliseconds(),
+	meta := ActiveUserCouponMeta{
+		Code:          *activeCoupon.Coupon.Code,
+		Balance:       activeCoupon.Balance,
+		Claimed:       activeCoupon.Coupon.BonusBalance,
+		Wagered:       activeCoupon.Wagered,
+		WagerLimit:    activeCoupon.Coupon.BonusBalance * getRequiredWagerTimes(&activeCoupon.Coupon),
+		RemainingTime: time.Until(getActiveCouponExpiresAt(&activeCoupon)).Milliseconds(),
 	}
+	if activeCoupon.Coupon.Campaign != nil {
+		meta.MaxBet = activeCoupon.Coupon.Campaign.MaxBetWhileActive
+		meta.AllowedGames = activeCoupon.Coupon.Campaign.AllowedGames
+	}
+
+	return &meta
 }
 
 // @Internal
@@ -1027,3 +1052,828 @@ func existingCrashRoundWithCoupon(
 	}
 	return false
 }
+
+/**
+* @Internal
+* Retrieves campaign record by name.
+* Returns nil without error for not found one.
+ */
+func retrieveCampaignByName(
+	name string,
+) (*models.CouponCampaign, error) {
+	// 1. Retrieve main session.
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"coupon_db",
+			"retrieveCampaignByName",
+			"failed to retrieve session",
+			err,
+		)
+	}
+
+	// 2. Retrieve campaign record.
+	campaign := models.CouponCampaign{}
+	if result := session.Where(
+		"name = ?",
+		name,
+	).First(&campaign); errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if result.Error != nil {
+		return nil, utils.MakeError(
+			"coupon_db",
+			"retrieveCampaignByName",
+			"failed to retrieve campaign record",
+			result.Error,
+		)
+	}
+
+	return &campaign, nil
+}
+
+/**
+* @Internal
+* Creates campaign record and its codes in batches.
+* Each code is single-use and limited to the campaign settings.
+ */
+func createCampaignWithCodesUnchecked(
+	campaign *models.CouponCampaign,
+	sessionId db_aggregator.UUID,
+) error {
+	// 1. Validate parameter.
+	if campaign == nil ||
+		campaign.CodeCount == 0 {
+		return utils.MakeError(
+			"coupon_db",
+			"createCampaignWithCodesUnchecked",
+			"invalid parameter",
+			fmt.Errorf("campaign: %v", campaign),
+		)
+	}
+
+	// 2. Retrieve session.
+	session, err := db_aggregator.GetSession(sessionId)
+	if err != nil {
+		return utils.MakeError(
+			"coupon_db",
+			"createCampaignWithCodesUnchecked",
+			"failed to retrieve session",
+			err,
+		)
+	}
+
+	// 3. Create campaign record.
+	if result := session.Omit(
+		"Coupons",
+	).Create(campaign); result.Error != nil {
+		return utils.MakeError(
+			"coupon_db",
+			"createCampaignWithCodesUnchecked",
+			"failed to create campaign record",
+			result.Error,
+		)
+	}
+
+	// 4. Generate codes in batches.
+	coupons := make([]models.Coupon, campaign.CodeCount)
+	for i := range coupons {
+		coupons[i] = models.Coupon{
+			Type:            models.CouponForLimitUsers,
+			AccessUserLimit: 1,
+			BonusBalance:    campaign.BonusBalance,
+			CampaignID:      &campaign.ID,
+		}
+	}
+	if result := session.CreateInBatches(
+		&coupons,
+		config.COUPON_CAMPAIGN_CODE_BATCH_SIZE,
+	); result.Error != nil ||
+		result.RowsAffected != int64(campaign.CodeCount) {
+		return utils.MakeError(
+			"coupon_db",
+			"createCampaignWithCodesUnchecked",
+			"failed to generate campaign codes",
+			fmt.Errorf(
+				"campaignID: %d, count: %d, rowsAffected: %d, err: %v",
+				campaign.ID, campaign.CodeCount, result.RowsAffected, result.Error,
+			),
+		)
+	}
+
+	return nil
+}
+
+/**
+* @Internal
+* Retrieves codes of the campaign with claimed status.
+ */
+func retrieveCampaignCodes(
+	campaignID uint,
+) ([]CampaignCode, error) {
+	// 1. Retrieve main session.
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"coupon_db",
+			"retrieveCampaignCodes",
+			"failed to retrieve session",
+			err,
+		)
+	}
+
+	// 2. Retrieve codes.
+	codes := []CampaignCode{}
+	if result := session.Model(
+		&models.Coupon{},
+	).Select(
+		"coupons.code as code, exists(select 1 from claimed_coupons where claimed_coupons.coupon_id = coupons.code) as claimed",
+	).Where(
+		"coupons.campaign_id = ?",
+		campaignID,
+	).Order(
+		"coupons.created_at",
+	).Scan(&codes); result.Error != nil {
+		return nil, utils.MakeError(
+			"coupon_db",
+			"retrieveCampaignCodes",
+			"failed to retrieve campaign codes",
+			result.Error,
+		)
+	}
+
+	return codes, nil
+}
+
+/**
+* @Internal
+* Retrieves all campaign records, latest first.
+ */
+func retrieveAllCampaigns() ([]models.CouponCampaign, error) {
+	// 1. Retrieve main session.
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"coupon_db",
+			"retrieveAllCampaigns",
+			"failed to retrieve session",
+			err,
+		)
+	}
+
+	// 2. Retrieve campaigns.
+	campaigns := []models.CouponCampaign{}
+	if result := session.Order(
+		"id desc",
+	).Find(&campaigns); result.Error != nil {
+		return nil, utils.MakeError(
+			"coupon_db",
+			"retrieveAllCampaigns",
+			"failed to retrieve campaigns",
+			result.Error,
+		)
+	}
+
+	return campaigns, nil
+}
+
+/**
+* @Internal
+* Retrieves redemption aggregates of claimed campaign codes
+* mapped by campaign id.
+ */
+func retrieveCampaignRedemptions() (map[uint]CampaignStats, error) {
+	// 1. Retrieve main session.
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"coupon_db",
+			"retrieveCampaignRedemptions",
+			"failed to retrieve session",
+			err,
+		)
+	}
+
+	// 2. Aggregate claimed coupons by campaign.
+	rows := []CampaignStats{}
+	if result := session.Model(
+		&models.ClaimedCoupon{},
+	).Select(
+		"coupons.campaign_id as campaign_id, " +
+			"count(*) as redeemed, " +
+			"count(distinct claimed_coupons.claimed_ip) as redeemed_ips, " +
+			"count(*) filter (where claimed_coupons.exchanged > 0) as exchanged, " +
+			"coalesce(sum(coupons.bonus_balance), 0) as claimed_balance, " +
+			"coalesce(sum(claimed_coupons.exchanged), 0) as exchanged_balance, " +
+			"coalesce(sum(claimed_coupons.wagered), 0) as wagered",
+	).Joins(
+		"join coupons on coupons.code = claimed_coupons.coupon_id",
+	).Where(
+		"coupons.campaign_id is not null",
+	).Group(
+		"coupons.campaign_id",
+	).Scan(&rows); result.Error != nil {
+		return nil, utils.MakeError(
+			"coupon_db",
+			"retrieveCampaignRedemptions",
+			"failed to aggregate campaign redemptions",
+			result.Error,
+		)
+	}
+
+	redemptions := map[uint]CampaignStats{}
+	for _, row := range rows {
+		redemptions[row.CampaignID] = row
+	}
+
+	return redemptions, nil
+}
+
+/**
+* @Internal
+* Locks the campaign and counts its claimed codes from the ip address.
+* Campaign row is locked so that concurrent claims of different codes
+* of the campaign are counted one after another.
+ */
+func lockCampaignAndCountClaimsFromIP(
+	campaignID uint,
+	claimedIP string,
+	sessionId db_aggregator.UUID,
+) (int64, error) {
+	// 1. Retrieve session.
+	session, err := db_aggregator.GetSession(sessionId)
+	if err != nil {
+		return 0, utils.MakeError(
+			"coupon_db",
+			"lockCampaignAndCountClaimsFromIP",
+			"failed to retrieve session",
+			err,
+		)
+	}
+
+	// 2. Lock campaign record.
+	campaign := models.CouponCampaign{}
+	if result := session.Clauses(
+		clause.Locking{
+			Strength: "UPDATE",
+		},
+	).Where(
+		"id = ?",
+		campaignID,
+	).First(&campaign); result.Error != nil {
+		return 0, utils.MakeError(
+			"coupon_db",
+			"lockCampaignAndCountClaimsFromIP",
+			"failed to lock campaign record",
+			fmt.Errorf(
+				"campaignID: %d, err: %v",
+				campaignID, result.Error,
+			),
+		)
+	}
+
+	// 3. Count claimed coupons.
+	var count int64
+	if result := session.Model(
+		&models.ClaimedCoupon{},
+	).Joins(
+		"join coupons on coupons.code = claimed_coupons.coupon_id",
+	).Where(
+		"coupons.campaign_id = ?",
+		campaignID,
+	).Where(
+		"claimed_coupons.claimed_ip = ?",
+		claimedIP,
+	).Count(&count); result.Error != nil {
+		return 0, utils.MakeError(
+			"coupon_db",
+			"lockCampaignAndCountClaimsFromIP",
+			"failed to count claims from ip",
+			result.Error,
+		)
+	}
+
+	return count, nil
+}
+
+/**
+* @Internal
+* Creates deposit bonus campaign record with its coupon campaign
+* holding bonus balance rules.
+ */
+func createDepositBonusCampaignUnchecked(
+	depositCampaign *models.DepositBonusCampaign,
+	couponCampaign *models.CouponCampaign,
+	sessionId db_aggregator.UUID,
+) error {
+	// 1. Validate parameter.
+	if depositCampaign == nil ||
+		couponCampaign == nil {
+		return utils.MakeError(
+			"coupon_db",
+			"createDepositBonusCampaignUnchecked",
+			"invalid parameter",
+			fmt.Errorf(
+				"depositCampaign: %v, couponCampaign: %v",
+				depositCampaign, couponCampaign,
+			),
+		)
+	}
+
+	// 2. Retrieve session.
+	session, err := db_aggregator.GetSession(sessionId)
+	if err != nil {
+		return utils.MakeError(
+			"coupon_db",
+			"createDepositBonusCampaignUnchecked",
+			"failed to retrieve session",
+			err,
+		)
+	}
+
+	// 3. Create coupon campaign record.
+	if result := session.Omit(
+		"Coupons",
+	).Create(couponCampaign); result.Error != nil {
+		return utils.MakeError(
+			"coupon_db",
+			"createDepositBonusCampaignUnchecked",
+			"failed to create coupon campaign record",
+			result.Error,
+		)
+	}
+
+	// 4. Create deposit bonus campaign record.
+	depositCampaign.CouponCampaignID = couponCampaign.ID
+	depositCampaign.CouponCampaign = couponCampaign
+	if result := session.Omit(
+		"CouponCampaign",
+	).Create(depositCampaign); result.Error != nil {
+		return utils.MakeError(
+			"coupon_db",
+			"createDepositBonusCampaignUnchecked",
+			"failed to create deposit bonus campaign record",
+			result.Error,
+		)
+	}
+
+	return nil
+}
+
+/**
+* @Internal
+* Updates enabled status of deposit bonus campaign.
+ */
+func updateDepositBonusCampaignEnabled(
+	campaignID uint,
+	enabled bool,
+) error {
+	// 1. Retrieve main session.
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return utils.MakeError(
+			"coupon_db",
+			"updateDepositBonusCampaignEnabled",
+			"failed to retrieve session",
+			err,
+		)
+	}
+
+	// 2. Update enabled status.
+	if result := session.Model(
+		&models.DepositBonusCampaign{},
+	).Where(
+		"id = ?",
+		campaignID,
+	).Update(
+		"enabled", enabled,
+	); result.Error != nil {
+		return utils.MakeError(
+			"coupon_db",
+			"updateDepositBonusCampaignEnabled",
+			"failed to update campaign",
+			result.Error,
+		)
+	} else if result.RowsAffected != 1 {
+		return utils.MakeErrorWithCode(
+			"coupon_db",
+			"updateDepositBonusCampaignEnabled",
+			"campaign not found",
+			ErrCodeDepositBonusNotFound,
+			fmt.Errorf("campaignID: %d", campaignID),
+		)
+	}
+
+	return nil
+}
+
+/**
+* @Internal
+* Retrieves all deposit bonus campaigns, latest first.
+ */
+func retrieveAllDepositBonusCampaigns() ([]models.DepositBonusCampaign, error) {
+	// 1. Retrieve main session.
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"coupon_db",
+			"retrieveAllDepositBonusCampaigns",
+			"failed to retrieve session",
+			err,
+		)
+	}
+
+	// 2. Retrieve campaigns.
+	campaigns := []models.DepositBonusCampaign{}
+	if result := session.Preload(
+		"CouponCampaign",
+	).Order(
+		"id desc",
+	).Find(&campaigns); result.Error != nil {
+		return nil, utils.MakeError(
+			"coupon_db",
+			"retrieveAllDepositBonusCampaigns",
+			"failed to retrieve campaigns",
+			result.Error,
+		)
+	}
+
+	return campaigns, nil
+}
+
+/**
+* @Internal
+* Retrieves enabled deposit bonus campaigns not ended yet.
+ */
+func retrieveOpenDepositBonusCampaigns() ([]models.DepositBonusCampaign, error) {
+	// 1. Retrieve main session.
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"coupon_db",
+			"retrieveOpenDepositBonusCampaigns",
+			"failed to retrieve session",
+			err,
+		)
+	}
+
+	// 2. Retrieve campaigns.
+	campaigns := []models.DepositBonusCampaign{}
+	if result := session.Preload(
+		"CouponCampaign",
+	).Where(
+		"enabled = ?",
+		true,
+	).Where(
+		"end_at > ?",
+		time.Now(),
+	).Order(
+		"start_at",
+	).Find(&campaigns); result.Error != nil {
+		return nil, utils.MakeError(
+			"coupon_db",
+			"retrieveOpenDepositBonusCampaigns",
+			"failed to retrieve campaigns",
+			result.Error,
+		)
+	}
+
+	return campaigns, nil
+}
+
+/**
+* @Internal
+* Retrieves enabled deposit bonus campaign not ended yet by id.
+* Returns error with `ErrCodeDepositBonusNotFound` for not found one.
+ */
+func retrieveOpenDepositBonusCampaign(
+	campaignID uint,
+) (*models.DepositBonusCampaign, error) {
+	// 1. Retrieve main session.
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"coupon_db",
+			"retrieveOpenDepositBonusCampaign",
+			"failed to retrieve session",
+			err,
+		)
+	}
+
+	// 2. Retrieve campaign.
+	campaign := models.DepositBonusCampaign{}
+	if result := session.Where(
+		"id = ?",
+		campaignID,
+	).Where(
+		"enabled = ?",
+		true,
+	).Where(
+		"end_at > ?",
+		time.Now(),
+	).First(&campaign); errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, utils.MakeErrorWithCode(
+			"coupon_db",
+			"retrieveOpenDepositBonusCampaign",
+			"campaign not found",
+			ErrCodeDepositBonusNotFound,
+			fmt.Errorf("campaignID: %d", campaignID),
+		)
+	} else if result.Error != nil {
+		return nil, utils.MakeError(
+			"coupon_db",
+			"retrieveOpenDepositBonusCampaign",
+			"failed to retrieve campaign",
+			result.Error,
+		)
+	}
+
+	return &campaign, nil
+}
+
+/**
+* @Internal
+* Retrieves user's deposit bonus opt-ins with campaigns, latest first.
+ */
+func retrieveUserDepositBonusOptIns(
+	userID uint,
+) ([]models.DepositBonusOptIn, error) {
+	// 1. Retrieve main session.
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"coupon_db",
+			"retrieveUserDepositBonusOptIns",
+			"failed to retrieve session",
+			err,
+		)
+	}
+
+	// 2. Retrieve opt-ins.
+	optIns := []models.DepositBonusOptIn{}
+	if result := session.Preload(
+		"Campaign.CouponCampaign",
+	).Where(
+		"user_id = ?",
+		userID,
+	).Order(
+		"id desc",
+	).Find(&optIns); result.Error != nil {
+		return nil, utils.MakeError(
+			"coupon_db",
+			"retrieveUserDepositBonusOptIns",
+			"failed to retrieve opt-ins",
+			result.Error,
+		)
+	}
+
+	return optIns, nil
+}
+
+/**
+* @Internal
+* Creates deposit bonus opt-in record.
+* Returns error with `ErrCodeDepositBonusAlreadyOptedIn` for duplicated one.
+ */
+func createDepositBonusOptIn(
+	userID uint,
+	campaignID uint,
+) error {
+	// 1. Retrieve main session.
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return utils.MakeError(
+			"coupon_db",
+			"createDepositBonusOptIn",
+			"failed to retrieve session",
+			err,
+		)
+	}
+
+	// 2. Create opt-in record if not exists.
+	optIn := models.DepositBonusOptIn{
+		CampaignID: campaignID,
+		UserID:     userID,
+	}
+	if result := session.Clauses(
+		clause.OnConflict{DoNothing: true},
+	).Create(&optIn); result.Error != nil {
+		return utils.MakeError(
+			"coupon_db",
+			"createDepositBonusOptIn",
+			"failed to create opt-in record",
+			result.Error,
+		)
+	} else if result.RowsAffected == 0 {
+		return utils.MakeErrorWithCode(
+			"coupon_db",
+			"createDepositBonusOptIn",
+			"already opted in",
+			ErrCodeDepositBonusAlreadyOptedIn,
+			fmt.Errorf(
+				"userID: %d, campaignID: %d",
+				userID, campaignID,
+			),
+		)
+	}
+
+	return nil
+}
+
+/**
+* @Internal
+* Lock and retrieve user's earliest pending opt-in to the campaign
+* running now and accepting the deposit amount.
+* Returns nil without error for not found one.
+ */
+func lockAndRetrievePendingDepositBonusOptIn(
+	userID uint,
+	depositAmount int64,
+	sessionId db_aggregator.UUID,
+) (*models.DepositBonusOptIn, error) {
+	// 1. Retrieve session.
+	session, err := db_aggregator.GetSession(sessionId)
+	if err != nil {
+		return nil, utils.MakeError(
+			"coupon_db",
+			"lockAndRetrievePendingDepositBonusOptIn",
+			"failed to retrieve session",
+			err,
+		)
+	}
+
+	// 2. Lock and retrieve opt-in record.
+	now := time.Now()
+	optIn := models.DepositBonusOptIn{}
+	if result := session.Clauses(
+		clause.Locking{
+			Strength: "UPDATE",
+		},
+	).Preload(
+		"Campaign",
+	).Joins(
+		"join deposit_bonus_campaigns on deposit_bonus_campaigns.id = deposit_bonus_opt_ins.campaign_id",
+	).Where(
+		"deposit_bonus_opt_ins.user_id = ?",
+		userID,
+	).Where(
+		"deposit_bonus_opt_ins.applied_at is null",
+	).Where(
+		"deposit_bonus_campaigns.deleted_at is null",
+	).Where(
+		"deposit_bonus_campaigns.enabled = ?",
+		true,
+	).Where(
+		"deposit_bonus_campaigns.start_at <= ?",
+		now,
+	).Where(
+		"deposit_bonus_campaigns.end_at > ?",
+		now,
+	).Where(
+		"deposit_bonus_campaigns.min_deposit <= ?",
+		depositAmount,
+	).Order(
+		"deposit_bonus_opt_ins.created_at",
+	).First(&optIn); errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if result.Error != nil {
+		return nil, utils.MakeError(
+			"coupon_db",
+			"lockAndRetrievePendingDepositBonusOptIn",
+			"failed to retrieve opt-in record",
+			result.Error,
+		)
+	}
+
+	return &optIn, nil
+}
+
+/**
+* @Internal
+* Creates single-use coupon for the user under the coupon campaign.
+* Returns created coupon code.
+ */
+func createDepositBonusCouponUnchecked(
+	userID uint,
+	bonus int64,
+	couponCampaignID uint,
+	sessionId db_aggregator.UUID,
+) (uuid.UUID, error) {
+	// 1. Validate parameter.
+	if userID == 0 ||
+		bonus <= 0 ||
+		couponCampaignID == 0 {
+		return uuid.Nil, utils.MakeError(
+			"coupon_db",
+			"createDepositBonusCouponUnchecked",
+			"invalid parameter",
+			fmt.Errorf(
+				"userID: %d, bonus: %d, couponCampaignID: %d",
+				userID, bonus, couponCampaignID,
+			),
+		)
+	}
+
+	// 2. Retrieve session.
+	session, err := db_aggregator.GetSession(sessionId)
+	if err != nil {
+		return uuid.Nil, utils.MakeError(
+			"coupon_db",
+			"createDepositBonusCouponUnchecked",
+			"failed to retrieve session",
+			err,
+		)
+	}
+
+	// 3. Create coupon record.
+	newCoupon := models.Coupon{
+		Type:            models.CouponForSpecUsers,
+		AccessUserIDs:   pq.Int64Array{int64(userID)},
+		AccessUserLimit: 1,
+		BonusBalance:    bonus,
+		CampaignID:      &couponCampaignID,
+	}
+	if result := session.Create(&newCoupon); result.Error != nil {
+		return uuid.Nil, utils.MakeError(
+			"coupon_db",
+			"createDepositBonusCouponUnchecked",
+			"failed to create coupon record",
+			result.Error,
+		)
+	}
+	if newCoupon.Code == nil {
+		return uuid.Nil, utils.MakeError(
+			"coupon_db",
+			"createDepositBonusCouponUnchecked",
+			"default uuid is not assigned",
+			errors.New("coupon code is nil pointer"),
+		)
+	}
+
+	return *newCoupon.Code, nil
+}
+
+/**
+* @Internal
+* Marks deposit bonus opt-in as applied with the issued coupon.
+ */
+func updateDepositBonusOptInApplied(
+	optIn *models.DepositBonusOptIn,
+	paymentID uint,
+	deposit int64,
+	bonus int64,
+	code uuid.UUID,
+	sessionId db_aggregator.UUID,
+) error {
+	// 1. Validate parameter.
+	if optIn == nil ||
+		optIn.ID == 0 {
+		return utils.MakeError(
+			"coupon_db",
+			"updateDepositBonusOptInApplied",
+			"invalid parameter",
+			errors.New("provided opt-in is invalid"),
+		)
+	}
+
+	// 2. Retrieve session.
+	session, err := db_aggregator.GetSession(sessionId)
+	if err != nil {
+		return utils.MakeError(
+			"coupon_db",
+			"updateDepositBonusOptInApplied",
+			"failed to retrieve session",
+			err,
+		)
+	}
+
+	// 3. Update opt-in record.
+	now := time.Now()
+	updates := map[string]interface{}{
+		"deposit":       deposit,
+		"bonus_balance": bonus,
+		"coupon_code":   code,
+		"applied_at":    now,
+	}
+	if paymentID != 0 {
+		updates["payment_id"] = paymentID
+	}
+	if result := session.Model(
+		optIn,
+	).Where(
+		"applied_at is null",
+	).Updates(
+		updates,
+	); result.Error != nil || result.RowsAffected != 1 {
+		return utils.MakeError(
+			"coupon_db",
+			"updateDepositBonusOptInApplied",
+			"failed to update opt-in",
+			fmt.Errorf(
+				"optInID: %d, rowsAffected: %d, err: %v",
+				optIn.ID, result.RowsAffected, result.Error,
+			),
+		)
+	}
+
+	return nil
+}
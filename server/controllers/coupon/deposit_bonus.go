@@ -0,0 +1,511 @@
+package coupon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/controllers/transaction/db_aggregator"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"github.com/google/uuid"
+)
+
+/**
+* @External
+* For admin.
+* Creates a deposit match bonus campaign.
+* Bonus rules like wager times and allowed games are kept in a
+* coupon campaign linked to it, so the bonus balance follows the
+* same rules as campaign codes.
+* Returns error on
+*  - Invalid parameter. `ErrCodeInvalidParameter`
+*  - Campaign name is already used. `ErrCodeCampaignNameDuplicated`
+ */
+func CreateDepositBonusCampaign(
+	request CreateDepositBonusCampaignRequest,
+) (*models.DepositBonusCampaign, error) {
+	// 1. Validate parameter.
+	depositCampaign, couponCampaign, err := buildDepositBonusCampaignFromRequest(request)
+	if err != nil {
+		return nil, utils.MakeErrorWithCode(
+			"coupon_deposit_bonus",
+			"CreateDepositBonusCampaign",
+			"invalid parameter",
+			ErrCodeInvalidParameter,
+			err,
+		)
+	}
+
+	// 2. Check whether the campaign name is already used.
+	if existing, err := retrieveCampaignByName(
+		couponCampaign.Name,
+	); err != nil {
+		return nil, utils.MakeError(
+			"coupon_deposit_bonus",
+			"CreateDepositBonusCampaign",
+			"failed to check campaign name",
+			err,
+		)
+	} else if existing != nil {
+		return nil, utils.MakeErrorWithCode(
+			"coupon_deposit_bonus",
+			"CreateDepositBonusCampaign",
+			"campaign name duplicated",
+			ErrCodeCampaignNameDuplicated,
+			fmt.Errorf("name: %s", couponCampaign.Name),
+		)
+	}
+
+	// 3. Create campaign records in a unit of work.
+	// A retried attempt starts from fresh copies of the campaigns.
+	builtDeposit, builtCoupon := *depositCampaign, *couponCampaign
+	if err := db_aggregator.WithTx(
+		context.Background(),
+		func(ctx context.Context) error {
+			*depositCampaign, *couponCampaign = builtDeposit, builtCoupon
+			if err := createDepositBonusCampaignUnchecked(
+				depositCampaign,
+				couponCampaign,
+				db_aggregator.SessionIdFromContext(ctx),
+			); err != nil {
+				return utils.MakeError(
+					"coupon_deposit_bonus",
+					"CreateDepositBonusCampaign",
+					"failed to create deposit bonus campaign",
+					err,
+				)
+			}
+			return nil
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	return depositCampaign, nil
+}
+
+/**
+* @External
+* For admin.
+* Enables or disables the deposit bonus campaign.
+* Disabled campaigns are not available for opt-in and applying.
+ */
+func SetDepositBonusCampaignEnabled(campaignID uint, enabled bool) error {
+	if campaignID == 0 {
+		return utils.MakeErrorWithCode(
+			"coupon_deposit_bonus",
+			"SetDepositBonusCampaignEnabled",
+			"invalid parameter",
+			ErrCodeInvalidParameter,
+			errors.New("provided campaign id is zero"),
+		)
+	}
+
+	if err := updateDepositBonusCampaignEnabled(
+		campaignID,
+		enabled,
+	); err != nil {
+		return utils.MakeError(
+			"coupon_deposit_bonus",
+			"SetDepositBonusCampaignEnabled",
+			"failed to update campaign",
+			err,
+		)
+	}
+
+	return nil
+}
+
+/**
+* @External
+* For admin.
+* Returns all deposit bonus campaigns, latest first.
+ */
+func GetAllDepositBonusCampaigns() ([]models.DepositBonusCampaign, error) {
+	campaigns, err := retrieveAllDepositBonusCampaigns()
+	if err != nil {
+		return nil, utils.MakeError(
+			"coupon_deposit_bonus",
+			"GetAllDepositBonusCampaigns",
+			"failed to retrieve campaigns",
+			err,
+		)
+	}
+
+	return campaigns, nil
+}
+
+/**
+* @External
+* For client.
+* Opts in the user to the deposit bonus campaign.
+* Bonus is applied on the first deposit within the campaign window
+* after opting in.
+* Returns error on
+*  - Campaign is not found, disabled or ended. `ErrCodeDepositBonusNotFound`
+*  - Already opted in. `ErrCodeDepositBonusAlreadyOptedIn`
+*  - User is attributed to an affiliate for excluding campaign. `ErrCodeDepositBonusNotAllowed`
+ */
+func OptInDepositBonus(userID uint, campaignID uint) error {
+	// 1. Validate parameter.
+	if userID == 0 ||
+		campaignID == 0 {
+		return utils.MakeErrorWithCode(
+			"coupon_deposit_bonus",
+			"OptInDepositBonus",
+			"invalid parameter",
+			ErrCodeInvalidParameter,
+			fmt.Errorf(
+				"userID: %d, campaignID: %d",
+				userID, campaignID,
+			),
+		)
+	}
+
+	// 2. Retrieve opt-in available campaign.
+	campaign, err := retrieveOpenDepositBonusCampaign(campaignID)
+	if err != nil {
+		return utils.MakeError(
+			"coupon_deposit_bonus",
+			"OptInDepositBonus",
+			"failed to retrieve campaign",
+			err,
+		)
+	}
+
+	// 3. Check for affiliate exclusion.
+	if excluded, err := isExcludedFromDepositBonus(
+		userID,
+		campaign,
+	); err != nil {
+		return utils.MakeError(
+			"coupon_deposit_bonus",
+			"OptInDepositBonus",
+			"failed to check affiliate exclusion",
+			err,
+		)
+	} else if excluded {
+		return utils.MakeErrorWithCode(
+			"coupon_deposit_bonus",
+			"OptInDepositBonus",
+			"affiliate attributed user excluded",
+			ErrCodeDepositBonusNotAllowed,
+			fmt.Errorf(
+				"userID: %d, campaignID: %d",
+				userID, campaignID,
+			),
+		)
+	}
+
+	// 4. Create opt-in record.
+	if err := createDepositBonusOptIn(
+		userID,
+		campaignID,
+	); err != nil {
+		return utils.MakeError(
+			"coupon_deposit_bonus",
+			"OptInDepositBonus",
+			"failed to create opt-in",
+			err,
+		)
+	}
+
+	return nil
+}
+
+/**
+* @External
+* For client.
+* Returns deposit bonus campaigns open for opt-in, and the ones the user
+* has already opted in, with the user's status.
+ */
+func GetDepositBonuses(userID uint) ([]DepositBonusMeta, error) {
+	campaigns, err := retrieveOpenDepositBonusCampaigns()
+	if err != nil {
+		return nil, utils.MakeError(
+			"coupon_deposit_bonus",
+			"GetDepositBonuses",
+			"failed to retrieve open campaigns",
+			err,
+		)
+	}
+
+	optIns, err := retrieveUserDepositBonusOptIns(userID)
+	if err != nil {
+		return nil, utils.MakeError(
+			"coupon_deposit_bonus",
+			"GetDepositBonuses",
+			"failed to retrieve user opt-ins",
+			err,
+		)
+	}
+
+	metas := []DepositBonusMeta{}
+	listed := map[uint]bool{}
+	for _, optIn := range optIns {
+		if optIn.Campaign == nil {
+			continue
+		}
+		meta := buildDepositBonusMeta(optIn.Campaign)
+		meta.OptedIn = true
+		meta.BonusBalance = optIn.BonusBalance
+		meta.CouponCode = optIn.CouponCode
+		meta.AppliedAt = optIn.AppliedAt
+		metas = append(metas, meta)
+		listed[optIn.CampaignID] = true
+	}
+	for i := range campaigns {
+		if listed[campaigns[i].ID] {
+			continue
+		}
+		metas = append(metas, buildDepositBonusMeta(&campaigns[i]))
+	}
+
+	return metas, nil
+}
+
+/**
+* @External
+* Applies deposit match bonus for the deposit if the user is opted in
+* an active campaign and not applied yet.
+* The bonus is issued as a coupon code for the user and redeemed at once.
+* If redeem fails because of another active coupon, the code is kept
+* in the opt-in record and can be redeemed later by the user.
+* Returns redeemed bonus balance and error object.
+ */
+func TryApplyDepositBonus(
+	userID uint,
+	depositAmount int64,
+	paymentID uint,
+) (int64, error) {
+	// 1. Validate parameter.
+	if userID == 0 ||
+		depositAmount <= 0 {
+		return 0, nil
+	}
+
+	// 2. Issue bonus coupon in a unit of work.
+	code := uuid.Nil
+	if err := db_aggregator.WithTx(
+		context.Background(),
+		func(ctx context.Context) error {
+			sessionId := db_aggregator.SessionIdFromContext(ctx)
+			code = uuid.Nil
+
+			// 2.1. Lock and retrieve pending opt-in for active campaign.
+			optIn, err := lockAndRetrievePendingDepositBonusOptIn(
+				userID,
+				depositAmount,
+				sessionId,
+			)
+			if err != nil {
+				return utils.MakeError(
+					"coupon_deposit_bonus",
+					"TryApplyDepositBonus",
+					"failed to retrieve pending opt-in",
+					err,
+				)
+			}
+			if optIn == nil {
+				return nil
+			}
+
+			// 2.2. Check for affiliate exclusion.
+			if excluded, err := isExcludedFromDepositBonus(
+				userID,
+				optIn.Campaign,
+			); err != nil {
+				return utils.MakeError(
+					"coupon_deposit_bonus",
+					"TryApplyDepositBonus",
+					"failed to check affiliate exclusion",
+					err,
+				)
+			} else if excluded {
+				return nil
+			}
+
+			// 2.3. Issue bonus coupon for the user.
+			bonus := calculateDepositBonus(optIn.Campaign, depositAmount)
+			issued, err := createDepositBonusCouponUnchecked(
+				userID,
+				bonus,
+				optIn.Campaign.CouponCampaignID,
+				sessionId,
+			)
+			if err != nil {
+				return utils.MakeError(
+					"coupon_deposit_bonus",
+					"TryApplyDepositBonus",
+					"failed to create bonus coupon",
+					err,
+				)
+			}
+
+			// 2.4. Mark opt-in as applied.
+			if err := updateDepositBonusOptInApplied(
+				optIn,
+				paymentID,
+				depositAmount,
+				bonus,
+				issued,
+				sessionId,
+			); err != nil {
+				return utils.MakeError(
+					"coupon_deposit_bonus",
+					"TryApplyDepositBonus",
+					"failed to update opt-in",
+					err,
+				)
+			}
+			code = issued
+			return nil
+		},
+	); err != nil {
+		return 0, err
+	}
+	if code == uuid.Nil {
+		return 0, nil
+	}
+
+	// 3. Try to redeem the bonus code.
+	claimed, err := Claim(userID, code.String())
+	if err != nil {
+		return 0, utils.MakeError(
+			"coupon_deposit_bonus",
+			"TryApplyDepositBonus",
+			"failed to redeem bonus code, left for later redeem",
+			fmt.Errorf(
+				"userID: %d, code: %v, err: %v",
+				userID, code, err,
+			),
+		)
+	}
+
+	return claimed, nil
+}
+
+/**
+* @Internal
+* Validates deposit bonus campaign request and builds campaign records.
+ */
+func buildDepositBonusCampaignFromRequest(
+	request CreateDepositBonusCampaignRequest,
+) (*models.DepositBonusCampaign, *models.CouponCampaign, error) {
+	if request.Name == "" {
+		return nil, nil, errors.New("campaign name is empty")
+	}
+	if request.MatchPercent == 0 ||
+		request.MatchPercent > config.COUPON_DEPOSIT_BONUS_MAXIMUM_MATCH_PERCENT {
+		return nil, nil, fmt.Errorf(
+			"match percent should be in 1 ~ %d, provided: %d",
+			config.COUPON_DEPOSIT_BONUS_MAXIMUM_MATCH_PERCENT,
+			request.MatchPercent,
+		)
+	}
+	if request.MaxBonus <= 0 ||
+		request.MinDeposit < 0 {
+		return nil, nil, fmt.Errorf(
+			"invalid bonus range. maxBonus: %d, minDeposit: %d",
+			request.MaxBonus, request.MinDeposit,
+		)
+	}
+	if !request.EndAt.After(request.StartAt) ||
+		!request.EndAt.After(time.Now()) {
+		return nil, nil, fmt.Errorf(
+			"invalid campaign window. startAt: %v, endAt: %v",
+			request.StartAt, request.EndAt,
+		)
+	}
+
+	couponCampaign, err := buildCampaignRules(
+		models.CouponCampaign{
+			Name: request.Name,
+		},
+		CreateCampaignRequest{
+			ExpiresAt: request.EndAt.Add(
+				time.Hour * 24 * time.Duration(config.COUPON_CODE_LIFE_TIME_IN_DAYS),
+			),
+			WagerTimes:             request.WagerTimes,
+			BalanceLifeTimeInHours: request.BalanceLifeTimeInHours,
+			MaxBetWhileActive:      request.MaxBetWhileActive,
+			AllowedGames:           request.AllowedGames,
+		},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &models.DepositBonusCampaign{
+		Name:              request.Name,
+		MatchPercent:      request.MatchPercent,
+		MaxBonus:          request.MaxBonus,
+		MinDeposit:        request.MinDeposit,
+		StartAt:           request.StartAt,
+		EndAt:             request.EndAt,
+		ExcludeAffiliated: request.ExcludeAffiliated,
+		Enabled:           true,
+	}, couponCampaign, nil
+}
+
+/**
+* @Internal
+* Calculates matched bonus for the deposit, capped by campaign max bonus.
+ */
+func calculateDepositBonus(
+	campaign *models.DepositBonusCampaign,
+	depositAmount int64,
+) int64 {
+	bonus := depositAmount * int64(campaign.MatchPercent) / 100
+	if bonus > campaign.MaxBonus {
+		bonus = campaign.MaxBonus
+	}
+	return bonus
+}
+
+/**
+* @Internal
+* Checks whether the user is excluded from the campaign as an
+* affiliate attributed user.
+ */
+func isExcludedFromDepositBonus(
+	userID uint,
+	campaign *models.DepositBonusCampaign,
+) (bool, error) {
+	if campaign == nil ||
+		!campaign.ExcludeAffiliated {
+		return false, nil
+	}
+
+	activeAffiliate, err := db_aggregator.GetActiveAffiliateCode(
+		db_aggregator.User(userID),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return activeAffiliate != nil, nil
+}
+
+/**
+* @Internal
+* Builds client facing deposit bonus meta from campaign record.
+ */
+func buildDepositBonusMeta(campaign *models.DepositBonusCampaign) DepositBonusMeta {
+	meta := DepositBonusMeta{
+		CampaignID:   campaign.ID,
+		Name:         campaign.Name,
+		MatchPercent: campaign.MatchPercent,
+		MaxBonus:     campaign.MaxBonus,
+		MinDeposit:   campaign.MinDeposit,
+		StartAt:      campaign.StartAt,
+		EndAt:        campaign.EndAt,
+	}
+	if campaign.CouponCampaign != nil {
+		meta.WagerTimes = campaign.CouponCampaign.WagerTimes
+	}
+	return meta
+}
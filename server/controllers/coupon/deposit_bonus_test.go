@@ -0,0 +1,71 @@
+package coupon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/models"
+)
+
+func TestBuildDepositBonusCampaignFromRequest(t *testing.T) {
+	request := CreateDepositBonusCampaignRequest{
+		Name:         "half-match",
+		MatchPercent: 50,
+		MaxBonus:     100 * config.ONE_CHIP_WITH_DECIMALS,
+		StartAt:      time.Now(),
+		EndAt:        time.Now().Add(time.Hour * 24 * 7),
+		WagerTimes:   20,
+		AllowedGames: []models.GameType{models.Crash, models.Dreamtower},
+	}
+
+	depositCampaign, couponCampaign, err := buildDepositBonusCampaignFromRequest(request)
+	if err != nil {
+		t.Fatalf("failed to build campaign: %v", err)
+	}
+	if !depositCampaign.Enabled ||
+		depositCampaign.MatchPercent != 50 ||
+		couponCampaign.WagerTimes != 20 ||
+		len(couponCampaign.AllowedGames) != 2 ||
+		!couponCampaign.ExpiresAt.After(request.EndAt) {
+		t.Fatalf(
+			"unexpected campaign: %v, %v",
+			*depositCampaign, *couponCampaign,
+		)
+	}
+
+	invalids := []CreateDepositBonusCampaignRequest{
+		{MatchPercent: 50, MaxBonus: 1, StartAt: request.StartAt, EndAt: request.EndAt},
+		{Name: "a", MaxBonus: 1, StartAt: request.StartAt, EndAt: request.EndAt},
+		{Name: "a", MatchPercent: config.COUPON_DEPOSIT_BONUS_MAXIMUM_MATCH_PERCENT + 1, MaxBonus: 1, StartAt: request.StartAt, EndAt: request.EndAt},
+		{Name: "a", MatchPercent: 50, StartAt: request.StartAt, EndAt: request.EndAt},
+		{Name: "a", MatchPercent: 50, MaxBonus: 1, MinDeposit: -1, StartAt: request.StartAt, EndAt: request.EndAt},
+		{Name: "a", MatchPercent: 50, MaxBonus: 1, StartAt: request.EndAt, EndAt: request.StartAt},
+		{Name: "a", MatchPercent: 50, MaxBonus: 1, StartAt: request.StartAt.Add(-time.Hour * 2), EndAt: request.StartAt.Add(-time.Hour)},
+	}
+	for i, invalid := range invalids {
+		if _, _, err := buildDepositBonusCampaignFromRequest(invalid); err == nil {
+			t.Fatalf("should fail for invalid request #%d: %v", i, invalid)
+		}
+	}
+}
+
+func TestCalculateDepositBonus(t *testing.T) {
+	campaign := models.DepositBonusCampaign{
+		MatchPercent: 50,
+		MaxBonus:     100 * config.ONE_CHIP_WITH_DECIMALS,
+	}
+
+	if bonus := calculateDepositBonus(
+		&campaign,
+		80*config.ONE_CHIP_WITH_DECIMALS,
+	); bonus != 40*config.ONE_CHIP_WITH_DECIMALS {
+		t.Fatalf("should match half of deposit: %d", bonus)
+	}
+	if bonus := calculateDepositBonus(
+		&campaign,
+		500*config.ONE_CHIP_WITH_DECIMALS,
+	); bonus != campaign.MaxBonus {
+		t.Fatalf("should be capped by max bonus: %d", bonus)
+	}
+}
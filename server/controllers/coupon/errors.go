@@ -22,6 +22,13 @@ const ErrCodeCouponShortcutDuplicated = ErrCodeBase + "017"
 const ErrCodeCouponShortcutNotFound = ErrCodeBase + "018"
 const ErrCodeExistingPlayingRounds = ErrCodeBase + "019"
 const ErrCodeMissingRequiredAffiliate = ErrCodeBase + "020"
+const ErrCodeCouponNotAllowedForGame = ErrCodeBase + "021"
+const ErrCodeExceedingCouponMaxBet = ErrCodeBase + "022"
+const ErrCodeCouponClaimReachedIPLimit = ErrCodeBase + "023"
+const ErrCodeCampaignNameDuplicated = ErrCodeBase + "024"
+const ErrCodeDepositBonusNotFound = ErrCodeBase + "025"
+const ErrCodeDepositBonusAlreadyOptedIn = ErrCodeBase + "026"
+const ErrCodeDepositBonusNotAllowed = ErrCodeBase + "027"
 
 // API Response errors
 const ErrResponseCouponAlreadyHasActiveCode = 140001
@@ -33,3 +40,7 @@ const ErrResponseCouponNotReachedWagerLimit = 140006
 const ErrResponseCouponInsufficientAdminBalance = 140007
 const ErrResponseCouponExistingPlayingRounds = 140008
 const ErrResponseCouponMssingRequiredAffiliate = 140009
+const ErrResponseCouponIPLimitExceed = 140010
+const ErrResponseDepositBonusNotFound = 140011
+const ErrResponseDepositBonusAlreadyOptedIn = 140012
+const ErrResponseDepositBonusNotAllowed = 140013
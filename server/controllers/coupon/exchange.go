@@ -94,7 +94,7 @@ func Exchange(userID uint, code uuid.UUID) (int64, error) {
 	}
 
 	// 5. Check whether wager limit is reached requirement.
-	if activeCoupon.Wagered < int64(config.COUPON_REQUIRED_WAGER_TIMES)*activeCoupon.Coupon.BonusBalance {
+	if activeCoupon.Wagered < getRequiredWagerTimes(&activeCoupon.Coupon)*activeCoupon.Coupon.BonusBalance {
 		return 0, utils.MakeErrorWithCode(
 			"coupon_exchange",
 			"Exchange",
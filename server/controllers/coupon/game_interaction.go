@@ -14,7 +14,10 @@ import (
 //   - `CouponBetUnavailable`: No active coupon code, should bet with real chip.
 //   - `CouponBetSucceed`: Bet with coupon balance succeed.
 //   - `CouponBetInsufficientFunds`: Active coupon, but insufficient balance.
-//   - `CouponBetFailed`: There is active coupon, but failed with unknown error.
+//   - `CouponBetFailed`: There is active coupon, but failed with unknown error
+//     or bet amount exceeding campaign max bet.
+//
+// Active coupon of a campaign not allowing the game is treated as unavailable.
 func TryBet(request TryBetWithCouponRequest) (TryBetWithCouponResult, uint, error) {
 	// 1. Validate parameter.
 	if request.UserID == 0 ||
@@ -49,6 +52,16 @@ func TryBet(request TryBetWithCouponRequest) (TryBetWithCouponResult, uint, erro
 				request, err,
 			),
 		)
+	} else if utils.IsErrorCode(err, ErrCodeCouponNotAllowedForGame) {
+		return CouponBetUnavailable, 0, utils.MakeError(
+			"coupon_game_interaction",
+			"TryBet",
+			"game not allowed by active coupon",
+			fmt.Errorf(
+				"request: %v, err: %v",
+				request, err,
+			),
+		)
 	} else if utils.IsErrorCode(err, ErrCodeInsufficientBonusBalance) {
 		return CouponBetInsufficientFunds, 0, utils.MakeError(
 			"coupon_game_interaction",
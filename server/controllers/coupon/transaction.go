@@ -66,7 +66,8 @@ func isSupportedByPerform(transactionType models.CouponTransactionType) bool {
 		transactionType == models.CpTxDreamtowerBet ||
 		transactionType == models.CpTxDreamtowerProfit ||
 		transactionType == models.CpTxCrashBet ||
-		transactionType == models.CpTxCrashProfit
+		transactionType == models.CpTxCrashProfit ||
+		transactionType == models.CpTxCrashRefund
 }
 
 // To Do
@@ -75,6 +76,7 @@ func isSupportedByPerform(transactionType models.CouponTransactionType) bool {
 // Returns recorded coupon transaction id and error object.
 //   - Balance is zero.
 //   - Active coupon is not found.
+//   - Bet is restricted by campaign of the active coupon.
 //   - For adding balance transactions like claim, and profit, we assume that the ToBeConfirmed
 //     is always `true`.
 func performTransactionInSession(
@@ -112,10 +114,23 @@ func performTransactionInSession(
 		)
 	}
 
-	// 4. Save current coupon record.
+	// 4. Check for campaign restrictions on bets.
+	if err := checkCampaignBetRestriction(
+		&activeCoupon.Coupon,
+		transactionRequest,
+	); err != nil {
+		return 0, utils.MakeError(
+			"coupon_transaction",
+			"performTransactionInSession",
+			"bet restricted by campaign",
+			err,
+		)
+	}
+
+	// 5. Save current coupon record.
 	backupBalance := activeCoupon.Balance
 
-	// 5. Perform balance change. Mint or burn.
+	// 6. Perform balance change. Mint or burn.
 	if isAddingBalanceTransaction(transactionRequest.Type) {
 		if err := addBalanceToClaimedCouponUnchecked(
 			transactionRequest.Balance,
@@ -144,7 +159,7 @@ func performTransactionInSession(
 		}
 	}
 
-	// 6. Perform wager amount change.
+	// 7. Perform wager amount change.
 	// ============= Migrated wager amount increase to `Confirm` method =============
 	// if isWagerTransaction(transactionRequest.Type) {
 	// 	if err := addWagerAmountUnchecked(
@@ -161,7 +176,7 @@ func performTransactionInSession(
 	// 	}
 	// }
 
-	// 7. Leave transaction.
+	// 8. Leave transaction.
 	status := models.CouponTransactionSucceed
 	if !transactionRequest.ToBeConfirmed {
 		status = models.CouponTransactionPending
@@ -196,7 +211,8 @@ func isAddingBalanceTransaction(transactionType models.CouponTransactionType) bo
 	return transactionType == models.CpTxClaimCode ||
 		transactionType == models.CpTxCoinflipProfit ||
 		transactionType == models.CpTxDreamtowerProfit ||
-		transactionType == models.CpTxCrashProfit
+		transactionType == models.CpTxCrashProfit ||
+		transactionType == models.CpTxCrashRefund
 }
 
 // @Internal
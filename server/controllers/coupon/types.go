@@ -1,6 +1,8 @@
 package coupon
 
 import (
+	"time"
+
 	"github.com/Duelana-Team/duelana-v1/models"
 	"github.com/google/uuid"
 )
@@ -29,6 +31,8 @@ type ActiveUserCouponMeta struct {
 	Wagered       int64     `json:"wagered"`
 	WagerLimit    int64     `json:"wagerLimit"`
 	RemainingTime int64     `json:"remainingTime"`
+	MaxBet        int64     `json:"maxBet"`
+	AllowedGames  []string  `json:"allowedGames"`
 }
 
 type TryBetWithCouponResult string
@@ -45,3 +49,62 @@ type TryBetWithCouponRequest struct {
 	Balance int64                        `json:"balance"`
 	Type    models.CouponTransactionType `json:"type"`
 }
+
+type CreateCampaignRequest struct {
+	Name                   string            `json:"name"`
+	CodeCount              uint              `json:"codeCount"`
+	Balance                int64             `json:"balance"`
+	ExpiresAt              time.Time         `json:"expiresAt"`
+	WagerTimes             uint              `json:"wagerTimes"`
+	BalanceLifeTimeInHours uint              `json:"balanceLifeTimeInHours"`
+	MaxBetWhileActive      int64             `json:"maxBetWhileActive"`
+	AllowedGames           []models.GameType `json:"allowedGames"`
+	ClaimLimitPerIP        uint              `json:"claimLimitPerIp"`
+}
+
+type CampaignCode struct {
+	Code    uuid.UUID `json:"code"`
+	Claimed bool      `json:"claimed"`
+}
+
+type CampaignStats struct {
+	CampaignID       uint      `json:"campaignId"`
+	Name             string    `json:"name"`
+	CodeCount        uint      `json:"codeCount"`
+	ExpiresAt        time.Time `json:"expiresAt"`
+	Redeemed         int64     `json:"redeemed"`
+	RedeemedIPs      int64     `json:"redeemedIps"`
+	Exchanged        int64     `json:"exchanged"`
+	ClaimedBalance   int64     `json:"claimedBalance"`
+	ExchangedBalance int64     `json:"exchangedBalance"`
+	Wagered          int64     `json:"wagered"`
+}
+
+type CreateDepositBonusCampaignRequest struct {
+	Name                   string            `json:"name"`
+	MatchPercent           uint              `json:"matchPercent"`
+	MaxBonus               int64             `json:"maxBonus"`
+	MinDeposit             int64             `json:"minDeposit"`
+	StartAt                time.Time         `json:"startAt"`
+	EndAt                  time.Time         `json:"endAt"`
+	ExcludeAffiliated      bool              `json:"excludeAffiliated"`
+	WagerTimes             uint              `json:"wagerTimes"`
+	BalanceLifeTimeInHours uint              `json:"balanceLifeTimeInHours"`
+	MaxBetWhileActive      int64             `json:"maxBetWhileActive"`
+	AllowedGames           []models.GameType `json:"allowedGames"`
+}
+
+type DepositBonusMeta struct {
+	CampaignID   uint       `json:"campaignId"`
+	Name         string     `json:"name"`
+	MatchPercent uint       `json:"matchPercent"`
+	MaxBonus     int64      `json:"maxBonus"`
+	MinDeposit   int64      `json:"minDeposit"`
+	StartAt      time.Time  `json:"startAt"`
+	EndAt        time.Time  `json:"endAt"`
+	WagerTimes   uint       `json:"wagerTimes"`
+	OptedIn      bool       `json:"optedIn"`
+	BonusBalance int64      `json:"bonusBalance"`
+	CouponCode   *uuid.UUID `json:"couponCode"`
+	AppliedAt    *time.Time `json:"appliedAt"`
+}
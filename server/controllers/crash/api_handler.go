@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/controllers/game_settings"
 	"github.com/Duelana-Team/duelana-v1/log"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
@@ -16,7 +17,8 @@ func (c *GameController) RoundData(ctx *gin.Context) {
 	}
 
 	if err := ctx.Bind(&params); err != nil {
-		log.LogMessage(
+		log.LogContext(
+			ctx,
 			"crash round data",
 			"invalid param",
 			"error",
@@ -28,7 +30,8 @@ func (c *GameController) RoundData(ctx *gin.Context) {
 
 	round, err := GetRoundHistoryDetail(params.RoundID)
 	if err != nil || round == nil {
-		log.LogMessage(
+		log.LogContext(
+			ctx,
 			"crash round data",
 			"failed to get round history detail",
 			"error",
@@ -44,17 +47,18 @@ func (c *GameController) RoundData(ctx *gin.Context) {
 }
 
 func (c *GameController) GetMeta() gin.H {
+	settings, _ := game_settings.GetCrashSettings()
 	return gin.H{
 		"eventInterval":     config.CRASH_EVENT_INTERVAL_MILLI,
-		"bettingDuration":   (time.Millisecond * time.Duration(config.CRASH_BETTING_DURATION_MILLI)).Seconds(),
-		"pendingDuration":   (time.Millisecond * time.Duration(config.CRASH_PENDING_DURATION_MILLI)).Seconds(),
-		"preparingDuration": (time.Millisecond * time.Duration(config.CRASH_PREPARING_DURATION_MILLI)).Seconds(),
-		"betCountLimit":     config.CRASH_BET_COUNT_LIMIT,
-		"minBetAmount":      config.CRASH_MIN_BET_AMOUNT,
-		"maxBetAmount":      config.CRASH_MAX_BET_AMOUNT,
-		"houseEdge":         config.CRASH_HOUSE_EDGE / 100,
-		"maxPlayerLimit":    config.CRASH_MAX_PLAYER_LIMIT,
-		"minCashOutAt":      config.CRASH_MIN_CASH_OUT_AT,
-		"maxCashOut":        config.CRASH_MAX_CASH_OUT,
+		"bettingDuration":   (time.Millisecond * time.Duration(settings.BettingDurationMilli)).Seconds(),
+		"pendingDuration":   (time.Millisecond * time.Duration(settings.PendingDurationMilli)).Seconds(),
+		"preparingDuration": (time.Millisecond * time.Duration(settings.PreparingDurationMilli)).Seconds(),
+		"betCountLimit":     settings.BetCountLimit,
+		"minBetAmount":      settings.MinBetAmount,
+		"maxBetAmount":      settings.MaxBetAmount,
+		"houseEdge":         settings.HouseEdge / 100,
+		"maxPlayerLimit":    settings.MaxPlayerLimit,
+		"minCashOutAt":      settings.MinCashOutAt,
+		"maxCashOut":        settings.MaxCashOut,
 	}
 }
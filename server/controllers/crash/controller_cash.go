@@ -222,6 +222,8 @@ func (c *GameController) cashIn(params CashInRequestParams) (uint, error) {
 			},
 			Type:          models.TxCrashBet,
 			ToBeConfirmed: false,
+			OwnerID:       params.RoundID,
+			OwnerType:     models.TransactionCrashRoundReferenced,
 		})
 		if err != nil && strings.Contains(err.Error(), "insufficient funds") {
 			return 0, utils.MakeErrorWithCode(
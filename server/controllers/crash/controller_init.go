@@ -4,6 +4,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Duelana-Team/duelana-v1/controllers/game_settings"
 	"github.com/Duelana-Team/duelana-v1/models"
 	"github.com/Duelana-Team/duelana-v1/types"
 	"github.com/Duelana-Team/duelana-v1/utils"
@@ -64,6 +65,8 @@ type GameController struct {
 	cashMut sync.Mutex
 	// Block crash flag.
 	isBlockCrash bool
+	// Closed once the round is finished after draining started.
+	drained chan struct{}
 	// Mutex to block data race of status managing functions.
 	statusMut sync.Mutex
 	// Map to save cashIn events made by user. Cannot exceed `betCountLimit`.
@@ -72,6 +75,8 @@ type GameController struct {
 	cashOutFlagPerBet sync.Map
 	// Max winning chips.
 	maxCashOut int64
+	// Version of runtime settings currently applied.
+	settingsVersion uint
 }
 
 /*
@@ -95,6 +100,10 @@ func (c *GameController) Init(initParams GameControllerInitParams) error {
 	c.minCashOutAt = initParams.MinCashOutAt
 	c.maxCashOut = initParams.MaxCashOut
 
+	// Override static fields with runtime settings if saved.
+	c.settingsVersion = 0
+	c.reloadSettings()
+
 	// Initialize other feilds with default values.
 	c.eventTicker = time.NewTicker(c.eventInterval)
 	c.roundStatus = Preparing
@@ -121,3 +130,27 @@ func (c *GameController) Init(initParams GameControllerInitParams) error {
 
 	return nil
 }
+
+/*
+/* @Internal
+/* Applies runtime settings when a new version has been saved.
+/* Called only while preparing a round, before its outcome is calculated.
+*/
+func (c *GameController) reloadSettings() {
+	settings, version := game_settings.GetCrashSettings()
+	if version == c.settingsVersion {
+		return
+	}
+	c.bettingDuration = time.Millisecond * time.Duration(settings.BettingDurationMilli)
+	c.pendingDuration = time.Millisecond * time.Duration(settings.PendingDurationMilli)
+	c.preparingDuration = time.Millisecond * time.Duration(settings.PreparingDurationMilli)
+	c.betCountLimit = settings.BetCountLimit
+	c.minBetAmount = settings.MinBetAmount
+	c.maxBetAmount = settings.MaxBetAmount
+	c.multiplierIncreaseRate = settings.MultiplierIncreaseRate
+	c.houseEdge = settings.HouseEdge
+	c.maxPlayerLimit = settings.MaxPlayerLimit
+	c.minCashOutAt = settings.MinCashOutAt
+	c.maxCashOut = settings.MaxCashOut
+	c.settingsVersion = version
+}
@@ -2,6 +2,8 @@ package crash
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/Duelana-Team/duelana-v1/config"
 	"github.com/Duelana-Team/duelana-v1/utils"
@@ -11,6 +13,39 @@ func (c *GameController) Pause() {
 	c.isBlockCrash = true
 }
 
+/*
+/* @External
+/* Pauses crash and waits till the current round is finished.
+/* Returns an error if the round is still in progress after `timeout`,
+/* the round is refunded by recovery on next boot then.
+*/
+func (c *GameController) Drain(timeout time.Duration) error {
+	round := c.round
+	if round == nil {
+		c.Pause()
+		return nil
+	}
+
+	drained := make(chan struct{})
+	c.drained = drained
+	c.Pause()
+
+	select {
+	case <-drained:
+		return nil
+	case <-time.After(timeout):
+		return utils.MakeError(
+			"crash_controller_maintain",
+			"Drain",
+			"round is still in progress",
+			fmt.Errorf(
+				"round: %d, status: %v, timeout: %v",
+				round.ID, c.roundStatus, timeout,
+			),
+		)
+	}
+}
+
 func (c *GameController) Start() error {
 	if c.round != nil {
 		return utils.MakeError(
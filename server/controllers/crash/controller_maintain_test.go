@@ -0,0 +1,56 @@
+package crash
+
+import (
+	"testing"
+
+	"github.com/Duelana-Team/duelana-v1/controllers/maintenance"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/types"
+)
+
+func TestCashOutWhileDraining(t *testing.T) {
+	c := GameController{
+		round:          &models.CrashRound{},
+		minCashOutAt:   1.01,
+		minBetAmount:   1,
+		maxBetAmount:   1000,
+		betCountLimit:  1,
+		nextMultiplier: 2,
+		EventEmitter:   make(chan types.WSEvent, 1),
+		cashInEvents:   make(chan CashInEvent, 1),
+		cashOutEvents:  make(chan CashOutEvent, 1),
+	}
+	c.round.ID = 1
+
+	maintenance.StartDraining()
+	c.Pause()
+
+	c.roundStatus = Betting
+	c.CashIn(CashInEvent{
+		UserID:  1,
+		Amount:  100,
+		RoundID: 1,
+	})
+	if len(c.cashInEvents) != 0 {
+		t.Fatalf("cash-in should be refused while draining")
+	}
+	if len(c.EventEmitter) != 1 {
+		t.Fatalf("refused cash-in should be refunded")
+	}
+
+	c.insertEmptyCashInEvent()
+	if len(c.cashInEvents) != 1 {
+		t.Fatalf("empty cash-in should be let through while draining")
+	}
+
+	c.roundStatus = Running
+	c.CashOut(CashOutEvent{
+		UserID:           1,
+		RoundID:          1,
+		BetID:            1,
+		PayoutMultiplier: 1.5,
+	})
+	if len(c.cashOutEvents) != 1 {
+		t.Fatalf("cash-out should be accepted while draining")
+	}
+}
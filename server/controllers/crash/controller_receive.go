@@ -3,6 +3,7 @@ package crash
 import (
 	"runtime/debug"
 
+	"github.com/Duelana-Team/duelana-v1/controllers/maintenance"
 	"github.com/Duelana-Team/duelana-v1/controllers/user"
 	"github.com/Duelana-Team/duelana-v1/log"
 	"github.com/Duelana-Team/duelana-v1/utils"
@@ -10,8 +11,10 @@ import (
 )
 
 // Check current status `crash-status-betting`
+// Refused while bets are not allowed, cash-outs are not.
 func (c *GameController) CashIn(event CashInEvent) {
 	if c.isBettingStatus() &&
+		c.isAbleToCashIn(event) &&
 		c.isValidCashInEvent(event) &&
 		c.checkAndIncreaseCashInCountsPerUser(event.UserID) {
 		c.cashInEvents <- event
@@ -20,21 +23,22 @@ func (c *GameController) CashIn(event CashInEvent) {
 			"crash CashIn",
 			"cash-in blocked",
 			"info",
-			logrus.Fields{
+			c.withRoundID(logrus.Fields{
 				"userID":                              event.UserID,
 				"isBettingStatus":                     c.isBettingStatus(),
+				"isAbleToCashIn":                      c.isAbleToCashIn(event),
 				"isValidCashInEvent":                  c.isValidCashInEvent(event),
 				"checkAndIncreaseCashInCountsPerUser": c.checkAndIncreaseCashInCountsPerUser(event.UserID),
-			},
+			}),
 		)
 		if err := c.emitRefundEvent(event); err != nil {
 			log.LogMessage(
 				"crash CashIn",
 				"an error occured during refund failed cash-in.",
 				"error",
-				logrus.Fields{
+				c.withRoundID(logrus.Fields{
 					"error": err.Error(),
-				},
+				}),
 			)
 		}
 	}
@@ -63,10 +67,10 @@ func (c *GameController) eventListener() {
 				"crash",
 				"event listener recovered",
 				"info",
-				logrus.Fields{
+				c.withRoundID(logrus.Fields{
 					"recover": r,
 					"stack":   string(debug.Stack()),
-				},
+				}),
 			)
 		}
 	}()
@@ -78,10 +82,10 @@ func (c *GameController) eventListener() {
 				"crash_event_listener",
 				"poped cash-in event",
 				"info",
-				logrus.Fields{
+				c.withRoundID(logrus.Fields{
 					"event":   cashInEvent,
 					"isEmpty": c.isEmptyCashIn(cashInEvent),
-				},
+				}),
 			)
 			if !c.isEmptyCashIn(cashInEvent) {
 				c.cashInHandler(cashInEvent)
@@ -91,7 +95,7 @@ func (c *GameController) eventListener() {
 				"crash_event_listener",
 				"poped cash-out event",
 				"info",
-				logrus.Fields{"event": cashOutEvent},
+				c.withRoundID(logrus.Fields{"event": cashOutEvent}),
 			)
 			if !c.isEmptyCashOut(cashOutEvent) {
 				c.cashOutHandler(cashOutEvent)
@@ -100,6 +104,14 @@ func (c *GameController) eventListener() {
 	}
 }
 
+/*
+/* @Internal
+/* Empty cash-ins are let through, the ticker waits for them.
+*/
+func (c *GameController) isAbleToCashIn(event CashInEvent) bool {
+	return c.isEmptyCashIn(event) || maintenance.AbleToBet()
+}
+
 /*
 	@Internal
 
@@ -135,18 +147,18 @@ func (c *GameController) cashInHandler(event CashInEvent) {
 			"crash cashInHandler",
 			"an error occured during cash-in.",
 			"error",
-			logrus.Fields{
+			c.withRoundID(logrus.Fields{
 				"error": err.Error(),
-			},
+			}),
 		)
 		if err := c.emitRefundEvent(event); err != nil {
 			log.LogMessage(
 				"crash cashInHandler",
 				"an error occured during refund failed cash-in.",
 				"error",
-				logrus.Fields{
+				c.withRoundID(logrus.Fields{
 					"error": err.Error(),
-				},
+				}),
 			)
 		}
 		return
@@ -155,9 +167,9 @@ func (c *GameController) cashInHandler(event CashInEvent) {
 			"crash_cashInHandler",
 			"successfully cashed in",
 			"success",
-			logrus.Fields{
+			c.withRoundID(logrus.Fields{
 				"event": event,
-			},
+			}),
 		)
 	}
 
@@ -165,7 +177,7 @@ func (c *GameController) cashInHandler(event CashInEvent) {
 		"crash_cash_in_handler",
 		"cash-in performed",
 		"success",
-		logrus.Fields{"betID": betID},
+		c.withRoundID(logrus.Fields{"betID": betID}),
 	)
 
 	user := user.GetUserInfoByID(event.UserID)
@@ -188,7 +200,7 @@ func (c *GameController) cashInHandler(event CashInEvent) {
 		"crash_cash_in_handler",
 		"insert performed result",
 		"success",
-		logrus.Fields{"betID": betID},
+		c.withRoundID(logrus.Fields{"betID": betID}),
 	)
 }
 
@@ -202,9 +214,9 @@ func (c *GameController) cashOutHandler(event CashOutEvent) {
 			"crash cashOutHandler",
 			"an error occured during cash-out.",
 			"error",
-			logrus.Fields{
+			c.withRoundID(logrus.Fields{
 				"error": err.Error(),
-			},
+			}),
 		)
 		return
 	} else {
@@ -212,9 +224,9 @@ func (c *GameController) cashOutHandler(event CashOutEvent) {
 			"crash_cashOutHandler",
 			"successfully cashed out",
 			"success",
-			logrus.Fields{
+			c.withRoundID(logrus.Fields{
 				"event": event,
-			},
+			}),
 		)
 	}
 
@@ -6,6 +6,7 @@ import (
 
 	"github.com/Duelana-Team/duelana-v1/controllers/wager"
 	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/metrics"
 	"github.com/Duelana-Team/duelana-v1/models"
 	"github.com/Duelana-Team/duelana-v1/utils"
 	"github.com/sirupsen/logrus"
@@ -91,11 +92,11 @@ func (c *GameController) startBetting() error {
 		"crash_controller_status",
 		"started betting",
 		"info",
-		logrus.Fields{
+		c.withRoundID(logrus.Fields{
 			"time":        time.Now(),
 			"round":       c.round,
 			"roundStatus": c.roundStatus,
-		},
+		}),
 	)
 
 	return nil
@@ -166,11 +167,11 @@ func (c *GameController) startPending() error {
 		"crash_controller_status",
 		"started pending",
 		"info",
-		logrus.Fields{
+		c.withRoundID(logrus.Fields{
 			"time":        time.Now(),
 			"round":       c.round,
 			"roundStatus": c.roundStatus,
-		},
+		}),
 	)
 
 	return nil
@@ -255,11 +256,11 @@ func (c *GameController) startRunning() error {
 		"crash_controller_status",
 		"started running",
 		"info",
-		logrus.Fields{
+		c.withRoundID(logrus.Fields{
 			"time":        time.Now(),
 			"round":       c.round,
 			"roundStatus": c.roundStatus,
-		},
+		}),
 	)
 
 	return nil
@@ -331,11 +332,11 @@ func (c *GameController) startPreparing() error {
 		"crash_controller_status",
 		"started preparing",
 		"info",
-		logrus.Fields{
+		c.withRoundID(logrus.Fields{
 			"time":        time.Now(),
 			"round":       c.round,
 			"roundStatus": c.roundStatus,
-		},
+		}),
 	)
 
 	// 4. Transfer house fee to feeWallet.
@@ -347,6 +348,7 @@ func (c *GameController) startPreparing() error {
 /* 1. Save round's `endedAt` as current time.
 /* 2. Load next round to `round`.
 /* 3. Initializes `performedCashOuts` and `performedCashIns`.
+/* 4. Applies runtime settings saved during the last round.
 /*
 /* This function is called if there is not evet in `cashOutEvents` channel and,
 /* current game status is `crash-status-preparing`.
@@ -395,20 +397,20 @@ func (c *GameController) prepareBetting() error {
 			"crash_controller_status",
 			"failed to charge fee",
 			"error",
-			logrus.Fields{
+			c.withRoundID(logrus.Fields{
 				"round": c.round,
 				"error": err.Error(),
-			},
+			}),
 		)
 	} else {
 		log.LogMessage(
 			"crash_controller_status",
 			"successfully charged fee",
 			"success",
-			logrus.Fields{
+			c.withRoundID(logrus.Fields{
 				"round":   c.round,
 				"charged": charged,
-			},
+			}),
 		)
 	}
 
@@ -426,6 +428,10 @@ func (c *GameController) prepareBetting() error {
 	if c.isBlockCrash {
 		c.eventTicker.Stop()
 		c.round = nil
+		if c.drained != nil {
+			close(c.drained)
+			c.drained = nil
+		}
 		return utils.MakeError(
 			"crash status",
 			"prepareBetting",
@@ -434,7 +440,10 @@ func (c *GameController) prepareBetting() error {
 		)
 	}
 
-	// 6. Load next round to `round`.
+	// 6. Apply runtime settings saved during the last round.
+	c.reloadSettings()
+
+	// 7. Load next round to `round`.
 	if err := c.loadNextRound(); err != nil {
 		return utils.MakeError(
 			"crash status",
@@ -448,11 +457,11 @@ func (c *GameController) prepareBetting() error {
 		"crash_controller_status",
 		"preparing betting",
 		"info",
-		logrus.Fields{
+		c.withRoundID(logrus.Fields{
 			"time":        time.Now(),
 			"round":       c.round,
 			"roundStatus": c.roundStatus,
-		},
+		}),
 	)
 
 	return nil
@@ -556,6 +565,11 @@ func (c *GameController) isRunningStatus() bool {
 func (c *GameController) updateRoundStatus(status GameStatus) {
 	c.statusMut.Lock()
 
+	if c.roundStatus != "" && !c.lastStatusUpdated.IsZero() {
+		metrics.CrashPhaseDuration.WithLabelValues(
+			string(c.roundStatus),
+		).Observe(time.Since(c.lastStatusUpdated).Seconds())
+	}
 	c.roundStatus = status
 	switch status {
 	case Betting:
@@ -615,6 +629,7 @@ func (c *GameController) updatePlayerStatistics() {
 		}
 		if bet.Profit != nil && bet.PayoutMultiplier != nil {
 			playerInPerformAfterWager.Profit = *bet.Profit
+			playerInPerformAfterWager.Multiplier = *bet.PayoutMultiplier
 		}
 		params.Players = append(
 			params.Players,
@@ -626,10 +641,21 @@ func (c *GameController) updatePlayerStatistics() {
 			"crash_controller_status_updatePlayerStatistics",
 			"failed to update wager status",
 			"error",
-			logrus.Fields{
+			c.withRoundID(logrus.Fields{
 				"error":  err.Error(),
 				"params": params,
-			},
+			}),
 		)
 	}
 }
+
+/*
+/* @Internal
+/* Attaches current round correlation id to `fields`.
+*/
+func (c *GameController) withRoundID(fields logrus.Fields) logrus.Fields {
+	if c.round == nil {
+		return fields
+	}
+	return log.WithRoundID(fields, "crash", c.round.ID)
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/metrics"
 	"github.com/Duelana-Team/duelana-v1/models"
 	"github.com/sirupsen/logrus"
 )
@@ -12,7 +13,8 @@ import (
 func (c *GameController) runTicker() {
 	for {
 		select {
-		case <-c.eventTicker.C:
+		case tick := <-c.eventTicker.C:
+			metrics.CrashTickLag.Observe(time.Since(tick).Seconds())
 			c.ticker()
 		}
 	}
@@ -25,9 +27,9 @@ func (c *GameController) ticker() {
 			"crash ticker",
 			"betting",
 			"info",
-			logrus.Fields{
+			c.withRoundID(logrus.Fields{
 				"isBettingDurationPassed": c.isBettingDurationPassed(),
-			},
+			}),
 		)
 		// 1. Perform event broadcast.
 		if err := c.emitRealTimeEvent(); err != nil {
@@ -35,9 +37,9 @@ func (c *GameController) ticker() {
 				"crash ticker",
 				"failed to broadcast betting event",
 				"error",
-				logrus.Fields{
+				c.withRoundID(logrus.Fields{
 					"error": err.Error(),
-				},
+				}),
 			)
 			return
 		}
@@ -49,9 +51,9 @@ func (c *GameController) ticker() {
 					"crash ticker",
 					"failed to start pending period",
 					"error",
-					logrus.Fields{
+					c.withRoundID(logrus.Fields{
 						"error": err.Error(),
-					},
+					}),
 				)
 			}
 		}
@@ -60,11 +62,11 @@ func (c *GameController) ticker() {
 			"crash ticker",
 			"pending",
 			"info",
-			logrus.Fields{
+			c.withRoundID(logrus.Fields{
 				"isPerformedCashInsEmpty": c.isPerformedCashInsEmpty(),
 				"isPendingDurationPassed": c.isPendingDurationPassed(),
 				"isCashInEventsEmpty":     c.isCashInEventsEmpty(),
-			},
+			}),
 		)
 		// 1. Perform event broadcast if `cashInEvents` has element.
 		if !c.isPerformedCashInsEmpty() {
@@ -73,9 +75,9 @@ func (c *GameController) ticker() {
 					"crash ticker",
 					"failed to broadcast performed cash-ins",
 					"error",
-					logrus.Fields{
+					c.withRoundID(logrus.Fields{
 						"error": err.Error(),
-					},
+					}),
 				)
 				return
 			}
@@ -87,9 +89,9 @@ func (c *GameController) ticker() {
 					"crash ticker",
 					"failed to start running period",
 					"error",
-					logrus.Fields{
+					c.withRoundID(logrus.Fields{
 						"error": err.Error(),
-					},
+					}),
 				)
 			}
 		}
@@ -98,9 +100,9 @@ func (c *GameController) ticker() {
 			"crash ticker",
 			"running",
 			"info",
-			logrus.Fields{
+			c.withRoundID(logrus.Fields{
 				"nextMultiplier": c.nextMultiplier,
-			},
+			}),
 		)
 		// 1. `currentMultiplier` <- `nextMultiplier`, ++currentStep, Calculate `nextMultiplier`.
 		c.toNextRunningStep()
@@ -117,9 +119,9 @@ func (c *GameController) ticker() {
 					"crash ticker",
 					"failed to broadcast running event.",
 					"error",
-					logrus.Fields{
+					c.withRoundID(logrus.Fields{
 						"error": err.Error(),
-					},
+					}),
 				)
 				return
 			}
@@ -130,9 +132,9 @@ func (c *GameController) ticker() {
 					"crash ticker",
 					"failed to start preparing period.",
 					"error",
-					logrus.Fields{
+					c.withRoundID(logrus.Fields{
 						"error": err.Error(),
-					},
+					}),
 				)
 			}
 		}
@@ -141,13 +143,13 @@ func (c *GameController) ticker() {
 			"crash ticker",
 			"preparing",
 			"info",
-			logrus.Fields{
+			c.withRoundID(logrus.Fields{
 				"isPerformedCashOutsEmpty":     c.isPerformedCashOutsEmpty(),
 				"isCashOutEventsEmpty":         c.isCashOutEventsEmpty(),
 				"isPreparingRound":             c.isPreparingRound(),
 				"isPreparingDurationPassed":    c.isPreparingDurationPassed(),
 				"isRoundInitializedForBetting": c.isRoundInitializedForBetting(),
-			},
+			}),
 		)
 		// 1. Perform event broadcast if `cashOutEvents` has element.
 		if !c.isPerformedCashOutsEmpty() {
@@ -156,9 +158,9 @@ func (c *GameController) ticker() {
 					"crash ticker",
 					"failed to broadcast performed cash-outss",
 					"error",
-					logrus.Fields{
+					c.withRoundID(logrus.Fields{
 						"error": err.Error(),
-					},
+					}),
 				)
 				return
 			}
@@ -172,9 +174,9 @@ func (c *GameController) ticker() {
 					"crash ticker",
 					"failed to prepare betting",
 					"error",
-					logrus.Fields{
+					c.withRoundID(logrus.Fields{
 						"error": err.Error(),
-					},
+					}),
 				)
 			}
 		}
@@ -188,9 +190,9 @@ func (c *GameController) ticker() {
 					"crash ticker",
 					"failed to start betting",
 					"error",
-					logrus.Fields{
+					c.withRoundID(logrus.Fields{
 						"error": err.Error(),
-					},
+					}),
 				)
 			}
 		}
@@ -287,14 +289,14 @@ func (c *GameController) getShouldBePerformedCashOuts() {
 			"getShouldBePerformedCashOuts",
 			"checking",
 			"info",
-			logrus.Fields{
+			c.withRoundID(logrus.Fields{
 				"cashoutAt":         bet.CashOutAt,
 				"profit":            bet.Profit,
 				"payoutMultiplier":  bet.PayoutMultiplier,
 				"shouldCashedOut":   c.isShouldBeCashedOut(bet),
 				"currentMultiplier": c.currentMultiplier,
 				"nextMultiplier":    c.nextMultiplier,
-			},
+			}),
 		)
 		if c.isShouldBeCashedOut(bet) {
 			event := CashOutEvent{
@@ -799,3 +799,140 @@ func GetRoundHistoryDetail(roundID uint) (*RoundHistoryDetail, error) {
 
 	return &result, nil
 }
+
+/*
+/* @Internal
+/* Get rounds whose betting started but never ended.
+/* Only called on boot, before crash starts.
+*/
+func getAbandonedRounds() ([]models.CrashRound, error) {
+	// 1. Retrieve main session.
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"crash_db",
+			"getAbandonedRounds",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	// 2. Get abandoned rounds with unpaid bets.
+	rounds := []models.CrashRound{}
+	if result := session.Preload(
+		"Bets",
+		"profit is null",
+	).Where(
+		"bet_started_at is not null",
+	).Where(
+		"ended_at is null",
+	).Order(
+		"id",
+	).Find(&rounds); result.Error != nil {
+		return nil, utils.MakeError(
+			"crash_db",
+			"getAbandonedRounds",
+			"failed to retrieve abandoned rounds",
+			result.Error,
+		)
+	}
+
+	return rounds, nil
+}
+
+/*
+/* @Internal
+/* Checks whether real chips cash in of the bet is confirmed.
+*/
+func isCashInConfirmed(betID uint) (bool, error) {
+	// 1. Retrieve main session.
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return false, utils.MakeError(
+			"crash_db",
+			"isCashInConfirmed",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	// 2. Count succeed cash in transaction.
+	count := int64(0)
+	if result := session.Model(
+		&models.Transaction{},
+	).Where(
+		"owner_type = ?",
+		models.TransactionCrashBetReferencedForCashIn,
+	).Where(
+		"owner_id = ?",
+		betID,
+	).Where(
+		"status = ?",
+		models.TransactionSucceed,
+	).Count(&count); result.Error != nil {
+		return false, utils.MakeError(
+			"crash_db",
+			"isCashInConfirmed",
+			"failed to count cash in transaction",
+			fmt.Errorf(
+				"betID: %d, err: %v",
+				betID, result.Error,
+			),
+		)
+	}
+
+	return count > 0, nil
+}
+
+/*
+/* @Internal
+/* Closes abandoned round, which can be ended before running.
+*/
+func closeAbandonedRound(
+	crashRound *models.CrashRound,
+	endedAt time.Time,
+) error {
+	// 1. Validate parameter.
+	if crashRound == nil ||
+		crashRound.ID == 0 ||
+		crashRound.BetStartedAt == nil ||
+		crashRound.EndedAt != nil {
+		return utils.MakeErrorWithCode(
+			"crash_db",
+			"closeAbandonedRound",
+			"invalid parameter",
+			ErrCodeInvalidParameter,
+			fmt.Errorf("crashRound: %v", crashRound),
+		)
+	}
+
+	// 2. Retrieve main session.
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return utils.MakeError(
+			"crash_db",
+			"closeAbandonedRound",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	// 3. Update endedAt.
+	if result := session.Model(
+		crashRound,
+	).Update(
+		"ended_at", endedAt,
+	); result.Error != nil {
+		return utils.MakeError(
+			"crash_db",
+			"closeAbandonedRound",
+			"failed to update endedAt",
+			fmt.Errorf(
+				"record: %v, err: %v",
+				crashRound, result.Error,
+			),
+		)
+	}
+
+	return nil
+}
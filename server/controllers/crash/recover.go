@@ -0,0 +1,203 @@
+package crash
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/controllers/coupon"
+	"github.com/Duelana-Team/duelana-v1/controllers/transaction"
+	"github.com/Duelana-Team/duelana-v1/controllers/transaction/db_aggregator"
+	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+/* @External
+/* Refunds unpaid bets of rounds left unfinished by shutdown and closes them.
+/* Should be called on boot, before crash starts.
+/* Returns refunded bet count and closed round count.
+*/
+func RefundAbandonedRounds() (int, int, error) {
+	// 1. Get abandoned rounds.
+	rounds, err := getAbandonedRounds()
+	if err != nil {
+		return 0, 0, utils.MakeError(
+			"crash_recover",
+			"RefundAbandonedRounds",
+			"failed to get abandoned rounds",
+			err,
+		)
+	}
+
+	refunded := 0
+	closed := 0
+	for i := range rounds {
+		round := &rounds[i]
+
+		// 2. Refund unpaid bets.
+		failed := false
+		for j := range round.Bets {
+			ok, err := refundBet(&round.Bets[j])
+			if err != nil {
+				failed = true
+				log.LogMessage(
+					"crash_recover",
+					"failed to refund bet",
+					"error",
+					log.WithRoundID(logrus.Fields{
+						"bet":   round.Bets[j],
+						"error": err.Error(),
+					}, "crash", round.ID),
+				)
+				continue
+			}
+			if ok {
+				refunded++
+			}
+		}
+
+		// 3. Keep the round open to retry failed refunds on next boot.
+		if failed {
+			continue
+		}
+		if err := closeAbandonedRound(round, time.Now()); err != nil {
+			log.LogMessage(
+				"crash_recover",
+				"failed to close abandoned round",
+				"error",
+				log.WithRoundID(logrus.Fields{
+					"error": err.Error(),
+				}, "crash", round.ID),
+			)
+			continue
+		}
+		closed++
+		log.LogMessage(
+			"crash_recover",
+			"closed abandoned round",
+			"success",
+			log.WithRoundID(logrus.Fields{
+				"bets": len(round.Bets),
+			}, "crash", round.ID),
+		)
+	}
+
+	return refunded, closed, nil
+}
+
+/*
+/* @Internal
+/* Pays back bet amount with multiplier 1.
+/* Real chip bets whose cash in was never confirmed are skipped,
+/* the chips did not reach temp wallet.
+*/
+func refundBet(bet *models.CrashBet) (bool, error) {
+	// 1. Check cash in of real chip bet.
+	if bet.PaidBalanceType == models.ChipBalanceForGame {
+		confirmed, err := isCashInConfirmed(bet.ID)
+		if err != nil {
+			return false, err
+		}
+		if !confirmed {
+			return false, nil
+		}
+	}
+
+	// 2. Transfer real chips back from temp.
+	amount := bet.BetAmount
+	txId := (*db_aggregator.Transaction)(nil)
+	if bet.PaidBalanceType == models.ChipBalanceForGame {
+		var err error
+		txId, err = transaction.Transfer(&transaction.TransactionRequest{
+			FromUser: (*db_aggregator.User)(&config.CRASH_TEMP_ID),
+			ToUser:   (*db_aggregator.User)(&bet.UserID),
+			Balance: db_aggregator.BalanceLoad{
+				ChipBalance: &amount,
+			},
+			ToBeConfirmed: false,
+			Type:          models.TxCrashRefund,
+		})
+		if err != nil {
+			return false, utils.MakeError(
+				"crash_recover",
+				"refundBet",
+				"failed to transfer refund",
+				fmt.Errorf(
+					"bet: %v, err: %v",
+					bet, err,
+				),
+			)
+		}
+	}
+
+	// 3. Update bet's payout fields.
+	if err := db_aggregator.WithTx(
+		context.Background(),
+		func(ctx context.Context) error {
+			return updateCrashBetPayoutFields(
+				bet,
+				amount,
+				1,
+				db_aggregator.SessionIdFromContext(ctx),
+			)
+		},
+	); err != nil {
+		var declineError error
+		if txId != nil {
+			declineError = transaction.Decline(transaction.DeclineRequest{
+				Transaction: *txId,
+				OwnerType:   models.TransactionCrashBetReferenced,
+				OwnerID:     bet.ID,
+			})
+		}
+		return false, utils.MakeError(
+			"crash_recover",
+			"refundBet",
+			"failed to update bet payout fields",
+			fmt.Errorf(
+				"bet: %v, err: %v, declineError: %v",
+				bet, err, declineError,
+			),
+		)
+	}
+
+	// 4. Confirm refund.
+	if txId != nil {
+		if err := transaction.Confirm(transaction.ConfirmRequest{
+			Transaction: *txId,
+			OwnerType:   models.TransactionCrashBetReferencedForCashOut,
+			OwnerID:     bet.ID,
+		}); err != nil {
+			return false, utils.MakeError(
+				"crash_recover",
+				"refundBet",
+				"failed to confirm real chip refund",
+				fmt.Errorf(
+					"bet: %v, err: %v",
+					bet, err,
+				),
+			)
+		}
+	} else if _, err := coupon.Perform(coupon.CouponTransactionRequest{
+		Type:          models.CpTxCrashRefund,
+		UserID:        bet.UserID,
+		Balance:       amount,
+		ToBeConfirmed: true,
+	}); err != nil {
+		return false, utils.MakeError(
+			"crash_recover",
+			"refundBet",
+			"failed to refund coupon",
+			fmt.Errorf(
+				"bet: %v, err: %v",
+				bet, err,
+			),
+		)
+	}
+
+	return true, nil
+}
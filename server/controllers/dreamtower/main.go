@@ -289,6 +289,7 @@ func (c *Controller) Bet(ctx *gin.Context) {
 							UserID: userID,
 							Bet:    round.BetAmount,
 							Profit: realProfit - round.BetAmount,
+							Level:  uint(len(round.Bets)),
 						},
 					},
 					Type: models.Dreamtower,
@@ -423,6 +424,7 @@ func (c *Controller) Raise(ctx *gin.Context) {
 					{
 						UserID: userID,
 						Bet:    playingRound.BetAmount,
+						Level:  uint(len(playingRound.Bets) - 1),
 					},
 				},
 				Type: models.Dreamtower,
@@ -492,6 +494,7 @@ func (c *Controller) Raise(ctx *gin.Context) {
 							UserID: userID,
 							Bet:    playingRound.BetAmount,
 							Profit: realProfit - playingRound.BetAmount,
+							Level:  uint(len(playingRound.Bets)),
 						},
 					},
 					Type: models.Dreamtower,
@@ -604,6 +607,7 @@ func (c *Controller) Cashout(ctx *gin.Context) {
 					UserID: userID,
 					Bet:    playingRound.BetAmount,
 					Profit: realProfit - playingRound.BetAmount,
+					Level:  uint(len(playingRound.Bets)),
 				},
 			},
 			Type: models.Dreamtower,
@@ -0,0 +1,284 @@
+package financial_report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"github.com/sirupsen/logrus"
+)
+
+/**
+* @Internal
+* Truncates time to the start of its UTC day.
+ */
+func startOfDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+/**
+* @Internal
+* Builds GGR rows of a day from summed transaction amounts.
+* `userBets` and `userReceipts` only contain real users' amounts.
+ */
+func buildGameRevenues(
+	day time.Time,
+	userBets TxAmounts,
+	userReceipts TxAmounts,
+	fees TxAmounts,
+) []models.DailyGameRevenue {
+	revenues := []models.DailyGameRevenue{}
+	for game, txTypes := range getGameTxTypes() {
+		wager := userBets[txTypes.Bet]
+		if txTypes.Refund != "" {
+			wager -= userReceipts[txTypes.Refund]
+		}
+		payout := userReceipts[txTypes.Profit]
+		revenues = append(revenues, models.DailyGameRevenue{
+			Day:    day,
+			Game:   game,
+			Wager:  wager,
+			Payout: payout,
+			Fee:    fees[txTypes.Fee],
+			GGR:    wager - payout,
+		})
+	}
+	sort.Slice(revenues, func(i, j int) bool {
+		return revenues[i].Game < revenues[j].Game
+	})
+	return revenues
+}
+
+/**
+* @Internal
+* Builds deduction rows of a day from summed transaction amounts.
+ */
+func buildDeductions(
+	day time.Time,
+	amounts TxAmounts,
+) []models.DailyRevenueDeduction {
+	deductions := []models.DailyRevenueDeduction{}
+	for txType, category := range getDeductionTxTypes() {
+		deductions = append(deductions, models.DailyRevenueDeduction{
+			Day:      day,
+			Category: category,
+			Amount:   amounts[txType],
+		})
+	}
+	sort.Slice(deductions, func(i, j int) bool {
+		return deductions[i].Category < deductions[j].Category
+	})
+	return deductions
+}
+
+/**
+* @Internal
+* Builds payment volume rows of a day from payment summaries.
+* Payment types other than `deposit_<token>` and `withdraw_<token>` are skipped.
+ */
+func buildPaymentVolumes(
+	day time.Time,
+	summaries []PaymentSummary,
+) []models.DailyPaymentVolume {
+	volumes := []models.DailyPaymentVolume{}
+	for _, summary := range summaries {
+		direction, token, found := strings.Cut(summary.Type, "_")
+		if !found || token == "" ||
+			(direction != string(models.PaymentDeposit) &&
+				direction != string(models.PaymentWithdraw)) {
+			continue
+		}
+		volumes = append(volumes, models.DailyPaymentVolume{
+			Day:         day,
+			Direction:   models.PaymentDirection(direction),
+			Token:       token,
+			Count:       summary.Count,
+			TokenAmount: summary.TokenAmount,
+			UsdAmount:   summary.UsdAmount,
+		})
+	}
+	return volumes
+}
+
+/**
+* @Internal
+* Builds NGR of each day from materialized GGR and deductions.
+ */
+func buildNetRevenues(
+	revenues []models.DailyGameRevenue,
+	deductions []models.DailyRevenueDeduction,
+) []DailyNetRevenue {
+	days := map[time.Time]*DailyNetRevenue{}
+	getDay := func(day time.Time) *DailyNetRevenue {
+		day = startOfDay(day)
+		if _, ok := days[day]; !ok {
+			days[day] = &DailyNetRevenue{Day: day}
+		}
+		return days[day]
+	}
+	for _, revenue := range revenues {
+		getDay(revenue.Day).GGR += revenue.GGR
+	}
+	for _, deduction := range deductions {
+		getDay(deduction.Day).Deductions += deduction.Amount
+	}
+
+	result := []DailyNetRevenue{}
+	for _, net := range days {
+		net.NGR = net.GGR - net.Deductions
+		result = append(result, *net)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Day.Before(result[j].Day)
+	})
+	return result
+}
+
+/**
+* @Internal
+* Aggregates transactions and payments of a UTC day and
+* replaces its materialized rows.
+ */
+func materializeDay(day time.Time) error {
+	// 1. Sum chip amounts of the day.
+	day = startOfDay(day)
+	from, to := day, day.Add(24*time.Hour)
+
+	betTypes, receiptTypes, feeTypes := []models.TransactionType{}, []models.TransactionType{}, []models.TransactionType{}
+	for _, txTypes := range getGameTxTypes() {
+		betTypes = append(betTypes, txTypes.Bet)
+		receiptTypes = append(receiptTypes, txTypes.Profit)
+		if txTypes.Refund != "" {
+			receiptTypes = append(receiptTypes, txTypes.Refund)
+		}
+		feeTypes = append(feeTypes, txTypes.Fee)
+	}
+	deductionTypes := []models.TransactionType{}
+	for txType := range getDeductionTxTypes() {
+		deductionTypes = append(deductionTypes, txType)
+	}
+
+	userBets, err := sumTxAmounts(betTypes, from, to, txSideFrom)
+	if err != nil {
+		return utils.MakeError(
+			"financial_report",
+			"materializeDay",
+			"failed to sum user bets",
+			err,
+		)
+	}
+	userReceipts, err := sumTxAmounts(receiptTypes, from, to, txSideTo)
+	if err != nil {
+		return utils.MakeError(
+			"financial_report",
+			"materializeDay",
+			"failed to sum user receipts",
+			err,
+		)
+	}
+	fees, err := sumTxAmounts(feeTypes, from, to, txSideAny)
+	if err != nil {
+		return utils.MakeError(
+			"financial_report",
+			"materializeDay",
+			"failed to sum fees",
+			err,
+		)
+	}
+	deductionAmounts, err := sumTxAmounts(deductionTypes, from, to, txSideAny)
+	if err != nil {
+		return utils.MakeError(
+			"financial_report",
+			"materializeDay",
+			"failed to sum deductions",
+			err,
+		)
+	}
+
+	// 2. Summarize payments of the day.
+	summaries, err := summarizePayments(from, to)
+	if err != nil {
+		return utils.MakeError(
+			"financial_report",
+			"materializeDay",
+			"failed to summarize payments",
+			err,
+		)
+	}
+
+	// 3. Replace materialized rows of the day.
+	if err := replaceDailyAggregates(
+		day,
+		buildGameRevenues(day, userBets, userReceipts, fees),
+		buildDeductions(day, deductionAmounts),
+		buildPaymentVolumes(day, summaries),
+	); err != nil {
+		return utils.MakeError(
+			"financial_report",
+			"materializeDay",
+			"failed to replace daily aggregates",
+			fmt.Errorf("day: %v, err: %v", day, err),
+		)
+	}
+	return nil
+}
+
+/**
+* @Internal
+* Materializes days in the range one by one.
+ */
+func materializeRange(from time.Time, to time.Time) error {
+	for day := startOfDay(from); !day.After(to); day = day.Add(24 * time.Hour) {
+		if err := materializeDay(day); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/**
+* @Internal
+* Materializes finished days not aggregated yet, up to
+* `FINANCIAL_REPORT_BACKFILL_DAYS` days back.
+ */
+func materializePendingDays() error {
+	yesterday := startOfDay(time.Now()).Add(-24 * time.Hour)
+	from := yesterday.Add(
+		-time.Duration(config.FINANCIAL_REPORT_BACKFILL_DAYS-1) * 24 * time.Hour,
+	)
+
+	last, err := getLastMaterializedDay()
+	if err != nil {
+		return utils.MakeError(
+			"financial_report",
+			"materializePendingDays",
+			"failed to retrieve last materialized day",
+			err,
+		)
+	}
+	if last != nil && !startOfDay(*last).Before(from) {
+		from = startOfDay(*last).Add(24 * time.Hour)
+	}
+	if from.After(yesterday) {
+		return nil
+	}
+
+	if err := materializeRange(from, yesterday); err != nil {
+		return err
+	}
+	log.LogMessage(
+		"financial_report",
+		"materialized daily aggregates",
+		"success",
+		logrus.Fields{
+			"from": from,
+			"to":   yesterday,
+		},
+	)
+	return nil
+}
@@ -0,0 +1,252 @@
+package financial_report
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/middlewares"
+	"github.com/gin-gonic/gin"
+)
+
+/**
+* Amounts are in balance decimals for json, and in chips for csv.
+* Pass `format=csv` to download the report as csv.
+ */
+func GetGameRevenueHandler(ctx *gin.Context) {
+	reportRange, err := parseReportRange(ctx)
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	revenues, err := getGameRevenues(reportRange)
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to retrieve game revenues",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.Set(middlewares.ADMIN_AUDIT_OMIT_RESULT_KEY, true)
+	if ctx.Query("format") == "csv" {
+		respondCSV(ctx, "ggr", reportRange, gameRevenueRecords(revenues))
+		return
+	}
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"revenues": revenues,
+		},
+	)
+}
+
+func GetNetRevenueHandler(ctx *gin.Context) {
+	reportRange, err := parseReportRange(ctx)
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	revenues, err := getGameRevenues(reportRange)
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to retrieve game revenues",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+	deductions, err := getDeductions(reportRange)
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to retrieve deductions",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+	nets := buildNetRevenues(revenues, deductions)
+
+	ctx.Set(middlewares.ADMIN_AUDIT_OMIT_RESULT_KEY, true)
+	if ctx.Query("format") == "csv" {
+		respondCSV(ctx, "ngr", reportRange, netRevenueRecords(nets, deductions))
+		return
+	}
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"netRevenues": nets,
+			"deductions":  deductions,
+		},
+	)
+}
+
+func GetPaymentVolumeHandler(ctx *gin.Context) {
+	reportRange, err := parseReportRange(ctx)
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	volumes, err := getPaymentVolumes(reportRange)
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to retrieve payment volumes",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.Set(middlewares.ADMIN_AUDIT_OMIT_RESULT_KEY, true)
+	if ctx.Query("format") == "csv" {
+		respondCSV(ctx, "payments", reportRange, paymentVolumeRecords(volumes))
+		return
+	}
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"volumes": volumes,
+		},
+	)
+}
+
+func RebuildReportHandler(ctx *gin.Context) {
+	var params struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	}
+
+	if err := ctx.BindJSON(&params); err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	from, fromErr := time.Parse(REPORT_DAY_LAYOUT, params.From)
+	to, toErr := time.Parse(REPORT_DAY_LAYOUT, params.To)
+	reportRange := ReportRange{From: from, To: to}
+	if fromErr != nil || toErr != nil ||
+		!to.Before(startOfDay(time.Now())) {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+				"error":   "from and to should be finished days in YYYY-MM-DD",
+			},
+		)
+		return
+	} else if err := validateReportRange(reportRange); err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	if err := rebuildRange(reportRange); err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to rebuild report",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"message": "successfully rebuilt report",
+		},
+	)
+}
+
+/**
+* @Internal
+* Parses inclusive UTC day range from query. Defaults to the last
+* 30 finished days.
+ */
+func parseReportRange(ctx *gin.Context) (ReportRange, error) {
+	yesterday := startOfDay(time.Now()).Add(-24 * time.Hour)
+	reportRange := ReportRange{
+		From: yesterday.Add(-29 * 24 * time.Hour),
+		To:   yesterday,
+	}
+
+	if from := ctx.Query("from"); from != "" {
+		value, err := time.Parse(REPORT_DAY_LAYOUT, from)
+		if err != nil {
+			return reportRange, fmt.Errorf("invalid from: %s", from)
+		}
+		reportRange.From = value
+	}
+	if to := ctx.Query("to"); to != "" {
+		value, err := time.Parse(REPORT_DAY_LAYOUT, to)
+		if err != nil {
+			return reportRange, fmt.Errorf("invalid to: %s", to)
+		}
+		reportRange.To = value
+	}
+	return reportRange, validateReportRange(reportRange)
+}
+
+/**
+* @Internal
+* Validates the range is ordered and not longer than allowed.
+ */
+func validateReportRange(reportRange ReportRange) error {
+	if reportRange.To.Before(reportRange.From) {
+		return fmt.Errorf(
+			"to should not be before from: from: %v, to: %v",
+			reportRange.From, reportRange.To,
+		)
+	}
+	if reportRange.To.Sub(reportRange.From) >=
+		time.Duration(config.FINANCIAL_REPORT_MAX_RANGE_DAYS)*24*time.Hour {
+		return fmt.Errorf(
+			"range should not exceed %d days",
+			config.FINANCIAL_REPORT_MAX_RANGE_DAYS,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package financial_report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/gin-gonic/gin"
+)
+
+const REPORT_DAY_LAYOUT = "2006-01-02"
+
+/**
+* @Internal
+* Formats balance amount as decimal chips without truncation.
+ */
+func formatChips(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	unit := int64(1)
+	for i := 0; i < config.BALANCE_DECIMALS; i++ {
+		unit *= 10
+	}
+	return fmt.Sprintf(
+		"%s%d.%0*d",
+		sign,
+		amount/unit,
+		config.BALANCE_DECIMALS,
+		amount%unit,
+	)
+}
+
+/**
+* @Internal
+* Returns CSV records of GGR rows.
+ */
+func gameRevenueRecords(revenues []models.DailyGameRevenue) [][]string {
+	records := [][]string{{"day", "game", "wager", "payout", "fee", "ggr"}}
+	for _, revenue := range revenues {
+		records = append(records, []string{
+			revenue.Day.Format(REPORT_DAY_LAYOUT),
+			revenue.Game,
+			formatChips(revenue.Wager),
+			formatChips(revenue.Payout),
+			formatChips(revenue.Fee),
+			formatChips(revenue.GGR),
+		})
+	}
+	return records
+}
+
+/**
+* @Internal
+* Returns CSV records of NGR with deductions per category.
+ */
+func netRevenueRecords(
+	nets []DailyNetRevenue,
+	deductions []models.DailyRevenueDeduction,
+) [][]string {
+	categories := []string{}
+	for _, category := range getDeductionTxTypes() {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	amounts := map[string]int64{}
+	for _, deduction := range deductions {
+		amounts[deduction.Day.Format(REPORT_DAY_LAYOUT)+"/"+deduction.Category] += deduction.Amount
+	}
+
+	header := append([]string{"day", "ggr"}, categories...)
+	header = append(header, "deductions", "ngr")
+	records := [][]string{header}
+	for _, net := range nets {
+		day := net.Day.Format(REPORT_DAY_LAYOUT)
+		record := []string{day, formatChips(net.GGR)}
+		for _, category := range categories {
+			record = append(record, formatChips(amounts[day+"/"+category]))
+		}
+		record = append(record, formatChips(net.Deductions), formatChips(net.NGR))
+		records = append(records, record)
+	}
+	return records
+}
+
+/**
+* @Internal
+* Returns CSV records of payment volumes.
+ */
+func paymentVolumeRecords(volumes []models.DailyPaymentVolume) [][]string {
+	records := [][]string{{"day", "direction", "token", "count", "tokenAmount", "usdAmount"}}
+	for _, volume := range volumes {
+		records = append(records, []string{
+			volume.Day.Format(REPORT_DAY_LAYOUT),
+			string(volume.Direction),
+			volume.Token,
+			strconv.FormatInt(volume.Count, 10),
+			strconv.FormatInt(volume.TokenAmount, 10),
+			formatChips(volume.UsdAmount),
+		})
+	}
+	return records
+}
+
+/**
+* @Internal
+* Responds CSV records as an attachment.
+ */
+func respondCSV(
+	ctx *gin.Context,
+	name string,
+	reportRange ReportRange,
+	records [][]string,
+) {
+	ctx.Header(
+		"Content-Disposition",
+		fmt.Sprintf(
+			"attachment; filename=%s_%s_%s.csv",
+			name,
+			reportRange.From.Format(REPORT_DAY_LAYOUT),
+			reportRange.To.Format(REPORT_DAY_LAYOUT),
+		),
+	)
+	ctx.Status(http.StatusOK)
+	ctx.Writer.Header().Set("Content-Type", "text/csv")
+
+	writer := csv.NewWriter(ctx.Writer)
+	writer.WriteAll(records)
+}
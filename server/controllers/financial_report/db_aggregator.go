@@ -0,0 +1,346 @@
+package financial_report
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/controllers/transaction/db_aggregator"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"gorm.io/gorm"
+)
+
+type txSide string
+
+const (
+	txSideAny  txSide = ""
+	txSideFrom txSide = "from_wallet"
+	txSideTo   txSide = "to_wallet"
+)
+
+/**
+* @Internal
+* Sums chip amounts of succeed transactions per type in [from, to).
+* Rain transactions hold amount for each recipient, so are counted
+* once per recipient.
+* If `side` is set, only counts transactions whose wallet on that
+* side is owned by a real user.
+ */
+func sumTxAmounts(
+	txTypes []models.TransactionType,
+	from time.Time,
+	to time.Time,
+	side txSide,
+) (TxAmounts, error) {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"financial_report_db",
+			"sumTxAmounts",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	query := session.Table(
+		"transactions t",
+	).Joins(
+		"join balances b on b.owner_type = ? and b.owner_id = t.id",
+		models.InTransaction,
+	).Joins(
+		"join chip_balances cb on cb.id = b.chip_balance_id",
+	).Where(
+		"t.deleted_at is null",
+	).Where(
+		"t.type in ?",
+		txTypes,
+	).Where(
+		"t.status = ?",
+		models.TransactionSucceed,
+	).Where(
+		"t.created_at >= ? and t.created_at < ?",
+		from, to,
+	)
+	if side != txSideAny {
+		query = query.Joins(
+			fmt.Sprintf("join wallets w on w.id = t.%s", side),
+		).Where(
+			"w.user_id not in ?",
+			config.GetHouseUserIDs(),
+		)
+	}
+
+	rows := []struct {
+		Type   models.TransactionType
+		Amount int64
+	}{}
+	if err := query.Select(
+		"t.type as type, coalesce(sum(cb.balance * greatest(coalesce(cardinality(t.receipients), 0), 1)), 0) as amount",
+	).Group(
+		"t.type",
+	).Scan(&rows).Error; err != nil {
+		return nil, utils.MakeError(
+			"financial_report_db",
+			"sumTxAmounts",
+			"failed to sum transaction amounts",
+			fmt.Errorf(
+				"types: %v, from: %v, to: %v, side: %s, err: %v",
+				txTypes, from, to, side, err,
+			),
+		)
+	}
+
+	amounts := TxAmounts{}
+	for _, row := range rows {
+		amounts[row.Type] = row.Amount
+	}
+	return amounts, nil
+}
+
+/**
+* @Internal
+* Summarizes succeed payments per type in [from, to).
+ */
+func summarizePayments(from time.Time, to time.Time) ([]PaymentSummary, error) {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"financial_report_db",
+			"summarizePayments",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	summaries := []PaymentSummary{}
+	if err := session.Model(
+		&models.Payment{},
+	).Where(
+		"status = ?",
+		models.Success,
+	).Where(
+		"created_at >= ? and created_at < ?",
+		from, to,
+	).Select(
+		"type, count(*) as count, coalesce(sum(sol_amount), 0) as token_amount, coalesce(sum(usd_amount), 0) as usd_amount",
+	).Group(
+		"type",
+	).Scan(&summaries).Error; err != nil {
+		return nil, utils.MakeError(
+			"financial_report_db",
+			"summarizePayments",
+			"failed to summarize payments",
+			fmt.Errorf(
+				"from: %v, to: %v, err: %v",
+				from, to, err,
+			),
+		)
+	}
+
+	return summaries, nil
+}
+
+/**
+* @Internal
+* Replaces materialized rows of the day in a single transaction.
+ */
+func replaceDailyAggregates(
+	day time.Time,
+	revenues []models.DailyGameRevenue,
+	deductions []models.DailyRevenueDeduction,
+	volumes []models.DailyPaymentVolume,
+) error {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return utils.MakeError(
+			"financial_report_db",
+			"replaceDailyAggregates",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	if err := session.Transaction(func(tx *gorm.DB) error {
+		for _, model := range []interface{}{
+			&models.DailyGameRevenue{},
+			&models.DailyRevenueDeduction{},
+			&models.DailyPaymentVolume{},
+		} {
+			if err := tx.Unscoped().Where(
+				"day = ?",
+				day,
+			).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		if len(revenues) > 0 {
+			if err := tx.Create(&revenues).Error; err != nil {
+				return err
+			}
+		}
+		if len(deductions) > 0 {
+			if err := tx.Create(&deductions).Error; err != nil {
+				return err
+			}
+		}
+		if len(volumes) > 0 {
+			if err := tx.Create(&volumes).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return utils.MakeError(
+			"financial_report_db",
+			"replaceDailyAggregates",
+			"failed to replace daily aggregates",
+			fmt.Errorf(
+				"day: %v, err: %v",
+				day, err,
+			),
+		)
+	}
+
+	return nil
+}
+
+/**
+* @Internal
+* Returns the latest materialized day, nil if nothing materialized.
+ */
+func getLastMaterializedDay() (*time.Time, error) {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"financial_report_db",
+			"getLastMaterializedDay",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	var last *time.Time
+	if err := session.Model(
+		&models.DailyGameRevenue{},
+	).Select(
+		"max(day)",
+	).Scan(&last).Error; err != nil {
+		return nil, utils.MakeError(
+			"financial_report_db",
+			"getLastMaterializedDay",
+			"failed to retrieve last materialized day",
+			err,
+		)
+	}
+
+	return last, nil
+}
+
+/**
+* @Internal
+* Returns materialized GGR rows in the day range.
+ */
+func getGameRevenues(reportRange ReportRange) ([]models.DailyGameRevenue, error) {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"financial_report_db",
+			"getGameRevenues",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	revenues := []models.DailyGameRevenue{}
+	if err := session.Where(
+		"day >= ? and day <= ?",
+		reportRange.From, reportRange.To,
+	).Order(
+		"day, game",
+	).Find(&revenues).Error; err != nil {
+		return nil, utils.MakeError(
+			"financial_report_db",
+			"getGameRevenues",
+			"failed to retrieve game revenues",
+			fmt.Errorf(
+				"range: %v, err: %v",
+				reportRange, err,
+			),
+		)
+	}
+
+	return revenues, nil
+}
+
+/**
+* @Internal
+* Returns materialized deduction rows in the day range.
+ */
+func getDeductions(reportRange ReportRange) ([]models.DailyRevenueDeduction, error) {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"financial_report_db",
+			"getDeductions",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	deductions := []models.DailyRevenueDeduction{}
+	if err := session.Where(
+		"day >= ? and day <= ?",
+		reportRange.From, reportRange.To,
+	).Order(
+		"day, category",
+	).Find(&deductions).Error; err != nil {
+		return nil, utils.MakeError(
+			"financial_report_db",
+			"getDeductions",
+			"failed to retrieve deductions",
+			fmt.Errorf(
+				"range: %v, err: %v",
+				reportRange, err,
+			),
+		)
+	}
+
+	return deductions, nil
+}
+
+/**
+* @Internal
+* Returns materialized payment volume rows in the day range.
+ */
+func getPaymentVolumes(reportRange ReportRange) ([]models.DailyPaymentVolume, error) {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"financial_report_db",
+			"getPaymentVolumes",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	volumes := []models.DailyPaymentVolume{}
+	if err := session.Where(
+		"day >= ? and day <= ?",
+		reportRange.From, reportRange.To,
+	).Order(
+		"day, direction, token",
+	).Find(&volumes).Error; err != nil {
+		return nil, utils.MakeError(
+			"financial_report_db",
+			"getPaymentVolumes",
+			"failed to retrieve payment volumes",
+			fmt.Errorf(
+				"range: %v, err: %v",
+				reportRange, err,
+			),
+		)
+	}
+
+	return volumes, nil
+}
@@ -0,0 +1,158 @@
+package financial_report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/models"
+)
+
+func TestBuildGameRevenues(t *testing.T) {
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	revenues := buildGameRevenues(
+		day,
+		TxAmounts{
+			models.TxCoinflipBet: 1000,
+			models.TxCrashBet:    500,
+		},
+		TxAmounts{
+			models.TxCoinflipCancel: 200,
+			models.TxCoinflipProfit: 700,
+			models.TxCrashProfit:    600,
+		},
+		TxAmounts{
+			models.TxCoinflipFee: 30,
+		},
+	)
+	if len(revenues) != len(getGameTxTypes()) {
+		t.Fatalf("every game should have a row: %d", len(revenues))
+	}
+
+	byGame := map[string]models.DailyGameRevenue{}
+	for _, revenue := range revenues {
+		byGame[revenue.Game] = revenue
+	}
+	coinflip := byGame["coinflip"]
+	if coinflip.Wager != 800 ||
+		coinflip.Payout != 700 ||
+		coinflip.Fee != 30 ||
+		coinflip.GGR != 100 {
+		t.Fatalf("cancelled bets should be excluded from wager: %v", coinflip)
+	}
+	if byGame["crash"].GGR != -100 {
+		t.Fatalf("house loss should be negative ggr: %v", byGame["crash"])
+	}
+	if byGame["jackpot"].GGR != 0 {
+		t.Fatalf("game without transactions should be zero: %v", byGame["jackpot"])
+	}
+}
+
+func TestBuildNetRevenues(t *testing.T) {
+	first := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	second := first.Add(24 * time.Hour)
+	nets := buildNetRevenues(
+		[]models.DailyGameRevenue{
+			{Day: second, Game: "crash", GGR: 300},
+			{Day: first, Game: "crash", GGR: 100},
+			{Day: first, Game: "coinflip", GGR: 50},
+		},
+		[]models.DailyRevenueDeduction{
+			{Day: first, Category: "rakeback", Amount: 20},
+			{Day: second, Category: "affiliate", Amount: 400},
+		},
+	)
+	if len(nets) != 2 {
+		t.Fatalf("should have a row per day: %v", nets)
+	}
+	if !nets[0].Day.Equal(first) ||
+		nets[0].GGR != 150 ||
+		nets[0].Deductions != 20 ||
+		nets[0].NGR != 130 {
+		t.Fatalf("invalid first day: %v", nets[0])
+	}
+	if nets[1].NGR != -100 {
+		t.Fatalf("invalid second day: %v", nets[1])
+	}
+}
+
+func TestBuildDeductions(t *testing.T) {
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	deductions := buildDeductions(
+		day,
+		TxAmounts{
+			models.TxClaimRakebackReward: 10,
+			models.TxClaimQuestReward:    20,
+			models.TxHouseRain:           30,
+			models.TxCoinflipBet:         1000,
+		},
+	)
+	if len(deductions) != len(getDeductionTxTypes()) {
+		t.Fatalf("every category should have a row: %d", len(deductions))
+	}
+
+	byCategory := map[string]int64{}
+	total := int64(0)
+	for _, deduction := range deductions {
+		byCategory[deduction.Category] = deduction.Amount
+		total += deduction.Amount
+	}
+	if byCategory["quest"] != 20 {
+		t.Fatalf("quest rewards should be deducted: %v", byCategory)
+	}
+	if byCategory["house-rain"] != 30 {
+		t.Fatalf("house rains should be deducted: %v", byCategory)
+	}
+	if total != 60 {
+		t.Fatalf("only deduction types should be deducted: %d", total)
+	}
+}
+
+func TestBuildPaymentVolumes(t *testing.T) {
+	volumes := buildPaymentVolumes(
+		time.Now(),
+		[]PaymentSummary{
+			{Type: "deposit_sol", Count: 2, TokenAmount: 10, UsdAmount: 20},
+			{Type: "withdraw_usdc", Count: 1, TokenAmount: 5, UsdAmount: 5},
+			{Type: "admin_deposit", Count: 1},
+			{Type: "deposit_", Count: 1},
+		},
+	)
+	if len(volumes) != 2 {
+		t.Fatalf("unknown payment types should be skipped: %v", volumes)
+	}
+	if volumes[0].Direction != models.PaymentDeposit ||
+		volumes[0].Token != "sol" {
+		t.Fatalf("invalid deposit volume: %v", volumes[0])
+	}
+	if volumes[1].Direction != models.PaymentWithdraw ||
+		volumes[1].Token != "usdc" {
+		t.Fatalf("invalid withdraw volume: %v", volumes[1])
+	}
+}
+
+func TestFormatChips(t *testing.T) {
+	cases := map[int64]string{
+		0:        "0.00000",
+		123456:   "1.23456",
+		-100001:  "-1.00001",
+		10000000: "100.00000",
+	}
+	for amount, expected := range cases {
+		if formatChips(amount) != expected {
+			t.Fatalf("formatChips(%d) = %s, expected %s", amount, formatChips(amount), expected)
+		}
+	}
+}
+
+func TestValidateReportRange(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := validateReportRange(ReportRange{From: from, To: from}); err != nil {
+		t.Fatalf("single day range should be valid: %v", err)
+	}
+	if err := validateReportRange(ReportRange{From: from, To: from.Add(-24 * time.Hour)}); err == nil {
+		t.Fatalf("reversed range should be invalid")
+	}
+	if err := validateReportRange(ReportRange{From: from, To: from.Add(400 * 24 * time.Hour)}); err == nil {
+		t.Fatalf("too long range should be invalid")
+	}
+}
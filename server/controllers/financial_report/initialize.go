@@ -0,0 +1,9 @@
+package financial_report
+
+/**
+* @External
+* Initializes financial_report module.
+ */
+func Initialize() error {
+	return initScheduler()
+}
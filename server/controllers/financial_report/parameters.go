@@ -0,0 +1,57 @@
+package financial_report
+
+import "github.com/Duelana-Team/duelana-v1/models"
+
+/**
+* @Internal
+* Returns revenue transaction types of each game.
+ */
+func getGameTxTypes() map[string]GameTxTypes {
+	return map[string]GameTxTypes{
+		"coinflip": {
+			Bet:    models.TxCoinflipBet,
+			Refund: models.TxCoinflipCancel,
+			Profit: models.TxCoinflipProfit,
+			Fee:    models.TxCoinflipFee,
+		},
+		"crash": {
+			Bet:    models.TxCrashBet,
+			Refund: models.TxCrashRefund,
+			Profit: models.TxCrashProfit,
+			Fee:    models.TxCrashFee,
+		},
+		"dreamtower": {
+			Bet:    models.TxDreamtowerBet,
+			Profit: models.TxDreamtowerProfit,
+			Fee:    models.TxDreamtowerFee,
+		},
+		"grand-jackpot": {
+			Bet:    models.TxGrandJackpotBet,
+			Profit: models.TxGrandJackpotProfit,
+			Fee:    models.TxGrandJackpotFee,
+		},
+		"jackpot": {
+			Bet:    models.TxJackpotBet,
+			Profit: models.TxJackpotProfit,
+			Fee:    models.TxJackpotFee,
+		},
+	}
+}
+
+/**
+* @Internal
+* Returns house cost categories subtracted from GGR for NGR.
+ */
+func getDeductionTxTypes() map[models.TransactionType]string {
+	return map[models.TransactionType]string{
+		models.TxClaimRakebackReward:     "rakeback",
+		models.TxClaimAffiliateReward:    "affiliate",
+		models.TxClaimStakingReward:      "duel-bot-staking",
+		models.TxExchangeCouponToChips:   "coupon-exchange",
+		models.TxClaimDailyRaceReward:    "daily-race",
+		models.TxClaimWeeklyRaffleReward: "weekly-raffle",
+		models.TxAdminUserDeposit:        "admin-deposit",
+		models.TxClaimQuestReward:        "quest",
+		models.TxHouseRain:               "house-rain",
+	}
+}
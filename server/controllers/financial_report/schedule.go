@@ -0,0 +1,72 @@
+package financial_report
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+)
+
+var scheduler *cron.Cron = nil
+
+// Prevents nightly job and manual rebuild from materializing concurrently.
+var materializeMutex sync.Mutex
+
+/**
+* @Internal
+* Starts nightly job materializing finished days.
+* This function is called on module initialization.
+ */
+func initScheduler() error {
+	scheduler = cron.New(cron.WithLocation(time.UTC))
+	if _, err := scheduler.AddFunc(
+		config.FINANCIAL_REPORT_AGGREGATE_SPEC,
+		triggerMaterialize,
+	); err != nil {
+		return utils.MakeError(
+			"financial_report_schedule",
+			"initScheduler",
+			"failed to schedule nightly aggregation",
+			err,
+		)
+	}
+	scheduler.Start()
+
+	go triggerMaterialize()
+	return nil
+}
+
+/**
+* @Internal
+* Trigger function called nightly and on start up.
+ */
+func triggerMaterialize() {
+	materializeMutex.Lock()
+	defer materializeMutex.Unlock()
+
+	if err := materializePendingDays(); err != nil {
+		log.LogMessage(
+			"financial_report_triggerMaterialize",
+			"failed to materialize pending days",
+			"error",
+			logrus.Fields{
+				"error": err.Error(),
+			},
+		)
+	}
+}
+
+/**
+* @Internal
+* Rematerializes days in the range, e.g. after late corrections.
+ */
+func rebuildRange(reportRange ReportRange) error {
+	materializeMutex.Lock()
+	defer materializeMutex.Unlock()
+
+	return materializeRange(reportRange.From, reportRange.To)
+}
@@ -0,0 +1,52 @@
+package financial_report
+
+import (
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/models"
+)
+
+/**
+* Transaction types making up revenue of a game.
+* `Refund` is optional and returns a bet back to the user.
+ */
+type GameTxTypes struct {
+	Bet    models.TransactionType
+	Refund models.TransactionType
+	Profit models.TransactionType
+	Fee    models.TransactionType
+}
+
+/**
+* Chip amounts summed per transaction type within a day.
+ */
+type TxAmounts map[models.TransactionType]int64
+
+/**
+* Successful payments of a type within a day.
+* `Type` is `deposit_<token>` or `withdraw_<token>`.
+ */
+type PaymentSummary struct {
+	Type        string
+	Count       int64
+	TokenAmount int64
+	UsdAmount   int64
+}
+
+/**
+* Net gaming revenue of a day.
+ */
+type DailyNetRevenue struct {
+	Day        time.Time `json:"day"`
+	GGR        int64     `json:"ggr"`
+	Deductions int64     `json:"deductions"`
+	NGR        int64     `json:"ngr"`
+}
+
+/**
+* Inclusive UTC day range of a report query.
+ */
+type ReportRange struct {
+	From time.Time
+	To   time.Time
+}
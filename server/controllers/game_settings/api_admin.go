@@ -0,0 +1,137 @@
+package game_settings
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/middlewares"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"github.com/gin-gonic/gin"
+)
+
+func GetSettingsHandler(ctx *gin.Context) {
+	settings := []VersionedSettings{}
+	for _, game := range getGames() {
+		active, err := getActiveSettings(game)
+		if err != nil {
+			ctx.AbortWithStatusJSON(
+				http.StatusInternalServerError,
+				gin.H{
+					"message": "failed to retrieve settings",
+					"error":   err.Error(),
+				},
+			)
+			return
+		}
+		settings = append(settings, active)
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"settings": settings,
+		},
+	)
+}
+
+func SaveSettingsHandler(ctx *gin.Context) {
+	var params struct {
+		Game     string          `json:"game"`
+		Settings json.RawMessage `json:"settings"`
+	}
+
+	if err := ctx.BindJSON(&params); err != nil ||
+		len(params.Settings) == 0 {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+			},
+		)
+		return
+	}
+
+	operator := ""
+	if identity := middlewares.GetAdminOperator(ctx); identity != nil {
+		operator = identity.Name
+	}
+
+	active, err := saveSettings(
+		params.Game,
+		params.Settings,
+		operator,
+	)
+	if utils.IsErrorCode(err, ErrCodeUnknownGame) {
+		ctx.AbortWithStatusJSON(
+			http.StatusNotFound,
+			gin.H{
+				"message": "unknown game",
+				"error":   err.Error(),
+			},
+		)
+		return
+	} else if utils.IsErrorCode(err, ErrCodeInvalidParameter) {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid settings",
+				"error":   err.Error(),
+			},
+		)
+		return
+	} else if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to save settings",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"message":  "successfully saved settings",
+			"settings": active,
+		},
+	)
+}
+
+func GetSettingsHistoryHandler(ctx *gin.Context) {
+	game := ctx.Query("game")
+	if _, err := getDefaultSettings(game); err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	history, err := getSettingsHistory(
+		game,
+		config.GAME_SETTINGS_HISTORY_LIMIT,
+	)
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to retrieve settings history",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"history": history,
+		},
+	)
+}
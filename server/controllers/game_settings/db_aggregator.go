@@ -0,0 +1,137 @@
+package game_settings
+
+import (
+	"fmt"
+
+	"github.com/Duelana-Team/duelana-v1/controllers/transaction/db_aggregator"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+/**
+* @Internal
+* Returns latest settings version of each game.
+ */
+func getLatestSettings() ([]models.GameSettings, error) {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"game_settings_db",
+			"getLatestSettings",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	records := []models.GameSettings{}
+	if err := session.Where(
+		"version = (?)",
+		session.Model(
+			&models.GameSettings{},
+		).Select(
+			"max(version)",
+		).Where(
+			"game = game_settings.game",
+		),
+	).Find(&records).Error; err != nil {
+		return nil, utils.MakeError(
+			"game_settings_db",
+			"getLatestSettings",
+			"failed to retrieve latest settings",
+			err,
+		)
+	}
+
+	return records, nil
+}
+
+/**
+* @Internal
+* Creates next settings version of the game.
+ */
+func createSettingsVersion(
+	game string,
+	settings []byte,
+	operator string,
+) (*models.GameSettings, error) {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"game_settings_db",
+			"createSettingsVersion",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	record := models.GameSettings{
+		Game:     game,
+		Settings: datatypes.JSON(settings),
+		Operator: operator,
+	}
+	if err := session.Transaction(func(tx *gorm.DB) error {
+		var latest uint
+		if err := tx.Model(
+			&models.GameSettings{},
+		).Where(
+			"game = ?",
+			game,
+		).Select(
+			"coalesce(max(version), 0)",
+		).Scan(&latest).Error; err != nil {
+			return err
+		}
+		record.Version = latest + 1
+		return tx.Create(&record).Error
+	}); err != nil {
+		return nil, utils.MakeError(
+			"game_settings_db",
+			"createSettingsVersion",
+			"failed to create settings version",
+			fmt.Errorf(
+				"game: %s, operator: %s, err: %v",
+				game, operator, err,
+			),
+		)
+	}
+
+	return &record, nil
+}
+
+/**
+* @Internal
+* Returns settings versions of the game, latest first.
+ */
+func getSettingsHistory(game string, limit int) ([]models.GameSettings, error) {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"game_settings_db",
+			"getSettingsHistory",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	records := []models.GameSettings{}
+	if err := session.Where(
+		"game = ?",
+		game,
+	).Order(
+		"version desc",
+	).Limit(limit).Find(&records).Error; err != nil {
+		return nil, utils.MakeError(
+			"game_settings_db",
+			"getSettingsHistory",
+			"failed to retrieve settings history",
+			fmt.Errorf(
+				"game: %s, err: %v",
+				game, err,
+			),
+		)
+	}
+
+	return records, nil
+}
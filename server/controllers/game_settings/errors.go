@@ -0,0 +1,6 @@
+package game_settings
+
+// Error code range: #110xxx
+const ErrCodeBase = "#110"
+const ErrCodeInvalidParameter = ErrCodeBase + "000"
+const ErrCodeUnknownGame = ErrCodeBase + "001"
@@ -0,0 +1,62 @@
+package game_settings
+
+import (
+	"testing"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/utils"
+)
+
+func TestDefaultSettingsAreValid(t *testing.T) {
+	for _, game := range getGames() {
+		settings, err := getDefaultSettings(game)
+		if err != nil {
+			t.Fatalf("failed to get default settings of %s: %v", game, err)
+		}
+		if err := settings.Validate(); err != nil {
+			t.Fatalf("default settings of %s should be valid: %v", game, err)
+		}
+	}
+}
+
+func TestDecodeSettings(t *testing.T) {
+	settings, err := decodeSettings(
+		CrashGame,
+		[]byte(`{"houseEdge": 300}`),
+	)
+	if err != nil {
+		t.Fatalf("failed to decode partial settings: %v", err)
+	}
+	crash := settings.(CrashSettings)
+	if crash.HouseEdge != 300 {
+		t.Fatalf("house edge should be overridden: %d", crash.HouseEdge)
+	}
+	if crash.MaxBetAmount != config.CRASH_MAX_BET_AMOUNT {
+		t.Fatalf("omitted fields should keep defaults: %d", crash.MaxBetAmount)
+	}
+
+	if _, err := decodeSettings(
+		CrashGame,
+		[]byte(`{"houseEdge": 5000}`),
+	); !utils.IsErrorCode(err, ErrCodeInvalidParameter) {
+		t.Fatalf("house edge out of bounds should be rejected: %v", err)
+	}
+	if _, err := decodeSettings(
+		CoinflipGame,
+		[]byte(`{"minBetAmount": 100, "maxBetAmount": 10}`),
+	); !utils.IsErrorCode(err, ErrCodeInvalidParameter) {
+		t.Fatalf("inverted bet range should be rejected: %v", err)
+	}
+	if _, err := decodeSettings(
+		JackpotLowGame,
+		[]byte(`{"fee": 50}`),
+	); !utils.IsErrorCode(err, ErrCodeInvalidParameter) {
+		t.Fatalf("fee above max should be rejected: %v", err)
+	}
+	if _, err := decodeSettings(
+		"roulette",
+		[]byte(`{}`),
+	); !utils.IsErrorCode(err, ErrCodeUnknownGame) {
+		t.Fatalf("unknown game should be rejected: %v", err)
+	}
+}
@@ -0,0 +1,289 @@
+package game_settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+)
+
+type validatable interface {
+	Validate() error
+}
+
+// Active settings of each game loaded from db.
+// Games without record follow defaults from config.
+var activeSettings = map[string]VersionedSettings{}
+var activeMutex sync.RWMutex
+
+/**
+* @Internal
+* Returns games configurable at runtime.
+ */
+func getGames() []string {
+	return []string{
+		CoinflipGame,
+		JackpotLowGame,
+		JackpotMediumGame,
+		JackpotWildGame,
+		CrashGame,
+	}
+}
+
+/**
+* @Internal
+* Returns default settings of the game from config.
+ */
+func getDefaultSettings(game string) (validatable, error) {
+	switch game {
+	case CoinflipGame:
+		return CoinflipSettings{
+			RoundLimit:   config.COINFLIP_ROUND_LIMIT,
+			MinBetAmount: config.COINFLIP_MIN_AMOUNT,
+			MaxBetAmount: config.COINFLIP_MAX_AMOUNT,
+			Fee:          config.COINFLIP_FEE,
+		}, nil
+	case JackpotLowGame, JackpotMediumGame, JackpotWildGame:
+		settings := JackpotSettings{
+			BetCountLimit: config.JACKPOT_BET_COUNT_LIMIT,
+			PlayerLimit:   config.JACKPOT_PLAYER_LIMIT,
+			CountingTime:  config.JACKPOT_COUNTING_TIME,
+			RollingTime:   config.JACKPOT_ROLLING_TIME,
+			Fee:           config.JACKPOT_FEE,
+		}
+		switch game {
+		case JackpotLowGame:
+			settings.MinBetAmount = config.JACKPOT_MIN_AMOUNT_LOW
+			settings.MaxBetAmount = config.JACKPOT_MAX_AMOUNT_LOW
+		case JackpotMediumGame:
+			settings.MinBetAmount = config.JACKPOT_MIN_AMOUNT_MEDIUM
+			settings.MaxBetAmount = config.JACKPOT_MAX_AMOUNT_MEDIUM
+		case JackpotWildGame:
+			settings.MinBetAmount = config.JACKPOT_MIN_AMOUNT_WILD
+			settings.MaxBetAmount = config.JACKPOT_MAX_AMOUNT_WILD
+		}
+		return settings, nil
+	case CrashGame:
+		return CrashSettings{
+			BettingDurationMilli:   config.CRASH_BETTING_DURATION_MILLI,
+			PendingDurationMilli:   config.CRASH_PENDING_DURATION_MILLI,
+			PreparingDurationMilli: config.CRASH_PREPARING_DURATION_MILLI,
+			BetCountLimit:          config.CRASH_BET_COUNT_LIMIT,
+			MinBetAmount:           config.CRASH_MIN_BET_AMOUNT,
+			MaxBetAmount:           config.CRASH_MAX_BET_AMOUNT,
+			MultiplierIncreaseRate: config.CRASH_MULTIPLIER_INCREASE_RATE,
+			HouseEdge:              config.CRASH_HOUSE_EDGE,
+			MaxPlayerLimit:         config.CRASH_MAX_PLAYER_LIMIT,
+			MinCashOutAt:           config.CRASH_MIN_CASH_OUT_AT,
+			MaxCashOut:             config.CRASH_MAX_CASH_OUT,
+		}, nil
+	}
+	return nil, utils.MakeErrorWithCode(
+		"game_settings",
+		"getDefaultSettings",
+		"unknown game",
+		ErrCodeUnknownGame,
+		fmt.Errorf("game: %s", game),
+	)
+}
+
+/**
+* @Internal
+* Decodes and validates raw settings of the game.
+* Omitted fields keep default values.
+ */
+func decodeSettings(game string, raw []byte) (validatable, error) {
+	defaults, err := getDefaultSettings(game)
+	if err != nil {
+		return nil, err
+	}
+
+	var settings validatable
+	switch value := defaults.(type) {
+	case CoinflipSettings:
+		err = json.Unmarshal(raw, &value)
+		settings = value
+	case JackpotSettings:
+		err = json.Unmarshal(raw, &value)
+		settings = value
+	case CrashSettings:
+		err = json.Unmarshal(raw, &value)
+		settings = value
+	}
+	if err != nil {
+		return nil, utils.MakeErrorWithCode(
+			"game_settings",
+			"decodeSettings",
+			"failed to decode settings",
+			ErrCodeInvalidParameter,
+			err,
+		)
+	}
+
+	if err := settings.Validate(); err != nil {
+		return nil, utils.MakeErrorWithCode(
+			"game_settings",
+			"decodeSettings",
+			"invalid settings",
+			ErrCodeInvalidParameter,
+			fmt.Errorf("game: %s, err: %v", game, err),
+		)
+	}
+	return settings, nil
+}
+
+/**
+* @External
+* Loads latest settings of each game from db.
+ */
+func Initialize() error {
+	records, err := getLatestSettings()
+	if err != nil {
+		return utils.MakeError(
+			"game_settings",
+			"Initialize",
+			"failed to retrieve latest settings",
+			err,
+		)
+	}
+
+	activeMutex.Lock()
+	defer activeMutex.Unlock()
+	for _, record := range records {
+		settings, err := decodeSettings(record.Game, record.Settings)
+		if err != nil {
+			return utils.MakeError(
+				"game_settings",
+				"Initialize",
+				"invalid persisted settings",
+				fmt.Errorf(
+					"game: %s, version: %d, err: %v",
+					record.Game, record.Version, err,
+				),
+			)
+		}
+		activeSettings[record.Game] = VersionedSettings{
+			Game:     record.Game,
+			Version:  record.Version,
+			Settings: settings,
+		}
+	}
+	return nil
+}
+
+/**
+* @Internal
+* Validates and saves settings as a new version, then activates it.
+* Running controllers pick it up at their next safe point.
+ */
+func saveSettings(
+	game string,
+	raw []byte,
+	operator string,
+) (*VersionedSettings, error) {
+	settings, err := decodeSettings(game, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := json.Marshal(settings)
+	if err != nil {
+		return nil, utils.MakeError(
+			"game_settings",
+			"saveSettings",
+			"failed to encode settings",
+			err,
+		)
+	}
+
+	activeMutex.Lock()
+	defer activeMutex.Unlock()
+	record, err := createSettingsVersion(game, encoded, operator)
+	if err != nil {
+		return nil, utils.MakeError(
+			"game_settings",
+			"saveSettings",
+			"failed to create settings version",
+			err,
+		)
+	}
+
+	active := VersionedSettings{
+		Game:     game,
+		Version:  record.Version,
+		Settings: settings,
+	}
+	activeSettings[game] = active
+	return &active, nil
+}
+
+/**
+* @Internal
+* Returns active settings of the game with version.
+ */
+func getActiveSettings(game string) (VersionedSettings, error) {
+	activeMutex.RLock()
+	active, ok := activeSettings[game]
+	activeMutex.RUnlock()
+	if ok {
+		return active, nil
+	}
+
+	defaults, err := getDefaultSettings(game)
+	if err != nil {
+		return VersionedSettings{}, err
+	}
+	return VersionedSettings{
+		Game:     game,
+		Settings: defaults,
+	}, nil
+}
+
+/**
+* @External
+* Returns active coinflip settings and its version.
+ */
+func GetCoinflipSettings() (CoinflipSettings, uint) {
+	active, _ := getActiveSettings(CoinflipGame)
+	settings, _ := active.Settings.(CoinflipSettings)
+	return settings, active.Version
+}
+
+/**
+* @External
+* Returns active jackpot settings of the level and its version.
+ */
+func GetJackpotSettings(jackpotType models.JackpotType) (JackpotSettings, uint) {
+	active, _ := getActiveSettings(getJackpotGame(jackpotType))
+	settings, _ := active.Settings.(JackpotSettings)
+	return settings, active.Version
+}
+
+/**
+* @External
+* Returns active crash settings and its version.
+ */
+func GetCrashSettings() (CrashSettings, uint) {
+	active, _ := getActiveSettings(CrashGame)
+	settings, _ := active.Settings.(CrashSettings)
+	return settings, active.Version
+}
+
+/**
+* @Internal
+* Returns settings game name of the jackpot level.
+ */
+func getJackpotGame(jackpotType models.JackpotType) string {
+	switch jackpotType {
+	case models.Low:
+		return JackpotLowGame
+	case models.Medium:
+		return JackpotMediumGame
+	case models.Wild:
+		return JackpotWildGame
+	}
+	return ""
+}
@@ -0,0 +1,50 @@
+package game_settings
+
+const (
+	CoinflipGame      = "coinflip"
+	JackpotLowGame    = "jackpot-low"
+	JackpotMediumGame = "jackpot-medium"
+	JackpotWildGame   = "jackpot-wild"
+	CrashGame         = "crash"
+)
+
+type CoinflipSettings struct {
+	RoundLimit   uint  `json:"roundLimit"`
+	MinBetAmount int64 `json:"minBetAmount"`
+	MaxBetAmount int64 `json:"maxBetAmount"`
+	Fee          int64 `json:"fee"`
+}
+
+type JackpotSettings struct {
+	MinBetAmount  int64 `json:"minBetAmount"`
+	MaxBetAmount  int64 `json:"maxBetAmount"`
+	BetCountLimit uint  `json:"betCountLimit"`
+	PlayerLimit   uint  `json:"playerLimit"`
+	CountingTime  uint  `json:"countingTime"`
+	RollingTime   uint  `json:"rollingTime"`
+	Fee           int64 `json:"fee"`
+}
+
+type CrashSettings struct {
+	BettingDurationMilli   int64   `json:"bettingDurationMilli"`
+	PendingDurationMilli   int64   `json:"pendingDurationMilli"`
+	PreparingDurationMilli int64   `json:"preparingDurationMilli"`
+	BetCountLimit          uint    `json:"betCountLimit"`
+	MinBetAmount           int64   `json:"minBetAmount"`
+	MaxBetAmount           int64   `json:"maxBetAmount"`
+	MultiplierIncreaseRate float64 `json:"multiplierIncreaseRate"`
+	HouseEdge              int64   `json:"houseEdge"`
+	MaxPlayerLimit         uint    `json:"maxPlayerLimit"`
+	MinCashOutAt           float64 `json:"minCashOutAt"`
+	MaxCashOut             int64   `json:"maxCashOut"`
+}
+
+/**
+* Settings with version currently active for a game.
+* Version zero means defaults from config.
+ */
+type VersionedSettings struct {
+	Game     string      `json:"game"`
+	Version  uint        `json:"version"`
+	Settings interface{} `json:"settings"`
+}
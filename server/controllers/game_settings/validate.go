@@ -0,0 +1,134 @@
+package game_settings
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+)
+
+/**
+* @Internal
+* Validates bet amount range shared by all games.
+ */
+func validateBetRange(minBetAmount int64, maxBetAmount int64) error {
+	if minBetAmount <= 0 {
+		return fmt.Errorf("min bet amount should be positive: %d", minBetAmount)
+	}
+	if maxBetAmount < minBetAmount {
+		return fmt.Errorf(
+			"max bet amount should not be less than min: min: %d, max: %d",
+			minBetAmount, maxBetAmount,
+		)
+	}
+	return nil
+}
+
+/**
+* @Internal
+* Validates fee percent.
+ */
+func validateFee(fee int64) error {
+	if fee < 0 || fee > config.GAME_SETTINGS_MAX_FEE {
+		return fmt.Errorf(
+			"fee should be in 0 ~ %d: %d",
+			config.GAME_SETTINGS_MAX_FEE, fee,
+		)
+	}
+	return nil
+}
+
+func (settings CoinflipSettings) Validate() error {
+	if settings.RoundLimit == 0 {
+		return errors.New("round limit should be positive")
+	}
+	if err := validateBetRange(
+		settings.MinBetAmount,
+		settings.MaxBetAmount,
+	); err != nil {
+		return err
+	}
+	return validateFee(settings.Fee)
+}
+
+func (settings JackpotSettings) Validate() error {
+	if err := validateBetRange(
+		settings.MinBetAmount,
+		settings.MaxBetAmount,
+	); err != nil {
+		return err
+	}
+	if settings.BetCountLimit == 0 ||
+		settings.PlayerLimit < 2 {
+		return fmt.Errorf(
+			"invalid limits: betCountLimit: %d, playerLimit: %d",
+			settings.BetCountLimit, settings.PlayerLimit,
+		)
+	}
+	if settings.CountingTime <= config.JACKPOT_TAIL {
+		return fmt.Errorf(
+			"counting time should be longer than tail %d: %d",
+			config.JACKPOT_TAIL, settings.CountingTime,
+		)
+	}
+	// Winner is announced 15 seconds before rolling ends.
+	if settings.RollingTime <= 15 {
+		return fmt.Errorf(
+			"rolling time should be longer than 15: %d",
+			settings.RollingTime,
+		)
+	}
+	return validateFee(settings.Fee)
+}
+
+func (settings CrashSettings) Validate() error {
+	if err := validateBetRange(
+		settings.MinBetAmount,
+		settings.MaxBetAmount,
+	); err != nil {
+		return err
+	}
+	if settings.BettingDurationMilli < config.CRASH_EVENT_INTERVAL_MILLI ||
+		settings.PendingDurationMilli < config.CRASH_EVENT_INTERVAL_MILLI ||
+		settings.PreparingDurationMilli < config.CRASH_EVENT_INTERVAL_MILLI {
+		return fmt.Errorf(
+			"durations should not be shorter than event interval %d",
+			config.CRASH_EVENT_INTERVAL_MILLI,
+		)
+	}
+	if settings.BetCountLimit == 0 ||
+		settings.MaxPlayerLimit == 0 {
+		return fmt.Errorf(
+			"invalid limits: betCountLimit: %d, maxPlayerLimit: %d",
+			settings.BetCountLimit, settings.MaxPlayerLimit,
+		)
+	}
+	if settings.MultiplierIncreaseRate <= 1 {
+		return fmt.Errorf(
+			"multiplier increase rate should be greater than 1: %f",
+			settings.MultiplierIncreaseRate,
+		)
+	}
+	if settings.HouseEdge < config.CRASH_HOUSE_EDGE_MIN ||
+		settings.HouseEdge > config.CRASH_HOUSE_EDGE_MAX {
+		return fmt.Errorf(
+			"house edge should be in %d ~ %d: %d",
+			config.CRASH_HOUSE_EDGE_MIN,
+			config.CRASH_HOUSE_EDGE_MAX,
+			settings.HouseEdge,
+		)
+	}
+	if settings.MinCashOutAt <= 1 {
+		return fmt.Errorf(
+			"min cash out should be greater than 1: %f",
+			settings.MinCashOutAt,
+		)
+	}
+	if settings.MaxCashOut < settings.MaxBetAmount {
+		return fmt.Errorf(
+			"max cash out should not be less than max bet: %d",
+			settings.MaxCashOut,
+		)
+	}
+	return nil
+}
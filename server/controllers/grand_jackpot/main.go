@@ -306,9 +306,6 @@ func (c *Controller) Bet(userID uint, betData jackpot.BetData) error {
 	if err := c.validateStatusOnBet(userID, betData); err != nil {
 		return err
 	}
-	if err := c.validateBetAmount(userID, betData); err != nil {
-		return err
-	}
 	if err := c.validateBetNFTsDuplication(userID, betData); err != nil {
 		return err
 	}
@@ -318,6 +315,26 @@ func (c *Controller) Bet(userID uint, betData jackpot.BetData) error {
 		mintAddresses = append(mintAddresses, nft.MintAddress)
 	}
 
+	// Value NFTs from cache before checking limits, so that limits
+	// apply to the credited value rather than the one sent.
+	db := db.GetDB()
+	var nfts []models.DepositedNft
+	if err := db.Where("mint_address IN ?", mintAddresses).Find(&nfts).Error; err != nil {
+		c.emitErrMessageWithBalanceUpdate("Failed to get nfts from db", userID, betData)
+		return err
+	}
+	nftsInGame, totalNftPrice, err := jackpot.ValuateNfts(nfts)
+	if err == nil && len(nfts) != len(mintAddresses) {
+		err = errors.New("nft not found")
+	}
+	if err != nil {
+		c.emitErrMessageWithBalanceUpdate("Failed to ge NFT from db", userID, betData)
+		return err
+	}
+	if err := c.validateBetAmount(userID, betData, totalNftPrice); err != nil {
+		return err
+	}
+
 	tx, err := transaction.Transfer(&transaction.TransactionRequest{
 		FromUser: (*db_aggregator.User)(&userID),
 		ToUser:   (*db_aggregator.User)(&config.GRAND_JACKPOT_TEMP_ID),
@@ -327,19 +344,14 @@ func (c *Controller) Bet(userID uint, betData jackpot.BetData) error {
 		},
 		Type:          models.TxGrandJackpotBet,
 		ToBeConfirmed: false,
+		OwnerID:       c.roundID,
+		OwnerType:     models.TransactionJackpotReferenced,
 	})
 	if err != nil {
 		c.emitErrMessageWithBalanceUpdate("", userID, betData)
 		return err
 	}
 
-	db := db.GetDB()
-	var nfts []models.DepositedNft
-	if err := db.Where("mint_address IN ?", mintAddresses).Find(&nfts).Error; err != nil {
-		c.emitErrMessageWithBalanceUpdate("Failed to get nfts from db", userID, betData)
-		return err
-	}
-
 	var round models.JackpotRound
 	var player models.JackpotPlayer
 	if err := db.First(&round, c.roundID).Error; err != nil {
@@ -371,8 +383,8 @@ func (c *Controller) Bet(userID uint, betData jackpot.BetData) error {
 		}
 	}
 
-	totalNftPrice, err := c.placeBet(player, userID, betData, nfts)
-	if err != nil {
+	if err := c.placeBet(player, betData, nftsInGame); err != nil {
+		c.emitErrMessageWithBalanceUpdate("Failed to place bet", userID, betData)
 		transaction.Decline(transaction.DeclineRequest{
 			Transaction: *tx,
 			OwnerID:     round.ID,
@@ -393,44 +405,25 @@ func (c *Controller) Bet(userID uint, betData jackpot.BetData) error {
 }
 
 // @Internal
-// Place bet
-func (c *Controller) placeBet(player models.JackpotPlayer, userID uint, betData jackpot.BetData, nfts []models.DepositedNft) (int64, error) {
+// Place bet with valued NFTs
+func (c *Controller) placeBet(player models.JackpotPlayer, betData jackpot.BetData, nftsInGame []models.NftInGame) error {
 	db := db.GetDB()
 	var bet = models.JackpotBet{
 		PlayerID:  player.ID,
 		UsdAmount: betData.Amount,
 	}
-	db.Create(&bet)
+	if err := db.Create(&bet).Error; err != nil {
+		return err
+	}
 
-	collectionID2Info := make(map[uint]*models.NftCollection)
-	totalNftPrice := int64(0)
-	var nftsInGame []models.NftInGame
-	for _, nft := range nfts {
-		if _, prs := collectionID2Info[nft.CollectionID]; !prs {
-			var collectionInfo models.NftCollection
-			if err := db.First(&collectionInfo, nft.CollectionID).Error; err != nil {
-				c.emitErrMessageWithBalanceUpdate("Failed to ge NFT from db", userID, betData)
-				return totalNftPrice, err
-			}
-			collectionID2Info[nft.CollectionID] = &collectionInfo
-		}
-		collectionInfo := collectionID2Info[nft.CollectionID]
-		nftsInGame = append(nftsInGame, models.NftInGame{
-			Name:            nft.Name,
-			MintAddress:     nft.MintAddress,
-			Image:           nft.Image,
-			CollectionName:  collectionInfo.Name,
-			CollectionImage: collectionInfo.Image,
-			Price:           collectionInfo.FloorPrice,
-			BetID:           bet.ID,
-		})
-		totalNftPrice += collectionInfo.FloorPrice
+	for i := range nftsInGame {
+		nftsInGame[i].BetID = bet.ID
 	}
 	if len(nftsInGame) > 0 {
 		db.Create(&nftsInGame)
 		c.nftsInGame = append(c.nftsInGame, nftsInGame...)
 	}
-	return totalNftPrice, nil
+	return nil
 }
 
 // @Internal
@@ -490,8 +483,8 @@ func (c *Controller) validateStatusOnBet(userID uint, betData jackpot.BetData) e
 
 // @Interanl
 // Bet amount validator
-func (c *Controller) validateBetAmount(userID uint, betData jackpot.BetData) error {
-	if betData.Amount+betData.NftAmount < c.minBetAmount {
+func (c *Controller) validateBetAmount(userID uint, betData jackpot.BetData, nftAmount int64) error {
+	if betData.Amount+nftAmount < c.minBetAmount {
 		c.emitErrMessageWithBalanceUpdate("Exceed bet amount limit", userID, betData)
 		return errors.New("invalid bet amount")
 	}
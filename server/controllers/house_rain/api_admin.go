@@ -0,0 +1,179 @@
+package house_rain
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"github.com/gin-gonic/gin"
+)
+
+func GetSchedulesHandler(ctx *gin.Context) {
+	schedules, err := getAllSchedules()
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to retrieve schedules",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	dailyBudget, err := getDailyBudget()
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to retrieve daily budget",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"schedules":   schedules,
+			"dailyBudget": utils.ConvertBalanceToChip(dailyBudget),
+		},
+	)
+}
+
+func SaveScheduleHandler(ctx *gin.Context) {
+	var params struct {
+		ID uint `json:"id"`
+		ScheduleRequest
+	}
+
+	if err := ctx.BindJSON(&params); err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	schedule, err := buildScheduleFromRequest(params.ScheduleRequest)
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+	schedule.ID = params.ID
+
+	if err := saveSchedule(schedule); utils.IsErrorCode(
+		err,
+		ErrCodeScheduleNotFound,
+	) {
+		ctx.AbortWithStatusJSON(
+			http.StatusNotFound,
+			gin.H{
+				"message": "schedule not found",
+				"error":   err.Error(),
+			},
+		)
+		return
+	} else if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to save schedule",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	if err := reloadSchedules(); err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "saved schedule but failed to reload scheduler",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"message":  "successfully saved schedule",
+			"schedule": schedule,
+		},
+	)
+}
+
+func SetDailyBudgetHandler(ctx *gin.Context) {
+	var params struct {
+		DailyBudget int64 `json:"dailyBudget"`
+	}
+
+	if err := ctx.BindJSON(&params); err != nil ||
+		params.DailyBudget < 0 {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+			},
+		)
+		return
+	}
+
+	if err := setDailyBudget(
+		utils.ConvertChipToBalance(params.DailyBudget),
+	); err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to set daily budget",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+	GetSchedulesHandler(ctx)
+}
+
+func GetHistoryHandler(ctx *gin.Context) {
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+			},
+		)
+		return
+	}
+
+	history, err := getHouseRainHistory(limit)
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to retrieve house rain history",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"history": history,
+		},
+	)
+}
@@ -0,0 +1,509 @@
+package house_rain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/controllers/transaction/db_aggregator"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+/**
+* @Internal
+* Returns all house rain schedules ordered by id.
+ */
+func getAllSchedules() ([]models.HouseRainSchedule, error) {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"house_rain_db",
+			"getAllSchedules",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	schedules := []models.HouseRainSchedule{}
+	if err := session.Order(
+		"id",
+	).Find(&schedules).Error; err != nil {
+		return nil, utils.MakeError(
+			"house_rain_db",
+			"getAllSchedules",
+			"failed to retrieve schedules",
+			err,
+		)
+	}
+
+	return schedules, nil
+}
+
+/**
+* @Internal
+* Returns enabled house rain schedules.
+ */
+func getEnabledSchedules() ([]models.HouseRainSchedule, error) {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"house_rain_db",
+			"getEnabledSchedules",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	schedules := []models.HouseRainSchedule{}
+	if err := session.Where(
+		"enabled = ?",
+		true,
+	).Order(
+		"id",
+	).Find(&schedules).Error; err != nil {
+		return nil, utils.MakeError(
+			"house_rain_db",
+			"getEnabledSchedules",
+			"failed to retrieve schedules",
+			err,
+		)
+	}
+
+	return schedules, nil
+}
+
+/**
+* @Internal
+* Returns house rain schedule by id.
+* Returns error on
+*  - Schedule not found. `ErrCodeScheduleNotFound`
+ */
+func getSchedule(scheduleID uint) (*models.HouseRainSchedule, error) {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"house_rain_db",
+			"getSchedule",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	schedule := models.HouseRainSchedule{}
+	if err := session.First(
+		&schedule,
+		scheduleID,
+	).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, utils.MakeErrorWithCode(
+			"house_rain_db",
+			"getSchedule",
+			"schedule not found",
+			ErrCodeScheduleNotFound,
+			fmt.Errorf("scheduleID: %d", scheduleID),
+		)
+	} else if err != nil {
+		return nil, utils.MakeError(
+			"house_rain_db",
+			"getSchedule",
+			"failed to retrieve schedule",
+			fmt.Errorf(
+				"scheduleID: %d, err: %v",
+				scheduleID, err,
+			),
+		)
+	}
+
+	return &schedule, nil
+}
+
+/**
+* @Internal
+* Creates or updates house rain schedule.
+ */
+func saveSchedule(schedule *models.HouseRainSchedule) error {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return utils.MakeError(
+			"house_rain_db",
+			"saveSchedule",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	if schedule.ID != 0 {
+		if _, err := getSchedule(schedule.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := session.Save(schedule).Error; err != nil {
+		return utils.MakeError(
+			"house_rain_db",
+			"saveSchedule",
+			"failed to save schedule",
+			fmt.Errorf(
+				"schedule: %v, err: %v",
+				*schedule, err,
+			),
+		)
+	}
+
+	return nil
+}
+
+/**
+* @Internal
+* Returns sum of house rain amounts performed since `from`.
+ */
+func getPerformedAmountSince(
+	from time.Time,
+	sessionId ...db_aggregator.UUID,
+) (int64, error) {
+	session, err := db_aggregator.GetSession(sessionId...)
+	if err != nil {
+		return 0, utils.MakeError(
+			"house_rain_db",
+			"getPerformedAmountSince",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	var amount int64
+	if err := session.Model(
+		&models.HouseRain{},
+	).Where(
+		"status = ?",
+		models.HouseRainPerformed,
+	).Where(
+		"created_at >= ?",
+		from,
+	).Select(
+		"coalesce(sum(amount), 0)",
+	).Scan(&amount).Error; err != nil {
+		return 0, utils.MakeError(
+			"house_rain_db",
+			"getPerformedAmountSince",
+			"failed to sum performed amount",
+			fmt.Errorf(
+				"from: %v, err: %v",
+				from, err,
+			),
+		)
+	}
+
+	return amount, nil
+}
+
+/**
+* @Internal
+* Records house rain result, in the session if provided.
+ */
+func recordHouseRain(
+	houseRain *models.HouseRain,
+	sessionId ...db_aggregator.UUID,
+) error {
+	session, err := db_aggregator.GetSession(sessionId...)
+	if err != nil {
+		return utils.MakeError(
+			"house_rain_db",
+			"recordHouseRain",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	if err := session.Create(houseRain).Error; err != nil {
+		return utils.MakeError(
+			"house_rain_db",
+			"recordHouseRain",
+			"failed to create house rain record",
+			fmt.Errorf(
+				"houseRain: %v, err: %v",
+				*houseRain, err,
+			),
+		)
+	}
+
+	return nil
+}
+
+/**
+* @Internal
+* Returns budget of the latest day, which is carried over to a new day.
+* Returns `HOUSE_RAIN_DAILY_BUDGET` if no budget has been set yet.
+ */
+func getLatestBudget(sessionId ...db_aggregator.UUID) (int64, error) {
+	session, err := db_aggregator.GetSession(sessionId...)
+	if err != nil {
+		return 0, utils.MakeError(
+			"house_rain_db",
+			"getLatestBudget",
+			"failed to retrieve session",
+			err,
+		)
+	}
+
+	latest := models.HouseRainBudget{}
+	if err := session.Order(
+		"day desc",
+	).First(&latest).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+		return config.HOUSE_RAIN_DAILY_BUDGET, nil
+	} else if err != nil {
+		return 0, utils.MakeError(
+			"house_rain_db",
+			"getLatestBudget",
+			"failed to retrieve latest budget",
+			err,
+		)
+	}
+
+	return latest.Budget, nil
+}
+
+/**
+* @Internal
+* Locks and retrieves budget of the day of `now` in the session,
+* creating it on the first call of the day.
+* Spent of a new day starts from house rains performed on the day
+* before it was created.
+ */
+func lockDailyBudget(
+	now time.Time,
+	sessionId db_aggregator.UUID,
+) (*models.HouseRainBudget, error) {
+	// 1. Retrieve session.
+	session, err := db_aggregator.GetSession(sessionId)
+	if err != nil {
+		return nil, utils.MakeError(
+			"house_rain_db",
+			"lockDailyBudget",
+			"failed to retrieve session",
+			err,
+		)
+	}
+
+	// 2. Create budget of the day if not exists.
+	day := getBudgetDay(now)
+	budget, err := getLatestBudget(sessionId)
+	if err != nil {
+		return nil, utils.MakeError(
+			"house_rain_db",
+			"lockDailyBudget",
+			"failed to retrieve latest budget",
+			err,
+		)
+	}
+	spent, err := getPerformedAmountSince(
+		getBudgetDayStart(now),
+		sessionId,
+	)
+	if err != nil {
+		return nil, utils.MakeError(
+			"house_rain_db",
+			"lockDailyBudget",
+			"failed to retrieve spent amount",
+			err,
+		)
+	}
+	if err := session.Clauses(
+		clause.OnConflict{DoNothing: true},
+	).Create(&models.HouseRainBudget{
+		Day:    day,
+		Budget: budget,
+		Spent:  spent,
+	}).Error; err != nil {
+		return nil, utils.MakeError(
+			"house_rain_db",
+			"lockDailyBudget",
+			"failed to create daily budget",
+			fmt.Errorf(
+				"day: %s, err: %v",
+				day, err,
+			),
+		)
+	}
+
+	// 3. Lock and retrieve budget of the day.
+	dailyBudget := models.HouseRainBudget{}
+	if err := session.Clauses(
+		clause.Locking{
+			Strength: "UPDATE",
+			Table:    clause.Table{Name: clause.CurrentTable},
+		},
+	).Where(
+		"day = ?",
+		day,
+	).First(&dailyBudget).Error; err != nil {
+		return nil, utils.MakeError(
+			"house_rain_db",
+			"lockDailyBudget",
+			"failed to lock daily budget",
+			fmt.Errorf(
+				"day: %s, err: %v",
+				day, err,
+			),
+		)
+	}
+
+	return &dailyBudget, nil
+}
+
+/**
+* @Internal
+* Updates budget of the locked daily budget in the session.
+ */
+func updateDailyBudget(
+	budgetID uint,
+	budget int64,
+	sessionId db_aggregator.UUID,
+) error {
+	session, err := db_aggregator.GetSession(sessionId)
+	if err != nil {
+		return utils.MakeError(
+			"house_rain_db",
+			"updateDailyBudget",
+			"failed to retrieve session",
+			err,
+		)
+	}
+
+	if err := session.Model(
+		&models.HouseRainBudget{},
+	).Where(
+		"id = ?",
+		budgetID,
+	).Update(
+		"budget",
+		budget,
+	).Error; err != nil {
+		return utils.MakeError(
+			"house_rain_db",
+			"updateDailyBudget",
+			"failed to update budget",
+			fmt.Errorf(
+				"budgetID: %d, budget: %d, err: %v",
+				budgetID, budget, err,
+			),
+		)
+	}
+
+	return nil
+}
+
+/**
+* @Internal
+* Adds `amount` to spent of the locked daily budget in the session.
+ */
+func addDailySpent(
+	budgetID uint,
+	amount int64,
+	sessionId db_aggregator.UUID,
+) error {
+	session, err := db_aggregator.GetSession(sessionId)
+	if err != nil {
+		return utils.MakeError(
+			"house_rain_db",
+			"addDailySpent",
+			"failed to retrieve session",
+			err,
+		)
+	}
+
+	if err := session.Model(
+		&models.HouseRainBudget{},
+	).Where(
+		"id = ?",
+		budgetID,
+	).Update(
+		"spent",
+		gorm.Expr("spent + ?", amount),
+	).Error; err != nil {
+		return utils.MakeError(
+			"house_rain_db",
+			"addDailySpent",
+			"failed to update spent",
+			fmt.Errorf(
+				"budgetID: %d, amount: %d, err: %v",
+				budgetID, amount, err,
+			),
+		)
+	}
+
+	return nil
+}
+
+/**
+* @Internal
+* Returns latest house rain records.
+ */
+func getHouseRainHistory(limit int) ([]models.HouseRain, error) {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"house_rain_db",
+			"getHouseRainHistory",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	houseRains := []models.HouseRain{}
+	if err := session.Order(
+		"id desc",
+	).Limit(limit).Find(&houseRains).Error; err != nil {
+		return nil, utils.MakeError(
+			"house_rain_db",
+			"getHouseRainHistory",
+			"failed to retrieve house rains",
+			err,
+		)
+	}
+
+	return houseRains, nil
+}
+
+/**
+* @Internal
+* Returns users with statistics of provided ids.
+ */
+func getUsersWithStatistics(userIDs []uint) ([]models.User, error) {
+	if len(userIDs) == 0 {
+		return []models.User{}, nil
+	}
+
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"house_rain_db",
+			"getUsersWithStatistics",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	users := []models.User{}
+	if err := session.Preload(
+		"Statistics",
+	).Where(
+		"id in ?",
+		userIDs,
+	).Find(&users).Error; err != nil {
+		return nil, utils.MakeError(
+			"house_rain_db",
+			"getUsersWithStatistics",
+			"failed to retrieve users",
+			fmt.Errorf(
+				"userIDs: %v, err: %v",
+				userIDs, err,
+			),
+		)
+	}
+
+	return users, nil
+}
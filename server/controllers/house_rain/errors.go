@@ -0,0 +1,9 @@
+package house_rain
+
+// Error code range: #109xxx
+const ErrCodeBase = "#109"
+const ErrCodeInvalidParameter = ErrCodeBase + "000"
+const ErrCodeInvalidCronSpec = ErrCodeBase + "001"
+const ErrCodeDailyBudgetExceeded = ErrCodeBase + "002"
+const ErrCodeNotEnoughRecipients = ErrCodeBase + "003"
+const ErrCodeScheduleNotFound = ErrCodeBase + "004"
@@ -0,0 +1,143 @@
+package house_rain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+)
+
+func TestGetVipLevel(t *testing.T) {
+	thresholds := config.VIP_LEVEL_WAGER_THRESHOLDS
+	if getVipLevel(0) != 0 {
+		t.Fatalf("zero wagered should be level 0")
+	}
+	if getVipLevel(thresholds[0]-1) != 0 {
+		t.Fatalf("below first threshold should be level 0")
+	}
+	if getVipLevel(thresholds[0]) != 1 {
+		t.Fatalf("first threshold should be level 1")
+	}
+	last := thresholds[len(thresholds)-1]
+	if getVipLevel(last*2) != uint(len(thresholds)) {
+		t.Fatalf("above last threshold should be max level")
+	}
+}
+
+func TestIsEligibleRecipient(t *testing.T) {
+	schedule := models.HouseRainSchedule{
+		MinWager:    utils.ConvertChipToBalance(10),
+		MinVipLevel: 1,
+	}
+	eligible := RecipientCandidate{
+		UserID:       1,
+		TotalWagered: config.VIP_LEVEL_WAGER_THRESHOLDS[0],
+		DailyWagered: utils.ConvertChipToBalance(10),
+	}
+	if !isEligibleRecipient(eligible, schedule) {
+		t.Fatalf("candidate satisfying all rules should be eligible")
+	}
+
+	lowWager := eligible
+	lowWager.DailyWagered--
+	excluded := eligible
+	excluded.Excluded = true
+	banned := eligible
+	banned.Banned = true
+	lowVip := eligible
+	lowVip.TotalWagered--
+	for name, candidate := range map[string]RecipientCandidate{
+		"low wager": lowWager,
+		"excluded":  excluded,
+		"banned":    banned,
+		"low vip":   lowVip,
+	} {
+		if isEligibleRecipient(candidate, schedule) {
+			t.Fatalf("%s candidate should not be eligible", name)
+		}
+	}
+}
+
+func TestIsInBudget(t *testing.T) {
+	if !isInBudget(0, 100, 100) {
+		t.Fatalf("amount equal to budget should be allowed")
+	}
+	if isInBudget(50, 51, 100) {
+		t.Fatalf("exceeding budget should not be allowed")
+	}
+	if isInBudget(0, 0, 100) {
+		t.Fatalf("zero amount should not be allowed")
+	}
+}
+
+func TestGetCountdownNotices(t *testing.T) {
+	config.HOUSE_RAIN_COUNTDOWN_NOTICES = []uint{10, 60, 30}
+	if notices := getCountdownNotices(0); len(notices) != 0 {
+		t.Fatalf("zero countdown should have no notice: %v", notices)
+	}
+	if notices := getCountdownNotices(45); !reflect.DeepEqual(
+		notices,
+		[]uint{45, 30, 10},
+	) {
+		t.Fatalf("unexpected notices: %v", notices)
+	}
+	if notices := getCountdownNotices(120); !reflect.DeepEqual(
+		notices,
+		[]uint{120, 60, 30, 10},
+	) {
+		t.Fatalf("unexpected notices: %v", notices)
+	}
+}
+
+func TestBuildScheduleFromRequest(t *testing.T) {
+	request := ScheduleRequest{
+		Name:       "hourly",
+		CronSpec:   "0 * * * *",
+		Amount:     100,
+		SplitCount: 10,
+	}
+	schedule, err := buildScheduleFromRequest(request)
+	if err != nil {
+		t.Fatalf("failed to build schedule: %v", err)
+	}
+	if schedule.Amount != utils.ConvertChipToBalance(100) {
+		t.Fatalf("amount should be converted to balance: %d", schedule.Amount)
+	}
+
+	invalidCron := request
+	invalidCron.CronSpec = "every hour"
+	if _, err := buildScheduleFromRequest(invalidCron); !utils.IsErrorCode(
+		err,
+		ErrCodeInvalidCronSpec,
+	) {
+		t.Fatalf("invalid cron spec should be rejected: %v", err)
+	}
+
+	noSplit := request
+	noSplit.SplitCount = 0
+	if _, err := buildScheduleFromRequest(noSplit); !utils.IsErrorCode(
+		err,
+		ErrCodeInvalidParameter,
+	) {
+		t.Fatalf("zero split count should be rejected: %v", err)
+	}
+}
+
+func TestGetBudgetDay(t *testing.T) {
+	location := time.FixedZone("UTC+9", 9*60*60)
+	at := time.Date(2026, 10, 19, 23, 59, 59, 0, location)
+	if day := getBudgetDay(at); day != "2026-10-19" {
+		t.Fatalf("unexpected budget day: %s", day)
+	}
+	if start := getBudgetDayStart(at); !start.Equal(
+		time.Date(2026, 10, 19, 0, 0, 0, 0, location),
+	) {
+		t.Fatalf("unexpected start of budget day: %v", start)
+	}
+	if day := getBudgetDay(at.Add(time.Second)); day != "2026-10-20" {
+		t.Fatalf("next second should belong to next day: %s", day)
+	}
+}
@@ -0,0 +1,19 @@
+package house_rain
+
+import (
+	"github.com/Duelana-Team/duelana-v1/types"
+)
+
+/**
+* @External
+* Initializes house_rain module.
+* `isActiveUser` reports whether the user is online in chat.
+ */
+func Initialize(
+	eventEmitter chan types.WSEvent,
+	isActiveUser func(uint) bool,
+) error {
+	initSocket(eventEmitter)
+	initActiveUserChecker(isActiveUser)
+	return initScheduler()
+}
@@ -0,0 +1,81 @@
+package house_rain
+
+import (
+	"context"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/controllers/transaction/db_aggregator"
+	"github.com/Duelana-Team/duelana-v1/utils"
+)
+
+/**
+* @Internal
+* Updates daily budget of house rains from today, persisted on the
+* locked budget of today so that a rain in progress is not affected.
+* This function can be called from admin api handler.
+ */
+func setDailyBudget(budget int64) error {
+	return db_aggregator.WithTx(
+		context.Background(),
+		func(ctx context.Context) error {
+			sessionId := db_aggregator.SessionIdFromContext(ctx)
+			dailyBudget, err := lockDailyBudget(time.Now(), sessionId)
+			if err != nil {
+				return utils.MakeError(
+					"house_rain",
+					"setDailyBudget",
+					"failed to lock daily budget",
+					err,
+				)
+			}
+			return updateDailyBudget(dailyBudget.ID, budget, sessionId)
+		},
+	)
+}
+
+/**
+* @Internal
+* Gets daily budget of house rains.
+ */
+func getDailyBudget() (int64, error) {
+	return getLatestBudget()
+}
+
+/**
+* @Internal
+* Returns key of the daily budget `at` belongs to.
+ */
+func getBudgetDay(at time.Time) string {
+	return at.Format("2006-01-02")
+}
+
+/**
+* @Internal
+* Returns start of the day `at` belongs to.
+ */
+func getBudgetDayStart(at time.Time) time.Time {
+	return time.Date(
+		at.Year(),
+		at.Month(),
+		at.Day(),
+		0, 0, 0, 0,
+		at.Location(),
+	)
+}
+
+/**
+* @Internal
+* Returns VIP level of the user from total wagered amount.
+* Level is the count of `VIP_LEVEL_WAGER_THRESHOLDS` reached.
+ */
+func getVipLevel(totalWagered int64) uint {
+	level := uint(0)
+	for _, threshold := range config.VIP_LEVEL_WAGER_THRESHOLDS {
+		if totalWagered < threshold {
+			break
+		}
+		level++
+	}
+	return level
+}
@@ -0,0 +1,289 @@
+package house_rain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/controllers/redis"
+	"github.com/Duelana-Team/duelana-v1/controllers/self_exclusion"
+	"github.com/Duelana-Team/duelana-v1/controllers/transaction"
+	"github.com/Duelana-Team/duelana-v1/controllers/transaction/db_aggregator"
+	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+var isActiveUser func(uint) bool = nil
+
+/**
+* @Internal
+* Initializes checker to determine whether the user is online in chat.
+ */
+func initActiveUserChecker(checker func(uint) bool) {
+	isActiveUser = checker
+}
+
+/**
+* @Internal
+* Performs house rain of the schedule and records the result.
+* Rain is skipped when the daily budget is exceeded or there is
+* no eligible recipient.
+ */
+func performHouseRain(schedule models.HouseRainSchedule) (*models.HouseRain, error) {
+	amountForEach, recipients, houseRain, err := distributeHouseRain(schedule)
+	if err != nil {
+		// Performed record was rolled back with the transfer.
+		houseRain = &models.HouseRain{
+			ScheduleID: schedule.ID,
+			Amount:     schedule.Amount,
+			Recipients: pq.Int64Array{},
+			Status:     models.HouseRainFailed,
+			Reason:     err.Error(),
+		}
+		if utils.IsErrorCode(err, ErrCodeDailyBudgetExceeded) ||
+			utils.IsErrorCode(err, ErrCodeNotEnoughRecipients) {
+			houseRain.Status = models.HouseRainSkipped
+		}
+		if recordErr := recordHouseRain(houseRain); recordErr != nil {
+			log.LogMessage(
+				"house_rain_performHouseRain",
+				"failed to record house rain",
+				"error",
+				logrus.Fields{
+					"scheduleID": schedule.ID,
+					"error":      recordErr.Error(),
+				},
+			)
+		}
+		return houseRain, err
+	}
+
+	if err := broadcastHouseRain(
+		schedule,
+		amountForEach,
+		recipients,
+	); err != nil {
+		log.LogMessage(
+			"house_rain_performHouseRain",
+			"failed to broadcast house rain",
+			"error",
+			logrus.Fields{
+				"scheduleID": schedule.ID,
+				"error":      err.Error(),
+			},
+		)
+	}
+
+	return houseRain, nil
+}
+
+/**
+* @Internal
+* Selects recipients, and checks daily budget, transfers chips from
+* house rain wallet and records the rain in a unit of work.
+* Budget of the day is locked until commit, so that concurrent rains
+* can not overspend it.
+* Returns amount for each recipient, recipients and performed record.
+* Returns error on
+*  - Daily budget exceeded. `ErrCodeDailyBudgetExceeded`
+*  - No eligible recipient. `ErrCodeNotEnoughRecipients`
+ */
+func distributeHouseRain(
+	schedule models.HouseRainSchedule,
+) (int64, []models.User, *models.HouseRain, error) {
+	// 1. Select recipients.
+	recipients, err := selectRecipients(schedule)
+	if err != nil {
+		return 0, nil, nil, utils.MakeError(
+			"house_rain",
+			"distributeHouseRain",
+			"failed to select recipients",
+			err,
+		)
+	}
+	if len(recipients) == 0 {
+		return 0, nil, nil, utils.MakeErrorWithCode(
+			"house_rain",
+			"distributeHouseRain",
+			"no eligible recipient",
+			ErrCodeNotEnoughRecipients,
+			fmt.Errorf("scheduleID: %d", schedule.ID),
+		)
+	}
+
+	amountForEach := schedule.Amount / int64(len(recipients))
+	houseRain := models.HouseRain{
+		ScheduleID: schedule.ID,
+		Amount:     amountForEach * int64(len(recipients)),
+		Recipients: pq.Int64Array{},
+		Status:     models.HouseRainPerformed,
+	}
+	toUsers := []db_aggregator.User{}
+	for _, recipient := range recipients {
+		toUsers = append(toUsers, db_aggregator.User(recipient.ID))
+		houseRain.Recipients = append(
+			houseRain.Recipients,
+			int64(recipient.ID),
+		)
+	}
+
+	if err := db_aggregator.WithTx(
+		context.Background(),
+		func(ctx context.Context) error {
+			sessionId := db_aggregator.SessionIdFromContext(ctx)
+
+			// 2. Lock and check daily budget.
+			dailyBudget, err := lockDailyBudget(time.Now(), sessionId)
+			if err != nil {
+				return utils.MakeError(
+					"house_rain",
+					"distributeHouseRain",
+					"failed to lock daily budget",
+					err,
+				)
+			}
+			if !isInBudget(
+				dailyBudget.Spent,
+				schedule.Amount,
+				dailyBudget.Budget,
+			) {
+				return utils.MakeErrorWithCode(
+					"house_rain",
+					"distributeHouseRain",
+					"daily budget exceeded",
+					ErrCodeDailyBudgetExceeded,
+					fmt.Errorf(
+						"spent: %d, amount: %d, budget: %d",
+						dailyBudget.Spent, schedule.Amount, dailyBudget.Budget,
+					),
+				)
+			}
+
+			// 3. Transfer from house rain wallet.
+			fromUser := db_aggregator.User(config.HOUSE_RAIN_TEMP_ID)
+			tx, err := transaction.RainInSession(
+				&transaction.RainRequest{
+					FromUser: &fromUser,
+					ToUsers:  &toUsers,
+					Balance: db_aggregator.BalanceLoad{
+						ChipBalance: &amountForEach,
+					},
+					Type: models.TxHouseRain,
+				},
+				sessionId,
+			)
+			if err != nil {
+				return utils.MakeError(
+					"house_rain",
+					"distributeHouseRain",
+					"failed to distribute funds",
+					fmt.Errorf(
+						"amountForEach: %d, recipients: %v, err: %v",
+						amountForEach, toUsers, err,
+					),
+				)
+			}
+
+			// 4. Spend daily budget and record the rain.
+			if err := addDailySpent(
+				dailyBudget.ID,
+				houseRain.Amount,
+				sessionId,
+			); err != nil {
+				return utils.MakeError(
+					"house_rain",
+					"distributeHouseRain",
+					"failed to spend daily budget",
+					err,
+				)
+			}
+			txID := uint(*tx)
+			houseRain.TransactionID = &txID
+			if err := recordHouseRain(&houseRain, sessionId); err != nil {
+				return utils.MakeError(
+					"house_rain",
+					"distributeHouseRain",
+					"failed to record house rain",
+					err,
+				)
+			}
+			return nil
+		},
+	); err != nil {
+		return 0, nil, nil, err
+	}
+
+	return amountForEach, recipients, &houseRain, nil
+}
+
+/**
+* @Internal
+* Selects up to `SplitCount` random recipients among recently
+* wagered users online in chat, satisfying schedule rules.
+ */
+func selectRecipients(schedule models.HouseRainSchedule) ([]models.User, error) {
+	candidateIDs := []uint{}
+	for _, userID := range redis.ZRevRangeRecentlyWagered() {
+		if userID == config.HOUSE_RAIN_TEMP_ID {
+			continue
+		}
+		if isActiveUser != nil && !isActiveUser(userID) {
+			continue
+		}
+		candidateIDs = append(candidateIDs, userID)
+	}
+
+	users, err := getUsersWithStatistics(candidateIDs)
+	if err != nil {
+		return nil, err
+	}
+	utils.ShuffleSlice(users)
+
+	recipients := []models.User{}
+	for _, user := range users {
+		if len(recipients) >= int(schedule.SplitCount) {
+			break
+		}
+		if isEligibleRecipient(
+			RecipientCandidate{
+				UserID:       user.ID,
+				TotalWagered: user.Statistics.TotalWagered,
+				DailyWagered: redis.GetUserDailyWagered(user.ID, 0),
+				Excluded:     self_exclusion.ExclusionRemaining(user.ID) > 0,
+				Banned:       user.Banned,
+			},
+			schedule,
+		) {
+			recipients = append(recipients, user)
+		}
+	}
+
+	return recipients, nil
+}
+
+/**
+* @Internal
+* Checks whether the candidate satisfies recipient rules of the schedule.
+ */
+func isEligibleRecipient(
+	candidate RecipientCandidate,
+	schedule models.HouseRainSchedule,
+) bool {
+	return !candidate.Banned &&
+		!candidate.Excluded &&
+		candidate.DailyWagered >= schedule.MinWager &&
+		getVipLevel(candidate.TotalWagered) >= schedule.MinVipLevel
+}
+
+/**
+* @Internal
+* Checks whether `amount` can be spent in addition to `spent`
+* without exceeding `budget`.
+ */
+func isInBudget(spent int64, amount int64, budget int64) bool {
+	return amount > 0 && spent+amount <= budget
+}
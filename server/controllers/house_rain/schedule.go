@@ -0,0 +1,216 @@
+package house_rain
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+)
+
+var scheduler *cron.Cron = nil
+var schedulerMutex sync.Mutex
+
+/**
+* @Internal
+* Starts cron scheduler with enabled house rain schedules.
+* This function is called on module initialization.
+ */
+func initScheduler() error {
+	schedulerMutex.Lock()
+	scheduler = cron.New()
+	scheduler.Start()
+	schedulerMutex.Unlock()
+
+	return reloadSchedules()
+}
+
+/**
+* @Internal
+* Replaces scheduled entries with enabled schedules in db.
+* This function should be called whenever a schedule is saved.
+ */
+func reloadSchedules() error {
+	schedules, err := getEnabledSchedules()
+	if err != nil {
+		return utils.MakeError(
+			"house_rain_schedule",
+			"reloadSchedules",
+			"failed to retrieve enabled schedules",
+			err,
+		)
+	}
+
+	schedulerMutex.Lock()
+	defer schedulerMutex.Unlock()
+	if scheduler == nil {
+		return nil
+	}
+
+	for _, entry := range scheduler.Entries() {
+		scheduler.Remove(entry.ID)
+	}
+	for _, schedule := range schedules {
+		scheduleID := schedule.ID
+		if _, err := scheduler.AddFunc(
+			schedule.CronSpec,
+			func() { triggerSchedule(scheduleID) },
+		); err != nil {
+			log.LogMessage(
+				"house_rain_reloadSchedules",
+				"failed to add schedule",
+				"error",
+				logrus.Fields{
+					"scheduleID": scheduleID,
+					"cronSpec":   schedule.CronSpec,
+					"error":      err.Error(),
+				},
+			)
+		}
+	}
+
+	return nil
+}
+
+/**
+* @Internal
+* Trigger function called by cron scheduler.
+* Announces countdown to the chat and performs house rain
+* `CountdownInSeconds` after the trigger.
+ */
+func triggerSchedule(scheduleID uint) {
+	// 1. Retrieve latest schedule, since it can be updated
+	// after being scheduled.
+	schedule, err := getSchedule(scheduleID)
+	if err != nil {
+		log.LogMessage(
+			"house_rain_triggerSchedule",
+			"failed to retrieve schedule",
+			"error",
+			logrus.Fields{
+				"scheduleID": scheduleID,
+				"error":      err.Error(),
+			},
+		)
+		return
+	}
+	if !schedule.Enabled {
+		return
+	}
+
+	// 2. Announce countdown.
+	rainAt := time.Now().Add(
+		time.Second * time.Duration(schedule.CountdownInSeconds),
+	)
+	for _, remaining := range getCountdownNotices(schedule.CountdownInSeconds) {
+		time.Sleep(time.Until(
+			rainAt.Add(-time.Second * time.Duration(remaining)),
+		))
+		if err := broadcastCountdown(*schedule, remaining); err != nil {
+			log.LogMessage(
+				"house_rain_triggerSchedule",
+				"failed to broadcast countdown",
+				"error",
+				logrus.Fields{
+					"scheduleID": scheduleID,
+					"error":      err.Error(),
+				},
+			)
+		}
+	}
+	time.Sleep(time.Until(rainAt))
+
+	// 3. Perform house rain.
+	if houseRain, err := performHouseRain(*schedule); err != nil {
+		log.LogMessage(
+			"house_rain_triggerSchedule",
+			"house rain not performed",
+			"info",
+			logrus.Fields{
+				"scheduleID": scheduleID,
+				"error":      err.Error(),
+			},
+		)
+	} else {
+		log.LogMessage(
+			"house_rain_triggerSchedule",
+			"house rain performed",
+			"success",
+			logrus.Fields{
+				"scheduleID": scheduleID,
+				"amount":     houseRain.Amount,
+				"recipients": houseRain.Recipients,
+			},
+		)
+	}
+}
+
+/**
+* @Internal
+* Returns remaining seconds to be announced before house rain in
+* descending order. The whole countdown is always announced first,
+* followed by `HOUSE_RAIN_COUNTDOWN_NOTICES` shorter than it.
+ */
+func getCountdownNotices(countdown uint) []uint {
+	if countdown == 0 {
+		return []uint{}
+	}
+
+	notices := []uint{countdown}
+	for _, notice := range config.HOUSE_RAIN_COUNTDOWN_NOTICES {
+		if notice > 0 && notice < countdown {
+			notices = append(notices, notice)
+		}
+	}
+	sort.Slice(notices, func(i, j int) bool {
+		return notices[i] > notices[j]
+	})
+
+	return notices
+}
+
+/**
+* @Internal
+* Validates schedule request and converts it to schedule model.
+* Amount and minimum wager are provided in chips.
+ */
+func buildScheduleFromRequest(request ScheduleRequest) (*models.HouseRainSchedule, error) {
+	if request.Name == "" ||
+		request.Amount <= 0 ||
+		request.SplitCount == 0 ||
+		request.MinWager < 0 {
+		return nil, utils.MakeErrorWithCode(
+			"house_rain_schedule",
+			"buildScheduleFromRequest",
+			"invalid parameter",
+			ErrCodeInvalidParameter,
+			errors.New("missing or invalid schedule field"),
+		)
+	}
+	if _, err := cron.ParseStandard(request.CronSpec); err != nil {
+		return nil, utils.MakeErrorWithCode(
+			"house_rain_schedule",
+			"buildScheduleFromRequest",
+			"invalid cron spec",
+			ErrCodeInvalidCronSpec,
+			err,
+		)
+	}
+
+	return &models.HouseRainSchedule{
+		Name:               request.Name,
+		CronSpec:           request.CronSpec,
+		Amount:             utils.ConvertChipToBalance(request.Amount),
+		SplitCount:         request.SplitCount,
+		MinWager:           utils.ConvertChipToBalance(request.MinWager),
+		MinVipLevel:        request.MinVipLevel,
+		CountdownInSeconds: request.CountdownInSeconds,
+		Enabled:            request.Enabled,
+	}, nil
+}
@@ -0,0 +1,127 @@
+package house_rain
+
+import (
+	"encoding/json"
+
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/types"
+	"github.com/Duelana-Team/duelana-v1/utils"
+)
+
+var EventEmitter chan types.WSEvent
+
+/*
+* @Internal
+* Initialize socket event emitter.
+ */
+func initSocket(eventEmitter chan types.WSEvent) {
+	EventEmitter = eventEmitter
+}
+
+/**
+* @Internal
+* Broadcasts remaining seconds till the house rain to the chat room.
+ */
+func broadcastCountdown(schedule models.HouseRainSchedule, remaining uint) error {
+	return broadcastToChat(
+		"house_rain_countdown",
+		CountdownPayload{
+			ScheduleID: schedule.ID,
+			Amount:     schedule.Amount,
+			Remaining:  remaining,
+		},
+	)
+}
+
+/**
+* @Internal
+* Broadcasts performed house rain to the chat room, and sends
+* balance update events to recipients.
+ */
+func broadcastHouseRain(
+	schedule models.HouseRainSchedule,
+	amountForEach int64,
+	recipients []models.User,
+) error {
+	recipientIDs := []uint{}
+	recipientData := []types.User{}
+	for _, recipient := range recipients {
+		recipientIDs = append(recipientIDs, recipient.ID)
+		recipientData = append(
+			recipientData,
+			utils.GetUserDataWithPermissions(
+				recipient,
+				nil,
+				0,
+			),
+		)
+	}
+
+	if err := broadcastToChat(
+		"house_rain",
+		HouseRainPayload{
+			ScheduleID:    schedule.ID,
+			Amount:        amountForEach * int64(len(recipients)),
+			AmountForEach: amountForEach,
+			Recipients:    recipientData,
+		},
+	); err != nil {
+		return err
+	}
+
+	if EventEmitter == nil {
+		return nil
+	}
+	b, err := json.Marshal(types.WSMessage{
+		EventType: "balance_update",
+		Payload: types.BalanceUpdatePayload{
+			UpdateType:  types.Increase,
+			Balance:     amountForEach,
+			BalanceType: models.ChipBalanceForGame,
+			Delay:       0,
+		},
+	})
+	if err != nil {
+		return utils.MakeError(
+			"house_rain_socket",
+			"broadcastHouseRain",
+			"failed to marshal json",
+			err,
+		)
+	}
+	EventEmitter <- types.WSEvent{
+		Users:   recipientIDs,
+		Message: b,
+	}
+	return nil
+}
+
+/**
+* @Internal
+* Broadcasts event to the chat room.
+ */
+func broadcastToChat(eventType string, payload interface{}) error {
+	if EventEmitter == nil {
+		return nil
+	}
+
+	b, err := json.Marshal(types.WSMessage{
+		Room:      string(types.Chat),
+		EventType: eventType,
+		Payload:   payload,
+	})
+	if err != nil {
+		return utils.MakeError(
+			"house_rain_socket",
+			"broadcastToChat",
+			"failed to marshal json",
+			err,
+		)
+	}
+
+	EventEmitter <- types.WSEvent{
+		Room:    types.Chat,
+		Message: b,
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package house_rain
+
+import (
+	"github.com/Duelana-Team/duelana-v1/types"
+)
+
+type ScheduleRequest struct {
+	Name               string `json:"name"`
+	CronSpec           string `json:"cronSpec"`
+	Amount             int64  `json:"amount"`
+	SplitCount         uint   `json:"splitCount"`
+	MinWager           int64  `json:"minWager"`
+	MinVipLevel        uint   `json:"minVipLevel"`
+	CountdownInSeconds uint   `json:"countdownInSeconds"`
+	Enabled            bool   `json:"enabled"`
+}
+
+/**
+* Candidate of house rain recipients with the data
+* required to check recipient rules.
+ */
+type RecipientCandidate struct {
+	UserID       uint
+	TotalWagered int64
+	DailyWagered int64
+	Excluded     bool
+	Banned       bool
+}
+
+type CountdownPayload struct {
+	ScheduleID uint  `json:"scheduleId"`
+	Amount     int64 `json:"amount"`
+	Remaining  uint  `json:"remaining"`
+}
+
+type HouseRainPayload struct {
+	ScheduleID    uint         `json:"scheduleId"`
+	Amount        int64        `json:"amount"`
+	AmountForEach int64        `json:"amountForEach"`
+	Recipients    []types.User `json:"recipients"`
+}
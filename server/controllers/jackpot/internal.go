@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/controllers/game_settings"
+	"github.com/Duelana-Team/duelana-v1/controllers/nft_collection"
 	"github.com/Duelana-Team/duelana-v1/controllers/transaction"
 	"github.com/Duelana-Team/duelana-v1/controllers/transaction/db_aggregator"
 	"github.com/Duelana-Team/duelana-v1/controllers/wager"
@@ -19,6 +21,26 @@ import (
 	"golang.org/x/sync/syncmap"
 )
 
+/**
+* @Internal
+* Applies runtime settings when a new version has been saved.
+* Only called between rounds, so a running round is never affected.
+ */
+func (c *Controller) reloadSettings() {
+	settings, version := game_settings.GetJackpotSettings(c.Type)
+	if version == c.settingsVersion {
+		return
+	}
+	c.minBetAmount = settings.MinBetAmount
+	c.maxBetAmount = settings.MaxBetAmount
+	c.betCountLimit = settings.BetCountLimit
+	c.playerLimit = settings.PlayerLimit
+	c.baseCountingTime = settings.CountingTime
+	c.rollingTime = settings.RollingTime
+	c.fee = settings.Fee
+	c.settingsVersion = version
+}
+
 func (c *Controller) setAvailable() {
 	c.status = Available
 	c.roundID = 0
@@ -38,7 +60,8 @@ func (c *Controller) setAvailable() {
 	c.totalFee = 0
 	c.rollingDuration = 0
 	c.candidates = []types.User{}
-	c.countingTime = config.JACKPOT_COUNTING_TIME
+	c.reloadSettings()
+	c.countingTime = c.baseCountingTime
 
 	b, _ := json.Marshal(types.WSMessage{
 		Room:      string(c.Type),
@@ -82,7 +105,7 @@ func (c *Controller) create(roundID uint, ticketID string) {
 		EventType: string(c.status),
 		Payload:   types.JackpotPayload{RoundID: c.roundID, TicketID: c.ticketID}})
 	c.EventEmitter <- types.WSEvent{Room: c.Room, Message: b}
-	log.LogMessage("jackpot controller", "new round created", "info", logrus.Fields{"round": c.roundID, "ticket": *c.ticketID})
+	log.LogMessage("jackpot controller", "new round created", "info", log.WithRoundID(logrus.Fields{"round": c.roundID, "ticket": *c.ticketID}, "jackpot", c.roundID))
 }
 
 func (c *Controller) start(userID uint) {
@@ -109,7 +132,7 @@ func (c *Controller) start(userID uint) {
 		EventType: string(c.status),
 		Payload:   types.JackpotPayload{RoundID: c.roundID}})
 	c.EventEmitter <- types.WSEvent{Room: c.Room, Message: b}
-	log.LogMessage("jackpot controller", "round started", "info", logrus.Fields{"round": c.roundID})
+	log.LogMessage("jackpot controller", "round started", "info", log.WithRoundID(logrus.Fields{"round": c.roundID}, "jackpot", c.roundID))
 }
 
 func (c *Controller) end() {
@@ -170,7 +193,7 @@ func (c *Controller) checkRemainingTime() {
 					"countingTime": c.countingTime,
 				}})
 			c.EventEmitter <- types.WSEvent{Room: c.Room, Message: b}
-			log.LogMessage("jackpot controller", "betting delayed", "info", logrus.Fields{"round": c.roundID, "countingTime": c.countingTime})
+			log.LogMessage("jackpot controller", "betting delayed", "info", log.WithRoundID(logrus.Fields{"round": c.roundID, "countingTime": c.countingTime}, "jackpot", c.roundID))
 		}
 	}
 }
@@ -183,7 +206,7 @@ func (c *Controller) determineWinner() (utils.PickWinnerResult[uint], error) {
 			EventType: "message",
 			Payload:   types.ErrorMessagePayload{Message: "Failed to generate a random string"}})
 		c.EventEmitter <- types.WSEvent{Room: c.Room, Message: b}
-		log.LogMessage("jackpot controller", "failed to generate a random string", "error", logrus.Fields{"round": c.roundID, "ticket": *c.ticketID})
+		log.LogMessage("jackpot controller", "failed to generate a random string", "error", log.WithRoundID(logrus.Fields{"round": c.roundID, "ticket": *c.ticketID}, "jackpot", c.roundID))
 		return utils.PickWinnerResult[uint]{}, errors.New("failed to generate a random string")
 	}
 
@@ -211,7 +234,7 @@ func (c *Controller) updateDbAtRoundEnd() (int64, []types.NftDetails, int64, int
 			EventType: "message",
 			Payload:   types.ErrorMessagePayload{Message: "can not find started round in DB "}})
 		c.EventEmitter <- types.WSEvent{Room: c.Room, Message: b}
-		log.LogMessage("jackpot controller", "failed to get started Round from DB", "error", logrus.Fields{"round": c.roundID})
+		log.LogMessage("jackpot controller", "failed to get started Round from DB", "error", log.WithRoundID(logrus.Fields{"round": c.roundID}, "jackpot", c.roundID))
 		return 0, []types.NftDetails{}, 0, 0, []types.NftDetails{}, 0, 0, errors.New("failed to get started round from db")
 	}
 	usdProfit, nfts4Profit, totalProfit, usdFee, nfts4Fee, totalFee, totalAmount := c.calculateJackpots()
@@ -240,7 +263,7 @@ func (c *Controller) updateDbAtRoundEnd() (int64, []types.NftDetails, int64, int
 		OwnerType:     models.TransactionJackpotReferenced,
 	})
 	if err != nil {
-		log.LogMessage("jackpot controller", "failed to transfer fee", "error", logrus.Fields{"round": c.roundID, "error": err.Error()})
+		log.LogMessage("jackpot controller", "failed to transfer fee", "error", log.WithRoundID(logrus.Fields{"round": c.roundID, "error": err.Error()}, "jackpot", c.roundID))
 	}
 
 	return usdProfit, nfts4Profit, totalProfit, usdFee, nfts4Fee, totalFee, totalAmount, nil
@@ -325,7 +348,7 @@ func (c *Controller) prizeWinner(usdPrize int64, nfts4Profit []types.NftDetails)
 		OwnerType:     models.TransactionJackpotReferenced,
 	})
 	if err != nil {
-		log.LogMessage("jackpot controller", "failed to transfer profit to winner", "error", logrus.Fields{"round": c.roundID, "error": err.Error()})
+		log.LogMessage("jackpot controller", "failed to transfer profit to winner", "error", log.WithRoundID(logrus.Fields{"round": c.roundID, "error": err.Error()}, "jackpot", c.roundID))
 		return
 	}
 }
@@ -402,7 +425,7 @@ func (c *Controller) emitRoundEndEvent(winnedCandidate utils.PickWinnerResult[ui
 			RollingDuration: c.rollingDuration,
 		}})
 	c.EventEmitter <- types.WSEvent{Room: c.Room, Message: b}
-	log.LogMessage("jackpot controller", "round ended", "info", logrus.Fields{"round": c.roundID, "winner": map[string]any{"id": winner.ID, "name": winner.Name}})
+	log.LogMessage("jackpot controller", "round ended", "info", log.WithRoundID(logrus.Fields{"round": c.roundID, "winner": map[string]any{"id": winner.ID, "name": winner.Name}}, "jackpot", c.roundID))
 }
 
 func (c *Controller) emitErrMessageWithBalanceUpdate(message string, userID uint, betData BetData) {
@@ -428,3 +451,46 @@ func (c *Controller) emitErrMessageWithBalanceUpdate(message string, userID uint
 		c.EventEmitter <- types.WSEvent{Users: []uint{userID}, Message: b}
 	}
 }
+
+/**
+* @External
+* Values deposited NFTs of a bet from cached marketplace data.
+* Returns NFTs in game without bet id, their total price and
+* error object when a collection is not found.
+ */
+func ValuateNfts(nfts []models.DepositedNft) ([]models.NftInGame, int64, error) {
+	db := db.GetDB()
+	collections := make(map[uint]*models.NftCollection)
+	totalNftPrice := int64(0)
+	nftsInGame := []models.NftInGame{}
+	for _, nft := range nfts {
+		if _, prs := collections[nft.CollectionID]; !prs {
+			var collection models.NftCollection
+			if err := db.First(&collection, nft.CollectionID).Error; err != nil {
+				return nil, 0, utils.MakeError(
+					"jackpot_internal",
+					"ValuateNfts",
+					"failed to get collection",
+					err,
+				)
+			}
+			collections[nft.CollectionID] = &collection
+		}
+
+		collection := collections[nft.CollectionID]
+		valuation := nft_collection.ValuateNft(collection, nft.MintAddress)
+		nftsInGame = append(nftsInGame, models.NftInGame{
+			Name:            nft.Name,
+			MintAddress:     nft.MintAddress,
+			Image:           nft.Image,
+			CollectionName:  collection.Name,
+			CollectionImage: collection.Image,
+			Price:           valuation.Price,
+			FloorPrice:      valuation.FloorPrice,
+			ValuationMethod: valuation.Method,
+			RarityRank:      valuation.RarityRank,
+		})
+		totalNftPrice += valuation.Price
+	}
+	return nftsInGame, totalNftPrice, nil
+}
@@ -35,7 +35,7 @@ func (c *Controller) initLastRound() (bool, error) {
 		return false, nil
 	}
 
-	log.LogMessage("Jackpot Controller", "Found an old round", "info", logrus.Fields{"roundId": round.ID})
+	log.LogMessage("Jackpot Controller", "Found an old round", "info", log.WithRoundID(logrus.Fields{"round": round.ID}, "jackpot", round.ID))
 
 	// Information needs to be loaded
 	// - roundID
@@ -120,6 +120,7 @@ func (c *Controller) Init(minBetAmount int64, maxBetAmount int64, betCountLimit
 	c.betCountLimit = betCountLimit
 	c.playerLimit = playerLimit
 	c.countingTime = countingTime
+	c.baseCountingTime = countingTime
 	c.rollingTime = rollingTime
 	c.fee = fee
 	c.status = Available
@@ -197,8 +198,28 @@ func (c *Controller) Bet(userID uint, betData BetData) {
 		c.emitErrMessageWithBalanceUpdate("Please wait for the next round.", userID, betData)
 		return
 	}
+	if utils.IsDuplicateInArray(betData.Nfts) {
+		c.emitErrMessageWithBalanceUpdate("Duplicated NFTs.", userID, betData)
+		return
+	}
+
+	// Value NFTs from cache before checking limits, so that limits
+	// apply to the credited value rather than the one sent.
+	db := db.GetDB()
+	nftMintAddresses := []string{}
+	for i := 0; i < len(betData.Nfts); i++ {
+		nftMintAddresses = append(nftMintAddresses, betData.Nfts[i].MintAddress)
+	}
+	nfts := []models.DepositedNft{}
+	db.Where("mint_address IN ?", nftMintAddresses).Find(&nfts)
+	nftsInGame, totalNftPrice, err := ValuateNfts(nfts)
+	if err != nil || len(nfts) != len(nftMintAddresses) {
+		c.emitErrMessageWithBalanceUpdate("Can not find NFT", userID, betData)
+		return
+	}
+
 	playerBets, prs := c.playerToBets.Load(userID)
-	finalAmount := betData.Amount + betData.NftAmount
+	finalAmount := betData.Amount + totalNftPrice
 	if prs {
 		finalAmount += playerBets.(Bet4Player).TotalUsdAmount + playerBets.(Bet4Player).TotalNftAmount
 	}
@@ -207,10 +228,6 @@ func (c *Controller) Bet(userID uint, betData BetData) {
 		c.emitErrMessageWithBalanceUpdate("Exceed bet amount limit.", userID, betData)
 		return
 	}
-	if utils.IsDuplicateInArray(betData.Nfts) {
-		c.emitErrMessageWithBalanceUpdate("Duplicated NFTs.", userID, betData)
-		return
-	}
 
 	if !prs && c.totalPlayers == c.playerLimit {
 		c.emitErrMessageWithBalanceUpdate("Exceed total player limit.", userID, betData)
@@ -221,15 +238,9 @@ func (c *Controller) Bet(userID uint, betData BetData) {
 	}
 
 	var user models.User
-	db := db.GetDB()
 	db.First(&user, userID)
 	userInfo := utils.GetUserDataWithPermissions(user, nil, 0)
 
-	nftMintAddresses := []string{}
-	for i := 0; i < len(betData.Nfts); i++ {
-		nftMintAddresses = append(nftMintAddresses, betData.Nfts[i].MintAddress)
-	}
-
 	tx, err := transaction.Transfer(&transaction.TransactionRequest{
 		FromUser: (*db_aggregator.User)(&userID),
 		ToUser:   (*db_aggregator.User)(&config.JACKPOT_TEMP_ID),
@@ -238,6 +249,8 @@ func (c *Controller) Bet(userID uint, betData BetData) {
 			NftBalance:  db_aggregator.ConvertStringArrayToNftArray(&nftMintAddresses)},
 		Type:          models.TxJackpotBet,
 		ToBeConfirmed: false,
+		OwnerID:       c.roundID,
+		OwnerType:     models.TransactionJackpotReferenced,
 	})
 	if err != nil {
 		c.emitErrMessageWithBalanceUpdate("", userID, betData)
@@ -246,9 +259,6 @@ func (c *Controller) Bet(userID uint, betData BetData) {
 
 	c.checkRemainingTime()
 
-	nfts := []models.DepositedNft{}
-	db.Where("mint_address IN ?", nftMintAddresses).Find(&nfts)
-
 	var round models.JackpotRound
 	var player models.JackpotPlayer
 
@@ -260,7 +270,7 @@ func (c *Controller) Bet(userID uint, betData BetData) {
 				OwnerID:     round.ID,
 				OwnerType:   models.TransactionJackpotReferenced,
 			})
-			log.LogMessage("jackpot controller", "failed to get round data from db", "error", logrus.Fields{"round": c.roundID, "ticket": *c.ticketID})
+			log.LogMessage("jackpot controller", "failed to get round data from db", "error", log.WithRoundID(logrus.Fields{"round": c.roundID, "ticket": *c.ticketID}, "jackpot", c.roundID))
 			return
 		}
 		if !prs {
@@ -283,7 +293,7 @@ func (c *Controller) Bet(userID uint, betData BetData) {
 					OwnerID:     round.ID,
 					OwnerType:   models.TransactionJackpotReferenced,
 				})
-				log.LogMessage("jackpot controller", "failed to get Player data from db", "error", logrus.Fields{"round": c.roundID, "user": userID})
+				log.LogMessage("jackpot controller", "failed to get Player data from db", "error", log.WithRoundID(logrus.Fields{"round": c.roundID, "user": userID}, "jackpot", c.roundID))
 				return
 			}
 		}
@@ -333,35 +343,8 @@ func (c *Controller) Bet(userID uint, betData BetData) {
 	}
 	db.Create(&bet)
 
-	collectionID2Info := make(map[uint]*models.NftCollection)
-	totalNftPrice := int64(0)
-	var nftsInGame []models.NftInGame
-	for _, nft := range nfts {
-		if _, prs = collectionID2Info[nft.CollectionID]; !prs {
-			var collectionInfo models.NftCollection
-			if err := db.First(&collectionInfo, nft.CollectionID).Error; err != nil {
-				c.emitErrMessageWithBalanceUpdate("Can not find NFT", userID, betData)
-				transaction.Decline(transaction.DeclineRequest{
-					Transaction: *tx,
-					OwnerID:     round.ID,
-					OwnerType:   models.TransactionJackpotReferenced,
-				})
-				return
-			}
-			collectionID2Info[nft.CollectionID] = &collectionInfo
-		}
-
-		collectionInfo := collectionID2Info[nft.CollectionID]
-		nftsInGame = append(nftsInGame, models.NftInGame{
-			Name:            nft.Name,
-			MintAddress:     nft.MintAddress,
-			Image:           nft.Image,
-			CollectionName:  collectionInfo.Name,
-			CollectionImage: collectionInfo.Image,
-			Price:           collectionInfo.FloorPrice,
-			BetID:           bet.ID,
-		})
-		totalNftPrice += collectionInfo.FloorPrice
+	for i := range nftsInGame {
+		nftsInGame[i].BetID = bet.ID
 	}
 	if len(nftsInGame) > 0 {
 		db.Create(&nftsInGame)
@@ -406,5 +389,5 @@ func (c *Controller) Bet(userID uint, betData BetData) {
 		OwnerType:   models.TransactionJackpotReferenced,
 	})
 
-	log.LogMessage("jackpot controller", "betted", "success", logrus.Fields{"round": c.roundID, "user": userID, "bet": betData})
+	log.LogMessage("jackpot controller", "betted", "success", log.WithRoundID(logrus.Fields{"round": c.roundID, "user": userID, "bet": betData}, "jackpot", c.roundID))
 }
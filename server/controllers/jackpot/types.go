@@ -65,4 +65,8 @@ type Controller struct {
 	rollingDuration uint64
 	candidates      []types.User
 	lockUser syncmap.Map
+	// Counting time of a fresh round, extended while betting.
+	baseCountingTime uint
+	// Version of runtime settings currently applied.
+	settingsVersion uint
 }
@@ -9,9 +9,17 @@ import (
 	"github.com/Duelana-Team/duelana-v1/controllers/crash"
 	"github.com/Duelana-Team/duelana-v1/controllers/daily_race"
 	"github.com/Duelana-Team/duelana-v1/controllers/dreamtower"
+	"github.com/Duelana-Team/duelana-v1/controllers/financial_report"
+	"github.com/Duelana-Team/duelana-v1/controllers/game_settings"
 	"github.com/Duelana-Team/duelana-v1/controllers/grand_jackpot"
+	"github.com/Duelana-Team/duelana-v1/controllers/house_rain"
 	"github.com/Duelana-Team/duelana-v1/controllers/jackpot"
+	"github.com/Duelana-Team/duelana-v1/controllers/maintenance"
+	"github.com/Duelana-Team/duelana-v1/controllers/nft_collection"
 	"github.com/Duelana-Team/duelana-v1/controllers/payment"
+	"github.com/Duelana-Team/duelana-v1/controllers/quest"
+	"github.com/Duelana-Team/duelana-v1/controllers/recovery"
+	"github.com/Duelana-Team/duelana-v1/controllers/token_registry"
 	"github.com/Duelana-Team/duelana-v1/controllers/transaction/db_aggregator"
 	"github.com/Duelana-Team/duelana-v1/controllers/user"
 	"github.com/Duelana-Team/duelana-v1/controllers/weekly_raffle"
@@ -37,6 +45,17 @@ var (
 )
 
 func Init(eventEmitter chan types.WSEvent) {
+	if err := game_settings.Initialize(); err != nil {
+		log.LogMessage(
+			"controllers_Init",
+			"failed to initialize game settings module",
+			"error",
+			logrus.Fields{
+				"error": err.Error(),
+			},
+		)
+	}
+	token_registry.Initialize()
 	Chat = chat.Controller{EventEmitter: eventEmitter}
 	User = user.Controller{EventEmitter: eventEmitter, Chat: &Chat}
 	Payment = payment.Controller{EventEmitter: eventEmitter}
@@ -57,7 +76,58 @@ func Init(eventEmitter chan types.WSEvent) {
 			},
 		)
 	}
+	if err := maintenance.Initialize(eventEmitter); err != nil {
+		log.LogMessage(
+			"controllers_Init",
+			"failed to initialize maintenance module",
+			"error",
+			logrus.Fields{
+				"error": err.Error(),
+			},
+		)
+	}
 	weekly_raffle.Initialize(eventEmitter)
+	quest.Initialize(eventEmitter)
+	if err := house_rain.Initialize(eventEmitter, Chat.IsActiveUser); err != nil {
+		log.LogMessage(
+			"controllers_Init",
+			"failed to initialize house rain module",
+			"error",
+			logrus.Fields{
+				"error": err.Error(),
+			},
+		)
+	}
+	if err := financial_report.Initialize(); err != nil {
+		log.LogMessage(
+			"controllers_Init",
+			"failed to initialize financial report module",
+			"error",
+			logrus.Fields{
+				"error": err.Error(),
+			},
+		)
+	}
+	if err := nft_collection.StartFloorUpdater(); err != nil {
+		log.LogMessage(
+			"controllers_Init",
+			"failed to start nft floor updater",
+			"error",
+			logrus.Fields{
+				"error": err.Error(),
+			},
+		)
+	}
+	if err := recovery.StartJanitor(); err != nil {
+		log.LogMessage(
+			"controllers_Init",
+			"failed to start pending transaction janitor",
+			"error",
+			logrus.Fields{
+				"error": err.Error(),
+			},
+		)
+	}
 	if config.CRASH_START_ON_SERVER_STARTUP &&
 		config.Get().ENV != "dev" {
 		if err := Crash.Start(); err != nil {
@@ -73,6 +143,29 @@ func Init(eventEmitter chan types.WSEvent) {
 	}
 }
 
+/**
+* @External
+* Refuses new bets and waits for the running crash round
+* to finish. Players of that round can still cash out.
+* Called once on shutdown.
+ */
+func Shutdown() {
+	// 1. Refuse new bets, cash-outs are still accepted.
+	maintenance.StartDraining()
+
+	// 2. Let the current crash round finish.
+	if err := Crash.Drain(config.SHUTDOWN_ROUND_DRAIN_TIMEOUT); err != nil {
+		log.LogMessage(
+			"controllers_Shutdown",
+			"crash round left unfinished, refunded on next boot",
+			"error",
+			logrus.Fields{
+				"error": err.Error(),
+			},
+		)
+	}
+}
+
 func GetServerConfig(ctx *gin.Context) {
 	user, _ := ctx.Get(middlewares.SocketAuthMiddleware().IdentityKey)
 	var userID *uint
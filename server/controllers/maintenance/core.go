@@ -2,24 +2,52 @@ package maintenance
 
 import (
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/types"
 	"github.com/Duelana-Team/duelana-v1/utils"
+	"github.com/sirupsen/logrus"
 )
 
 // @Internal
 // Saves the detail of the maintenance status.
 var _details MaintenanceDetails
 
+// @Internal
+// Guards maintenance details and scheduled timers.
+var _mutex sync.Mutex
+
+// @Internal
+// Set while the server is shutting down. Never cleared,
+// the process exits once draining is done.
+var _draining atomic.Bool
+
 // @Internal
 // Required constants.
 const DEFAULT_STATUS = NotMaintenance
 
 // @Internal
 // Initializes maintenance module.
-func initialize() error {
+// Restores unfinished maintenance window persisted before restart.
+func initialize(eventEmitter chan types.WSEvent) error {
+	initSocket(eventEmitter)
+
+	_mutex.Lock()
+	defer _mutex.Unlock()
+
 	updateStatus(DEFAULT_STATUS, true)
-	return nil
+	window, err := getActiveWindow()
+	if err != nil {
+		return makeError("initialize", "failed to retrieve active window", err)
+	}
+	if window == nil {
+		return nil
+	}
+	return restore(window)
 }
 
 // @Internal
@@ -32,32 +60,23 @@ func makeError(category string, reason string, err error) error {
 // Check for the state update validity.
 // Status Update rules: (exclusive)
 // 1. not-maintenance => before-maintenance
-// 2. before-maintenance => in-maintenance // 2nd Rule Archived
-// 3. in-maintenance => not-maintenance
+// 2. before-maintenance => in-maintenance
+// 3. not-maintenance => in-maintenance
+// 4. in-maintenance, before-maintenance => not-maintenance
 func isStatusUpdateable(updateStatus MaintenanceStatus) bool {
-	// switch updateStatus {
-	// case NotMaintenance:
-	// 	if currentStatus() != InMaintenance {
-	// 		return false
-	// 	}
-	// case BeforeMaintenance:
-	// 	if currentStatus() != NotMaintenance {
-	// 		return false
-	// 	}
-	// case InMaintenance:
-	// 	if currentStatus() != BeforeMaintenance {
-	// 		return false
-	// 	}
-	// }
 	switch updateStatus {
 	case NotMaintenance:
-		if currentStatus() != InMaintenance {
+		if currentStatus() == NotMaintenance {
 			return false
 		}
-	case InMaintenance:
+	case BeforeMaintenance:
 		if currentStatus() != NotMaintenance {
 			return false
 		}
+	case InMaintenance:
+		if currentStatus() == InMaintenance {
+			return false
+		}
 	}
 
 	return true
@@ -66,10 +85,6 @@ func isStatusUpdateable(updateStatus MaintenanceStatus) bool {
 // @Internal
 // Updates maintenance status.
 // Automatically updates the timestamp as the current time.
-// Status Update rules: (exclusive)
-// 1. not-maintenance => before-maintenance
-// 2. before-maintenance => in-maintenance
-// 3. in-maintenance => not-maintenance
 // If the forceUpdate param is true, checking for rule is not required.
 func updateStatus(status MaintenanceStatus, forceUpdate ...bool) error {
 	if ((len(forceUpdate) > 0 && !forceUpdate[0]) || len(forceUpdate) == 0) && !isStatusUpdateable(status) {
@@ -100,41 +115,215 @@ func setTimestamp(timestamp time.Time) {
 	_details.Timestamp = timestamp
 }
 
-// @External // Archived
-// Updates the maintenance details to prepare the maintenance.
-// func prepare() error {
-// 	if err := updateStatus(BeforeMaintenance); err != nil {
-// 		return makeError("prepare", "failed to update status for preparing maintenance", err)
-// 	}
-// 	return nil
-// }
+// @Internal
+// Applies persisted window on initialization.
+// Scheduled windows get timers again, started windows continue
+// till their end and outdated windows are finished.
+func restore(window *models.MaintenanceWindow) error {
+	now := time.Now()
+	if window.Status == models.MaintenanceScheduled &&
+		now.Before(window.StartAt) {
+		_details.Window = window
+		updateStatus(BeforeMaintenance, true)
+		scheduleTimers(window)
+		return nil
+	}
+
+	if window.EndAt == nil || now.Before(*window.EndAt) {
+		if window.Status != models.MaintenanceInProgress {
+			if err := updateWindowStatus(window, models.MaintenanceInProgress); err != nil {
+				return makeError("restore", "failed to start window", err)
+			}
+		}
+		_details.Window = window
+		updateStatus(InMaintenance, true)
+		scheduleTimers(window)
+		return nil
+	}
+
+	if err := updateWindowStatus(window, models.MaintenanceFinished); err != nil {
+		return makeError("restore", "failed to finish outdated window", err)
+	}
+	return nil
+}
+
+// @External
+// Schedules maintenance window in future.
+// Bets are refused from `startAt` and the service reopens at `endAt`.
+// `endAt` can be nil to finish the maintenance manually.
+func schedule(startAt time.Time, endAt *time.Time, reason string) (*models.MaintenanceWindow, error) {
+	if !startAt.After(time.Now()) {
+		return nil, makeError("schedule", "invalid parameter", fmt.Errorf("start time is not in future: %v", startAt))
+	}
+	if endAt != nil && !endAt.After(startAt) {
+		return nil, makeError("schedule", "invalid parameter", fmt.Errorf("end time is not after start time: %v", *endAt))
+	}
+
+	_mutex.Lock()
+	defer _mutex.Unlock()
+
+	if !isStatusUpdateable(BeforeMaintenance) {
+		return nil, makeError("schedule", "the update is against the rules", fmt.Errorf("cannot schedule in %v status", currentStatus()))
+	}
+
+	window := models.MaintenanceWindow{
+		StartAt: startAt,
+		EndAt:   endAt,
+		Reason:  reason,
+		Status:  models.MaintenanceScheduled,
+	}
+	if err := createWindow(&window); err != nil {
+		return nil, makeError("schedule", "failed to create window", err)
+	}
+
+	_details.Window = &window
+	updateStatus(BeforeMaintenance)
+	scheduleTimers(&window)
+	broadcastStatus()
+	return &window, nil
+}
 
 // @External
 // Updates the maintenance details to maintain the maintenance.
+// Starts scheduled window immediately if exists.
 func maintain() error {
+	_mutex.Lock()
+	defer _mutex.Unlock()
+
+	return startWindow()
+}
+
+// @Internal
+// Starts current scheduled window or a new window without end.
+// Should be called with `_mutex` locked.
+func startWindow() error {
+	if !isStatusUpdateable(InMaintenance) {
+		return makeError("maintain", "the update is against the rules", fmt.Errorf("cannot update from %v status to %v status", currentStatus(), InMaintenance))
+	}
+
+	window := _details.Window
+	if window == nil {
+		window = &models.MaintenanceWindow{
+			StartAt: time.Now(),
+			Status:  models.MaintenanceInProgress,
+		}
+		if err := createWindow(window); err != nil {
+			return makeError("maintain", "failed to create window", err)
+		}
+	} else if err := updateWindowStatus(window, models.MaintenanceInProgress); err != nil {
+		return makeError("maintain", "failed to start window", err)
+	}
+
+	_details.Window = window
 	if err := updateStatus(InMaintenance); err != nil {
 		return makeError("maintain", "failed to update status for maintaining maintenance", err)
 	}
+	clearTimers()
+	scheduleTimers(window)
+	broadcastStatus()
 	return nil
 }
 
 // @External
 // Updates the maintenance details to finish the maintenance.
 func finish() error {
+	_mutex.Lock()
+	defer _mutex.Unlock()
+
+	return finishWindow(InMaintenance, models.MaintenanceFinished)
+}
+
+// @External
+// Cancels scheduled maintenance which is not started yet.
+func cancel() error {
+	_mutex.Lock()
+	defer _mutex.Unlock()
+
+	return finishWindow(BeforeMaintenance, models.MaintenanceCancelled)
+}
+
+// @Internal
+// Closes current window with the status and reopens the service.
+// Should be called with `_mutex` locked.
+func finishWindow(
+	expectedStatus MaintenanceStatus,
+	windowStatus models.MaintenanceWindowStatus,
+) error {
+	if currentStatus() != expectedStatus {
+		return makeError("finish", "the update is against the rules", fmt.Errorf("cannot update from %v status to %v status", currentStatus(), NotMaintenance))
+	}
+
+	if window := _details.Window; window != nil {
+		if windowStatus == models.MaintenanceFinished &&
+			(window.EndAt == nil || window.EndAt.After(time.Now())) {
+			now := time.Now()
+			window.EndAt = &now
+		}
+		if err := updateWindowStatus(window, windowStatus); err != nil {
+			return makeError("finish", "failed to close window", err)
+		}
+	}
+
+	_details.Window = nil
 	if err := updateStatus(NotMaintenance); err != nil {
 		return makeError("finish", "failed to update status for finishing maintenance", err)
 	}
+	clearTimers()
+	broadcastStatus()
 	return nil
 }
 
+// @Internal
+// Triggered at the start time of scheduled window.
+func onWindowStart(windowID uint) {
+	_mutex.Lock()
+	defer _mutex.Unlock()
+
+	if _details.Window == nil || _details.Window.ID != windowID {
+		return
+	}
+	if err := startWindow(); err != nil {
+		log.LogMessage("maintenance", "failed to start scheduled maintenance", "error", logrus.Fields{"windowID": windowID, "error": err.Error()})
+	}
+}
+
+// @Internal
+// Triggered at the end time of the window.
+func onWindowEnd(windowID uint) {
+	_mutex.Lock()
+	defer _mutex.Unlock()
+
+	if _details.Window == nil || _details.Window.ID != windowID {
+		return
+	}
+	if err := finishWindow(InMaintenance, models.MaintenanceFinished); err != nil {
+		log.LogMessage("maintenance", "failed to finish scheduled maintenance", "error", logrus.Fields{"windowID": windowID, "error": err.Error()})
+	}
+}
+
 // @External
 // Get the current maintenance details.
 func current() MaintenanceDetails {
+	_mutex.Lock()
+	defer _mutex.Unlock()
+
 	return _details
 }
 
 // @External
 // Returns whether the current status allows new rounds.
+// Bets are still allowed during countdown before maintenance.
+// Refused once the server started draining for shutdown.
 func ableToBet() bool {
-	return currentStatus() == NotMaintenance
+	return currentStatus() != InMaintenance &&
+		!_draining.Load()
+}
+
+// @External
+// Refuses every new bet till the process exits.
+func startDraining() {
+	if _draining.Swap(true) {
+		return
+	}
+	log.LogMessage("maintenance", "started draining for shutdown", "info", logrus.Fields{})
 }
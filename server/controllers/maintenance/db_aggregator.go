@@ -0,0 +1,68 @@
+package maintenance
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Duelana-Team/duelana-v1/db"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"gorm.io/gorm"
+)
+
+// @Internal
+// Returns latest scheduled or in-progress window.
+// Returns nil when there is no such window.
+func getActiveWindow() (*models.MaintenanceWindow, error) {
+	db := db.GetDB()
+	if db == nil {
+		return nil, nil
+	}
+
+	window := models.MaintenanceWindow{}
+	if err := db.Where(
+		"status in ?",
+		[]models.MaintenanceWindowStatus{
+			models.MaintenanceScheduled,
+			models.MaintenanceInProgress,
+		},
+	).Order(
+		"id desc",
+	).First(&window).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, makeError("getActiveWindow", "failed to retrieve window", err)
+	}
+	return &window, nil
+}
+
+// @Internal
+// Creates maintenance window.
+func createWindow(window *models.MaintenanceWindow) error {
+	db := db.GetDB()
+	if db == nil {
+		return makeError("createWindow", "failed to retrieve db pointer", errors.New("retrieved db pointer is nil"))
+	}
+
+	if err := db.Create(window).Error; err != nil {
+		return makeError("createWindow", "failed to create window", fmt.Errorf("window: %v, err: %v", *window, err))
+	}
+	return nil
+}
+
+// @Internal
+// Updates status and end time of the window.
+func updateWindowStatus(window *models.MaintenanceWindow, status models.MaintenanceWindowStatus) error {
+	db := db.GetDB()
+	if db == nil {
+		return makeError("updateWindowStatus", "failed to retrieve db pointer", errors.New("retrieved db pointer is nil"))
+	}
+
+	if err := db.Model(window).Updates(map[string]interface{}{
+		"status": status,
+		"end_at": window.EndAt,
+	}).Error; err != nil {
+		return makeError("updateWindowStatus", "failed to update window", fmt.Errorf("windowID: %d, status: %s, err: %v", window.ID, status, err))
+	}
+	window.Status = status
+	return nil
+}
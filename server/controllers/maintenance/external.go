@@ -1,14 +1,16 @@
 package maintenance
 
-import "github.com/gin-gonic/gin"
+import (
+	"net/http"
+	"time"
 
-func Initialize() error {
-	return initialize()
-}
+	"github.com/Duelana-Team/duelana-v1/types"
+	"github.com/gin-gonic/gin"
+)
 
-// func Prepare() error {
-// 	return prepare()
-// }
+func Initialize(eventEmitter chan types.WSEvent) error {
+	return initialize(eventEmitter)
+}
 
 func Maintain() error {
 	return maintain()
@@ -26,6 +28,27 @@ func AbleToBet() bool {
 	return ableToBet()
 }
 
+func StartDraining() {
+	startDraining()
+}
+
+// Refuses the request while bets are not allowed. Only for bet
+// entry points, so that players can still leave open positions.
+func BetMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !ableToBet() {
+			ctx.AbortWithStatusJSON(
+				http.StatusServiceUnavailable,
+				gin.H{
+					"message": "server is under maintenance.",
+				},
+			)
+			return
+		}
+		ctx.Next()
+	}
+}
+
 func StartMaintenance(ctx *gin.Context) {
 	if err := maintain(); err != nil {
 		ctx.JSON(400, gin.H{
@@ -45,3 +68,43 @@ func FinishMaintenance(ctx *gin.Context) {
 	}
 	ctx.JSON(200, gin.H{"msg": "finished maintenance status"})
 }
+
+func ScheduleMaintenance(ctx *gin.Context) {
+	var params struct {
+		StartAt time.Time  `json:"startAt"`
+		EndAt   *time.Time `json:"endAt"`
+		Reason  string     `json:"reason"`
+	}
+	if err := ctx.BindJSON(&params); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	window, err := schedule(params.StartAt, params.EndAt, params.Reason)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"msg":    "scheduled maintenance",
+		"window": window,
+	})
+}
+
+func CancelMaintenance(ctx *gin.Context) {
+	if err := cancel(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"msg": "cancelled scheduled maintenance"})
+}
+
+func GetMaintenanceStatus(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, current())
+}
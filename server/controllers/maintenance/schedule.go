@@ -0,0 +1,63 @@
+package maintenance
+
+import (
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/models"
+)
+
+// @Internal
+// Timers of the current window.
+var _timers []*time.Timer
+
+// @Internal
+// Schedules countdown broadcasts, start and end of the window.
+// Should be called with `_mutex` locked.
+func scheduleTimers(window *models.MaintenanceWindow) {
+	now := time.Now()
+	windowID := window.ID
+
+	if window.Status == models.MaintenanceScheduled {
+		for _, notice := range getPendingNotices(window.StartAt, now) {
+			remaining := notice
+			_timers = append(_timers, time.AfterFunc(
+				time.Until(window.StartAt.Add(-remaining)),
+				func() { broadcastCountdown(*window, remaining) },
+			))
+		}
+		_timers = append(_timers, time.AfterFunc(
+			time.Until(window.StartAt),
+			func() { onWindowStart(windowID) },
+		))
+	}
+
+	if window.EndAt != nil {
+		_timers = append(_timers, time.AfterFunc(
+			time.Until(*window.EndAt),
+			func() { onWindowEnd(windowID) },
+		))
+	}
+}
+
+// @Internal
+// Stops all timers of the current window.
+// Should be called with `_mutex` locked.
+func clearTimers() {
+	for _, timer := range _timers {
+		timer.Stop()
+	}
+	_timers = nil
+}
+
+// @Internal
+// Returns countdown notices still to be broadcasted before `startAt`.
+func getPendingNotices(startAt time.Time, now time.Time) []time.Duration {
+	notices := []time.Duration{}
+	for _, notice := range config.MAINTENANCE_COUNTDOWN_NOTICES {
+		if notice > 0 && startAt.Add(-notice).After(now) {
+			notices = append(notices, notice)
+		}
+	}
+	return notices
+}
@@ -0,0 +1,68 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+)
+
+func TestGetPendingNotices(t *testing.T) {
+	config.MAINTENANCE_COUNTDOWN_NOTICES = []time.Duration{
+		10 * time.Minute,
+		time.Minute,
+		10 * time.Second,
+	}
+	now := time.Now()
+
+	if notices := getPendingNotices(now.Add(time.Hour), now); len(notices) != 3 {
+		t.Fatalf("all notices should be pending: %v", notices)
+	}
+	if notices := getPendingNotices(now.Add(5*time.Minute), now); len(notices) != 2 ||
+		notices[0] != time.Minute {
+		t.Fatalf("passed notices should be skipped: %v", notices)
+	}
+	if notices := getPendingNotices(now.Add(5*time.Second), now); len(notices) != 0 {
+		t.Fatalf("no notice should be pending: %v", notices)
+	}
+}
+
+func TestIsStatusUpdateable(t *testing.T) {
+	cases := []struct {
+		from     MaintenanceStatus
+		to       MaintenanceStatus
+		expected bool
+	}{
+		{NotMaintenance, BeforeMaintenance, true},
+		{NotMaintenance, InMaintenance, true},
+		{NotMaintenance, NotMaintenance, false},
+		{BeforeMaintenance, InMaintenance, true},
+		{BeforeMaintenance, NotMaintenance, true},
+		{BeforeMaintenance, BeforeMaintenance, false},
+		{InMaintenance, NotMaintenance, true},
+		{InMaintenance, BeforeMaintenance, false},
+		{InMaintenance, InMaintenance, false},
+	}
+	for _, c := range cases {
+		setStatus(c.from)
+		if isStatusUpdateable(c.to) != c.expected {
+			t.Fatalf("%s => %s should be %v", c.from, c.to, c.expected)
+		}
+	}
+
+	setStatus(InMaintenance)
+	if ableToBet() {
+		t.Fatalf("bets should be refused in maintenance")
+	}
+	setStatus(BeforeMaintenance)
+	if !ableToBet() {
+		t.Fatalf("bets should be allowed before maintenance")
+	}
+
+	setStatus(NotMaintenance)
+	startDraining()
+	defer _draining.Store(false)
+	if ableToBet() {
+		t.Fatalf("bets should be refused while draining")
+	}
+}
@@ -0,0 +1,55 @@
+package maintenance
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/types"
+)
+
+const MAINTENANCE_ROOM = "maintenance"
+
+// @Internal
+// Event emitter to broadcast maintenance events.
+var _eventEmitter chan types.WSEvent
+
+// @Internal
+// Initialize socket event emitter.
+func initSocket(eventEmitter chan types.WSEvent) {
+	_eventEmitter = eventEmitter
+}
+
+// @Internal
+// Broadcasts remaining time till maintenance start to all rooms.
+func broadcastCountdown(window models.MaintenanceWindow, remaining time.Duration) {
+	broadcast("maintenance_countdown", CountdownPayload{
+		Status:    BeforeMaintenance,
+		StartAt:   window.StartAt,
+		EndAt:     window.EndAt,
+		Remaining: uint(remaining.Seconds()),
+	})
+}
+
+// @Internal
+// Broadcasts current maintenance details to all rooms.
+// Should be called with `_mutex` locked.
+func broadcastStatus() {
+	broadcast("maintenance_status", _details)
+}
+
+// @Internal
+// Broadcasts event to all clients regardless of their rooms.
+func broadcast(eventType string, payload interface{}) {
+	if _eventEmitter == nil {
+		return
+	}
+
+	b, _ := json.Marshal(types.WSMessage{
+		Room:      MAINTENANCE_ROOM,
+		EventType: eventType,
+		Payload:   payload,
+	})
+	// Events of chat room are delivered to all clients.
+	_eventEmitter <- types.WSEvent{Room: types.Chat, Message: b}
+}
@@ -1,16 +1,28 @@
 package maintenance
 
-import "time"
+import (
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/models"
+)
 
 type MaintenanceStatus string
 
 const (
-	NotMaintenance MaintenanceStatus = "not-maintenance"
-	// BeforeMaintenance MaintenanceStatus = "before-maintenance"
-	InMaintenance MaintenanceStatus = "in-maintenance"
+	NotMaintenance    MaintenanceStatus = "not-maintenance"
+	BeforeMaintenance MaintenanceStatus = "before-maintenance"
+	InMaintenance     MaintenanceStatus = "in-maintenance"
 )
 
 type MaintenanceDetails struct {
-	Status    MaintenanceStatus
-	Timestamp time.Time
+	Status    MaintenanceStatus         `json:"status"`
+	Timestamp time.Time                 `json:"timestamp"`
+	Window    *models.MaintenanceWindow `json:"window"`
+}
+
+type CountdownPayload struct {
+	Status    MaintenanceStatus `json:"status"`
+	StartAt   time.Time         `json:"startAt"`
+	EndAt     *time.Time        `json:"endAt"`
+	Remaining uint              `json:"remaining"`
 }
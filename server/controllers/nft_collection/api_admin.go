@@ -0,0 +1,199 @@
+package nft_collection
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"github.com/gin-gonic/gin"
+)
+
+func GetCollectionsHandler(ctx *gin.Context) {
+	statuses := []models.NftCollectionStatus{}
+	if status := ctx.Query("status"); status != "" {
+		statuses = append(statuses, models.NftCollectionStatus(status))
+	}
+
+	collections, err := getCollections(statuses...)
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to retrieve collections",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"collections": collections,
+		},
+	)
+}
+
+func SaveCollectionHandler(ctx *gin.Context) {
+	var params struct {
+		ID         uint            `json:"id"`
+		Name       string          `json:"name"`
+		Image      string          `json:"image"`
+		FloorPrice int64           `json:"floorPrice"`
+		MagicEden  string          `json:"magicEden"`
+		HyperSpace string          `json:"hyperSpace"`
+		Solanart   string          `json:"solanart"`
+		HowRare    string          `json:"howRare"`
+		MoonRank   string          `json:"moonRank"`
+		Nfts       []CollectionNft `json:"nfts"`
+	}
+	if err := ctx.BindJSON(&params); err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+			},
+		)
+		return
+	}
+
+	collection := models.NftCollection{
+		Name:       params.Name,
+		Image:      params.Image,
+		FloorPrice: params.FloorPrice,
+		MagicEden:  params.MagicEden,
+		HyperSpace: params.HyperSpace,
+		Solanart:   params.Solanart,
+		HowRare:    params.HowRare,
+		MoonRank:   params.MoonRank,
+	}
+	collection.ID = params.ID
+	err := saveCollection(&collection, params.Nfts)
+	if utils.IsErrorCode(err, ErrCodeInvalidParameter) {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid collection",
+				"error":   err.Error(),
+			},
+		)
+		return
+	} else if utils.IsErrorCode(err, ErrCodeNotFound) {
+		ctx.AbortWithStatusJSON(
+			http.StatusNotFound,
+			gin.H{
+				"message": "collection not found",
+				"error":   err.Error(),
+			},
+		)
+		return
+	} else if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to save collection",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"message":    "successfully saved collection",
+			"collection": collection,
+		},
+	)
+}
+
+func SetCollectionStatusHandler(ctx *gin.Context) {
+	var params struct {
+		CollectionID uint                       `json:"collectionId"`
+		Status       models.NftCollectionStatus `json:"status"`
+	}
+	if err := ctx.BindJSON(&params); err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+			},
+		)
+		return
+	}
+
+	err := setCollectionStatus(params.CollectionID, params.Status)
+	if utils.IsErrorCode(err, ErrCodeInvalidParameter) {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid status",
+				"error":   err.Error(),
+			},
+		)
+		return
+	} else if utils.IsErrorCode(err, ErrCodeNotFound) {
+		ctx.AbortWithStatusJSON(
+			http.StatusNotFound,
+			gin.H{
+				"message": "collection not found",
+				"error":   err.Error(),
+			},
+		)
+		return
+	} else if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to set collection status",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"message": "successfully set collection status",
+		},
+	)
+}
+
+func GetFloorHistoryHandler(ctx *gin.Context) {
+	collectionID, err := strconv.ParseUint(ctx.Query("collectionId"), 10, 64)
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	history, err := getFloorHistory(
+		uint(collectionID),
+		config.NFT_FLOOR_HISTORY_LIMIT,
+	)
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to retrieve floor history",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"history": history,
+		},
+	)
+}
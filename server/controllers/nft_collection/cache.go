@@ -0,0 +1,70 @@
+package nft_collection
+
+import (
+	"sync"
+	"time"
+)
+
+type cacheEntry[T any] struct {
+	value T
+	at    time.Time
+}
+
+// Caches marketplace responses per key for a TTL.
+type ttlCache[T any] struct {
+	mutex   sync.Mutex
+	entries map[string]cacheEntry[T]
+}
+
+func newTtlCache[T any]() *ttlCache[T] {
+	return &ttlCache[T]{entries: map[string]cacheEntry[T]{}}
+}
+
+/**
+* @Internal
+* Returns cached value of the key, fetching it when older than
+* `ttl`. Stale value is kept when fetching fails.
+ */
+func (cache *ttlCache[T]) get(key string, ttl time.Duration, fetch func() (T, error)) (T, error) {
+	cache.mutex.Lock()
+	entry, prs := cache.entries[key]
+	cache.mutex.Unlock()
+	if prs && time.Since(entry.at) < ttl {
+		return entry.value, nil
+	}
+
+	value, err := fetch()
+	if err != nil {
+		if prs {
+			return entry.value, nil
+		}
+		return value, err
+	}
+
+	cache.mutex.Lock()
+	cache.entries[key] = cacheEntry[T]{value: value, at: time.Now()}
+	cache.mutex.Unlock()
+	return value, nil
+}
+
+/**
+* @Internal
+* Returns cached value of the key regardless of its age,
+* without fetching.
+ */
+func (cache *ttlCache[T]) peek(key string) (T, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	entry, prs := cache.entries[key]
+	return entry.value, prs
+}
+
+/**
+* @Internal
+* Caches the value of the key.
+ */
+func (cache *ttlCache[T]) put(key string, value T) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cache.entries[key] = cacheEntry[T]{value: value, at: time.Now()}
+}
@@ -0,0 +1,277 @@
+package nft_collection
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/controllers/transaction/db_aggregator"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"gorm.io/gorm"
+)
+
+/**
+* @Internal
+* Returns collections, of given statuses if any.
+ */
+func getCollections(statuses ...models.NftCollectionStatus) ([]models.NftCollection, error) {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"nft_collection_db",
+			"getCollections",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	query := session.Order("id")
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	collections := []models.NftCollection{}
+	if err := query.Find(&collections).Error; err != nil {
+		return nil, utils.MakeError(
+			"nft_collection_db",
+			"getCollections",
+			"failed to retrieve collections",
+			err,
+		)
+	}
+	return collections, nil
+}
+
+/**
+* @Internal
+* Creates the collection as pending, or updates its details, and
+* registers its NFTs. NFTs already registered are skipped.
+ */
+func saveCollection(collection *models.NftCollection, nfts []CollectionNft) error {
+	if err := validateCollection(collection, nfts); err != nil {
+		return err
+	}
+
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return utils.MakeError(
+			"nft_collection_db",
+			"saveCollection",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	if err := session.Transaction(func(tx *gorm.DB) error {
+		// 1. Save collection.
+		if collection.ID == 0 {
+			collection.Status = models.NftCollectionPending
+			if err := tx.Create(collection).Error; err != nil {
+				return err
+			}
+		} else {
+			result := tx.Model(collection).Select(
+				"name",
+				"image",
+				"floor_price",
+				"magic_eden",
+				"hyper_space",
+				"solanart",
+				"how_rare",
+				"moon_rank",
+			).Updates(collection)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected != 1 {
+				return gorm.ErrRecordNotFound
+			}
+		}
+
+		// 2. Register NFTs.
+		for _, nft := range nfts {
+			var count int64
+			if err := tx.Model(&models.DepositedNft{}).
+				Where("mint_address = ?", nft.MintAddress).
+				Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				continue
+			}
+			if err := tx.Create(&models.DepositedNft{
+				Name:         nft.Name,
+				CollectionID: collection.ID,
+				MintAddress:  nft.MintAddress,
+				Image:        nft.Image,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}); errors.Is(err, gorm.ErrRecordNotFound) {
+		return utils.MakeErrorWithCode(
+			"nft_collection_db",
+			"saveCollection",
+			"collection not found",
+			ErrCodeNotFound,
+			fmt.Errorf("collection: %d", collection.ID),
+		)
+	} else if err != nil {
+		return utils.MakeError(
+			"nft_collection_db",
+			"saveCollection",
+			"failed to save collection",
+			fmt.Errorf(
+				"collection: %v, err: %v",
+				collection.ID, err,
+			),
+		)
+	}
+	return nil
+}
+
+/**
+* @Internal
+* Approves or disables the collection.
+ */
+func setCollectionStatus(collectionID uint, status models.NftCollectionStatus) error {
+	if err := validateStatus(status); err != nil {
+		return err
+	}
+
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return utils.MakeError(
+			"nft_collection_db",
+			"setCollectionStatus",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	result := session.Model(&models.NftCollection{}).
+		Where("id = ?", collectionID).
+		Update("status", status)
+	if result.Error != nil {
+		return utils.MakeError(
+			"nft_collection_db",
+			"setCollectionStatus",
+			"failed to update status",
+			result.Error,
+		)
+	}
+	if result.RowsAffected != 1 {
+		return utils.MakeErrorWithCode(
+			"nft_collection_db",
+			"setCollectionStatus",
+			"collection not found",
+			ErrCodeNotFound,
+			fmt.Errorf("collection: %d", collectionID),
+		)
+	}
+	return nil
+}
+
+/**
+* @Internal
+* Returns raw floors of the collection observed since `since`.
+ */
+func getFloorSamples(collectionID uint, since time.Time) ([]floorSample, error) {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"nft_collection_db",
+			"getFloorSamples",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	records := []models.NftFloorPrice{}
+	if err := session.Where("collection_id = ?", collectionID).
+		Where("created_at >= ?", since).
+		Order("created_at").
+		Find(&records).Error; err != nil {
+		return nil, utils.MakeError(
+			"nft_collection_db",
+			"getFloorSamples",
+			"failed to retrieve floor prices",
+			err,
+		)
+	}
+
+	samples := make([]floorSample, len(records))
+	for i, record := range records {
+		samples[i] = floorSample{Price: record.RawPrice, At: record.CreatedAt}
+	}
+	return samples, nil
+}
+
+/**
+* @Internal
+* Records the floor and applies it to the collection.
+ */
+func saveFloorPrice(collection *models.NftCollection, record *models.NftFloorPrice, now time.Time) error {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return utils.MakeError(
+			"nft_collection_db",
+			"saveFloorPrice",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	record.CreatedAt = now
+	if err := session.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(record).Error; err != nil {
+			return err
+		}
+		return tx.Model(collection).Updates(map[string]interface{}{
+			"floor_price":            record.Price,
+			"floor_price_updated_at": now,
+		}).Error
+	}); err != nil {
+		return utils.MakeError(
+			"nft_collection_db",
+			"saveFloorPrice",
+			"failed to save floor price",
+			fmt.Errorf(
+				"collection: %d, err: %v",
+				collection.ID, err,
+			),
+		)
+	}
+	return nil
+}
+
+/**
+* @Internal
+* Returns latest floors of the collection, newest first.
+ */
+func getFloorHistory(collectionID uint, limit int) ([]models.NftFloorPrice, error) {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"nft_collection_db",
+			"getFloorHistory",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	history := []models.NftFloorPrice{}
+	if err := session.Where("collection_id = ?", collectionID).
+		Order("created_at desc").
+		Limit(limit).
+		Find(&history).Error; err != nil {
+		return nil, utils.MakeError(
+			"nft_collection_db",
+			"getFloorHistory",
+			"failed to retrieve floor prices",
+			err,
+		)
+	}
+	return history, nil
+}
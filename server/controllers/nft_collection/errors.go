@@ -0,0 +1,6 @@
+package nft_collection
+
+// Error code range: #114xxx
+const ErrCodeBase = "#114"
+const ErrCodeInvalidParameter = ErrCodeBase + "000"
+const ErrCodeNotFound = ErrCodeBase + "001"
@@ -0,0 +1,297 @@
+package nft_collection
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/controllers/price_oracle"
+	"github.com/Duelana-Team/duelana-v1/controllers/solana"
+	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	cron "github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+)
+
+// Guards floor updates.
+var floorMutex sync.Mutex
+
+const LAMPORTS_DECIMALS = 9
+
+/**
+* @External
+* Schedules refreshing floor prices of approved collections,
+* and warms valuation caches at once so that valuations after
+* boot do not wait for the first update.
+ */
+func StartFloorUpdater() error {
+	c := cron.New(cron.WithLocation(time.UTC))
+	if _, err := c.AddFunc(
+		config.NFT_FLOOR_UPDATE_SCHEDULE,
+		func() { refreshFloorPrices(time.Now()) },
+	); err != nil {
+		return utils.MakeError(
+			"nft_collection_floor",
+			"StartFloorUpdater",
+			"failed to schedule floor updater",
+			err,
+		)
+	}
+	c.Start()
+	go warmValuationCaches()
+	return nil
+}
+
+/**
+* @Internal
+* Refreshes floor prices of every approved collection at `now`,
+* then warms their valuation caches.
+* Skipped entirely while SOL price is unsafe.
+ */
+func refreshFloorPrices(now time.Time) {
+	floorMutex.Lock()
+	defer floorMutex.Unlock()
+
+	// 1. Price SOL.
+	solPrice, err := getSolPrice()
+	if err != nil {
+		log.LogMessage(
+			"nft_collection_refreshFloorPrices",
+			"skipped floor update, failed to price sol",
+			"error",
+			logrus.Fields{
+				"error": err.Error(),
+			},
+		)
+		return
+	}
+	solPriceCache.put(SOL_PRICE_CACHE_KEY, solPrice)
+
+	// 2. Refresh approved collections.
+	collections, err := getCollections(models.NftCollectionApproved)
+	if err != nil {
+		log.LogMessage(
+			"nft_collection_refreshFloorPrices",
+			"failed to retrieve collections",
+			"error",
+			logrus.Fields{
+				"error": err.Error(),
+			},
+		)
+		return
+	}
+	for i := range collections {
+		if err := refreshFloorPrice(&collections[i], solPrice, now); err != nil {
+			log.LogMessage(
+				"nft_collection_refreshFloorPrices",
+				"failed to refresh floor price",
+				"error",
+				logrus.Fields{
+					"collection": collections[i].ID,
+					"error":      err.Error(),
+				},
+			)
+		}
+	}
+
+	// 3. Warm valuation caches.
+	for i := range collections {
+		warmRanks(&collections[i])
+		warmSales(&collections[i])
+	}
+}
+
+/**
+* @Internal
+* Warms SOL price, ranks and sales caches of approved collections,
+* read by `ValuateNft`.
+ */
+func warmValuationCaches() {
+	if solPrice, err := getSolPrice(); err == nil {
+		solPriceCache.put(SOL_PRICE_CACHE_KEY, solPrice)
+	}
+	collections, err := getCollections(models.NftCollectionApproved)
+	if err != nil {
+		log.LogMessage(
+			"nft_collection_warmValuationCaches",
+			"failed to retrieve collections",
+			"error",
+			logrus.Fields{
+				"error": err.Error(),
+			},
+		)
+		return
+	}
+	for i := range collections {
+		warmRanks(&collections[i])
+		warmSales(&collections[i])
+	}
+}
+
+/**
+* @Internal
+* Returns oracle price of SOL in USD.
+ */
+func getSolPrice() (float64, error) {
+	for _, token := range solana.SupportedSpls() {
+		if !solana.IsSolSplMeta(token) {
+			continue
+		}
+		price, err := price_oracle.GetPrice(&token)
+		if err != nil {
+			return 0, err
+		}
+		return price.Price, nil
+	}
+	return 0, fmt.Errorf("sol is not a supported token")
+}
+
+/**
+* @Internal
+* Quotes collection floors from every marketplace listing it,
+* in lamports per marketplace.
+ */
+func collectFloors(collection *models.NftCollection) map[string]uint64 {
+	floors := map[string]uint64{}
+	for _, marketplace := range getMarketplaces() {
+		slug := marketplace.Slug(collection)
+		if slug == "" {
+			continue
+		}
+		floor, err := marketplace.GetFloor(slug)
+		if err != nil {
+			log.LogMessage(
+				"nft_collection_collectFloors",
+				"failed to get floor",
+				"info",
+				logrus.Fields{
+					"collection":  collection.ID,
+					"marketplace": marketplace.Name(),
+					"error":       err.Error(),
+				},
+			)
+			continue
+		}
+		floors[marketplace.Name()] = floor
+	}
+	return floors
+}
+
+/**
+* @Internal
+* Refreshes floor price of the collection and records it.
+ */
+func refreshFloorPrice(collection *models.NftCollection, solPrice float64, now time.Time) error {
+	// 1. Quote marketplace floors.
+	floors := collectFloors(collection)
+	if len(floors) == 0 {
+		return fmt.Errorf("no marketplace floor of collection: %d", collection.ID)
+	}
+	lamports := make([]uint64, 0, len(floors))
+	for _, floor := range floors {
+		lamports = append(lamports, floor)
+	}
+	rawPrice := lamportsToChips(medianLamports(lamports), solPrice)
+
+	// 2. Weight raw floors over the window and bound change.
+	samples, err := getFloorSamples(collection.ID, now.Add(-config.NFT_FLOOR_TWAP_WINDOW))
+	if err != nil {
+		return err
+	}
+	samples = append(samples, floorSample{Price: rawPrice, At: now})
+	price := clampFloorChange(
+		collection.FloorPrice,
+		twap(samples, now, config.NFT_FLOOR_TWAP_WINDOW),
+		config.NFT_FLOOR_MAX_CHANGE,
+	)
+
+	// 3. Save floor.
+	floorsJson, _ := json.Marshal(floors)
+	return saveFloorPrice(
+		collection,
+		&models.NftFloorPrice{
+			CollectionID: collection.ID,
+			RawPrice:     rawPrice,
+			Price:        price,
+			SolPrice:     solPrice,
+			Floors:       floorsJson,
+		},
+		now,
+	)
+}
+
+/**
+* @Internal
+* Converts lamports to chips at SOL price in USD.
+ */
+func lamportsToChips(lamports uint64, solPrice float64) int64 {
+	return int64(float64(lamports) * solPrice * math.Pow10(config.BALANCE_DECIMALS) / math.Pow10(LAMPORTS_DECIMALS))
+}
+
+/**
+* @Internal
+* Median of floors, lower middle on even count.
+ */
+func medianLamports(floors []uint64) uint64 {
+	if len(floors) == 0 {
+		return 0
+	}
+	sorted := append([]uint64{}, floors...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted[(len(sorted)-1)/2]
+}
+
+/**
+* @Internal
+* Time weighted average of samples within `window` before `now`.
+* Each sample holds since the previous one, the oldest since the
+* window start, so that a floor spiking between updates weighs
+* only the update interval.
+ */
+func twap(samples []floorSample, now time.Time, window time.Duration) int64 {
+	sorted := append([]floorSample{}, samples...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].At.Before(sorted[j].At) })
+
+	prev := now.Add(-window)
+	weighted, total := 0.0, 0.0
+	last := int64(0)
+	for _, sample := range sorted {
+		if sample.At.Before(prev) {
+			continue
+		}
+		weight := sample.At.Sub(prev).Seconds()
+		weighted += float64(sample.Price) * weight
+		total += weight
+		prev = sample.At
+		last = sample.Price
+	}
+	if total == 0 {
+		return last
+	}
+	return int64(weighted / total)
+}
+
+/**
+* @Internal
+* Bounds change from `current` floor to `maxChange` bps.
+* Unbounded while no floor is set.
+ */
+func clampFloorChange(current int64, next int64, maxChange int64) int64 {
+	if current <= 0 {
+		return next
+	}
+	upper := current + current*maxChange/10000
+	lower := current - current*maxChange/10000
+	if next > upper {
+		return upper
+	}
+	if next < lower {
+		return lower
+	}
+	return next
+}
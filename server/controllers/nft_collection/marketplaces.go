@@ -0,0 +1,124 @@
+package nft_collection
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/models"
+)
+
+var httpClient = &http.Client{Timeout: config.PRICE_ORACLE_REQUEST_TIMEOUT}
+
+// Marketplaces quoting collection floors.
+var marketplaces = []MarketplaceAdapter{
+	magicEdenAdapter{},
+}
+var marketplacesMutex sync.RWMutex
+
+// Marketplaces reporting collection sales.
+var salesProviders = []SalesProvider{
+	magicEdenAdapter{},
+}
+var salesProvidersMutex sync.RWMutex
+
+/**
+* @External
+* Adds a marketplace quoting collection floors.
+ */
+func RegisterMarketplace(adapter MarketplaceAdapter) {
+	marketplacesMutex.Lock()
+	defer marketplacesMutex.Unlock()
+	marketplaces = append(marketplaces, adapter)
+}
+
+/**
+* @External
+* Adds a marketplace reporting collection sales.
+ */
+func RegisterSalesProvider(provider SalesProvider) {
+	salesProvidersMutex.Lock()
+	defer salesProvidersMutex.Unlock()
+	salesProviders = append(salesProviders, provider)
+}
+
+func getSalesProviders() []SalesProvider {
+	salesProvidersMutex.RLock()
+	defer salesProvidersMutex.RUnlock()
+	return append([]SalesProvider{}, salesProviders...)
+}
+
+func getMarketplaces() []MarketplaceAdapter {
+	marketplacesMutex.RLock()
+	defer marketplacesMutex.RUnlock()
+	return append([]MarketplaceAdapter{}, marketplaces...)
+}
+
+// Quotes floors from Magic Eden collection stats.
+type magicEdenAdapter struct{}
+
+func (adapter magicEdenAdapter) Name() string {
+	return "magiceden"
+}
+
+func (adapter magicEdenAdapter) Slug(collection *models.NftCollection) string {
+	return collection.MagicEden
+}
+
+func (adapter magicEdenAdapter) GetFloor(slug string) (uint64, error) {
+	res, err := httpClient.Get(fmt.Sprintf(
+		"%v/collections/%v/stats",
+		config.NFT_FLOOR_MAGIC_EDEN_API, url.PathEscape(slug),
+	))
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status: %d", res.StatusCode)
+	}
+
+	var stats struct {
+		FloorPrice uint64 `json:"floorPrice"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&stats); err != nil {
+		return 0, err
+	}
+	if stats.FloorPrice == 0 {
+		return 0, fmt.Errorf("no floor of collection: %s", slug)
+	}
+	return stats.FloorPrice, nil
+}
+
+func (adapter magicEdenAdapter) GetRecentSales(slug string) ([]NftSale, error) {
+	var activities []struct {
+		Type      string  `json:"type"`
+		TokenMint string  `json:"tokenMint"`
+		BlockTime int64   `json:"blockTime"`
+		Price     float64 `json:"price"`
+	}
+	if err := getJson(fmt.Sprintf(
+		"%v/collections/%v/activities?offset=0&limit=%d",
+		config.NFT_FLOOR_MAGIC_EDEN_API, url.PathEscape(slug), config.NFT_SALES_LIMIT,
+	), &activities); err != nil {
+		return nil, err
+	}
+
+	sales := []NftSale{}
+	for _, activity := range activities {
+		if activity.Type != "buyNow" || activity.Price <= 0 {
+			continue
+		}
+		sales = append(sales, NftSale{
+			Mint:     activity.TokenMint,
+			Lamports: uint64(math.Round(activity.Price * math.Pow10(LAMPORTS_DECIMALS))),
+			At:       time.Unix(activity.BlockTime, 0),
+		})
+	}
+	return sales, nil
+}
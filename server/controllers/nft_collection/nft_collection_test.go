@@ -0,0 +1,227 @@
+package nft_collection
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/models"
+)
+
+func TestMedianLamports(t *testing.T) {
+	if median := medianLamports([]uint64{}); median != 0 {
+		t.Fatalf("expected 0 of no floors, got %d", median)
+	}
+	if median := medianLamports([]uint64{30, 10, 20}); median != 20 {
+		t.Fatalf("expected 20, got %d", median)
+	}
+	if median := medianLamports([]uint64{40, 10, 30, 20}); median != 20 {
+		t.Fatalf("expected lower middle 20, got %d", median)
+	}
+}
+
+func TestLamportsToChips(t *testing.T) {
+	// 2 SOL at 150 USD.
+	if chips := lamportsToChips(2000000000, 150); chips != 30000000 {
+		t.Fatalf("expected 30000000 chips, got %d", chips)
+	}
+}
+
+func TestTwap(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	window := 6 * time.Hour
+
+	// Only sample.
+	if price := twap(
+		[]floorSample{{Price: 100, At: now}},
+		now, window,
+	); price != 100 {
+		t.Fatalf("expected 100 of single sample, got %d", price)
+	}
+
+	// Spike weighs only the time since previous sample.
+	samples := []floorSample{
+		{Price: 100, At: now.Add(-5 * time.Hour)},
+		{Price: 100, At: now.Add(-time.Hour)},
+		{Price: 1000, At: now},
+	}
+	if price := twap(samples, now, window); price != 250 {
+		t.Fatalf("expected 250, got %d", price)
+	}
+	samples = append(samples, floorSample{Price: 1000, At: now.Add(30 * time.Minute)})
+	if price := twap(samples, now.Add(30*time.Minute), window); price != 325 {
+		t.Fatalf("expected 325, got %d", price)
+	}
+
+	// Samples before window are ignored.
+	samples = []floorSample{
+		{Price: 5000, At: now.Add(-7 * time.Hour)},
+		{Price: 100, At: now.Add(-time.Hour)},
+	}
+	if price := twap(samples, now, window); price != 100 {
+		t.Fatalf("expected 100 ignoring stale sample, got %d", price)
+	}
+}
+
+func TestClampFloorChange(t *testing.T) {
+	if price := clampFloorChange(0, 500, 1000); price != 500 {
+		t.Fatalf("expected unbounded 500 without floor, got %d", price)
+	}
+	if price := clampFloorChange(1000, 2000, 1000); price != 1100 {
+		t.Fatalf("expected 1100, got %d", price)
+	}
+	if price := clampFloorChange(1000, 100, 1000); price != 900 {
+		t.Fatalf("expected 900, got %d", price)
+	}
+	if price := clampFloorChange(1000, 1050, 1000); price != 1050 {
+		t.Fatalf("expected 1050, got %d", price)
+	}
+}
+
+func TestMagicEdenFloor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/collections/okay_bears/stats" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"symbol":"okay_bears","floorPrice":12500000000,"listedCount":120}`))
+	}))
+	defer server.Close()
+
+	prevApi := config.NFT_FLOOR_MAGIC_EDEN_API
+	config.NFT_FLOOR_MAGIC_EDEN_API = server.URL
+	defer func() { config.NFT_FLOOR_MAGIC_EDEN_API = prevApi }()
+
+	floor, err := magicEdenAdapter{}.GetFloor("okay_bears")
+	if err != nil {
+		t.Fatalf("failed to get floor: %v", err)
+	}
+	if floor != 12500000000 {
+		t.Fatalf("expected 12500000000, got %d", floor)
+	}
+	if _, err := (magicEdenAdapter{}).GetFloor("unknown"); err == nil {
+		t.Fatal("expected error of unknown collection")
+	}
+}
+
+// Ranks of a collection of 1000 mints named by their rank.
+func testRanks() map[string]uint {
+	ranks := map[string]uint{}
+	for i := uint(1); i <= 1000; i++ {
+		ranks[fmt.Sprintf("mint%d", i)] = i
+	}
+	return ranks
+}
+
+func TestValuate(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	ranks := testRanks()
+	floor := int64(10000000)
+
+	// Unranked and common NFTs are valued at floor.
+	if valuation := valuate(floor, nil, nil, 0, now); valuation.Price != floor ||
+		valuation.Method != ValuationFloor {
+		t.Fatalf("expected floor of unranked, got %v", valuation)
+	}
+	valuation := valuate(floor, &mintRarity{Rank: 800, Ranks: ranks}, nil, 0, now)
+	if valuation.Price != floor || valuation.Method != ValuationFloor || valuation.RarityRank != 800 {
+		t.Fatalf("expected floor of common, got %v", valuation)
+	}
+
+	// Top 1% is valued at its tier multiple.
+	valuation = valuate(floor, &mintRarity{Rank: 5, Ranks: ranks}, nil, 0, now)
+	if valuation.Price != floor*3 || valuation.Method != ValuationRarity {
+		t.Fatalf("expected 3x floor, got %v", valuation)
+	}
+
+	// Sales of the tier are preferred once enough, bounded to max multiple.
+	sales := []NftSale{
+		{Mint: "mint20", Lamports: 2000000000, At: now.Add(-time.Hour)},
+		{Mint: "mint30", Lamports: 2200000000, At: now.Add(-2 * time.Hour)},
+		{Mint: "mint2", Lamports: 9000000000, At: now.Add(-time.Hour)},            // Other tier
+		{Mint: "mint40", Lamports: 9000000000, At: now.Add(-30 * 24 * time.Hour)}, // Stale
+	}
+	valuation = valuate(floor, &mintRarity{Rank: 25, Ranks: ranks}, sales, 100, now)
+	if valuation.Method != ValuationRarity {
+		t.Fatalf("expected rarity with too few sales, got %v", valuation)
+	}
+	sales = append(sales, NftSale{Mint: "mint45", Lamports: 2500000000, At: now.Add(-time.Hour)})
+	valuation = valuate(floor, &mintRarity{Rank: 25, Ranks: ranks}, sales, 100, now)
+	if valuation.Price != lamportsToChips(2200000000, 100) || valuation.Method != ValuationTierSales {
+		t.Fatalf("expected median tier sale, got %v", valuation)
+	}
+	valuation = valuate(floor/100, &mintRarity{Rank: 25, Ranks: ranks}, sales, 100, now)
+	if valuation.Price != floor/100*3 || valuation.Method != ValuationTierSales {
+		t.Fatalf("expected sales bounded to 3x floor, got %v", valuation)
+	}
+	valuation = valuate(floor*100, &mintRarity{Rank: 25, Ranks: ranks}, sales, 100, now)
+	if valuation.Price != floor*100 {
+		t.Fatalf("expected sales bounded to floor, got %v", valuation)
+	}
+}
+
+func TestHowRareRanks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/collections/okaybears/only_rarity" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"result":{"api_code":200,"data":{"collection":"okaybears","items":[{"mint":"a","rank":1},{"mint":"b","rank":2}]}}}`))
+	}))
+	defer server.Close()
+
+	prevApi := config.NFT_RARITY_HOWRARE_API
+	config.NFT_RARITY_HOWRARE_API = server.URL
+	defer func() { config.NFT_RARITY_HOWRARE_API = prevApi }()
+
+	ranks, err := howRareProvider{}.GetRanks("okaybears")
+	if err != nil {
+		t.Fatalf("failed to get ranks: %v", err)
+	}
+	if len(ranks) != 2 || ranks["a"] != 1 || ranks["b"] != 2 {
+		t.Fatalf("unexpected ranks: %v", ranks)
+	}
+}
+
+// Ranks the test collection, counting calls.
+type fakeRarityProvider struct {
+	calls *int
+}
+
+func (provider fakeRarityProvider) Name() string {
+	return "fake_rarity"
+}
+
+func (provider fakeRarityProvider) Slug(collection *models.NftCollection) string {
+	if collection.Name != "valuate-nft-test" {
+		return ""
+	}
+	return collection.Name
+}
+
+func (provider fakeRarityProvider) GetRanks(slug string) (map[string]uint, error) {
+	*provider.calls++
+	return testRanks(), nil
+}
+
+func TestValuateNftReadsWarmedCaches(t *testing.T) {
+	calls := 0
+	RegisterRarityProvider(fakeRarityProvider{calls: &calls})
+	collection := models.NftCollection{Name: "valuate-nft-test", FloorPrice: 10000000}
+
+	// Cold cache values at floor without fetching.
+	valuation := ValuateNft(&collection, "mint5")
+	if valuation.Method != ValuationFloor || calls != 0 {
+		t.Fatalf("expected floor without fetching, got %v, calls: %d", valuation, calls)
+	}
+
+	// Warmed ranks are used.
+	warmRanks(&collection)
+	valuation = ValuateNft(&collection, "mint5")
+	if valuation.Method != ValuationRarity || valuation.Price != collection.FloorPrice*3 || calls != 1 {
+		t.Fatalf("expected rarity from warmed ranks, got %v, calls: %d", valuation, calls)
+	}
+}
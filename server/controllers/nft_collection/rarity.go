@@ -0,0 +1,196 @@
+package nft_collection
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/sirupsen/logrus"
+)
+
+// Providers ranking collection NFTs.
+var rarityProviders = []RarityProvider{
+	howRareProvider{},
+	moonRankProvider{},
+}
+var rarityProvidersMutex sync.RWMutex
+
+var rankCache = newTtlCache[map[string]uint]()
+
+/**
+* @External
+* Adds a provider ranking collection NFTs.
+ */
+func RegisterRarityProvider(provider RarityProvider) {
+	rarityProvidersMutex.Lock()
+	defer rarityProvidersMutex.Unlock()
+	rarityProviders = append(rarityProviders, provider)
+}
+
+func getRarityProviders() []RarityProvider {
+	rarityProvidersMutex.RLock()
+	defer rarityProvidersMutex.RUnlock()
+	return append([]RarityProvider{}, rarityProviders...)
+}
+
+/**
+* @Internal
+* Returns rarity of the mint by every provider ranking it,
+* choosing the least rare so that disagreeing providers never
+* over value a mint. Nil when no provider ranks it.
+* Reads ranks cached by `warmRanks` only.
+ */
+func getMintRarity(collection *models.NftCollection, mint string) *mintRarity {
+	var rarity *mintRarity
+	for _, provider := range getRarityProviders() {
+		slug := provider.Slug(collection)
+		if slug == "" {
+			continue
+		}
+		ranks, prs := rankCache.peek(provider.Name() + "/" + slug)
+		if !prs {
+			continue
+		}
+		rank, prs := ranks[mint]
+		if !prs || rank == 0 {
+			continue
+		}
+		candidate := &mintRarity{Rank: rank, Ranks: ranks}
+		if rarity == nil || rankBps(candidate) > rankBps(rarity) {
+			rarity = candidate
+		}
+	}
+	return rarity
+}
+
+/**
+* @Internal
+* Fetches ranks of the collection by every provider into cache,
+* once older than `config.NFT_RARITY_CACHE_TTL`.
+ */
+func warmRanks(collection *models.NftCollection) {
+	for _, provider := range getRarityProviders() {
+		slug := provider.Slug(collection)
+		if slug == "" {
+			continue
+		}
+		if _, err := rankCache.get(
+			provider.Name()+"/"+slug,
+			config.NFT_RARITY_CACHE_TTL,
+			func() (map[string]uint, error) { return provider.GetRanks(slug) },
+		); err != nil {
+			log.LogMessage(
+				"nft_collection_warmRanks",
+				"failed to get ranks",
+				"info",
+				logrus.Fields{
+					"collection": collection.ID,
+					"provider":   provider.Name(),
+					"error":      err.Error(),
+				},
+			)
+		}
+	}
+}
+
+/**
+* @Internal
+* Rank of the mint in bps of collection supply.
+ */
+func rankBps(rarity *mintRarity) int64 {
+	if rarity == nil || len(rarity.Ranks) == 0 {
+		return 10000
+	}
+	return int64(rarity.Rank) * 10000 / int64(len(rarity.Ranks))
+}
+
+func getJson(endpoint string, out interface{}) error {
+	res, err := httpClient.Get(endpoint)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status: %d", res.StatusCode)
+	}
+	return json.NewDecoder(res.Body).Decode(out)
+}
+
+// Ranks by HowRare collection rarity.
+type howRareProvider struct{}
+
+func (provider howRareProvider) Name() string {
+	return "howrare"
+}
+
+func (provider howRareProvider) Slug(collection *models.NftCollection) string {
+	return collection.HowRare
+}
+
+func (provider howRareProvider) GetRanks(slug string) (map[string]uint, error) {
+	var out struct {
+		Result struct {
+			Data struct {
+				Items []struct {
+					Mint string `json:"mint"`
+					Rank uint   `json:"rank"`
+				} `json:"items"`
+			} `json:"data"`
+		} `json:"result"`
+	}
+	if err := getJson(fmt.Sprintf(
+		"%v/collections/%v/only_rarity",
+		config.NFT_RARITY_HOWRARE_API, url.PathEscape(slug),
+	), &out); err != nil {
+		return nil, err
+	}
+
+	ranks := make(map[string]uint, len(out.Result.Data.Items))
+	for _, item := range out.Result.Data.Items {
+		ranks[item.Mint] = item.Rank
+	}
+	if len(ranks) == 0 {
+		return nil, fmt.Errorf("no ranks of collection: %s", slug)
+	}
+	return ranks, nil
+}
+
+// Ranks by MoonRank collection rarity.
+type moonRankProvider struct{}
+
+func (provider moonRankProvider) Name() string {
+	return "moonrank"
+}
+
+func (provider moonRankProvider) Slug(collection *models.NftCollection) string {
+	return collection.MoonRank
+}
+
+func (provider moonRankProvider) GetRanks(slug string) (map[string]uint, error) {
+	var out struct {
+		Mints []struct {
+			Mint string `json:"mint"`
+			Rank uint   `json:"rank"`
+		} `json:"mints"`
+	}
+	if err := getJson(fmt.Sprintf(
+		"%v/mints/%v",
+		config.NFT_RARITY_MOONRANK_API, url.PathEscape(slug),
+	), &out); err != nil {
+		return nil, err
+	}
+
+	ranks := make(map[string]uint, len(out.Mints))
+	for _, item := range out.Mints {
+		ranks[item.Mint] = item.Rank
+	}
+	if len(ranks) == 0 {
+		return nil, fmt.Errorf("no ranks of collection: %s", slug)
+	}
+	return ranks, nil
+}
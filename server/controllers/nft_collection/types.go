@@ -0,0 +1,70 @@
+package nft_collection
+
+import (
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/models"
+)
+
+// Quotes collection floors from a marketplace.
+type MarketplaceAdapter interface {
+	Name() string
+	// Slug of the collection on the marketplace, empty when not listed.
+	Slug(collection *models.NftCollection) string
+	// Floor price in lamports.
+	GetFloor(slug string) (uint64, error)
+}
+
+// Floor price of a collection observed at a time, in chips.
+type floorSample struct {
+	Price int64
+	At    time.Time
+}
+
+type CollectionNft struct {
+	Name        string `json:"name"`
+	MintAddress string `json:"mintAddress"`
+	Image       string `json:"image"`
+}
+
+// Ranks collection NFTs by rarity.
+type RarityProvider interface {
+	Name() string
+	// Slug of the collection on the provider, empty when not ranked.
+	Slug(collection *models.NftCollection) string
+	// Ranks of every mint of the collection, 1 being the rarest.
+	GetRanks(slug string) (map[string]uint, error)
+}
+
+// Reports recent collection sales.
+type SalesProvider interface {
+	Name() string
+	Slug(collection *models.NftCollection) string
+	GetRecentSales(slug string) ([]NftSale, error)
+}
+
+type NftSale struct {
+	Mint     string    `json:"mint"`
+	Lamports uint64    `json:"lamports"`
+	At       time.Time `json:"at"`
+}
+
+const (
+	ValuationFloor     = "floor"
+	ValuationRarity    = "rarity"
+	ValuationTierSales = "tier_sales"
+)
+
+// Price of an NFT in chips and how it was chosen.
+type NftValuation struct {
+	Price      int64  `json:"price"`
+	Method     string `json:"method"`
+	FloorPrice int64  `json:"floorPrice"`
+	RarityRank uint   `json:"rarityRank"`
+}
+
+// Rank of a mint along with the ranking it belongs to.
+type mintRarity struct {
+	Rank  uint
+	Ranks map[string]uint
+}
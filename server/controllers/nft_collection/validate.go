@@ -0,0 +1,74 @@
+package nft_collection
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+)
+
+/**
+* @Internal
+* Validates collection saved by admin.
+ */
+func validateCollection(collection *models.NftCollection, nfts []CollectionNft) error {
+	if collection == nil {
+		return utils.MakeErrorWithCode(
+			"nft_collection_validate",
+			"validateCollection",
+			"invalid parameter",
+			ErrCodeInvalidParameter,
+			errors.New("provided collection is nil pointer"),
+		)
+	}
+	if collection.Name == "" || collection.Image == "" {
+		return utils.MakeErrorWithCode(
+			"nft_collection_validate",
+			"validateCollection",
+			"invalid parameter",
+			ErrCodeInvalidParameter,
+			errors.New("name and image are required"),
+		)
+	}
+	if collection.FloorPrice < 0 {
+		return utils.MakeErrorWithCode(
+			"nft_collection_validate",
+			"validateCollection",
+			"invalid parameter",
+			ErrCodeInvalidParameter,
+			fmt.Errorf("floor price: %d", collection.FloorPrice),
+		)
+	}
+	for _, nft := range nfts {
+		if nft.MintAddress == "" || nft.Name == "" {
+			return utils.MakeErrorWithCode(
+				"nft_collection_validate",
+				"validateCollection",
+				"invalid parameter",
+				ErrCodeInvalidParameter,
+				fmt.Errorf("nft: %v", nft),
+			)
+		}
+	}
+	return nil
+}
+
+/**
+* @Internal
+* Validates status set by admin. Collections are never moved
+* back to pending.
+ */
+func validateStatus(status models.NftCollectionStatus) error {
+	if status != models.NftCollectionApproved &&
+		status != models.NftCollectionDisabled {
+		return utils.MakeErrorWithCode(
+			"nft_collection_validate",
+			"validateStatus",
+			"invalid parameter",
+			ErrCodeInvalidParameter,
+			fmt.Errorf("status: %s", status),
+		)
+	}
+	return nil
+}
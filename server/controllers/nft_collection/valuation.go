@@ -0,0 +1,197 @@
+package nft_collection
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/types"
+	"github.com/sirupsen/logrus"
+)
+
+var salesCache = newTtlCache[[]NftSale]()
+
+// SOL price of the latest floor update, valuing tier sales.
+var solPriceCache = newTtlCache[float64]()
+
+const SOL_PRICE_CACHE_KEY = "sol"
+
+/**
+* @External
+* Values the mint of the collection by its rarity and recent
+* sales of its rarity tier, bounded relative to floor. Falls
+* back to floor when the mint is not ranked.
+* Reads ranks, sales and SOL price cached by the floor updater
+* only, so that betting never waits for marketplaces.
+ */
+func ValuateNft(collection *models.NftCollection, mint string) NftValuation {
+	if collection == nil {
+		return NftValuation{Method: ValuationFloor}
+	}
+	if collection.FloorPrice <= 0 {
+		return valuate(collection.FloorPrice, nil, nil, 0, time.Now())
+	}
+
+	rarity := getMintRarity(collection, mint)
+	sales := []NftSale{}
+	solPrice := float64(0)
+	if rarity != nil && rarityTier(rankBps(rarity), config.NFT_RARITY_TIERS) >= 0 {
+		sales = getRecentSales(collection)
+		solPrice, _ = solPriceCache.peek(SOL_PRICE_CACHE_KEY)
+	}
+	return valuate(collection.FloorPrice, rarity, sales, solPrice, time.Now())
+}
+
+/**
+* @Internal
+* Returns recent sales of the collection on every marketplace.
+* Reads sales cached by `warmSales` only.
+ */
+func getRecentSales(collection *models.NftCollection) []NftSale {
+	sales := []NftSale{}
+	for _, provider := range getSalesProviders() {
+		slug := provider.Slug(collection)
+		if slug == "" {
+			continue
+		}
+		providerSales, prs := salesCache.peek(provider.Name() + "/" + slug)
+		if !prs {
+			continue
+		}
+		sales = append(sales, providerSales...)
+	}
+	return sales
+}
+
+/**
+* @Internal
+* Fetches recent sales of the collection on every marketplace
+* into cache, once older than `config.NFT_SALES_CACHE_TTL`.
+ */
+func warmSales(collection *models.NftCollection) {
+	for _, provider := range getSalesProviders() {
+		slug := provider.Slug(collection)
+		if slug == "" {
+			continue
+		}
+		if _, err := salesCache.get(
+			provider.Name()+"/"+slug,
+			config.NFT_SALES_CACHE_TTL,
+			func() ([]NftSale, error) { return provider.GetRecentSales(slug) },
+		); err != nil {
+			log.LogMessage(
+				"nft_collection_warmSales",
+				"failed to get recent sales",
+				"info",
+				logrus.Fields{
+					"collection": collection.ID,
+					"provider":   provider.Name(),
+					"error":      err.Error(),
+				},
+			)
+		}
+	}
+}
+
+/**
+* @Internal
+* Values an NFT at `floor`.
+* 1. Unranked or common NFTs are valued at floor.
+* 2. Ranked NFTs are valued at their rarity tier multiple of floor,
+*    or at median of recent sales in the tier when there are enough.
+* 3. Value is bounded relative to floor.
+ */
+func valuate(floor int64, rarity *mintRarity, sales []NftSale, solPrice float64, now time.Time) NftValuation {
+	valuation := NftValuation{
+		Price:      floor,
+		Method:     ValuationFloor,
+		FloorPrice: floor,
+	}
+	if floor <= 0 || rarity == nil {
+		return valuation
+	}
+	valuation.RarityRank = rarity.Rank
+
+	// 1. Rarity tier multiple.
+	tier := rarityTier(rankBps(rarity), config.NFT_RARITY_TIERS)
+	if tier < 0 {
+		return valuation
+	}
+	valuation.Price = floor * config.NFT_RARITY_TIERS[tier].Multiplier / 10000
+	valuation.Method = ValuationRarity
+
+	// 2. Recent sales of the tier.
+	if solPrice > 0 {
+		tierSales := tierSaleLamports(
+			sales,
+			rarity.Ranks,
+			tier,
+			now.Add(-config.NFT_VALUATION_SALES_WINDOW),
+		)
+		if len(tierSales) >= config.NFT_VALUATION_MIN_SALES {
+			valuation.Price = lamportsToChips(medianLamports(tierSales), solPrice)
+			valuation.Method = ValuationTierSales
+		}
+	}
+
+	// 3. Bound relative to floor.
+	valuation.Price = boundValuation(floor, valuation.Price)
+	return valuation
+}
+
+/**
+* @Internal
+* Index of the rarest tier including `rankBps`, -1 when none.
+ */
+func rarityTier(rankBps int64, tiers []types.RarityTier) int {
+	for i, tier := range tiers {
+		if rankBps <= tier.Top {
+			return i
+		}
+	}
+	return -1
+}
+
+/**
+* @Internal
+* Prices of sales since `since` of mints in the rarity tier.
+ */
+func tierSaleLamports(sales []NftSale, ranks map[string]uint, tier int, since time.Time) []uint64 {
+	lamports := []uint64{}
+	for _, sale := range sales {
+		if sale.At.Before(since) {
+			continue
+		}
+		rank, prs := ranks[sale.Mint]
+		if !prs || rank == 0 {
+			continue
+		}
+		if rarityTier(
+			rankBps(&mintRarity{Rank: rank, Ranks: ranks}),
+			config.NFT_RARITY_TIERS,
+		) != tier {
+			continue
+		}
+		lamports = append(lamports, sale.Lamports)
+	}
+	sort.Slice(lamports, func(i, j int) bool { return lamports[i] < lamports[j] })
+	return lamports
+}
+
+/**
+* @Internal
+* Bounds value to configured multiples of floor.
+ */
+func boundValuation(floor int64, price int64) int64 {
+	lower := floor * config.NFT_VALUATION_MIN_MULTIPLE / 10000
+	upper := floor * config.NFT_VALUATION_MAX_MULTIPLE / 10000
+	if price < lower {
+		return lower
+	}
+	if price > upper {
+		return upper
+	}
+	return price
+}
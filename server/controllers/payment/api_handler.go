@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/Duelana-Team/duelana-v1/controllers/price_oracle"
 	"github.com/Duelana-Team/duelana-v1/controllers/solana"
 	userController "github.com/Duelana-Team/duelana-v1/controllers/user"
 	"github.com/Duelana-Team/duelana-v1/db"
@@ -11,7 +12,6 @@ import (
 	"github.com/Duelana-Team/duelana-v1/middlewares"
 	"github.com/Duelana-Team/duelana-v1/models"
 	"github.com/Duelana-Team/duelana-v1/types"
-	"github.com/Duelana-Team/duelana-v1/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
@@ -27,17 +27,26 @@ import (
 // @Router /api/sol-price [GET]
 func (c *Controller) TokenPrices(ctx *gin.Context) {
 	supportedTokens := solana.SupportedSpls()
-	var tokens = make(map[string]string)
-	for _, token := range supportedTokens {
-		tokens[token.Keyword] = token.MintAddress.String()
-		// tokens = append(tokens, token.MintAddress)
+	prices := make(map[string]float64)
+	for i, token := range supportedTokens {
+		price, err := price_oracle.GetPrice(&supportedTokens[i])
+		if err != nil {
+			log.LogMessage("payment token prices handler", "skipped token at unsafe price", "info", logrus.Fields{"token": token.Keyword, "error": err.Error()})
+			continue
+		}
+		prices[token.Keyword] = price.Price
 	}
 
-	ctx.JSON(200, utils.FetchTokenPrice(tokens))
+	ctx.JSON(200, prices)
 }
 
 func (c *Controller) Tokens(ctx *gin.Context) {
-	supportedTokens := solana.SupportedSpls()
+	supportedTokens := []solana.SplTokenMeta{}
+	for _, token := range solana.SupportedSpls() {
+		if token.DepositEnabled || token.WithdrawEnabled {
+			supportedTokens = append(supportedTokens, token)
+		}
+	}
 	ctx.JSON(200, supportedTokens)
 }
 
@@ -214,7 +223,14 @@ func (c *Controller) AcceptableNfts(ctx *gin.Context) {
 
 	db := db.GetDB()
 	var acceptableNfts []models.DepositedNft
-	db.Where("mint_address IN ?", param.MintAddresses).Find(&acceptableNfts)
+	db.Where("mint_address IN ?", param.MintAddresses).
+		Where(
+			"collection_id IN (?)",
+			db.Model(&models.NftCollection{}).
+				Select("id").
+				Where("status = ?", models.NftCollectionApproved),
+		).
+		Find(&acceptableNfts)
 
 	nfts := []types.NftDetails{}
 
@@ -0,0 +1,174 @@
+package payment
+
+import (
+	"errors"
+
+	"github.com/Duelana-Team/duelana-v1/controllers/transaction/db_aggregator"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"gorm.io/gorm"
+)
+
+/**
+* @Internal
+* Checks whether a payment with the tx hash is already settled.
+ */
+func isPaymentSettled(txHash string) (bool, error) {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return false, utils.MakeError(
+			"payment_db",
+			"isPaymentSettled",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	var count int64
+	if err := session.Model(
+		&models.Payment{},
+	).Where(
+		"tx_hash = ?",
+		txHash,
+	).Where(
+		"status <> ?",
+		models.Pending,
+	).Count(&count).Error; err != nil {
+		return false, utils.MakeError(
+			"payment_db",
+			"isPaymentSettled",
+			"failed to count payments",
+			err,
+		)
+	}
+	return count > 0, nil
+}
+
+/**
+* @Internal
+* Returns the watcher cursor of the address.
+* Returns a new cursor not saved yet if not exists.
+ */
+func getWatcherCursor(address string) (*models.DepositWatcherCursor, error) {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"payment_db",
+			"getWatcherCursor",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	cursor := models.DepositWatcherCursor{}
+	if err := session.Where(
+		"address = ?",
+		address,
+	).First(&cursor).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.MakeError(
+				"payment_db",
+				"getWatcherCursor",
+				"failed to get cursor",
+				err,
+			)
+		}
+		cursor.Address = address
+	}
+	return &cursor, nil
+}
+
+/**
+* @Internal
+* Creates or updates the watcher cursor.
+ */
+func saveWatcherCursor(cursor *models.DepositWatcherCursor) error {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return utils.MakeError(
+			"payment_db",
+			"saveWatcherCursor",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	if err := session.Save(cursor).Error; err != nil {
+		return utils.MakeError(
+			"payment_db",
+			"saveWatcherCursor",
+			"failed to save cursor",
+			err,
+		)
+	}
+	return nil
+}
+
+/**
+* @Internal
+* Puts a deposit payment which was not credited back to the
+* queue of `retryQueuedDeposits`.
+ */
+func requeueDeposit(paymentID uint) error {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return utils.MakeError(
+			"payment_db",
+			"requeueDeposit",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	if err := session.Model(
+		&models.Payment{},
+	).Where(
+		"id = ?",
+		paymentID,
+	).Where(
+		"transaction_id IS NULL",
+	).Updates(map[string]interface{}{
+		"status":     models.Pending,
+		"usd_amount": 0,
+	}).Error; err != nil {
+		return utils.MakeError(
+			"payment_db",
+			"requeueDeposit",
+			"failed to update payment",
+			err,
+		)
+	}
+	return nil
+}
+
+/**
+* @Internal
+* Deletes a deposit payment which was not credited, so that
+* the deposit is recorded again when handled next time.
+ */
+func deleteUncreditedPayment(paymentID uint) error {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return utils.MakeError(
+			"payment_db",
+			"deleteUncreditedPayment",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	if err := session.Unscoped().Where(
+		"id = ?",
+		paymentID,
+	).Where(
+		"transaction_id IS NULL",
+	).Delete(&models.Payment{}).Error; err != nil {
+		return utils.MakeError(
+			"payment_db",
+			"deleteUncreditedPayment",
+			"failed to delete payment",
+			err,
+		)
+	}
+	return nil
+}
@@ -2,14 +2,15 @@ package payment
 
 import (
 	"encoding/json"
-	"errors"
-	"fmt"
 	"io"
 	"math"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/controllers/coupon"
+	"github.com/Duelana-Team/duelana-v1/controllers/price_oracle"
 	"github.com/Duelana-Team/duelana-v1/controllers/solana"
 	"github.com/Duelana-Team/duelana-v1/controllers/transaction"
 	"github.com/Duelana-Team/duelana-v1/controllers/transaction/db_aggregator"
@@ -22,64 +23,125 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func (c *Controller) depositChip(walletAddress string, decodedResult *solana.DecodedTransaction, txId string) {
+/**
+* @Internal
+* Records and credits a spl deposit. Returns an error if the
+* deposit should be handled again.
+ */
+func (c *Controller) depositChip(walletAddress string, decodedResult *solana.DecodedTransaction, txId string) error {
 	db := db.GetDB()
-	var user models.User
-	if result := db.Where("wallet_address = ?", walletAddress).First(&user); result.Error != nil {
-		log.LogMessage("payment chip deposit handler", "can not find User from db", "error", logrus.Fields{"wallet": walletAddress})
-		return
+	user, attribution, err := findDepositUser(decodedResult)
+	if err != nil {
+		log.LogMessage("payment chip deposit handler", "failed to find deposit user", "error", logrus.Fields{"wallet": walletAddress, "reference": decodedResult.Reference, "error": err.Error()})
+		return err
+	}
+	if user == nil {
+		log.LogMessage("payment chip deposit handler", "can not find User from db", "error", logrus.Fields{"wallet": walletAddress, "reference": decodedResult.Reference})
+		return nil
 	}
+	log.LogMessage("payment chip deposit handler", "deposit attributed", "info", logrus.Fields{"user": user.ID, "by": attribution, "tx": txId})
 
 	var pay models.Payment
 	if err := db.Where("tx_hash = ?", txId).First(&pay).Error; err == nil {
 		log.LogMessage("payment chip deposit handler", "duplicated transaction", "error", logrus.Fields{"tx": txId})
-		return
+		return nil
 	}
 
-	conf := config.Get()
-	var splAmount = float32(float64(decodedResult.Lamports) / math.Pow10(decodedResult.SplToken.Decimals))
-	var cashAmount int64
-	if conf.Network == "mainnet" && config.USDC_SPL_ADDRESS != decodedResult.SplToken.MintAddress.String() {
-		usdAmount, err := utils.SwapTokens(splAmount, decodedResult.SplToken.MintAddress.String(), config.USDC_SPL_ADDRESS)
-		if err != nil {
-			log.LogMessage("payment chip deposit handler", "failed to swap SPL to USDC via Jupiter instance", "error", logrus.Fields{"err": err, "amount": splAmount})
-			tm := map[string]string{decodedResult.SplToken.Keyword: decodedResult.SplToken.MintAddress.String()}
-			prices := utils.FetchTokenPrice(tm)
-			cashAmount = int64(float64(decodedResult.Lamports) * float64(prices[decodedResult.SplToken.Keyword]) * math.Pow10(config.BALANCE_DECIMALS) / math.Pow10(decodedResult.SplToken.Decimals))
-		} else {
-			log.LogMessage("payment chip deposit handler", "swap SPL => USDC succeed.", "success", logrus.Fields{"spl": splAmount, "USDC": usdAmount})
-			cashAmount = int64(float64(usdAmount) * math.Pow10(config.BALANCE_DECIMALS))
+	depositedAt := decodedResult.BlockTime
+	if depositedAt.IsZero() {
+		depositedAt = time.Now()
+	}
+
+	// Queue deposits refused by token registry for manual review,
+	// the tokens are already in the treasury.
+	if rejection := depositRejection(decodedResult.SplToken, decodedResult.Lamports); rejection != nil {
+		log.LogMessage("payment chip deposit handler", "deposit rejected by token registry", "error", logrus.Fields{"user": user.ID, "token": decodedResult.SplToken.Keyword, "amount": decodedResult.Lamports, "tx": txId, "reason": rejection.Error()})
+		payment := models.Payment{
+			UserID: user.ID,
+			Type:   "deposit_" + strings.ToLower(decodedResult.SplToken.Keyword),
+			Status: models.Pending,
+			SolDetail: models.SolDetail{
+				SolAmount: int64(decodedResult.Lamports),
+			},
+			TxHash: txId,
 		}
-	} else {
-		tm := map[string]string{decodedResult.SplToken.Keyword: decodedResult.SplToken.MintAddress.String()}
-		prices := utils.FetchTokenPrice(tm)
-		cashAmount = int64(float64(decodedResult.Lamports) * float64(prices[decodedResult.SplToken.Keyword]) * math.Pow10(config.BALANCE_DECIMALS) / math.Pow10(decodedResult.SplToken.Decimals))
+		if result := db.Create(&payment); result.Error != nil {
+			log.LogMessage("payment chip deposit handler", "failed to record rejected deposit", "error", logrus.Fields{"tx": txId, "error": result.Error.Error()})
+			return result.Error
+		}
+		savePriceSnapshot(payment.ID, decodedResult.SplToken.MintAddress.String(), &depositedAt, nil, rejection)
+		b, _ := json.Marshal(struct {
+			EventType string `json:"eventType"`
+			TxID      string `json:"txId"`
+			Reason    string `json:"reason"`
+		}{EventType: "deposit_rejected", TxID: txId, Reason: rejection.Error()})
+		c.EventEmitter <- types.WSEvent{Users: []uint{user.ID}, Message: b}
+		return nil
 	}
 
+	// Value at oracle price, queue at unsafe price to be valued
+	// at the block time later.
+	price, priceErr := price_oracle.GetPrice(decodedResult.SplToken)
 	payment := models.Payment{
 		UserID: user.ID,
 		Type:   "deposit_" + strings.ToLower(decodedResult.SplToken.Keyword),
 		Status: models.Success,
 		SolDetail: models.SolDetail{
 			SolAmount: int64(decodedResult.Lamports),
-			UsdAmount: cashAmount,
 		},
 		TxHash: txId,
 	}
+	if priceErr == nil {
+		payment.SolDetail.UsdAmount = depositChips(decodedResult.Lamports, decodedResult.SplToken.Decimals, price.Price)
+	} else {
+		payment.Status = models.Pending
+	}
 	if result := db.Create(&payment); result.Error != nil {
-		log.LogMessage("payment chip deposit handler", "failed to create record on payments", "error", logrus.Fields{})
-		return
+		log.LogMessage("payment chip deposit handler", "failed to create record on payments", "error", logrus.Fields{"tx": txId, "error": result.Error.Error()})
+		return result.Error
 	}
+	savePriceSnapshot(payment.ID, decodedResult.SplToken.MintAddress.String(), &depositedAt, price, priceErr)
+	if priceErr != nil {
+		log.LogMessage("payment chip deposit handler", "deposit queued at unsafe price", "error", logrus.Fields{"user": user.ID, "payment": payment.ID, "tx": txId, "error": priceErr.Error()})
+		b, _ := json.Marshal(struct {
+			EventType string `json:"eventType"`
+			TxID      string `json:"txId"`
+		}{EventType: "deposit_queued", TxID: txId})
+		c.EventEmitter <- types.WSEvent{Users: []uint{user.ID}, Message: b}
+		return nil
+	}
+
+	// Queue again to be credited by `retryQueuedDeposits`.
+	if err := c.creditDeposit(&payment, decodedResult.SplToken); err != nil {
+		if err := requeueDeposit(payment.ID); err != nil {
+			log.LogMessage("payment chip deposit handler", "failed to queue uncredited deposit", "error", logrus.Fields{"payment": payment.ID, "tx": txId, "error": err.Error()})
+		}
+		return err
+	}
+	return nil
+}
+
+/**
+* @Internal
+* Credits a valued deposit payment to its user with deposit bonuses,
+* then swaps deposited tokens to USDC on mainnet.
+* Returns an error only if nothing was credited.
+ */
+func (c *Controller) creditDeposit(payment *models.Payment, token *solana.SplTokenMeta) error {
+	db := db.GetDB()
+	cashAmount := payment.SolDetail.UsdAmount
+	txId := payment.TxHash
+	userID := payment.UserID
 
 	tx, err := transaction.Transfer(&transaction.TransactionRequest{
 		FromUser: nil,
-		ToUser:   (*db_aggregator.User)(&user.ID),
+		ToUser:   (*db_aggregator.User)(&userID),
 		Balance: db_aggregator.BalanceLoad{
 			ChipBalance: &cashAmount,
 		},
 		Type:          models.TxDepositSol,
 		ToBeConfirmed: true,
-		OwnerID:       user.ID,
+		OwnerID:       userID,
 		OwnerType:     models.TransactionUserReferenced,
 	})
 	if err != nil {
@@ -88,21 +150,21 @@ func (c *Controller) depositChip(walletAddress string, decodedResult *solana.Dec
 			"can not transfer balances",
 			"error",
 			logrus.Fields{
-				"error":  err.Error(),
-				"wallet": walletAddress},
+				"error":   err.Error(),
+				"payment": payment.ID},
 		)
-		return
+		return err
 	}
 
 	payment.TransactionID = (*uint)(tx)
-	if result := db.Save(&payment); result.Error != nil {
-		log.LogMessage("payment chip deposit handler", "failed to save payment with transaction id", "error", logrus.Fields{})
-		return
+	if result := db.Save(payment); result.Error != nil {
+		log.LogMessage("payment chip deposit handler", "failed to save payment with transaction id", "error", logrus.Fields{"payment": payment.ID, "error": result.Error.Error()})
+		return nil
 	}
 
 	// Try to applying first deposit bonus.
 	bonusBalance, err := transaction.TryApplyForFirstDepositBonus(
-		user.ID,
+		userID,
 		cashAmount,
 	)
 	if err != nil {
@@ -111,19 +173,39 @@ func (c *Controller) depositChip(walletAddress string, decodedResult *solana.Dec
 			"failed to perform first deposit bonus",
 			"error",
 			logrus.Fields{
-				"userID":        user.ID,
+				"userID":        userID,
+				"depositAmount": cashAmount,
+				"error":         err.Error(),
+			},
+		)
+	}
+
+	// Try to applying deposit match bonus campaign.
+	depositBonus, err := coupon.TryApplyDepositBonus(
+		userID,
+		cashAmount,
+		payment.ID,
+	)
+	if err != nil {
+		log.LogMessage(
+			"payment_internal_depositChips",
+			"failed to perform deposit bonus",
+			"error",
+			logrus.Fields{
+				"userID":        userID,
 				"depositAmount": cashAmount,
 				"error":         err.Error(),
 			},
 		)
 	}
+	bonusBalance += depositBonus
 
 	b, _ := json.Marshal(struct {
 		EventType string `json:"eventType"`
 		TxID      string `json:"txId"`
 		Amount    uint   `json:"amount"`
 	}{EventType: "deposit_sol", TxID: txId, Amount: uint(cashAmount)})
-	c.EventEmitter <- types.WSEvent{Users: []uint{user.ID}, Message: b}
+	c.EventEmitter <- types.WSEvent{Users: []uint{userID}, Message: b}
 
 	b, _ = json.Marshal(types.WSMessage{
 		EventType: "balance_update",
@@ -133,7 +215,7 @@ func (c *Controller) depositChip(walletAddress string, decodedResult *solana.Dec
 			BalanceType: models.ChipBalanceForGame,
 			Delay:       0,
 		}})
-	c.EventEmitter <- types.WSEvent{Users: []uint{user.ID}, Message: b}
+	c.EventEmitter <- types.WSEvent{Users: []uint{userID}, Message: b}
 
 	// Send coupon balance updating event if any redeemed.
 	if bonusBalance > 0 {
@@ -145,24 +227,46 @@ func (c *Controller) depositChip(walletAddress string, decodedResult *solana.Dec
 				BalanceType: models.CouponBalanceForGame,
 				Delay:       0,
 			}})
-		c.EventEmitter <- types.WSEvent{Users: []uint{user.ID}, Message: b}
+		c.EventEmitter <- types.WSEvent{Users: []uint{userID}, Message: b}
 	}
 
-	log.LogMessage("payment chip deposit handler", "deposit chip succeed", "success", logrus.Fields{"user": user.ID, "amount": cashAmount})
+	log.LogMessage("payment chip deposit handler", "deposit chip succeed", "success", logrus.Fields{"user": userID, "amount": cashAmount})
+
+	if config.Get().Network == "mainnet" && config.USDC_SPL_ADDRESS != token.MintAddress.String() {
+		splAmount := float32(float64(payment.SolDetail.SolAmount) / math.Pow10(token.Decimals))
+		usdAmount, err := utils.SwapTokens(splAmount, token.MintAddress.String(), config.USDC_SPL_ADDRESS)
+		if err != nil {
+			log.LogMessage("payment chip deposit handler", "failed to swap SPL to USDC via Jupiter instance", "error", logrus.Fields{"err": err, "amount": splAmount})
+			return nil
+		}
+		log.LogMessage("payment chip deposit handler", "swap SPL => USDC succeed.", "success", logrus.Fields{"spl": splAmount, "USDC": usdAmount})
+	}
+	return nil
 }
 
-func (c *Controller) depositNfts(walletAddress string, mintAddresses []string, txId string) {
+/**
+* @Internal
+* Records and credits a nft deposit. Returns an error if the
+* deposit should be handled again.
+ */
+func (c *Controller) depositNfts(walletAddress string, decodedResult *solana.DecodedTransaction, txId string) error {
 	db := db.GetDB()
-	var user models.User
-	if result := db.Where("wallet_address = ?", walletAddress).First(&user); result.Error != nil {
-		log.LogMessage("payment nft deposit handler", "failed to get User data from db", "error", logrus.Fields{"wallet": walletAddress})
-		return
+	mintAddresses := decodedResult.Nfts
+	user, attribution, err := findDepositUser(decodedResult)
+	if err != nil {
+		log.LogMessage("payment nft deposit handler", "failed to get User data from db", "error", logrus.Fields{"wallet": walletAddress, "reference": decodedResult.Reference, "error": err.Error()})
+		return err
 	}
+	if user == nil {
+		log.LogMessage("payment nft deposit handler", "can not find User from db", "error", logrus.Fields{"wallet": walletAddress, "reference": decodedResult.Reference})
+		return nil
+	}
+	log.LogMessage("payment nft deposit handler", "deposit attributed", "info", logrus.Fields{"user": user.ID, "by": attribution, "tx": txId})
 
 	var pay models.Payment
 	if err := db.Where("tx_hash = ?", txId).First(&pay).Error; err == nil {
-		log.LogMessage("payment nft deposit handler", "duplicated transaction", "error", logrus.Fields{"tx": txId, "error": err.Error()})
-		return
+		log.LogMessage("payment nft deposit handler", "duplicated transaction", "error", logrus.Fields{"tx": txId})
+		return nil
 	}
 
 	payment := models.Payment{
@@ -176,7 +280,7 @@ func (c *Controller) depositNfts(walletAddress string, mintAddresses []string, t
 	}
 	if result := db.Create(&payment); result.Error != nil {
 		log.LogMessage("payment nft deposit handler", "failed to create record on payments", "error", logrus.Fields{"error": result.Error.Error()})
-		return
+		return result.Error
 	}
 
 	tx, err := transaction.Transfer(&transaction.TransactionRequest{
@@ -192,13 +296,17 @@ func (c *Controller) depositNfts(walletAddress string, mintAddresses []string, t
 	})
 	if err != nil {
 		log.LogMessage("payment nft deposit handler", "Can not transfer balances", "error", logrus.Fields{"error": err.Error()})
-		return
+		// Dropped to be recorded again on retry.
+		if err := deleteUncreditedPayment(payment.ID); err != nil {
+			log.LogMessage("payment nft deposit handler", "failed to delete uncredited payment", "error", logrus.Fields{"payment": payment.ID, "tx": txId, "error": err.Error()})
+		}
+		return err
 	}
 
 	payment.TransactionID = (*uint)(tx)
 	if result := db.Save(&payment); result.Error != nil {
 		log.LogMessage("payment nft deposit handler", "failed to save payment with transaction id", "error", logrus.Fields{"error": result.Error.Error()})
-		return
+		return nil
 	}
 
 	b, _ := json.Marshal(struct {
@@ -219,6 +327,7 @@ func (c *Controller) depositNfts(walletAddress string, mintAddresses []string, t
 	c.EventEmitter <- types.WSEvent{Users: []uint{user.ID}, Message: b}
 
 	log.LogMessage("payment nft deposit handler", "deposit nft succeed", "success", logrus.Fields{"user": user.ID, "nfts": mintAddresses, "tx": txId})
+	return nil
 }
 
 func parseSolanaTransaction(byteArray []byte) types.TransactionDataResult {
@@ -253,31 +362,3 @@ func getSolanaTransaction(txID string) (types.TransactionDataResult, error) {
 
 	return result, nil
 }
-
-// Temporary function to handle migeration from `MintAddresses` to `Mints`
-func MigratePaymentModel() error {
-	const MIGRATE_DONE = "migrate done"
-	db := db.GetDB()
-	var allPayments []models.Payment
-	if result := db.Find(&allPayments); result.Error != nil {
-		return fmt.Errorf("failed to migrate Payment Model. \r\nError: %v", result.Error)
-	}
-
-	totalError := errors.New("")
-	for _, payment := range allPayments {
-		if payment.NftDetail.MintAddresses == "" || payment.NftDetail.MintAddresses == MIGRATE_DONE {
-			continue
-		}
-		if err := json.Unmarshal([]byte(payment.NftDetail.MintAddresses), &payment.NftDetail.Mints); err != nil {
-			totalError = fmt.Errorf("%v\r\n%v", totalError.Error(), err.Error())
-			continue
-		}
-		payment.NftDetail.MintAddresses = MIGRATE_DONE
-		db.Save(&payment)
-	}
-
-	if totalError.Error() == "" {
-		return nil
-	}
-	return totalError
-}
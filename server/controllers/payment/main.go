@@ -5,6 +5,8 @@ import (
 	"crypto/sha512"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"math"
 	"net/http"
@@ -12,6 +14,7 @@ import (
 	"time"
 
 	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/controllers/price_oracle"
 	"github.com/Duelana-Team/duelana-v1/controllers/solana"
 	"github.com/Duelana-Team/duelana-v1/controllers/transaction"
 	"github.com/Duelana-Team/duelana-v1/controllers/transaction/db_aggregator"
@@ -39,6 +42,11 @@ func (c *Controller) Init(withdrawWaitTime time.Duration, withdrawReviewDelay ti
 	c.withdrawReviewDelay = withdrawReviewDelay
 	c.player2BlockStatus = syncmap.Map{}
 	c.transactions = syncmap.Map{}
+	if config.DEPOSIT_WATCHER_ENABLED {
+		c.startDepositWatcher()
+	}
+	c.startWithdrawalPoller()
+	c.startQueuedDepositRetry()
 
 	// job := cron.New()
 	// job.Schedule(cron.ConstantDelaySchedule{Delay: withdrawReviewDelay}, cron.FuncJob(func() { reviewWithdrawals(withdrawReviewDelay) }))
@@ -100,20 +108,36 @@ func (c *Controller) ListnerV2(ctx *gin.Context) {
 		return
 	}
 
-	c.DecodeAndHandleTxById(subscriptionData.TxID)
+	if err := c.DecodeAndHandleTxById(subscriptionData.TxID); err != nil {
+		log.LogMessage("payment listener", "failed to handle transaction, left to deposit watcher", "error", logrus.Fields{"txId": subscriptionData.TxID, "error": err.Error()})
+	}
 }
 
-func (c *Controller) DecodeAndHandleTxById(txId string) {
+/**
+* @External
+* Decodes the transaction and handles it as deposit or withdrawal.
+* Returns an error only if handling should be retried, e.g. on rpc
+* or db failures. Transactions of nobody's business return nil.
+ */
+func (c *Controller) DecodeAndHandleTxById(txId string) error {
 	if _, prs := c.transactions.Load(txId); prs {
-		return
+		return utils.MakeError(
+			"payment_main",
+			"DecodeAndHandleTxById",
+			"transaction is being handled",
+			errors.New(txId),
+		)
 	}
 	c.transactions.Store(txId, true)
 	defer c.transactions.Delete(txId)
 
 	decodedResult, err := solana.DecodeTransactionType(txId)
-	if err != nil {
+	if utils.IsErrorCode(err, solana.ErrCodeRpcFailure) {
+		return err
+	}
+	if err != nil && !decodedResult.Failed {
 		log.LogMessage("payment listener", "transaction decode failed ", "info", logrus.Fields{"tx": txId, "error": err.Error()})
-		return
+		return nil
 	}
 	log.LogMessage("payment listener", "transaction subscribed", "info", logrus.Fields{"tx": txId, "result": decodedResult})
 
@@ -123,90 +147,48 @@ func (c *Controller) DecodeAndHandleTxById(txId string) {
 		var payment models.Payment
 		if result := db.Where("tx_hash = ?", txId).Where("type LIKE 'withdraw_%'").First(&payment); result.Error != nil {
 			log.LogMessage("payment tx handler", "can not find payment from db", "error", logrus.Fields{"tx": txId})
-			return
+			return nil
 		}
-		payment.Status = models.Failed
-
-		transaction.Decline(transaction.DeclineRequest{
-			Transaction: db_aggregator.Transaction(*payment.TransactionID),
-			OwnerID:     payment.ID,
-			OwnerType:   models.TransactionPaymentReferenced,
-		})
-		db.Save(&payment)
-		return
+		if err := c.settleWithdrawalPayment(&payment, false); err != nil {
+			log.LogMessage("payment tx handler", "failed to refund withdraw", "error", logrus.Fields{"tx": txId, "error": err.Error()})
+			return err
+		}
+		return nil
 	}
 
 	switch decodedResult.TransactionType {
 	case solana.TransactionSplDeposit:
-		c.depositChip(decodedResult.Participant, decodedResult, txId)
+		return c.depositChip(decodedResult.Participant, decodedResult, txId)
 	case solana.TransactionNftDeposit:
-		c.depositNfts(decodedResult.Participant, decodedResult.Nfts, txId)
+		return c.depositNfts(decodedResult.Participant, decodedResult, txId)
 	case solana.TransactionSplWithdraw:
 		var payment models.Payment
 		if result := db.Where("tx_hash = ?", txId).Where("type LIKE 'withdraw_%'").First(&payment); result.Error != nil {
 			log.LogMessage("payment chip withdraw handler", "can not find payment from db", "error", logrus.Fields{"tx": txId})
-			return
+			return nil
 		}
 		if payment.Status == models.Pending {
-			payment.Status = models.Success
-			if result := db.Save(&payment); result.Error != nil {
-				log.LogMessage("payment chip withdraw handler", "save payment failed", "error", logrus.Fields{"error": err.Error()})
-				return
-			}
-			if err := transaction.Confirm(transaction.ConfirmRequest{
-				Transaction: db_aggregator.Transaction(*payment.TransactionID),
-				OwnerID:     payment.ID,
-				OwnerType:   models.TransactionPaymentReferenced,
-			}); err != nil {
+			if err := c.settleWithdrawalPayment(&payment, true); err != nil {
 				log.LogMessage("payment chip withdraw handler", "transfer balance failed", "error", logrus.Fields{"error": err.Error()})
-				return
+				return err
 			}
-
-			log.LogMessage("payment chip withdraw handler", "withdraw confirmed", "info", logrus.Fields{"result": decodedResult})
-
-			var user models.User
-			if result := db.First(&user, payment.UserID); result.Error != nil {
-				log.LogMessage("payment chip withdraw handler", "failed to find user from db", "error", logrus.Fields{"result": decodedResult})
-				return
-			}
-			b, _ := json.Marshal(struct {
-				EventType string `json:"eventType"`
-				TxID      string `json:"txId"`
-			}{EventType: "withdraw_sol", TxID: txId})
-			c.EventEmitter <- types.WSEvent{Users: []uint{user.ID}, Message: b}
-			log.LogMessage("payment chip withdraw handler", "withdraw chip succeed", "success", logrus.Fields{"user": user.ID, "detail": payment.SolDetail, "tx": txId})
+			log.LogMessage("payment chip withdraw handler", "withdraw chip succeed", "success", logrus.Fields{"user": payment.UserID, "detail": payment.SolDetail, "tx": txId})
 		}
 	case solana.TransactionNftWithdraw:
 		var payment models.Payment
 		if result := db.Where("tx_hash = ?", txId).Where("type = ?", "withdraw_nft").First(&payment); result.Error != nil {
 			log.LogMessage("payment nft withdraw handler", "can not find payment from db", "error", logrus.Fields{"tx": txId})
-			return
+			return nil
 		}
 		if payment.Status == models.Pending {
-			payment.Status = models.Success
-			if result := db.Save(&payment); result.Error != nil {
-				log.LogMessage("payment nft withdraw handler", "save payment failed", "error", logrus.Fields{"error": err.Error()})
-				return
-			}
-			if err := transaction.Confirm(transaction.ConfirmRequest{
-				Transaction: db_aggregator.Transaction(*payment.TransactionID),
-				OwnerID:     payment.ID,
-				OwnerType:   models.TransactionPaymentReferenced,
-			}); err != nil {
+			if err := c.settleWithdrawalPayment(&payment, true); err != nil {
 				log.LogMessage("payment nft withdraw handler", "failed to transfer balance", "error", logrus.Fields{"error": err.Error()})
-				return
+				return err
 			}
-
-			var user models.User
-			db.Where("wallet_address = ?", decodedResult.Participant).First(&user)
-			b, _ := json.Marshal(struct {
-				EventType string `json:"eventType"`
-				TxID      string `json:"txId"`
-			}{EventType: "withdraw_nft", TxID: txId})
-			c.EventEmitter <- types.WSEvent{Users: []uint{user.ID}, Message: b}
-			log.LogMessage("payment nft withdraw handler", "withdraw nft succeed", "success", logrus.Fields{"user": user.ID, "detail": payment.NftDetail, "tx": txId})
+			log.LogMessage("payment nft withdraw handler", "withdraw nft succeed", "success", logrus.Fields{"user": payment.UserID, "detail": payment.NftDetail, "tx": txId})
 		}
 	}
+	return nil
 }
 
 // Withdraw chips godoc
@@ -262,27 +244,54 @@ func (c *Controller) WithdrawSol(ctx *gin.Context) {
 		return
 	}
 
-	if withDrawParam.UsdAmount < config.WITHDRAW_MIN_LIMIT {
+	supportedTokens := solana.SupportedSpls()
+	var targetToken *solana.SplTokenMeta
+	for i, token := range supportedTokens {
+		if token.Keyword == withDrawParam.TargetToken {
+			targetToken = &supportedTokens[i]
+			break
+		}
+	}
+	if targetToken == nil || !targetToken.WithdrawEnabled {
 		ctx.JSON(400, gin.H{
-			"status": "Minimum withdraw amount is 1 Chip.",
+			"status": "Withdrawal of the token is not supported.",
 		})
 		return
 	}
 
-	supportedTokens := solana.SupportedSpls()
-	var targetToken solana.SplTokenMeta
-	for _, token := range supportedTokens {
-		if token.Keyword == withDrawParam.TargetToken {
-			targetToken = token
-			break
-		}
+	minWithdraw := config.WITHDRAW_MIN_LIMIT
+	if targetToken.MinWithdraw > minWithdraw {
+		minWithdraw = targetToken.MinWithdraw
+	}
+	if withDrawParam.UsdAmount < minWithdraw {
+		ctx.JSON(400, gin.H{
+			"status": fmt.Sprintf(
+				"Minimum withdraw amount is %v Chip.",
+				float64(minWithdraw)/math.Pow10(config.BALANCE_DECIMALS),
+			),
+		})
+		return
 	}
 
 	transactionType := models.TxWithdrawSol
-	if !solana.IsSolSplMeta(targetToken) {
+	if !solana.IsSolSplMeta(*targetToken) {
 		transactionType = models.TxWithdrawSpl
 	}
 
+	// Swapped amount is sent on mainnet, otherwise priced by oracle.
+	useSwap := conf.Network == "mainnet" && config.USDC_SPL_ADDRESS != targetToken.MintAddress.String()
+	var price *price_oracle.Price
+	if !useSwap {
+		price, err = price_oracle.GetPrice(targetToken)
+		if err != nil {
+			log.LogMessage("payment sol withdraw handler", "refused to withdraw at unsafe price", "error", logrus.Fields{"token": targetToken.Keyword, "error": err.Error()})
+			ctx.JSON(503, gin.H{
+				"status": "Token price is unavailable. Please try again later.",
+			})
+			return
+		}
+	}
+
 	txId, err := transaction.Transfer(&transaction.TransactionRequest{
 		FromUser: (*db_aggregator.User)(&userInfo.ID),
 		ToUser:   nil,
@@ -291,6 +300,7 @@ func (c *Controller) WithdrawSol(ctx *gin.Context) {
 		},
 		Type:          transactionType,
 		ToBeConfirmed: false,
+		WithdrawFee:   &targetToken.WithdrawFee,
 	})
 	if err != nil {
 		log.LogMessage("payment withdraw sol handler", "critical: burn user balance", "error", logrus.Fields{"error": err.Error()})
@@ -301,7 +311,7 @@ func (c *Controller) WithdrawSol(ctx *gin.Context) {
 	}
 
 	var splLamports uint64
-	if conf.Network == "mainnet" && config.USDC_SPL_ADDRESS != targetToken.MintAddress.String() {
+	if useSwap {
 		splAmount, err := utils.SwapTokens(float32(float64(withDrawParam.UsdAmount)/math.Pow10(config.BALANCE_DECIMALS)), config.USDC_SPL_ADDRESS, targetToken.MintAddress.String())
 		if err != nil {
 			log.LogMessage("payment sol withdraw handler", "failed to swap USDC to SOL via Jupiter instance", "error", logrus.Fields{"err": err, "amount": withDrawParam.UsdAmount})
@@ -322,55 +332,41 @@ func (c *Controller) WithdrawSol(ctx *gin.Context) {
 		}
 		splLamports = uint64(float64(splAmount) * float64(math.Pow10(targetToken.Decimals)))
 	} else {
-		tm := map[string]string{
-			targetToken.Keyword: targetToken.MintAddress.String(),
-		}
-		prices := utils.FetchTokenPrice(tm)
-		splLamports = uint64(float64(withDrawParam.UsdAmount) * math.Pow10(targetToken.Decimals) / math.Pow10(config.BALANCE_DECIMALS) / prices[targetToken.Keyword])
+		splLamports = uint64(float64(withDrawParam.UsdAmount) * math.Pow10(targetToken.Decimals) / math.Pow10(config.BALANCE_DECIMALS) / price.Price)
 	}
 
-	txHash, err := solana.SendSplTokens(&solana.SendSplTokenRequest{
-		To:     userInfo.WalletAddress,
-		Mint:   targetToken.MintAddress.String(),
-		Amount: splLamports,
-	})
-
+	withdrawal, err := c.sendWithdrawal(
+		*txId,
+		&models.Payment{
+			UserID: userInfo.ID,
+			Type:   "withdraw_" + strings.ToLower(targetToken.Keyword),
+			SolDetail: models.SolDetail{
+				SolAmount: int64(splLamports),
+				UsdAmount: withDrawParam.UsdAmount,
+			},
+		},
+		solana.WithdrawalRequest{
+			To:     userInfo.WalletAddress,
+			Mint:   targetToken.MintAddress.String(),
+			Amount: splLamports,
+		},
+	)
 	if err != nil {
 		log.LogMessage("payment withdraw Chip handler", "failed to send transaction", "error", logrus.Fields{"error": err.Error()})
-		if err := transaction.Decline(transaction.DeclineRequest{
-			Transaction: *txId,
-			OwnerID:     userInfo.ID,
-			OwnerType:   models.TransactionUserReferenced,
-		}); err != nil {
-			ctx.JSON(500, gin.H{
-				"status": "Failed to swap USDC to SPL. && Failed to refund chip.",
-			})
-			return
-		}
 		ctx.JSON(500, gin.H{
 			"status": "Solana transaction failed. Please try again later.",
 		})
 		return
 	}
+	if price != nil {
+		savePriceSnapshot(withdrawal.PaymentID, targetToken.MintAddress.String(), nil, price, nil)
+	}
 
 	ctx.JSON(200, gin.H{
 		"status": "Withdraw SPL request successful.",
-		"txId":   txHash,
+		"txId":   withdrawal.Signature,
 		"amount": withDrawParam.UsdAmount,
 	})
-
-	payment := models.Payment{
-		UserID: userInfo.ID,
-		Type:   "withdraw_" + strings.ToLower(targetToken.Keyword),
-		Status: models.Pending,
-		SolDetail: models.SolDetail{
-			SolAmount: int64(splLamports),
-			UsdAmount: withDrawParam.UsdAmount,
-		},
-		TxHash:        txHash,
-		TransactionID: (*uint)(txId),
-	}
-	db.Create(&payment)
 }
 
 // Withdraw NFTs godoc
@@ -425,45 +421,32 @@ func (c *Controller) WithdrawNfts(ctx *gin.Context) {
 		return
 	}
 
-	txHash, err := solana.SendNfts(&solana.SendNftsRequest{
-		To:   userInfo.WalletAddress,
-		Nfts: withdrawParam.MintAddresses,
-	})
-
+	withdrawal, err := c.sendWithdrawal(
+		*txId,
+		&models.Payment{
+			UserID: userInfo.ID,
+			Type:   "withdraw_nft",
+			NftDetail: models.NftDetail{
+				Mints: withdrawParam.MintAddresses,
+			},
+		},
+		solana.WithdrawalRequest{
+			To:   userInfo.WalletAddress,
+			Nfts: withdrawParam.MintAddresses,
+		},
+	)
 	if err != nil {
 		log.LogMessage("withdraw nft handler", "failed to send transaction", "error", logrus.Fields{"error": err.Error()})
-		if err := transaction.Decline(transaction.DeclineRequest{
-			Transaction: *txId,
-			OwnerID:     userInfo.ID,
-			OwnerType:   models.TransactionUserReferenced,
-		}); err != nil {
-			ctx.JSON(500, gin.H{
-				"status": "Invalid mintAddresses. && Failed to refund.",
-			})
-			return
-		}
 		ctx.JSON(500, gin.H{
 			"status": "Failed to withdraw NFTs.",
 		})
 		return
 	}
 
-	log.LogMessage("withdraw nft handler", "send transaction succeed", "info", logrus.Fields{"tx": txHash, "nfts": withdrawParam.MintAddresses})
+	log.LogMessage("withdraw nft handler", "send transaction succeed", "info", logrus.Fields{"tx": withdrawal.Signature, "nfts": withdrawParam.MintAddresses})
 	ctx.JSON(200, gin.H{
 		"status":        "Withdraw NFTs request successful.",
-		"txId":          txHash,
+		"txId":          withdrawal.Signature,
 		"mintAddresses": withdrawParam.MintAddresses,
 	})
-
-	payment := models.Payment{
-		UserID: userInfo.ID,
-		Type:   "withdraw_nft",
-		Status: models.Pending,
-		NftDetail: models.NftDetail{
-			Mints: withdrawParam.MintAddresses,
-		},
-		TxHash:        txHash,
-		TransactionID: (*uint)(txId),
-	}
-	db.Create(&payment)
 }
@@ -0,0 +1,197 @@
+package payment
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/controllers/solana"
+	"github.com/Duelana-Team/duelana-v1/db"
+	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/middlewares"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	solanaGo "github.com/gagliardetto/solana-go"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// How a deposit was attributed to its user.
+type depositAttribution string
+
+const (
+	attributedByReference depositAttribution = "reference"
+	attributedByMemo      depositAttribution = "memo"
+	attributedByWallet    depositAttribution = "wallet"
+)
+
+// Retries on unlikely reference or memo collision.
+const CREATE_DEPOSIT_REFERENCE_RETRIES = 3
+
+/**
+* @Internal
+* Generates a random memo from `config.DEPOSIT_MEMO_ALPHABET`.
+ */
+func generateDepositMemo() (string, error) {
+	alphabetLength := big.NewInt(int64(len(config.DEPOSIT_MEMO_ALPHABET)))
+	memo := make([]byte, config.DEPOSIT_MEMO_LENGTH)
+	for i := range memo {
+		index, err := rand.Int(rand.Reader, alphabetLength)
+		if err != nil {
+			return "", err
+		}
+		memo[i] = config.DEPOSIT_MEMO_ALPHABET[index.Int64()]
+	}
+	return string(memo), nil
+}
+
+/**
+* @Internal
+* Normalizes a memo typed by user, exchanges may change case.
+ */
+func normalizeDepositMemo(memo string) string {
+	return strings.ToUpper(strings.TrimSpace(memo))
+}
+
+/**
+* @Internal
+* Returns deposit reference of the user, creating one on first call.
+* Reference is a fresh public key whose private key is dropped,
+* it is only looked up in transaction accounts.
+ */
+func getOrCreateDepositReference(userID uint) (*models.DepositReference, error) {
+	db := db.GetDB()
+	reference := models.DepositReference{}
+	err := db.Where("user_id = ?", userID).First(&reference).Error
+	if err == nil {
+		return &reference, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, utils.MakeError(
+			"payment_reference",
+			"getOrCreateDepositReference",
+			"failed to get deposit reference",
+			err,
+		)
+	}
+
+	for i := 0; ; i++ {
+		memo, err := generateDepositMemo()
+		if err != nil {
+			return nil, utils.MakeError(
+				"payment_reference",
+				"getOrCreateDepositReference",
+				"failed to generate memo",
+				err,
+			)
+		}
+		reference = models.DepositReference{
+			UserID:    userID,
+			Reference: solanaGo.NewWallet().PublicKey().String(),
+			Memo:      memo,
+		}
+		err = db.Create(&reference).Error
+		if err == nil {
+			return &reference, nil
+		}
+
+		// Concurrent request may have created it.
+		existing := models.DepositReference{}
+		if db.Where("user_id = ?", userID).First(&existing).Error == nil {
+			return &existing, nil
+		}
+		if i+1 >= CREATE_DEPOSIT_REFERENCE_RETRIES {
+			return nil, utils.MakeError(
+				"payment_reference",
+				"getOrCreateDepositReference",
+				"failed to create deposit reference",
+				err,
+			)
+		}
+	}
+}
+
+/**
+* @Internal
+* Finds the user a deposit is credited to: the owner of a
+* reference key in the transaction, then the owner of the memo,
+* and the sending wallet's user as fallback.
+* Returns nil user if the deposit belongs to no user.
+ */
+func findDepositUser(decodedResult *solana.DecodedTransaction) (*models.User, depositAttribution, error) {
+	db := db.GetDB()
+
+	userID := uint(0)
+	attribution := attributedByWallet
+	reference := models.DepositReference{}
+	if len(decodedResult.Reference.References) > 0 &&
+		db.Where(
+			"reference IN ?",
+			decodedResult.Reference.References,
+		).First(&reference).Error == nil {
+		userID = reference.UserID
+		attribution = attributedByReference
+	} else if memo := normalizeDepositMemo(decodedResult.Reference.Memo); memo != "" &&
+		db.Where("memo = ?", memo).First(&reference).Error == nil {
+		userID = reference.UserID
+		attribution = attributedByMemo
+	}
+
+	user := models.User{}
+	var err error
+	if userID != 0 {
+		err = db.First(&user, userID).Error
+	} else {
+		err = db.Where("wallet_address = ?", decodedResult.Participant).First(&user).Error
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, attribution, nil
+	}
+	if err != nil {
+		return nil, attribution, utils.MakeError(
+			"payment_reference",
+			"findDepositUser",
+			"failed to find user",
+			err,
+		)
+	}
+	return &user, attribution, nil
+}
+
+// Get deposit reference
+// @ID payment-deposit-reference
+// @Summary Deposit Reference
+// @Description Get reference key and memo attributing deposits from any wallet to the user.
+// @Tags Payments
+// @Produce json
+// @Success 200
+// @Failure 500
+// @Router /api/pay/deposit-reference [get]
+func (c *Controller) DepositReference(ctx *gin.Context) {
+	user, _ := ctx.Get(middlewares.AuthMiddleware().IdentityKey)
+	userID := user.(gin.H)["id"].(uint)
+
+	reference, err := getOrCreateDepositReference(userID)
+	if err != nil {
+		log.LogMessage(
+			"payment_reference_DepositReference",
+			"failed to get deposit reference",
+			"error",
+			logrus.Fields{
+				"user":  userID,
+				"error": err.Error(),
+			},
+		)
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"reference": reference.Reference,
+		"memo":      reference.Memo,
+	})
+}
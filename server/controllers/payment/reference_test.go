@@ -0,0 +1,26 @@
+package payment
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+)
+
+func TestGenerateDepositMemo(t *testing.T) {
+	memo, err := generateDepositMemo()
+	if err != nil {
+		t.Fatalf("failed to generate memo: %v", err)
+	}
+	if len(memo) != config.DEPOSIT_MEMO_LENGTH {
+		t.Fatalf("unexpected memo length: %s", memo)
+	}
+	for _, letter := range memo {
+		if !strings.ContainsRune(config.DEPOSIT_MEMO_ALPHABET, letter) {
+			t.Fatalf("unexpected memo letter: %s", memo)
+		}
+	}
+	if normalizeDepositMemo(" "+strings.ToLower(memo)+"\n") != memo {
+		t.Fatalf("memo should be normalized: %s", memo)
+	}
+}
@@ -0,0 +1,247 @@
+package payment
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/controllers/price_oracle"
+	"github.com/Duelana-Team/duelana-v1/controllers/solana"
+	"github.com/Duelana-Team/duelana-v1/db"
+	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Serializes retries of queued deposits.
+var queuedDepositsMutex sync.Mutex
+
+/**
+* @Internal
+* Chips worth `amount` base units of a token with `decimals`
+* at `price` in USD.
+ */
+func depositChips(amount uint64, decimals int, price float64) int64 {
+	return int64(float64(amount) * price * math.Pow10(config.BALANCE_DECIMALS) / math.Pow10(decimals))
+}
+
+/**
+* @Internal
+* Returns why the token registry refuses a deposit of `amount`
+* base units, nil if accepted.
+ */
+func depositRejection(token *solana.SplTokenMeta, amount uint64) error {
+	if !token.DepositEnabled {
+		return errors.New("deposits of the token are disabled")
+	}
+	if amount < token.MinDeposit {
+		return fmt.Errorf(
+			"amount below minimum deposit: %d < %d",
+			amount, token.MinDeposit,
+		)
+	}
+	return nil
+}
+
+/**
+* @Internal
+* Records price the payment was valued at, or why valuation failed.
+* `depositedAt` is nil for withdrawals.
+ */
+func savePriceSnapshot(
+	paymentID uint,
+	mint string,
+	depositedAt *time.Time,
+	price *price_oracle.Price,
+	priceErr error,
+) {
+	snapshot := models.PaymentPriceSnapshot{
+		PaymentID:   paymentID,
+		Mint:        mint,
+		DepositedAt: depositedAt,
+		Accepted:    priceErr == nil,
+	}
+	if price != nil {
+		snapshot.Price = price.Price
+		snapshot.PricedAt = price.At
+		snapshot.Quotes, _ = json.Marshal(price.Quotes)
+	}
+	if priceErr != nil {
+		snapshot.Reason = priceErr.Error()
+	}
+	if err := db.GetDB().Create(&snapshot).Error; err != nil {
+		log.LogMessage(
+			"payment_valuation_savePriceSnapshot",
+			"failed to save price snapshot",
+			"error",
+			logrus.Fields{
+				"payment": paymentID,
+				"price":   snapshot.Price,
+				"error":   err.Error(),
+			},
+		)
+	}
+}
+
+/**
+* @Internal
+* Retries crediting deposits queued at unsafe price.
+ */
+func (c *Controller) startQueuedDepositRetry() {
+	go func() {
+		ticker := time.NewTicker(config.PRICE_ORACLE_QUEUE_RETRY_INTERVAL)
+		defer ticker.Stop()
+		for range ticker.C {
+			c.retryQueuedDeposits()
+		}
+	}()
+}
+
+func (c *Controller) retryQueuedDeposits() {
+	queuedDepositsMutex.Lock()
+	defer queuedDepositsMutex.Unlock()
+
+	payments := []models.Payment{}
+	if err := db.GetDB().
+		Where("type LIKE 'deposit_%'").
+		Where("type <> ?", "deposit_nft").
+		Where("status = ?", models.Pending).
+		Where("transaction_id IS NULL").
+		Find(&payments).Error; err != nil {
+		log.LogMessage(
+			"payment_valuation_retryQueuedDeposits",
+			"failed to get queued deposits",
+			"error",
+			logrus.Fields{
+				"error": err.Error(),
+			},
+		)
+		return
+	}
+	for i := range payments {
+		if err := c.retryQueuedDeposit(&payments[i]); err != nil {
+			log.LogMessage(
+				"payment_valuation_retryQueuedDeposits",
+				"deposit is still queued",
+				"info",
+				logrus.Fields{
+					"payment": payments[i].ID,
+					"tx":      payments[i].TxHash,
+					"error":   err.Error(),
+				},
+			)
+		}
+	}
+}
+
+/**
+* @Internal
+* Credits a queued deposit once its token is priced safely at
+* the deposit time, so that waiting does not pay off a later
+* move of the market. Deposits which can no longer be priced at
+* their time stay queued for manual review.
+* Deposits queued again after a failed credit keep their
+* accepted price. Deposits refused by token registry stay queued
+* till the registry accepts them.
+* Only accepted prices are snapshotted on retry.
+ */
+func (c *Controller) retryQueuedDeposit(payment *models.Payment) error {
+	// 1. Find token from the latest snapshot.
+	snapshot := models.PaymentPriceSnapshot{}
+	if err := db.GetDB().
+		Where("payment_id = ?", payment.ID).
+		Order("id desc").
+		First(&snapshot).Error; err != nil {
+		return utils.MakeError(
+			"payment_valuation",
+			"retryQueuedDeposit",
+			"failed to get price snapshot",
+			err,
+		)
+	}
+	var token *solana.SplTokenMeta
+	supportedTokens := solana.SupportedSpls()
+	for i := range supportedTokens {
+		if supportedTokens[i].MintAddress.String() == snapshot.Mint {
+			token = &supportedTokens[i]
+			break
+		}
+	}
+	if token == nil {
+		return utils.MakeError(
+			"payment_valuation",
+			"retryQueuedDeposit",
+			"unsupported token",
+			errors.New(snapshot.Mint),
+		)
+	}
+
+	if rejection := depositRejection(token, uint64(payment.SolDetail.SolAmount)); rejection != nil {
+		return utils.MakeError(
+			"payment_valuation",
+			"retryQueuedDeposit",
+			"rejected by token registry",
+			rejection,
+		)
+	}
+
+	// 2. Value at the deposit time.
+	depositedAt := payment.CreatedAt
+	if snapshot.DepositedAt != nil {
+		depositedAt = *snapshot.DepositedAt
+	}
+	price := &price_oracle.Price{
+		Mint:  snapshot.Mint,
+		Price: snapshot.Price,
+		At:    snapshot.PricedAt,
+	}
+	if !snapshot.Accepted {
+		var err error
+		if price, err = price_oracle.GetPriceAt(token, depositedAt); err != nil {
+			return err
+		}
+	}
+	usdAmount := depositChips(uint64(payment.SolDetail.SolAmount), token.Decimals, price.Price)
+
+	// 3. Claim queued payment.
+	result := db.GetDB().Model(&models.Payment{}).
+		Where("id = ?", payment.ID).
+		Where("status = ?", models.Pending).
+		Updates(map[string]interface{}{
+			"status":     models.Success,
+			"usd_amount": usdAmount,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+	payment.Status = models.Success
+	payment.SolDetail.UsdAmount = usdAmount
+	if !snapshot.Accepted {
+		savePriceSnapshot(payment.ID, snapshot.Mint, &depositedAt, price, nil)
+	}
+
+	// 4. Credit, or queue again to be retried.
+	if err := c.creditDeposit(payment, token); err != nil {
+		if err := requeueDeposit(payment.ID); err != nil {
+			log.LogMessage(
+				"payment_valuation_retryQueuedDeposit",
+				"failed to queue uncredited deposit",
+				"error",
+				logrus.Fields{
+					"payment": payment.ID,
+					"error":   err.Error(),
+				},
+			)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package payment
+
+import (
+	"testing"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/controllers/solana"
+)
+
+func TestDepositChips(t *testing.T) {
+	// 1.5 SOL at 20 USD.
+	if chips := depositChips(1500000000, 9, 20); chips != 30*config.ONE_CHIP_WITH_DECIMALS {
+		t.Fatalf("unexpected chips of sol deposit: %d", chips)
+	}
+	// 2 USDC pegged.
+	if chips := depositChips(2000000, 6, 1); chips != 2*config.ONE_CHIP_WITH_DECIMALS {
+		t.Fatalf("unexpected chips of usdc deposit: %d", chips)
+	}
+}
+
+func TestDepositRejection(t *testing.T) {
+	token := solana.SplTokenMeta{
+		DepositEnabled: true,
+		MinDeposit:     1000,
+	}
+	if err := depositRejection(&token, 1000); err != nil {
+		t.Fatalf("deposit at minimum should be accepted: %v", err)
+	}
+	if err := depositRejection(&token, 999); err == nil {
+		t.Fatalf("deposit below minimum should be rejected")
+	}
+	token.DepositEnabled = false
+	if err := depositRejection(&token, 1000); err == nil {
+		t.Fatalf("deposit of disabled token should be rejected")
+	}
+}
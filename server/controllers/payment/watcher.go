@@ -0,0 +1,270 @@
+package payment
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/controllers/solana"
+	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	solanaGo "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// Serializes polling and backfill.
+var watcherMutex sync.Mutex
+
+/**
+* @Internal
+* Polls treasury addresses for new signatures and handles them
+* through `DecodeAndHandleTxById`, in addition to the webhook.
+* NFT deposits into already existing treasury token accounts are
+* not seen by the watcher, those are left to the webhook.
+ */
+func (c *Controller) startDepositWatcher() {
+	go func() {
+		ticker := time.NewTicker(config.DEPOSIT_WATCHER_INTERVAL)
+		defer ticker.Stop()
+		for range ticker.C {
+			c.pollDeposits()
+		}
+	}()
+}
+
+/**
+* @Internal
+* Handles signatures after the persisted cursor of each watched
+* address, advancing the cursor per handled signature.
+* The cursor stops at the first signature failed to be handled,
+* which is retried on next poll.
+* An address without cursor starts from its latest signature.
+ */
+func (c *Controller) pollDeposits() {
+	watcherMutex.Lock()
+	defer watcherMutex.Unlock()
+
+	for _, address := range solana.TreasuryWatchAddresses() {
+		if err := c.pollAddress(address); err != nil {
+			log.LogMessage(
+				"payment_watcher_pollDeposits",
+				"failed to poll address",
+				"error",
+				logrus.Fields{
+					"address": address.String(),
+					"error":   err.Error(),
+				},
+			)
+		}
+	}
+}
+
+func (c *Controller) pollAddress(address solanaGo.PublicKey) error {
+	// 1. Get cursor.
+	cursor, err := getWatcherCursor(address.String())
+	if err != nil {
+		return err
+	}
+
+	// 2. Start from latest signature on first poll.
+	if cursor.LastSignature == "" {
+		latest, err := solana.GetSignaturesForAddress(address, "", "", 1)
+		if err != nil {
+			return err
+		}
+		if len(latest) == 0 {
+			return nil
+		}
+		cursor.LastSignature = latest[0].Signature.String()
+		cursor.LastSlot = latest[0].Slot
+		log.LogMessage(
+			"payment_watcher_pollAddress",
+			"started watching address",
+			"info",
+			logrus.Fields{
+				"address": address.String(),
+				"slot":    cursor.LastSlot,
+			},
+		)
+		return saveWatcherCursor(cursor)
+	}
+
+	// 3. Handle newer signatures in order.
+	signatures, err := fetchSignatures(address, cursor.LastSignature, 0)
+	if err != nil {
+		return err
+	}
+	for _, signature := range signatures {
+		if err := c.handleWatchedSignature(signature.Signature.String()); err != nil {
+			return utils.MakeError(
+				"payment_watcher",
+				"pollAddress",
+				"failed to handle signature",
+				fmt.Errorf(
+					"tx: %s, err: %v",
+					signature.Signature.String(), err,
+				),
+			)
+		}
+		cursor.LastSignature = signature.Signature.String()
+		cursor.LastSlot = signature.Slot
+		if err := saveWatcherCursor(cursor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/**
+* @Internal
+* Handles every signature of watched addresses since `fromSlot`.
+* Cursors are left as they are, already handled deposits are
+* skipped by tx hash. Stops at the first signature failed to be
+* handled.
+ */
+func (c *Controller) backfillDeposits(fromSlot uint64) (int, error) {
+	if fromSlot == 0 {
+		return 0, utils.MakeError(
+			"payment_watcher",
+			"backfillDeposits",
+			"invalid parameter",
+			errors.New("from slot should be positive"),
+		)
+	}
+
+	watcherMutex.Lock()
+	defer watcherMutex.Unlock()
+
+	handled := 0
+	for _, address := range solana.TreasuryWatchAddresses() {
+		signatures, err := fetchSignatures(address, "", fromSlot)
+		if err != nil {
+			return handled, utils.MakeError(
+				"payment_watcher",
+				"backfillDeposits",
+				"failed to fetch signatures",
+				err,
+			)
+		}
+		for _, signature := range signatures {
+			if err := c.handleWatchedSignature(signature.Signature.String()); err != nil {
+				return handled, utils.MakeError(
+					"payment_watcher",
+					"backfillDeposits",
+					"failed to handle signature",
+					fmt.Errorf(
+						"tx: %s, err: %v",
+						signature.Signature.String(), err,
+					),
+				)
+			}
+			handled++
+		}
+	}
+	return handled, nil
+}
+
+/**
+* @Internal
+* Fetches signatures of `address` oldest first. Pages back until
+* `until` when given, otherwise until a signature before `fromSlot`.
+ */
+func fetchSignatures(
+	address solanaGo.PublicKey,
+	until string,
+	fromSlot uint64,
+) ([]*rpc.TransactionSignature, error) {
+	fetched := []*rpc.TransactionSignature{}
+	before := ""
+	for {
+		page, err := solana.GetSignaturesForAddress(
+			address,
+			before,
+			until,
+			config.DEPOSIT_WATCHER_PAGE_LIMIT,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		reachedSlot := false
+		for _, signature := range page {
+			if until == "" && signature.Slot < fromSlot {
+				reachedSlot = true
+				break
+			}
+			fetched = append(fetched, signature)
+		}
+		if reachedSlot ||
+			len(page) < config.DEPOSIT_WATCHER_PAGE_LIMIT {
+			break
+		}
+		before = page[len(page)-1].Signature.String()
+	}
+
+	for i, j := 0, len(fetched)-1; i < j; i, j = i+1, j-1 {
+		fetched[i], fetched[j] = fetched[j], fetched[i]
+	}
+	return fetched, nil
+}
+
+/**
+* @Internal
+* Skips signatures of already settled payments, saving the
+* transaction decode for treasury's own outgoing transfers.
+* Returns an error if the signature should be handled again.
+ */
+func (c *Controller) handleWatchedSignature(txId string) error {
+	settled, err := isPaymentSettled(txId)
+	if err != nil {
+		return err
+	}
+	if settled {
+		return nil
+	}
+	return c.DecodeAndHandleTxById(txId)
+}
+
+// Backfill deposits godoc
+// @ID admin-backfill-deposits
+// @Summary Backfill deposits
+// @Description Handles treasury transactions since the given slot.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param fromSlot body int true "The first slot to handle."
+// @Success 200
+// @Failure 400,500
+// @Router /api/admin/backfill-deposits [post]
+func (c *Controller) BackfillDeposits(ctx *gin.Context) {
+	var params struct {
+		FromSlot uint64 `json:"fromSlot"`
+	}
+	if err := ctx.ShouldBindJSON(&params); err != nil ||
+		params.FromSlot == 0 {
+		ctx.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	handled, err := c.backfillDeposits(params.FromSlot)
+	if err != nil {
+		log.LogMessage(
+			"payment_watcher_BackfillDeposits",
+			"failed to backfill deposits",
+			"error",
+			logrus.Fields{
+				"fromSlot": params.FromSlot,
+				"handled":  handled,
+				"error":    err.Error(),
+			},
+		)
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"handled": handled})
+}
@@ -0,0 +1,115 @@
+package payment
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/controllers/solana"
+	solanaGo "github.com/gagliardetto/solana-go"
+)
+
+// Serves `getSignaturesForAddress` from `signatures`, newest first.
+type stubSignaturesRpc struct {
+	signatures []solanaGo.Signature
+	slots      []uint64
+	calls      int
+}
+
+func (stub *stubSignaturesRpc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		ID     interface{}       `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil ||
+		request.Method != "getSignaturesForAddress" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	stub.calls++
+
+	var opts struct {
+		Limit  int    `json:"limit"`
+		Before string `json:"before"`
+		Until  string `json:"until"`
+	}
+	if len(request.Params) > 1 {
+		json.Unmarshal(request.Params[1], &opts)
+	}
+
+	result := []map[string]interface{}{}
+	started := opts.Before == ""
+	for i, signature := range stub.signatures {
+		if !started {
+			started = signature.String() == opts.Before
+			continue
+		}
+		if signature.String() == opts.Until ||
+			len(result) == opts.Limit {
+			break
+		}
+		result = append(result, map[string]interface{}{
+			"signature": signature.String(),
+			"slot":      stub.slots[i],
+		})
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      request.ID,
+		"result":  result,
+	})
+}
+
+func TestFetchSignatures(t *testing.T) {
+	stub := &stubSignaturesRpc{}
+	for i := 0; i < 5; i++ {
+		signature := solanaGo.Signature{}
+		signature[0] = byte(i + 1)
+		stub.signatures = append(stub.signatures, signature)
+		stub.slots = append(stub.slots, uint64(100-i*10))
+	}
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	if err := solana.Initialize(&solana.InitParam{
+		TreasuryBs58: solanaGo.NewWallet().PrivateKey.String(),
+		Cluster:      solana.ClusterDevNet,
+		RpcUrl:       server.URL,
+	}); err != nil {
+		t.Fatalf("failed to initialize solana: %v", err)
+	}
+	pageLimit := config.DEPOSIT_WATCHER_PAGE_LIMIT
+	config.DEPOSIT_WATCHER_PAGE_LIMIT = 2
+	defer func() { config.DEPOSIT_WATCHER_PAGE_LIMIT = pageLimit }()
+
+	address := solanaGo.NewWallet().PublicKey()
+
+	// Pages until cursor, oldest first.
+	signatures, err := fetchSignatures(address, stub.signatures[4].String(), 0)
+	if err != nil {
+		t.Fatalf("failed to fetch signatures: %v", err)
+	}
+	if len(signatures) != 4 ||
+		signatures[0].Signature != stub.signatures[3] ||
+		signatures[3].Signature != stub.signatures[0] {
+		t.Fatalf("unexpected signatures: %v", signatures)
+	}
+	if stub.calls != 3 {
+		t.Fatalf("expected 3 pages, got %d", stub.calls)
+	}
+
+	// Pages until slot.
+	signatures, err = fetchSignatures(address, "", 80)
+	if err != nil {
+		t.Fatalf("failed to fetch signatures: %v", err)
+	}
+	if len(signatures) != 3 ||
+		signatures[0].Slot != 80 ||
+		signatures[2].Slot != 100 {
+		t.Fatalf("unexpected backfill signatures: %v", signatures)
+	}
+}
@@ -0,0 +1,556 @@
+package payment
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/controllers/solana"
+	"github.com/Duelana-Team/duelana-v1/controllers/transaction"
+	"github.com/Duelana-Team/duelana-v1/controllers/transaction/db_aggregator"
+	"github.com/Duelana-Team/duelana-v1/db"
+	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/types"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Serializes withdrawal polls.
+var withdrawalMutex sync.Mutex
+
+/**
+* @Internal
+* Records `payment` with a queued withdrawal, then signs and
+* broadcasts it. Returns error only when nothing was broadcast,
+* the pending ledger transaction `txID` is declined then.
+ */
+func (c *Controller) sendWithdrawal(
+	txID db_aggregator.Transaction,
+	payment *models.Payment,
+	request solana.WithdrawalRequest,
+) (*models.Withdrawal, error) {
+	// 1. Record payment and queued withdrawal.
+	payment.Status = models.Pending
+	payment.TransactionID = (*uint)(&txID)
+	withdrawal := models.Withdrawal{
+		UserID: payment.UserID,
+		Status: models.WithdrawalQueued,
+		To:     request.To,
+		Mint:   request.Mint,
+		Amount: request.Amount,
+		Nfts:   request.Nfts,
+	}
+	if err := db.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(payment).Error; err != nil {
+			return err
+		}
+		withdrawal.PaymentID = payment.ID
+		return tx.Create(&withdrawal).Error
+	}); err != nil {
+		if err := transaction.Decline(transaction.DeclineRequest{
+			Transaction: txID,
+			OwnerID:     payment.UserID,
+			OwnerType:   models.TransactionUserReferenced,
+		}); err != nil {
+			log.LogMessage(
+				"payment_withdrawal_sendWithdrawal",
+				"critical: failed to refund unrecorded withdrawal",
+				"error",
+				logrus.Fields{
+					"transaction": txID,
+					"error":       err.Error(),
+				},
+			)
+		}
+		return nil, utils.MakeError(
+			"payment_withdrawal",
+			"sendWithdrawal",
+			"failed to record withdrawal",
+			err,
+		)
+	}
+	withdrawal.Payment = payment
+	c.emitWithdrawalStatus(&withdrawal)
+
+	// 2. Sign and broadcast.
+	if err := c.broadcastWithdrawal(&withdrawal); err != nil {
+		if err := c.settleWithdrawal(
+			&withdrawal,
+			models.WithdrawalFailed,
+			err.Error(),
+		); err != nil {
+			log.LogMessage(
+				"payment_withdrawal_sendWithdrawal",
+				"critical: failed to refund unsent withdrawal",
+				"error",
+				logrus.Fields{
+					"withdrawal": withdrawal.ID,
+					"error":      err.Error(),
+				},
+			)
+		}
+		return nil, utils.MakeError(
+			"payment_withdrawal",
+			"sendWithdrawal",
+			"failed to send withdrawal",
+			err,
+		)
+	}
+	return &withdrawal, nil
+}
+
+/**
+* @Internal
+* Signs the withdrawal with a fresh blockhash, saves its signature
+* and broadcasts it. Returns error only when nothing was broadcast.
+* A failed broadcast is left to the poller, as the transaction
+* may still have reached the cluster.
+ */
+func (c *Controller) broadcastWithdrawal(withdrawal *models.Withdrawal) error {
+	// 1. Sign.
+	signed, err := solana.SignWithdrawal(
+		&solana.WithdrawalRequest{
+			To:     withdrawal.To,
+			Mint:   withdrawal.Mint,
+			Amount: withdrawal.Amount,
+			Nfts:   withdrawal.Nfts,
+		},
+		withdrawal.Broadcasts,
+	)
+	if err != nil {
+		return err
+	}
+
+	// 2. Save signature before broadcast.
+	now := time.Now()
+	withdrawal.Status = models.WithdrawalSent
+	withdrawal.Signature = signed.Signature
+	withdrawal.Signatures = append(withdrawal.Signatures, signed.Signature)
+	withdrawal.LastValidBlockHeight = signed.LastValidBlockHeight
+	withdrawal.PriorityFee = signed.PriorityFee
+	withdrawal.Broadcasts++
+	withdrawal.SentAt = &now
+	if err := db.GetDB().Transaction(func(tx *gorm.DB) error {
+		// Refuse a withdrawal settled meanwhile, e.g. a stale queued one.
+		current := models.Withdrawal{}
+		if err := tx.Clauses(
+			clause.Locking{Strength: "UPDATE"},
+		).Select("status").First(&current, withdrawal.ID).Error; err != nil {
+			return err
+		}
+		if isSettledWithdrawalStatus(current.Status) {
+			return fmt.Errorf("withdrawal is already %s", current.Status)
+		}
+		if err := tx.Omit("Payment").Save(withdrawal).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Payment{}).
+			Where("id = ?", withdrawal.PaymentID).
+			Update("tx_hash", signed.Signature).Error
+	}); err != nil {
+		return err
+	}
+	if withdrawal.Payment != nil {
+		withdrawal.Payment.TxHash = signed.Signature
+	}
+
+	// 3. Broadcast.
+	if err := solana.BroadcastTx(signed.Tx); err != nil {
+		log.LogMessage(
+			"payment_withdrawal_broadcastWithdrawal",
+			"failed to broadcast, tracking until expiry",
+			"error",
+			logrus.Fields{
+				"withdrawal": withdrawal.ID,
+				"tx":         signed.Signature,
+				"error":      err.Error(),
+			},
+		)
+	}
+	c.emitWithdrawalStatus(withdrawal)
+	return nil
+}
+
+/**
+* @Internal
+* Checks signature status of withdrawals in flight.
+ */
+func (c *Controller) startWithdrawalPoller() {
+	go func() {
+		ticker := time.NewTicker(config.WITHDRAW_POLL_INTERVAL)
+		defer ticker.Stop()
+		for range ticker.C {
+			c.pollWithdrawals()
+		}
+	}()
+}
+
+func (c *Controller) pollWithdrawals() {
+	withdrawalMutex.Lock()
+	defer withdrawalMutex.Unlock()
+
+	// 1. Block height first, so that a signature unknown
+	// afterwards can no longer land.
+	blockHeight, err := solana.GetFinalizedBlockHeight()
+	if err != nil {
+		log.LogMessage(
+			"payment_withdrawal_pollWithdrawals",
+			"failed to get block height",
+			"error",
+			logrus.Fields{
+				"error": err.Error(),
+			},
+		)
+		return
+	}
+
+	// 2. Check withdrawals in flight.
+	withdrawals := []models.Withdrawal{}
+	if err := db.GetDB().Preload("Payment").Where(
+		"status IN ?",
+		[]models.WithdrawalStatus{
+			models.WithdrawalSent,
+			models.WithdrawalConfirmed,
+		},
+	).Find(&withdrawals).Error; err != nil {
+		log.LogMessage(
+			"payment_withdrawal_pollWithdrawals",
+			"failed to get withdrawals",
+			"error",
+			logrus.Fields{
+				"error": err.Error(),
+			},
+		)
+		return
+	}
+	for i := range withdrawals {
+		if err := c.checkWithdrawal(&withdrawals[i], blockHeight); err != nil {
+			log.LogMessage(
+				"payment_withdrawal_pollWithdrawals",
+				"failed to check withdrawal",
+				"error",
+				logrus.Fields{
+					"withdrawal": withdrawals[i].ID,
+					"tx":         withdrawals[i].Signature,
+					"error":      err.Error(),
+				},
+			)
+		}
+	}
+
+	// 3. Refund withdrawals never sent.
+	c.refundStaleWithdrawals()
+}
+
+/**
+* @Internal
+* Refunds withdrawals still queued `WITHDRAW_QUEUED_TIMEOUT` after
+* creation, left debited when the server stopped before signing them.
+* Nothing was signed for a queued withdrawal, so none can land.
+* Each is claimed as failed first so that a late broadcast refuses it,
+* and claimed ones whose refund failed are retried.
+ */
+func (c *Controller) refundStaleWithdrawals() {
+	withdrawals := []models.Withdrawal{}
+	if err := db.GetDB().Preload("Payment").Where(
+		"created_at < ?",
+		time.Now().Add(-config.WITHDRAW_QUEUED_TIMEOUT),
+	).Where(
+		"status = ? OR (status = ? AND settled_at IS NULL)",
+		models.WithdrawalQueued,
+		models.WithdrawalFailed,
+	).Find(&withdrawals).Error; err != nil {
+		log.LogMessage(
+			"payment_withdrawal_refundStaleWithdrawals",
+			"failed to get stale withdrawals",
+			"error",
+			logrus.Fields{
+				"error": err.Error(),
+			},
+		)
+		return
+	}
+
+	for i := range withdrawals {
+		withdrawal := &withdrawals[i]
+		if withdrawal.Status == models.WithdrawalQueued {
+			result := db.GetDB().Model(&models.Withdrawal{}).Where(
+				"id = ? AND status = ?",
+				withdrawal.ID,
+				models.WithdrawalQueued,
+			).Update("status", models.WithdrawalFailed)
+			if result.Error != nil {
+				log.LogMessage(
+					"payment_withdrawal_refundStaleWithdrawals",
+					"failed to claim stale withdrawal",
+					"error",
+					logrus.Fields{
+						"withdrawal": withdrawal.ID,
+						"error":      result.Error.Error(),
+					},
+				)
+				continue
+			}
+			if result.RowsAffected == 0 {
+				// Sent meanwhile.
+				continue
+			}
+		}
+		if err := c.settleWithdrawal(
+			withdrawal,
+			models.WithdrawalFailed,
+			fmt.Sprintf("not sent in %v", config.WITHDRAW_QUEUED_TIMEOUT),
+		); err != nil {
+			log.LogMessage(
+				"payment_withdrawal_refundStaleWithdrawals",
+				"critical: failed to refund stale withdrawal",
+				"error",
+				logrus.Fields{
+					"withdrawal": withdrawal.ID,
+					"error":      err.Error(),
+				},
+			)
+		}
+	}
+}
+
+/**
+* @Internal
+* Whether a withdrawal with the status is settled.
+ */
+func isSettledWithdrawalStatus(status models.WithdrawalStatus) bool {
+	return status == models.WithdrawalFinalized ||
+		status == models.WithdrawalExpired ||
+		status == models.WithdrawalFailed
+}
+
+/**
+* @Internal
+* Moves a withdrawal forward by its signature status.
+ */
+func (c *Controller) checkWithdrawal(withdrawal *models.Withdrawal, blockHeight uint64) error {
+	status, err := solana.GetSignatureStatus(withdrawal.Signature)
+	if err != nil {
+		return err
+	}
+
+	next := nextWithdrawalStatus(withdrawal, status, blockHeight)
+	switch next {
+	case withdrawal.Status:
+		return nil
+	case models.WithdrawalConfirmed:
+		withdrawal.Status = models.WithdrawalConfirmed
+		if err := db.GetDB().Omit("Payment").Save(withdrawal).Error; err != nil {
+			return err
+		}
+		c.emitWithdrawalStatus(withdrawal)
+		return nil
+	case models.WithdrawalFinalized:
+		return c.settleWithdrawal(withdrawal, next, "")
+	case models.WithdrawalFailed:
+		return c.settleWithdrawal(withdrawal, next, fmt.Sprint(status.Err))
+	case models.WithdrawalExpired:
+		if withdrawal.Broadcasts < config.WITHDRAW_MAX_BROADCASTS {
+			log.LogMessage(
+				"payment_withdrawal_checkWithdrawal",
+				"rebroadcasting expired withdrawal",
+				"info",
+				logrus.Fields{
+					"withdrawal": withdrawal.ID,
+					"tx":         withdrawal.Signature,
+					"broadcasts": withdrawal.Broadcasts,
+				},
+			)
+			if err := c.broadcastWithdrawal(withdrawal); err == nil {
+				return nil
+			}
+		}
+		return c.settleWithdrawal(
+			withdrawal,
+			next,
+			fmt.Sprintf("expired after %d broadcasts", withdrawal.Broadcasts),
+		)
+	}
+	return nil
+}
+
+/**
+* @Internal
+* Status of a withdrawal given its signature status, nil if the
+* cluster does not know the signature, and finalized block height.
+ */
+func nextWithdrawalStatus(
+	withdrawal *models.Withdrawal,
+	status *rpc.SignatureStatusesResult,
+	blockHeight uint64,
+) models.WithdrawalStatus {
+	if status == nil {
+		if blockHeight > withdrawal.LastValidBlockHeight {
+			return models.WithdrawalExpired
+		}
+		return withdrawal.Status
+	}
+	if status.Err != nil {
+		return models.WithdrawalFailed
+	}
+	switch status.ConfirmationStatus {
+	case rpc.ConfirmationStatusFinalized:
+		return models.WithdrawalFinalized
+	case rpc.ConfirmationStatusConfirmed:
+		return models.WithdrawalConfirmed
+	}
+	return withdrawal.Status
+}
+
+/**
+* @Internal
+* Settles payment of a finished withdrawal, confirming the ledger
+* transaction when finalized and refunding it otherwise.
+ */
+func (c *Controller) settleWithdrawal(
+	withdrawal *models.Withdrawal,
+	status models.WithdrawalStatus,
+	reason string,
+) error {
+	payment := withdrawal.Payment
+	if payment == nil {
+		payment = &models.Payment{}
+		if err := db.GetDB().First(payment, withdrawal.PaymentID).Error; err != nil {
+			return err
+		}
+		withdrawal.Payment = payment
+	}
+	if err := c.settleWithdrawalPayment(
+		payment,
+		status == models.WithdrawalFinalized,
+	); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	withdrawal.Status = status
+	withdrawal.SettledAt = &now
+	withdrawal.Error = reason
+	if err := db.GetDB().Omit("Payment").Save(withdrawal).Error; err != nil {
+		return err
+	}
+	c.emitWithdrawalStatus(withdrawal)
+
+	level := "success"
+	if status != models.WithdrawalFinalized {
+		level = "error"
+	}
+	log.LogMessage(
+		"payment_withdrawal_settleWithdrawal",
+		"withdrawal settled",
+		level,
+		logrus.Fields{
+			"withdrawal": withdrawal.ID,
+			"user":       withdrawal.UserID,
+			"status":     status,
+			"tx":         withdrawal.Signature,
+			"reason":     reason,
+		},
+	)
+	return nil
+}
+
+/**
+* @Internal
+* Moves a pending withdraw payment to success or failed, confirming
+* or refunding its ledger transaction. The status update is
+* conditional, so the poller and the transaction decoder settle
+* a payment once. Already settled payments are left as they are.
+ */
+func (c *Controller) settleWithdrawalPayment(payment *models.Payment, success bool) error {
+	if payment.TransactionID == nil {
+		return utils.MakeError(
+			"payment_withdrawal",
+			"settleWithdrawalPayment",
+			"invalid payment",
+			errors.New("payment without transaction"),
+		)
+	}
+
+	// 1. Claim pending payment.
+	status := models.Failed
+	if success {
+		status = models.Success
+	}
+	result := db.GetDB().Model(&models.Payment{}).
+		Where("id = ?", payment.ID).
+		Where("status = ?", models.Pending).
+		Update("status", status)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	// 2. Confirm or refund ledger transaction.
+	var err error
+	if success {
+		err = transaction.Confirm(transaction.ConfirmRequest{
+			Transaction: db_aggregator.Transaction(*payment.TransactionID),
+			OwnerID:     payment.ID,
+			OwnerType:   models.TransactionPaymentReferenced,
+		})
+	} else {
+		err = transaction.Decline(transaction.DeclineRequest{
+			Transaction: db_aggregator.Transaction(*payment.TransactionID),
+			OwnerID:     payment.ID,
+			OwnerType:   models.TransactionPaymentReferenced,
+		})
+	}
+	if err != nil {
+		db.GetDB().Model(&models.Payment{}).
+			Where("id = ?", payment.ID).
+			Update("status", models.Pending)
+		return utils.MakeError(
+			"payment_withdrawal",
+			"settleWithdrawalPayment",
+			"failed to settle ledger transaction",
+			err,
+		)
+	}
+	payment.Status = status
+
+	// 3. Notify as before lifecycle tracking.
+	if success {
+		eventType := "withdraw_sol"
+		if payment.Type == "withdraw_nft" {
+			eventType = "withdraw_nft"
+		}
+		b, _ := json.Marshal(struct {
+			EventType string `json:"eventType"`
+			TxID      string `json:"txId"`
+		}{EventType: eventType, TxID: payment.TxHash})
+		c.EventEmitter <- types.WSEvent{Users: []uint{payment.UserID}, Message: b}
+	}
+	return nil
+}
+
+func (c *Controller) emitWithdrawalStatus(withdrawal *models.Withdrawal) {
+	paymentType := ""
+	if withdrawal.Payment != nil {
+		paymentType = withdrawal.Payment.Type
+	}
+	b, _ := json.Marshal(types.WSMessage{
+		EventType: "withdraw_status",
+		Payload: types.WithdrawalStatusPayload{
+			PaymentID: withdrawal.PaymentID,
+			Type:      paymentType,
+			Status:    withdrawal.Status,
+			TxID:      withdrawal.Signature,
+		},
+	})
+	c.EventEmitter <- types.WSEvent{Users: []uint{withdrawal.UserID}, Message: b}
+}
@@ -0,0 +1,81 @@
+package payment
+
+import (
+	"testing"
+
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestNextWithdrawalStatus(t *testing.T) {
+	withdrawal := models.Withdrawal{
+		Status:               models.WithdrawalSent,
+		LastValidBlockHeight: 100,
+	}
+	for _, test := range []struct {
+		name        string
+		status      *rpc.SignatureStatusesResult
+		blockHeight uint64
+		next        models.WithdrawalStatus
+	}{
+		{
+			name:        "unknown and valid",
+			blockHeight: 100,
+			next:        models.WithdrawalSent,
+		},
+		{
+			name:        "unknown and expired",
+			blockHeight: 101,
+			next:        models.WithdrawalExpired,
+		},
+		{
+			name:        "processed",
+			status:      &rpc.SignatureStatusesResult{ConfirmationStatus: rpc.ConfirmationStatusProcessed},
+			blockHeight: 101,
+			next:        models.WithdrawalSent,
+		},
+		{
+			name:   "confirmed",
+			status: &rpc.SignatureStatusesResult{ConfirmationStatus: rpc.ConfirmationStatusConfirmed},
+			next:   models.WithdrawalConfirmed,
+		},
+		{
+			name:   "finalized",
+			status: &rpc.SignatureStatusesResult{ConfirmationStatus: rpc.ConfirmationStatusFinalized},
+			next:   models.WithdrawalFinalized,
+		},
+		{
+			name: "failed",
+			status: &rpc.SignatureStatusesResult{
+				ConfirmationStatus: rpc.ConfirmationStatusFinalized,
+				Err:                map[string]interface{}{"InstructionError": []interface{}{0, "Custom"}},
+			},
+			next: models.WithdrawalFailed,
+		},
+	} {
+		if next := nextWithdrawalStatus(&withdrawal, test.status, test.blockHeight); next != test.next {
+			t.Fatalf("%s: expected %s, got %s", test.name, test.next, next)
+		}
+	}
+}
+
+func TestIsSettledWithdrawalStatus(t *testing.T) {
+	for _, status := range []models.WithdrawalStatus{
+		models.WithdrawalQueued,
+		models.WithdrawalSent,
+		models.WithdrawalConfirmed,
+	} {
+		if isSettledWithdrawalStatus(status) {
+			t.Fatalf("%s withdrawal can still be broadcast", status)
+		}
+	}
+	for _, status := range []models.WithdrawalStatus{
+		models.WithdrawalFinalized,
+		models.WithdrawalExpired,
+		models.WithdrawalFailed,
+	} {
+		if !isSettledWithdrawalStatus(status) {
+			t.Fatalf("%s withdrawal should not be broadcast", status)
+		}
+	}
+}
@@ -109,6 +109,16 @@ func getInitialUsers() []InitialDuelUser {
 			Name:          "WR_TEMP",
 			WalletAddress: "A34Rv49byu8ebEY6LtLDp9hzLT3iW3uNWHgQq1Hzsrb1",
 		},
+		{
+			ID:            config.QUEST_TEMP_ID,
+			Name:          "QS_TEMP",
+			WalletAddress: "BqMm6r62rqzNupqL69weqrpF3wTEoFr9C1qfTc7DH7M",
+		},
+		{
+			ID:            config.HOUSE_RAIN_TEMP_ID,
+			Name:          "HR_TEMP",
+			WalletAddress: "3o3TECnPhkcxP1tRNpwkm2zbGFfS6QTH4YkDKuvaufsp",
+		},
 	}
 }
 
@@ -128,6 +138,8 @@ func getInitialUsers() []InitialDuelUser {
 * 13.CH_FEE,	100003	475ALhTThzNsqeA46sD55181KxVgZsGRbqmxm3ERKN7B
 * 14.DR_TEMP,	100004	EohHXvADJy3jFTWsNiTjmWhtCEGfkvgFdq6JsNjdZt96
 * 14.WR_TEMP,	100005	A34Rv49byu8ebEY6LtLDp9hzLT3iW3uNWHgQq1Hzsrb1
+* 15.QS_TEMP,	100006	BqMm6r62rqzNupqL69weqrpF3wTEoFr9C1qfTc7DH7M
+* 16.HR_TEMP,	100007	3o3TECnPhkcxP1tRNpwkm2zbGFfS6QTH4YkDKuvaufsp
  */
 func InitDuelMainUsers(db *gorm.DB) error {
 	initialUsers := getInitialUsers()
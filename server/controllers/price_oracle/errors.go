@@ -0,0 +1,6 @@
+package price_oracle
+
+// Error code range: #113xxx
+const ErrCodeBase = "#113"
+const ErrCodeUnsafePrice = ErrCodeBase + "000"
+const ErrCodeUnknownSource = ErrCodeBase + "001"
@@ -0,0 +1,67 @@
+package price_oracle
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/controllers/solana"
+	"github.com/Duelana-Team/duelana-v1/utils"
+)
+
+var defaultProviders = []Provider{
+	coinGeckoProvider{},
+	jupiterProvider{},
+	usdPegProvider{},
+}
+
+var defaultOracle = NewPriceOracle(defaultProviders...)
+
+/**
+* @External
+* Prices the token with the default oracle.
+ */
+func GetPrice(token *solana.SplTokenMeta) (*Price, error) {
+	return defaultOracle.GetPrice(token)
+}
+
+/**
+* @External
+* Prices the token at a past time with the default oracle,
+* from sources quoting past prices only.
+ */
+func GetPriceAt(token *solana.SplTokenMeta, at time.Time) (*Price, error) {
+	return defaultOracle.GetPriceAt(token, at)
+}
+
+/**
+* @External
+* Validates comma separated price sources of a token.
+ */
+func ValidatePriceSource(priceSource string) error {
+	sources := parseSources(priceSource)
+	if len(sources) == 0 {
+		return utils.MakeErrorWithCode(
+			"price_oracle",
+			"ValidatePriceSource",
+			"empty price source",
+			ErrCodeUnknownSource,
+			fmt.Errorf("price source: %s", priceSource),
+		)
+	}
+	for _, source := range sources {
+		known := false
+		for _, provider := range defaultProviders {
+			known = known || provider.Name() == source
+		}
+		if !known {
+			return utils.MakeErrorWithCode(
+				"price_oracle",
+				"ValidatePriceSource",
+				"unknown price source",
+				ErrCodeUnknownSource,
+				fmt.Errorf("source: %s", source),
+			)
+		}
+	}
+	return nil
+}
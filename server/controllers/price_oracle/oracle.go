@@ -0,0 +1,213 @@
+package price_oracle
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/controllers/solana"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"golang.org/x/sync/syncmap"
+)
+
+// Oracle aggregating quotes of its providers by median.
+type medianOracle struct {
+	providers map[string]Provider
+	cache     syncmap.Map
+}
+
+/**
+* @External
+* Creates an oracle pricing tokens from the given providers.
+ */
+func NewPriceOracle(providers ...Provider) PriceOracle {
+	oracle := medianOracle{
+		providers: map[string]Provider{},
+	}
+	for _, provider := range providers {
+		oracle.providers[provider.Name()] = provider
+	}
+	return &oracle
+}
+
+/**
+* @Internal
+* Splits comma separated price sources.
+ */
+func parseSources(priceSource string) []string {
+	sources := []string{}
+	for _, source := range strings.Split(priceSource, ",") {
+		if source = strings.TrimSpace(source); source != "" {
+			sources = append(sources, source)
+		}
+	}
+	return sources
+}
+
+func (oracle *medianOracle) GetPrice(token *solana.SplTokenMeta) (*Price, error) {
+	mint := token.MintAddress.String()
+	sources := parseSources(token.PriceSource)
+	cacheKey := mint + "|" + strings.Join(sources, ",")
+	if cached, ok := oracle.cache.Load(cacheKey); ok &&
+		time.Since(cached.(Price).At) < config.PRICE_ORACLE_CACHE_TTL {
+		price := cached.(Price)
+		return &price, nil
+	}
+
+	// 1. Collect quotes.
+	quotes := oracle.collectQuotes(
+		sources,
+		func(provider Provider) (*Quote, error) {
+			return provider.GetQuote(mint)
+		},
+	)
+
+	// 2. Aggregate.
+	minQuotes := config.PRICE_ORACLE_MIN_QUOTES
+	if minQuotes > len(sources) {
+		minQuotes = len(sources)
+	}
+	now := time.Now()
+	aggregated, err := aggregateQuotes(quotes, minQuotes, now)
+	price := Price{
+		Mint:   mint,
+		Price:  aggregated,
+		At:     now,
+		Quotes: quotes,
+	}
+	if err != nil {
+		return &price, utils.MakeErrorWithCode(
+			"price_oracle",
+			"GetPrice",
+			"unsafe price",
+			ErrCodeUnsafePrice,
+			fmt.Errorf("mint: %s, err: %v", mint, err),
+		)
+	}
+	oracle.cache.Store(cacheKey, price)
+	return &price, nil
+}
+
+func (oracle *medianOracle) GetPriceAt(token *solana.SplTokenMeta, at time.Time) (*Price, error) {
+	mint := token.MintAddress.String()
+	sources := []string{}
+	for _, source := range parseSources(token.PriceSource) {
+		if _, ok := oracle.providers[source].(HistoricalProvider); ok {
+			sources = append(sources, source)
+		}
+	}
+
+	// 1. Collect quotes at the time.
+	quotes := oracle.collectQuotes(
+		sources,
+		func(provider Provider) (*Quote, error) {
+			return provider.(HistoricalProvider).GetQuoteAt(mint, at)
+		},
+	)
+
+	// 2. Aggregate, requiring as many quotes as live pricing so
+	// that a token with a single historical source stays unsafe.
+	minQuotes := config.PRICE_ORACLE_MIN_QUOTES
+	if allSources := len(parseSources(token.PriceSource)); minQuotes > allSources {
+		minQuotes = allSources
+	}
+	aggregated, err := aggregateQuotes(quotes, minQuotes, at)
+	price := Price{
+		Mint:   mint,
+		Price:  aggregated,
+		At:     at,
+		Quotes: quotes,
+	}
+	if err != nil {
+		return &price, utils.MakeErrorWithCode(
+			"price_oracle",
+			"GetPriceAt",
+			"unsafe price",
+			ErrCodeUnsafePrice,
+			fmt.Errorf("mint: %s, at: %v, err: %v", mint, at, err),
+		)
+	}
+	return &price, nil
+}
+
+/**
+* @Internal
+* Quotes from each source concurrently.
+* Failed and unknown sources are left out.
+ */
+func (oracle *medianOracle) collectQuotes(
+	sources []string,
+	getQuote func(provider Provider) (*Quote, error),
+) []Quote {
+	results := make([]*Quote, len(sources))
+	var wg sync.WaitGroup
+	for i, source := range sources {
+		provider, ok := oracle.providers[source]
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, provider Provider) {
+			defer wg.Done()
+			quote, err := getQuote(provider)
+			if err == nil && quote != nil {
+				results[i] = quote
+			}
+		}(i, provider)
+	}
+	wg.Wait()
+
+	quotes := []Quote{}
+	for _, quote := range results {
+		if quote != nil {
+			quotes = append(quotes, *quote)
+		}
+	}
+	return quotes
+}
+
+/**
+* @Internal
+* Returns median of positive quotes quoted within staleness of
+* `now`. Fails when fewer than `minQuotes` are fresh, or one
+* deviates from median more than `config.PRICE_ORACLE_MAX_DEVIATION`.
+ */
+func aggregateQuotes(quotes []Quote, minQuotes int, now time.Time) (float64, error) {
+	prices := []float64{}
+	for _, quote := range quotes {
+		age := now.Sub(quote.At)
+		if quote.Price > 0 &&
+			age <= config.PRICE_ORACLE_MAX_STALENESS &&
+			age >= -config.PRICE_ORACLE_MAX_STALENESS {
+			prices = append(prices, quote.Price)
+		}
+	}
+	if len(prices) == 0 ||
+		len(prices) < minQuotes {
+		return 0, fmt.Errorf(
+			"not enough fresh quotes: fresh: %d, required: %d",
+			len(prices), minQuotes,
+		)
+	}
+
+	sort.Float64s(prices)
+	median := prices[len(prices)/2]
+	if len(prices)%2 == 0 {
+		median = (prices[len(prices)/2-1] + prices[len(prices)/2]) / 2
+	}
+
+	for _, price := range prices {
+		deviation := math.Abs(price-median) / median * 10000
+		if deviation > float64(config.PRICE_ORACLE_MAX_DEVIATION) {
+			return 0, fmt.Errorf(
+				"quotes deviate from median: price: %v, median: %v, deviation: %.0f bps",
+				price, median, deviation,
+			)
+		}
+	}
+	return median, nil
+}
@@ -0,0 +1,190 @@
+package price_oracle
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/controllers/solana"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	solanaGo "github.com/gagliardetto/solana-go"
+)
+
+// Quotes a fixed price, counting calls.
+type fakeProvider struct {
+	name  string
+	price float64
+	age   time.Duration
+	err   error
+	calls int
+}
+
+func (provider *fakeProvider) Name() string {
+	return provider.name
+}
+
+func (provider *fakeProvider) GetQuote(mint string) (*Quote, error) {
+	provider.calls++
+	if provider.err != nil {
+		return nil, provider.err
+	}
+	return &Quote{
+		Source: provider.name,
+		Price:  provider.price,
+		At:     time.Now().Add(-provider.age),
+	}, nil
+}
+
+// Quotes a fixed price at the asked time as well.
+type fakeHistoricalProvider struct {
+	fakeProvider
+}
+
+func (provider *fakeHistoricalProvider) GetQuoteAt(mint string, at time.Time) (*Quote, error) {
+	quote, err := provider.GetQuote(mint)
+	if quote != nil {
+		quote.At = at.Add(-provider.age)
+	}
+	return quote, err
+}
+
+func TestAggregateQuotes(t *testing.T) {
+	now := time.Now()
+	quote := func(price float64, age time.Duration) Quote {
+		return Quote{Price: price, At: now.Add(-age)}
+	}
+
+	if price, err := aggregateQuotes(
+		[]Quote{quote(100, 0), quote(101, 0), quote(100.5, 0)},
+		2, now,
+	); err != nil || price != 100.5 {
+		t.Fatalf("median of odd quotes: %v, %v", price, err)
+	}
+	if price, err := aggregateQuotes(
+		[]Quote{quote(100, 0), quote(101, 0)},
+		2, now,
+	); err != nil || price != 100.5 {
+		t.Fatalf("median of even quotes: %v, %v", price, err)
+	}
+	if _, err := aggregateQuotes(
+		[]Quote{quote(100, 0), quote(110, 0)},
+		2, now,
+	); err == nil {
+		t.Fatalf("deviated quotes should be unsafe")
+	}
+	if _, err := aggregateQuotes(
+		[]Quote{quote(100, 0), quote(100, config.PRICE_ORACLE_MAX_STALENESS+time.Second)},
+		2, now,
+	); err == nil {
+		t.Fatalf("stale quote should not count")
+	}
+	if _, err := aggregateQuotes(
+		[]Quote{quote(100, 0), quote(100, -config.PRICE_ORACLE_MAX_STALENESS-time.Second)},
+		2, now,
+	); err == nil {
+		t.Fatalf("quote too far after should not count")
+	}
+	if _, err := aggregateQuotes(
+		[]Quote{quote(0, 0)},
+		0, now,
+	); err == nil {
+		t.Fatalf("zero price should be unsafe")
+	}
+}
+
+func TestGetPrice(t *testing.T) {
+	coingecko := &fakeProvider{name: CoinGeckoSource, price: 20}
+	jupiter := &fakeProvider{name: JupiterSource, price: 20.1}
+	oracle := NewPriceOracle(coingecko, jupiter)
+	token := solana.SplTokenMeta{
+		MintAddress: solanaGo.NewWallet().PublicKey(),
+		PriceSource: "coingecko, jupiter",
+	}
+
+	// Median, cached afterwards.
+	price, err := oracle.GetPrice(&token)
+	if err != nil || price.Price != 20.05 || len(price.Quotes) != 2 {
+		t.Fatalf("unexpected price: %v, %v", price, err)
+	}
+	if _, err := oracle.GetPrice(&token); err != nil ||
+		coingecko.calls != 1 {
+		t.Fatalf("price should be cached: calls: %d, err: %v", coingecko.calls, err)
+	}
+
+	// Unsafe with a failed source, quotes are kept.
+	token.MintAddress = solanaGo.NewWallet().PublicKey()
+	jupiter.err = errors.New("unavailable")
+	price, err = oracle.GetPrice(&token)
+	if !utils.IsErrorCode(err, ErrCodeUnsafePrice) ||
+		price.Price != 0 || len(price.Quotes) != 1 {
+		t.Fatalf("single quote of two sources should be unsafe: %v, %v", price, err)
+	}
+
+	// Single source token needs a single quote.
+	token.PriceSource = CoinGeckoSource
+	if price, err := oracle.GetPrice(&token); err != nil || price.Price != 20 {
+		t.Fatalf("unexpected single source price: %v, %v", price, err)
+	}
+
+	// Unknown source is missing.
+	token.PriceSource = "unknown"
+	if _, err := oracle.GetPrice(&token); !utils.IsErrorCode(err, ErrCodeUnsafePrice) {
+		t.Fatalf("unknown source should be unsafe: %v", err)
+	}
+}
+
+func TestGetPriceAt(t *testing.T) {
+	coingecko := &fakeHistoricalProvider{fakeProvider{name: CoinGeckoSource, price: 20}}
+	archive := &fakeHistoricalProvider{fakeProvider{name: "archive", price: 20}}
+	jupiter := &fakeProvider{name: JupiterSource, price: 30}
+	oracle := NewPriceOracle(coingecko, archive, jupiter)
+	token := solana.SplTokenMeta{
+		MintAddress: solanaGo.NewWallet().PublicKey(),
+		PriceSource: "coingecko,archive,jupiter",
+	}
+	depositedAt := time.Now().Add(-time.Hour)
+
+	// Priced from historical sources only, at the time.
+	price, err := oracle.GetPriceAt(&token, depositedAt)
+	if err != nil || price.Price != 20 || !price.At.Equal(depositedAt) ||
+		jupiter.calls != 0 {
+		t.Fatalf("unexpected price: %v, %v, jupiter calls: %d", price, err, jupiter.calls)
+	}
+
+	// A single historical quote does not make up for live sources.
+	token.PriceSource = "coingecko,jupiter"
+	if _, err := oracle.GetPriceAt(&token, depositedAt); !utils.IsErrorCode(err, ErrCodeUnsafePrice) {
+		t.Fatalf("single historical quote of two sources should be unsafe: %v", err)
+	}
+
+	// Unless it is the only source of the token.
+	token.PriceSource = CoinGeckoSource
+	if _, err := oracle.GetPriceAt(&token, depositedAt); err != nil {
+		t.Fatalf("single source token should be priced: %v", err)
+	}
+
+	// Quote far from the time is unsafe.
+	coingecko.age = config.PRICE_ORACLE_MAX_STALENESS + time.Second
+	if _, err := oracle.GetPriceAt(&token, depositedAt); !utils.IsErrorCode(err, ErrCodeUnsafePrice) {
+		t.Fatalf("stale quote at the time should be unsafe: %v", err)
+	}
+
+	// No historical source is unsafe.
+	token.PriceSource = JupiterSource
+	if _, err := oracle.GetPriceAt(&token, depositedAt); !utils.IsErrorCode(err, ErrCodeUnsafePrice) {
+		t.Fatalf("no historical source should be unsafe: %v", err)
+	}
+}
+
+func TestValidatePriceSource(t *testing.T) {
+	if err := ValidatePriceSource(config.SPL_TOKEN_DEFAULT_PRICE_SOURCE); err != nil {
+		t.Fatalf("default price source should be valid: %v", err)
+	}
+	if err := ValidatePriceSource("coingecko,unknown"); !utils.IsErrorCode(err, ErrCodeUnknownSource) {
+		t.Fatalf("unknown source should be rejected: %v", err)
+	}
+	if err := ValidatePriceSource(" , "); !utils.IsErrorCode(err, ErrCodeUnknownSource) {
+		t.Fatalf("empty source should be rejected: %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+package price_oracle
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+)
+
+var httpClient = &http.Client{Timeout: config.PRICE_ORACLE_REQUEST_TIMEOUT}
+
+/**
+* @Internal
+* Gets json from `url` into `out`.
+ */
+func getJson(url string, out interface{}) error {
+	res, err := httpClient.Get(url)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status: %d", res.StatusCode)
+	}
+	return json.NewDecoder(res.Body).Decode(out)
+}
+
+// Quotes from CoinGecko compatible api at `TOKEN_PRICE_API`.
+type coinGeckoProvider struct{}
+
+func (provider coinGeckoProvider) Name() string {
+	return CoinGeckoSource
+}
+
+func (provider coinGeckoProvider) GetQuote(mint string) (*Quote, error) {
+	var response map[string]struct {
+		USD           float64 `json:"usd"`
+		LastUpdatedAt int64   `json:"last_updated_at"`
+	}
+	if err := getJson(
+		fmt.Sprintf(
+			"%v/simple/token_price/solana?contract_addresses=%v&vs_currencies=usd&include_last_updated_at=true",
+			config.Get().TokenPriceApi, url.QueryEscape(mint),
+		),
+		&response,
+	); err != nil {
+		return nil, err
+	}
+	price, ok := response[mint]
+	if !ok {
+		return nil, fmt.Errorf("no price of mint: %s", mint)
+	}
+	if price.LastUpdatedAt <= 0 {
+		return nil, fmt.Errorf("no update time of mint: %s", mint)
+	}
+	return &Quote{
+		Source: CoinGeckoSource,
+		Price:  price.USD,
+		At:     time.Unix(price.LastUpdatedAt, 0),
+	}, nil
+}
+
+// Quotes the price point closest to `at` within staleness.
+func (provider coinGeckoProvider) GetQuoteAt(mint string, at time.Time) (*Quote, error) {
+	var response struct {
+		Prices [][2]float64 `json:"prices"`
+	}
+	if err := getJson(
+		fmt.Sprintf(
+			"%v/coins/solana/contract/%v/market_chart/range?vs_currency=usd&from=%d&to=%d",
+			config.Get().TokenPriceApi, url.PathEscape(mint),
+			at.Add(-config.PRICE_ORACLE_MAX_STALENESS).Unix(),
+			at.Add(config.PRICE_ORACLE_MAX_STALENESS).Unix(),
+		),
+		&response,
+	); err != nil {
+		return nil, err
+	}
+	var closest *Quote
+	for _, point := range response.Prices {
+		pointAt := time.UnixMilli(int64(point[0]))
+		if closest == nil ||
+			absDuration(at.Sub(pointAt)) < absDuration(at.Sub(closest.At)) {
+			closest = &Quote{Source: CoinGeckoSource, Price: point[1], At: pointAt}
+		}
+	}
+	if closest == nil {
+		return nil, fmt.Errorf("no price of mint: %s, at: %v", mint, at)
+	}
+	return closest, nil
+}
+
+// Quotes from Jupiter price api.
+type jupiterProvider struct{}
+
+func (provider jupiterProvider) Name() string {
+	return JupiterSource
+}
+
+// Quoted at the latest of its buy and sell quote times.
+func (provider jupiterProvider) GetQuote(mint string) (*Quote, error) {
+	var response struct {
+		Data map[string]*struct {
+			Price     float64 `json:"price,string"`
+			ExtraInfo struct {
+				QuotedPrice struct {
+					BuyAt  int64 `json:"buyAt"`
+					SellAt int64 `json:"sellAt"`
+				} `json:"quotedPrice"`
+			} `json:"extraInfo"`
+		} `json:"data"`
+	}
+	if err := getJson(
+		fmt.Sprintf(
+			"%v?ids=%v&showExtraInfo=true",
+			config.PRICE_ORACLE_JUPITER_PRICE_API, url.QueryEscape(mint),
+		),
+		&response,
+	); err != nil {
+		return nil, err
+	}
+	price := response.Data[mint]
+	if price == nil {
+		return nil, fmt.Errorf("no price of mint: %s", mint)
+	}
+	quotedAt := price.ExtraInfo.QuotedPrice.BuyAt
+	if price.ExtraInfo.QuotedPrice.SellAt > quotedAt {
+		quotedAt = price.ExtraInfo.QuotedPrice.SellAt
+	}
+	if quotedAt <= 0 {
+		return nil, fmt.Errorf("no quote time of mint: %s", mint)
+	}
+	return &Quote{
+		Source: JupiterSource,
+		Price:  price.Price,
+		At:     time.Unix(quotedAt, 0),
+	}, nil
+}
+
+// Quotes one USD for stable coins.
+type usdPegProvider struct{}
+
+func (provider usdPegProvider) Name() string {
+	return UsdPegSource
+}
+
+// The peg is a fixed price rather than a market quote, so it is
+// quoted at the asked time. List a market source along with it
+// for the deviation check to catch a depeg.
+func (provider usdPegProvider) GetQuote(mint string) (*Quote, error) {
+	return provider.GetQuoteAt(mint, time.Now())
+}
+
+func (provider usdPegProvider) GetQuoteAt(mint string, at time.Time) (*Quote, error) {
+	return &Quote{Source: UsdPegSource, Price: 1, At: at}, nil
+}
+
+/**
+* @Internal
+* Absolute value of the duration.
+ */
+func absDuration(duration time.Duration) time.Duration {
+	if duration < 0 {
+		return -duration
+	}
+	return duration
+}
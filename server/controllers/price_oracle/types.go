@@ -0,0 +1,50 @@
+package price_oracle
+
+import (
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/controllers/solana"
+)
+
+const (
+	CoinGeckoSource = "coingecko"
+	JupiterSource   = "jupiter"
+	UsdPegSource    = "usd_peg"
+)
+
+// Price of a token in USD quoted by a provider.
+type Quote struct {
+	Source string    `json:"source"`
+	Price  float64   `json:"price"`
+	At     time.Time `json:"at"`
+}
+
+// Price aggregated from quotes. Zero when unsafe.
+type Price struct {
+	Mint   string    `json:"mint"`
+	Price  float64   `json:"price"`
+	At     time.Time `json:"at"`
+	Quotes []Quote   `json:"quotes"`
+}
+
+// Quotes token prices from a single source.
+type Provider interface {
+	Name() string
+	GetQuote(mint string) (*Quote, error)
+}
+
+// Quotes token prices at a past time as well.
+type HistoricalProvider interface {
+	Provider
+	GetQuoteAt(mint string, at time.Time) (*Quote, error)
+}
+
+// Prices tokens from the sources in their registry record.
+// Returns error with `ErrCodeUnsafePrice` when quotes are too
+// few, stale or apart, along with the quotes collected.
+// `GetPriceAt` uses only sources quoting past prices, but requires
+// as many quotes as `GetPrice`.
+type PriceOracle interface {
+	GetPrice(token *solana.SplTokenMeta) (*Price, error)
+	GetPriceAt(token *solana.SplTokenMeta, at time.Time) (*Price, error)
+}
@@ -0,0 +1,143 @@
+package quest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"github.com/gin-gonic/gin"
+)
+
+/**
+* @Internal
+* Validates quest request and converts it to quest model.
+* Target is provided in chips for wager-amount quests, as multiplier
+* for cash-out-multiplier quests and as count for the others.
+* Reward is provided in chips.
+ */
+func buildQuestFromRequest(request QuestRequest) (*models.Quest, error) {
+	if request.Title == "" ||
+		request.Target <= 0 ||
+		request.Reward <= 0 ||
+		(request.Period != models.QuestDaily &&
+			request.Period != models.QuestWeekly) ||
+		(request.RewardType != models.QuestRewardChip &&
+			request.RewardType != models.QuestRewardCoupon) {
+		return nil, errors.New("missing or invalid quest field")
+	}
+
+	quest := models.Quest{
+		Title:       request.Title,
+		Description: request.Description,
+		Kind:        request.Kind,
+		Game:        request.Game,
+		Period:      request.Period,
+		RewardType:  request.RewardType,
+		Reward:      utils.ConvertChipToBalance(request.Reward),
+		Enabled:     request.Enabled,
+	}
+	switch request.Kind {
+	case models.QuestWagerAmount:
+		quest.Target = int64(request.Target * float64(config.ONE_CHIP_WITH_DECIMALS))
+	case models.QuestCashOutMultiplier:
+		if request.Game != models.Crash {
+			return nil, errors.New("cash-out-multiplier quest is only for crash")
+		}
+		quest.Target = int64(request.Target * 100)
+	case models.QuestWinStreak:
+		if request.Game == "" {
+			return nil, errors.New("win-streak quest requires game")
+		}
+		quest.Target = int64(request.Target)
+	case models.QuestReachLevel:
+		if request.Game != models.Dreamtower ||
+			request.Target > float64(config.DREAMTOWER_HEIGHT) {
+			return nil, errors.New("reach-level quest is only for dreamtower")
+		}
+		quest.Target = int64(request.Target)
+	default:
+		return nil, errors.New("unknown quest kind")
+	}
+	if quest.Target <= 0 {
+		return nil, errors.New("target should be positive")
+	}
+
+	return &quest, nil
+}
+
+func GetQuestsHandler(ctx *gin.Context) {
+	quests, err := getAllQuests()
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to retrieve quests",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"quests": quests,
+			"active": gin.H{
+				"daily":  getActiveQuests(models.QuestDaily),
+				"weekly": getActiveQuests(models.QuestWeekly),
+			},
+		},
+	)
+}
+
+func SaveQuestHandler(ctx *gin.Context) {
+	var params struct {
+		ID uint `json:"id"`
+		QuestRequest
+	}
+
+	if err := ctx.BindJSON(&params); err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	quest, err := buildQuestFromRequest(params.QuestRequest)
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+	quest.ID = params.ID
+
+	if err := saveQuest(quest); err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to save quest",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"message": "successfully saved quest",
+			"quest":   quest,
+		},
+	)
+}
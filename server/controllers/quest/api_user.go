@@ -0,0 +1,136 @@
+package quest
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/middlewares"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gorm.io/datatypes"
+)
+
+/**
+* @Internal
+* Builds status of the active quests for the user.
+* For not authorized users, progress is left empty.
+ */
+func prepareQuestStatus(userID uint) []QuestStatus {
+	now := time.Now()
+	progresses := getUserProgressesSince(
+		userID,
+		getPeriodStartedAt(models.QuestWeekly, now),
+	)
+
+	result := []QuestStatus{}
+	for _, period := range []models.QuestPeriod{
+		models.QuestDaily,
+		models.QuestWeekly,
+	} {
+		periodStartedAt := datatypes.Date(getPeriodStartedAt(period, now))
+		for _, quest := range getActiveQuests(period) {
+			var progress *models.QuestProgress
+			for i := range progresses {
+				if progresses[i].QuestID == quest.ID &&
+					time.Time(progresses[i].PeriodStartedAt).Equal(time.Time(periodStartedAt)) {
+					progress = &progresses[i]
+					break
+				}
+			}
+			result = append(result, buildQuestStatus(quest, progress))
+		}
+	}
+	return result
+}
+
+func GetQuestStatusHandler(ctx *gin.Context) {
+	ctx.JSON(
+		http.StatusOK,
+		prepareQuestStatus(
+			middlewares.GetAuthUserID(ctx, false),
+		),
+	)
+}
+
+func GetQuestRewardsHandler(ctx *gin.Context) {
+	userID := middlewares.GetAuthUserID(ctx, true)
+	if userID == 0 {
+		return
+	}
+
+	rewards := []QuestStatus{}
+	for _, progress := range getUnclaimedProgresses(userID) {
+		rewards = append(
+			rewards,
+			buildQuestStatus(progress.Quest, &progress),
+		)
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"rewards": rewards,
+		},
+	)
+}
+
+func ClaimQuestRewardsHandler(ctx *gin.Context) {
+	userID := middlewares.GetAuthUserID(ctx, true)
+	if userID == 0 {
+		return
+	}
+
+	var params struct {
+		IDs []uint `json:"ids"`
+	}
+	if err := ctx.BindJSON(
+		&params,
+	); err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+			},
+		)
+		return
+	}
+
+	if claimed, err := claimRewards(
+		userID,
+		params.IDs,
+	); err == nil {
+		ctx.JSON(
+			http.StatusOK,
+			claimed,
+		)
+	} else {
+		log.LogMessage(
+			"quest_ClaimQuestRewardsHandler",
+			"failed to claim rewards",
+			"error",
+			logrus.Fields{
+				"userID":      userID,
+				"progressIDs": params.IDs,
+				"error":       err.Error(),
+			},
+		)
+		if utils.IsErrorCode(err, ErrCodeNotCompletedQuest) {
+			ctx.AbortWithStatusJSON(
+				http.StatusBadRequest,
+				gin.H{
+					"message": "not completed or already claimed quest",
+				},
+			)
+			return
+		}
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to claim rewards",
+			},
+		)
+	}
+}
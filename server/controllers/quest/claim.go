@@ -0,0 +1,258 @@
+package quest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/controllers/coupon"
+	"github.com/Duelana-Team/duelana-v1/controllers/transaction/db_aggregator"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"github.com/google/uuid"
+)
+
+/**
+* Claims user's completed quest rewards.
+* Chip rewards are paid from quest temp wallet, and coupon rewards
+* are issued as coupon codes only for the user, in the same session
+* with the claim so that a failed reward leaves the quest unclaimed.
+* Returns claim results and an error object.
+ */
+func claimRewards(
+	userID uint,
+	progressIDs []uint,
+) ([]QuestClaimResult, error) {
+	// 1. Validate parameters.
+	if userID == 0 ||
+		len(progressIDs) == 0 ||
+		utils.IsDuplicateInArray(progressIDs) {
+		return nil, utils.MakeErrorWithCode(
+			"quest_claim",
+			"claimRewards",
+			"invalid parameter",
+			ErrCodeInvalidParameter,
+			fmt.Errorf(
+				"userID: %d, progressIDs: %v",
+				userID, progressIDs,
+			),
+		)
+	}
+
+	// 2. Claim in a unit of work.
+	results := []QuestClaimResult{}
+	if err := db_aggregator.WithTx(
+		context.Background(),
+		func(ctx context.Context) error {
+			sessionId := db_aggregator.SessionIdFromContext(ctx)
+			results = []QuestClaimResult{}
+
+			// 2.1. Lock and retrieve completed progresses.
+			progresses, err := lockAndRetrieveCompletedProgresses(
+				userID,
+				progressIDs,
+				sessionId,
+			)
+			if err != nil {
+				return utils.MakeError(
+					"quest_claim",
+					"claimRewards",
+					"failed to lock and retrieve progresses",
+					err,
+				)
+			}
+
+			// 2.2. Update claimed of the progresses.
+			if err := updateProgressesClaimed(
+				progresses,
+				sessionId,
+			); err != nil {
+				return utils.MakeError(
+					"quest_claim",
+					"claimRewards",
+					"failed to update progresses claimed",
+					err,
+				)
+			}
+
+			// 2.3. Perform chip transactions, issue coupons and leave history.
+			for i, progress := range progresses {
+				result := QuestClaimResult{
+					QuestID: progress.QuestID,
+				}
+				switch progress.Quest.RewardType {
+				case models.QuestRewardChip:
+					if _, err := giveChipsForClaim(
+						userID,
+						progress.Quest.Reward,
+						progress.ID,
+						sessionId,
+					); err != nil {
+						return utils.MakeError(
+							"quest_claim",
+							"claimRewards",
+							"failed to give chips for claim",
+							fmt.Errorf(
+								"i: %d, progressID: %d, err: %v",
+								i, progress.ID, err,
+							),
+						)
+					}
+					result.Chips = progress.Quest.Reward
+				case models.QuestRewardCoupon:
+					code, err := issueCouponForClaim(
+						userID,
+						progress,
+						sessionId,
+					)
+					if err != nil {
+						return utils.MakeError(
+							"quest_claim",
+							"claimRewards",
+							"failed to issue coupon for claim",
+							fmt.Errorf(
+								"i: %d, progressID: %d, err: %v",
+								i, progress.ID, err,
+							),
+						)
+					}
+					result.Coupon = &code
+				}
+				results = append(results, result)
+			}
+			return nil
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+/**
+* @Internal
+* Creates a coupon code only for the user with quest reward as bonus
+* balance, and saves it to the progress in the session.
+ */
+func issueCouponForClaim(
+	userID uint,
+	progress models.QuestProgress,
+	sessionId db_aggregator.UUID,
+) (uuid.UUID, error) {
+	code, err := coupon.CreateForUser(
+		userID,
+		progress.Quest.Reward,
+		sessionId,
+	)
+	if err != nil {
+		return uuid.Nil, utils.MakeError(
+			"quest_claim",
+			"issueCouponForClaim",
+			"failed to create coupon",
+			err,
+		)
+	}
+	if err := setProgressRewardCoupon(
+		progress.ID,
+		code,
+		sessionId,
+	); err != nil {
+		return code, utils.MakeError(
+			"quest_claim",
+			"issueCouponForClaim",
+			"failed to save reward coupon",
+			err,
+		)
+	}
+	return code, nil
+}
+
+/**
+* @Internal
+* Gives chips for claim.
+* Returns generated tx id, and error object.
+* Utilize progressID as ownerID of polymorphic association.
+ */
+func giveChipsForClaim(
+	userID uint,
+	amount int64,
+	progressID uint,
+	sessionId db_aggregator.UUID,
+) (uint, error) {
+	// 1. Validate parameter.
+	if userID == 0 ||
+		amount <= 0 ||
+		progressID == 0 {
+		return 0, utils.MakeError(
+			"quest_claim",
+			"giveChipsForClaim",
+			"invalid parameter",
+			fmt.Errorf(
+				"userID: %d, amount: %d, progressID: %d",
+				userID, amount, progressID,
+			),
+		)
+	}
+
+	// 2. Give chips for claiming to the user.
+	txResult, err := db_aggregator.Transfer(
+		(*db_aggregator.User)(&config.QUEST_TEMP_ID),
+		(*db_aggregator.User)(&userID),
+		&db_aggregator.BalanceLoad{
+			ChipBalance: &amount,
+		},
+		sessionId,
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), "insufficient funds") &&
+			strings.Contains(err.Error(), "removeChipsFromUser") {
+			return 0, utils.MakeErrorWithCode(
+				"quest_claim",
+				"giveChipsForClaim",
+				"insufficient admin temp wallet balance",
+				ErrCodeInsufficientAdminBalance,
+				err,
+			)
+		}
+		return 0, utils.MakeError(
+			"quest_claim",
+			"giveChipsForClaim",
+			"failed to perform real chips transfer",
+			err,
+		)
+	}
+
+	// 3. Leave transaction.
+	transactionHistory := models.Transaction{
+		FromWallet: (*uint)(txResult.FromWallet),
+		ToWallet:   (*uint)(txResult.ToWallet),
+		Balance: models.Balance{
+			ChipBalance: &models.ChipBalance{
+				Balance: amount,
+			},
+		},
+		Type:   models.TxClaimQuestReward,
+		Status: models.TransactionSucceed,
+
+		FromWalletPrevID: (*uint)(txResult.FromPrevBalance),
+		FromWalletNextID: (*uint)(txResult.FromNextBalance),
+		ToWalletPrevID:   (*uint)(txResult.ToPrevBalance),
+		ToWalletNextID:   (*uint)(txResult.ToNextBalance),
+		OwnerID:          progressID,
+		OwnerType:        models.TransactionQuestProgressReferenced,
+	}
+	if err := db_aggregator.LeaveRealTransaction(
+		&transactionHistory,
+		sessionId,
+	); err != nil {
+		return 0, utils.MakeError(
+			"quest_claim",
+			"giveChipsForClaim",
+			"failed to leave transaction",
+			err,
+		)
+	}
+
+	return transactionHistory.ID, nil
+}
@@ -0,0 +1,622 @@
+package quest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/controllers/transaction/db_aggregator"
+	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+/**
+* @Internal
+* Returns enabled quests of the period ordered by id.
+ */
+func getEnabledQuests(
+	period models.QuestPeriod,
+) ([]models.Quest, error) {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"quest_db",
+			"getEnabledQuests",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	quests := []models.Quest{}
+	if err := session.Where(
+		"period = ?",
+		period,
+	).Where(
+		"enabled = ?",
+		true,
+	).Order(
+		"id",
+	).Find(&quests).Error; err != nil {
+		return nil, utils.MakeError(
+			"quest_db",
+			"getEnabledQuests",
+			"failed to retrieve quests",
+			fmt.Errorf(
+				"period: %s, err: %v",
+				period, err,
+			),
+		)
+	}
+
+	return quests, nil
+}
+
+/**
+* @Internal
+* Updates the user's progresses of the quests by a wager event in a
+* single unit of work, in order of quest id.
+* Creates progress records if not exist, and locks them before updating.
+* Returns changed progresses and error object.
+ */
+func updateQuestProgresses(
+	quests []models.Quest,
+	event QuestEvent,
+	now time.Time,
+) ([]questProgressUpdate, error) {
+	// 1. Validate parameter.
+	if len(quests) == 0 ||
+		event.UserID == 0 {
+		return nil, utils.MakeErrorWithCode(
+			"quest_db",
+			"updateQuestProgresses",
+			"invalid parameter",
+			ErrCodeInvalidParameter,
+			fmt.Errorf(
+				"quests: %d, userID: %d",
+				len(quests), event.UserID,
+			),
+		)
+	}
+	quests = append([]models.Quest{}, quests...)
+	sort.Slice(quests, func(i, j int) bool {
+		return quests[i].ID < quests[j].ID
+	})
+
+	// 2. Update progresses in a unit of work.
+	updates := []questProgressUpdate{}
+	if err := db_aggregator.WithTx(
+		context.Background(),
+		func(ctx context.Context) error {
+			session, err := db_aggregator.GetTx(ctx)
+			if err != nil {
+				return utils.MakeError(
+					"quest_db",
+					"updateQuestProgresses",
+					"failed to retrieve transaction",
+					err,
+				)
+			}
+			updates = []questProgressUpdate{}
+
+			for _, quest := range quests {
+				progress, changed, err := updateQuestProgress(
+					session,
+					quest,
+					event,
+					getPeriodStartedAt(quest.Period, now),
+				)
+				if err != nil {
+					return utils.MakeError(
+						"quest_db",
+						"updateQuestProgresses",
+						"failed to update quest progress",
+						err,
+					)
+				}
+				if changed {
+					updates = append(updates, questProgressUpdate{
+						quest:    quest,
+						progress: *progress,
+					})
+				}
+			}
+			return nil
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	return updates, nil
+}
+
+/**
+* @Internal
+* Updates the user's progress of the quest for the period in the session.
+* Creates progress record if not exists, and locks it before updating.
+* Returns updated progress, whether progress changed and error object.
+ */
+func updateQuestProgress(
+	session *gorm.DB,
+	quest models.Quest,
+	event QuestEvent,
+	periodStartedAt time.Time,
+) (*models.QuestProgress, bool, error) {
+	// 1. Validate parameter.
+	if quest.ID == 0 ||
+		event.UserID == 0 {
+		return nil, false, utils.MakeErrorWithCode(
+			"quest_db",
+			"updateQuestProgress",
+			"invalid parameter",
+			ErrCodeInvalidParameter,
+			fmt.Errorf(
+				"questID: %d, userID: %d",
+				quest.ID, event.UserID,
+			),
+		)
+	}
+
+	// 2. Create progress record if not exists.
+	progress := models.QuestProgress{
+		QuestID:         quest.ID,
+		UserID:          event.UserID,
+		PeriodStartedAt: datatypes.Date(periodStartedAt),
+	}
+	if result := session.Clauses(
+		clause.OnConflict{DoNothing: true},
+	).Create(&progress); result.Error != nil {
+		return nil, false, utils.MakeError(
+			"quest_db",
+			"updateQuestProgress",
+			"failed to create progress",
+			fmt.Errorf(
+				"progress: %v, err: %v",
+				progress, result.Error,
+			),
+		)
+	}
+
+	// 3. Lock and retrieve progress record.
+	progress = models.QuestProgress{}
+	if result := session.Clauses(
+		clause.Locking{
+			Strength: "UPDATE",
+		},
+	).Where(
+		"quest_id = ?",
+		quest.ID,
+	).Where(
+		"user_id = ?",
+		event.UserID,
+	).Where(
+		"period_started_at = ?",
+		datatypes.Date(periodStartedAt),
+	).First(&progress); result.Error != nil {
+		return nil, false, utils.MakeError(
+			"quest_db",
+			"updateQuestProgress",
+			"failed to lock and retrieve progress",
+			fmt.Errorf(
+				"questID: %d, userID: %d, err: %v",
+				quest.ID, event.UserID, result.Error,
+			),
+		)
+	}
+	if progress.Completed {
+		return &progress, false, nil
+	}
+
+	// 4. Evaluate and update progress.
+	next := evaluateProgress(quest, progress.Progress, event)
+	if next == progress.Progress {
+		return &progress, false, nil
+	}
+	progress.Progress = next
+	progress.Completed = next >= quest.Target
+	if result := session.Model(
+		&progress,
+	).Select(
+		"progress",
+		"completed",
+	).Updates(&progress); result.Error != nil {
+		return nil, false, utils.MakeError(
+			"quest_db",
+			"updateQuestProgress",
+			"failed to update progress",
+			fmt.Errorf(
+				"progress: %v, err: %v",
+				progress, result.Error,
+			),
+		)
+	}
+
+	return &progress, true, nil
+}
+
+/**
+* @Internal
+* Returns the user's progresses since the given time.
+ */
+func getUserProgressesSince(
+	userID uint,
+	since time.Time,
+) []models.QuestProgress {
+	if userID == 0 {
+		return nil
+	}
+
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil
+	}
+
+	progresses := []models.QuestProgress{}
+	if err := session.Where(
+		"user_id = ?",
+		userID,
+	).Where(
+		"period_started_at >= ?",
+		datatypes.Date(since),
+	).Find(&progresses).Error; err != nil {
+		log.LogMessage(
+			"quest_db_getUserProgressesSince",
+			"failed to retrieve progresses",
+			"error",
+			logrus.Fields{
+				"userID": userID,
+				"error":  err.Error(),
+			},
+		)
+		return nil
+	}
+
+	return progresses
+}
+
+/**
+* @Internal
+* Returns completed but unclaimed progresses of the user
+* with quest preloaded.
+ */
+func getUnclaimedProgresses(
+	userID uint,
+) []models.QuestProgress {
+	if userID == 0 {
+		return nil
+	}
+
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil
+	}
+
+	progresses := []models.QuestProgress{}
+	if err := session.Preload(
+		"Quest",
+	).Where(
+		"user_id = ?",
+		userID,
+	).Where(
+		"completed = ?",
+		true,
+	).Where(
+		"claimed = ?",
+		0,
+	).Order(
+		"period_started_at desc",
+	).Find(&progresses).Error; err != nil {
+		log.LogMessage(
+			"quest_db_getUnclaimedProgresses",
+			"failed to retrieve progresses",
+			"error",
+			logrus.Fields{
+				"userID": userID,
+				"error":  err.Error(),
+			},
+		)
+		return nil
+	}
+
+	return progresses
+}
+
+/**
+* @Internal
+* Lock and retrieve completed and unclaimed progresses by userID
+* and progressIDs with quest preloaded.
+* If not matching retrieved record count with length of progressIDs,
+* returns error.
+ */
+func lockAndRetrieveCompletedProgresses(
+	userID uint,
+	progressIDs []uint,
+	sessionId db_aggregator.UUID,
+) ([]models.QuestProgress, error) {
+	// 1. Validate parameter.
+	if userID == 0 ||
+		len(progressIDs) == 0 {
+		return nil, utils.MakeErrorWithCode(
+			"quest_db",
+			"lockAndRetrieveCompletedProgresses",
+			"invalid parameter",
+			ErrCodeInvalidParameter,
+			fmt.Errorf(
+				"userID: %d, progressIDs: %v",
+				userID, progressIDs,
+			),
+		)
+	}
+
+	// 2. Retrieve session.
+	session, err := db_aggregator.GetSession(sessionId)
+	if err != nil {
+		return nil, utils.MakeError(
+			"quest_db",
+			"lockAndRetrieveCompletedProgresses",
+			"failed to retrieve session",
+			err,
+		)
+	}
+
+	// 3. Lock and retrieve progresses.
+	progresses := []models.QuestProgress{}
+	if result := session.Clauses(
+		clause.Locking{
+			Strength: "UPDATE",
+			Table:    clause.Table{Name: clause.CurrentTable},
+		},
+	).Preload(
+		"Quest",
+	).Where(
+		"user_id = ?",
+		userID,
+	).Where(
+		"id in ?",
+		progressIDs,
+	).Where(
+		"completed = ?",
+		true,
+	).Where(
+		"claimed = ?",
+		0,
+	).Find(&progresses); result.Error != nil {
+		return nil, utils.MakeError(
+			"quest_db",
+			"lockAndRetrieveCompletedProgresses",
+			"failed to retrieve progresses",
+			fmt.Errorf(
+				"userID: %d, progressIDs: %v, err: %v",
+				userID, progressIDs, result.Error,
+			),
+		)
+	} else if len(progresses) != len(progressIDs) {
+		return nil, utils.MakeErrorWithCode(
+			"quest_db",
+			"lockAndRetrieveCompletedProgresses",
+			"mismatching record count with progressIDs arg",
+			ErrCodeNotCompletedQuest,
+			fmt.Errorf(
+				"progressIDs count: %d, retrieved count: %d",
+				len(progressIDs), len(progresses),
+			),
+		)
+	}
+
+	return progresses, nil
+}
+
+/**
+* @Internal
+* Sets claimed of progresses as the quest reward.
+ */
+func updateProgressesClaimed(
+	progresses []models.QuestProgress,
+	sessionId db_aggregator.UUID,
+) error {
+	// 1. Validate parameter.
+	if len(progresses) == 0 {
+		return utils.MakeError(
+			"quest_db",
+			"updateProgressesClaimed",
+			"invalid parameter",
+			errors.New("provided progresses is empty slice"),
+		)
+	}
+
+	// 2. Retrieve the session.
+	session, err := db_aggregator.GetSession(sessionId)
+	if err != nil {
+		return utils.MakeError(
+			"quest_db",
+			"updateProgressesClaimed",
+			"failed to retrieve session",
+			err,
+		)
+	}
+
+	// 3. Update records one by one since rewards differ by quest.
+	for i, progress := range progresses {
+		if progress.ID == 0 ||
+			progress.Claimed != 0 ||
+			progress.Quest.Reward <= 0 {
+			return utils.MakeError(
+				"quest_db",
+				"updateProgressesClaimed",
+				"invalid parameter",
+				fmt.Errorf(
+					"progressID: %d, claimed: %d, reward: %d",
+					progress.ID, progress.Claimed, progress.Quest.Reward,
+				),
+			)
+		}
+		if result := session.Model(
+			&models.QuestProgress{},
+		).Where(
+			"id = ?",
+			progress.ID,
+		).Where(
+			"claimed = ?",
+			0,
+		).Update(
+			"claimed",
+			progress.Quest.Reward,
+		); result.Error != nil || result.RowsAffected != 1 {
+			return utils.MakeError(
+				"quest_db",
+				"updateProgressesClaimed",
+				"failed to update claimed of progress",
+				fmt.Errorf(
+					"progressID: %d, err: %v, rowsAffected: %d",
+					progress.ID, result.Error, result.RowsAffected,
+				),
+			)
+		}
+		progresses[i].Claimed = progress.Quest.Reward
+	}
+
+	return nil
+}
+
+/**
+* @Internal
+* Saves generated reward coupon code to the progress.
+ */
+func setProgressRewardCoupon(
+	progressID uint,
+	code uuid.UUID,
+	sessionId db_aggregator.UUID,
+) error {
+	session, err := db_aggregator.GetSession(sessionId)
+	if err != nil {
+		return utils.MakeError(
+			"quest_db",
+			"setProgressRewardCoupon",
+			"failed to retrieve session",
+			err,
+		)
+	}
+
+	if result := session.Model(
+		&models.QuestProgress{},
+	).Where(
+		"id = ?",
+		progressID,
+	).Update(
+		"reward_coupon",
+		code,
+	); result.Error != nil {
+		return utils.MakeError(
+			"quest_db",
+			"setProgressRewardCoupon",
+			"failed to update reward coupon",
+			fmt.Errorf(
+				"progressID: %d, code: %v, err: %v",
+				progressID, code, result.Error,
+			),
+		)
+	}
+
+	return nil
+}
+
+/**
+* @Internal
+* Returns all quest definitions ordered by id.
+ */
+func getAllQuests() ([]models.Quest, error) {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"quest_db",
+			"getAllQuests",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	quests := []models.Quest{}
+	if err := session.Order(
+		"id",
+	).Find(&quests).Error; err != nil {
+		return nil, utils.MakeError(
+			"quest_db",
+			"getAllQuests",
+			"failed to retrieve quests",
+			err,
+		)
+	}
+
+	return quests, nil
+}
+
+/**
+* @Internal
+* Creates a new quest definition or updates existing one
+* if the ID is provided.
+ */
+func saveQuest(quest *models.Quest) error {
+	if quest == nil {
+		return utils.MakeErrorWithCode(
+			"quest_db",
+			"saveQuest",
+			"invalid parameter",
+			ErrCodeInvalidParameter,
+			errors.New("provided quest is nil pointer"),
+		)
+	}
+
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return utils.MakeError(
+			"quest_db",
+			"saveQuest",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	if err := session.Transaction(func(tx *gorm.DB) error {
+		if quest.ID == 0 {
+			return tx.Create(quest).Error
+		}
+		result := tx.Model(quest).Select(
+			"title",
+			"description",
+			"kind",
+			"game",
+			"period",
+			"target",
+			"reward_type",
+			"reward",
+			"enabled",
+		).Updates(quest)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	}); err != nil {
+		return utils.MakeError(
+			"quest_db",
+			"saveQuest",
+			"failed to save quest",
+			fmt.Errorf(
+				"quest: %v, err: %v",
+				*quest, err,
+			),
+		)
+	}
+
+	invalidateActiveQuests()
+	return nil
+}
@@ -0,0 +1,7 @@
+package quest
+
+// Error code range: #108xxx
+const ErrCodeBase = "#108"
+const ErrCodeInvalidParameter = ErrCodeBase + "000"
+const ErrCodeInsufficientAdminBalance = ErrCodeBase + "001"
+const ErrCodeNotCompletedQuest = ErrCodeBase + "002"
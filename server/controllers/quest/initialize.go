@@ -0,0 +1,14 @@
+package quest
+
+import (
+	"github.com/Duelana-Team/duelana-v1/types"
+)
+
+/**
+* @External
+* Initializes quest module.
+ */
+func Initialize(eventEmitter chan types.WSEvent) {
+	initSocket(eventEmitter)
+	initWagerQueue()
+}
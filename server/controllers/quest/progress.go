@@ -0,0 +1,157 @@
+package quest
+
+import (
+	"math"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/sirupsen/logrus"
+)
+
+/**
+* @Internal
+* Checks whether the event is related to the quest's game.
+* Quests without game restriction accept every game.
+ */
+func isMatchingGame(
+	quest models.Quest,
+	event QuestEvent,
+) bool {
+	return quest.Game == "" ||
+		quest.Game == event.Type
+}
+
+/**
+* @Internal
+* Calculates the next progress of the quest by the event.
+*  - wager-amount: Accumulates bet amount.
+*  - cash-out-multiplier: Completes on win with multiplier(x100) over target.
+*  - win-streak: Increases on win, resets on loss.
+*  - reach-level: Completes on reaching target level.
+* The result never exceeds the target.
+ */
+func evaluateProgress(
+	quest models.Quest,
+	progress int64,
+	event QuestEvent,
+) int64 {
+	if !isMatchingGame(quest, event) {
+		return progress
+	}
+
+	next := progress
+	switch quest.Kind {
+	case models.QuestWagerAmount:
+		next = progress + event.Bet
+	case models.QuestCashOutMultiplier:
+		if event.Profit > 0 &&
+			int64(math.Floor(event.Multiplier*100)) >= quest.Target {
+			next = quest.Target
+		}
+	case models.QuestWinStreak:
+		if event.Profit > 0 {
+			next = progress + 1
+		} else {
+			next = 0
+		}
+	case models.QuestReachLevel:
+		if int64(event.Level) >= quest.Target {
+			next = quest.Target
+		}
+	}
+
+	if next > quest.Target {
+		next = quest.Target
+	}
+	return next
+}
+
+/**
+* @External
+* Updates quest progresses of players by the wager events.
+* This function is called from `wager.AfterWager`.
+* Progresses of an event are updated in a single unit of work.
+* Sends websocket event to the player for every changed progress.
+ */
+func HandleWager(events []QuestEvent) {
+	now := time.Now()
+	quests := []models.Quest{}
+	for _, period := range []models.QuestPeriod{
+		models.QuestDaily,
+		models.QuestWeekly,
+	} {
+		quests = append(quests, getActiveQuests(period)...)
+	}
+	if len(quests) == 0 {
+		return
+	}
+
+	for _, event := range events {
+		if event.UserID == 0 {
+			continue
+		}
+		matching := []models.Quest{}
+		for _, quest := range quests {
+			if isMatchingGame(quest, event) {
+				matching = append(matching, quest)
+			}
+		}
+		if len(matching) == 0 {
+			continue
+		}
+
+		updates, err := updateQuestProgresses(matching, event, now)
+		if err != nil {
+			log.LogMessage(
+				"quest_HandleWager",
+				"failed to update quest progresses",
+				"error",
+				logrus.Fields{
+					"event": event,
+					"error": err.Error(),
+				},
+			)
+			continue
+		}
+		for _, update := range updates {
+			sendProgressEvent(
+				event.UserID,
+				buildQuestStatus(update.quest, &update.progress),
+			)
+		}
+	}
+}
+
+/**
+* @Internal
+* Builds quest status from the quest and the user's progress.
+* Progress can be nil for not started quests.
+ */
+func buildQuestStatus(
+	quest models.Quest,
+	progress *models.QuestProgress,
+) QuestStatus {
+	periodStartedAt := getPeriodStartedAt(quest.Period, time.Now())
+	status := QuestStatus{
+		ID:          quest.ID,
+		Title:       quest.Title,
+		Description: quest.Description,
+		Kind:        quest.Kind,
+		Game:        quest.Game,
+		Period:      quest.Period,
+		Target:      quest.Target,
+		RewardType:  quest.RewardType,
+		Reward:      quest.Reward,
+		Remaining: uint(time.Until(
+			getPeriodEndAt(quest.Period, periodStartedAt),
+		).Seconds()),
+	}
+	if progress != nil {
+		status.ProgressID = progress.ID
+		status.Progress = progress.Progress
+		status.Completed = progress.Completed
+		status.Claimed = progress.Claimed > 0
+	}
+	return status
+}
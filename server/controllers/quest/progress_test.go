@@ -0,0 +1,180 @@
+package quest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"gorm.io/gorm"
+)
+
+func TestEvaluateProgress(t *testing.T) {
+	wagerQuest := models.Quest{
+		Kind:   models.QuestWagerAmount,
+		Game:   models.Crash,
+		Target: 100 * config.ONE_CHIP_WITH_DECIMALS,
+	}
+	if progress := evaluateProgress(
+		wagerQuest,
+		0,
+		QuestEvent{Type: models.Dreamtower, Bet: 10 * config.ONE_CHIP_WITH_DECIMALS},
+	); progress != 0 {
+		t.Fatalf("should not progress on other game: %d", progress)
+	}
+	if progress := evaluateProgress(
+		wagerQuest,
+		95*config.ONE_CHIP_WITH_DECIMALS,
+		QuestEvent{Type: models.Crash, Bet: 10 * config.ONE_CHIP_WITH_DECIMALS},
+	); progress != wagerQuest.Target {
+		t.Fatalf("progress should be capped by target: %d", progress)
+	}
+
+	cashOutQuest := models.Quest{
+		Kind:   models.QuestCashOutMultiplier,
+		Game:   models.Crash,
+		Target: 500,
+	}
+	if progress := evaluateProgress(
+		cashOutQuest,
+		0,
+		QuestEvent{Type: models.Crash, Profit: 1, Multiplier: 4.99},
+	); progress != 0 {
+		t.Fatalf("should not complete under target multiplier: %d", progress)
+	}
+	if progress := evaluateProgress(
+		cashOutQuest,
+		0,
+		QuestEvent{Type: models.Crash, Profit: 1, Multiplier: 5.01},
+	); progress != cashOutQuest.Target {
+		t.Fatalf("should complete over target multiplier: %d", progress)
+	}
+
+	streakQuest := models.Quest{
+		Kind:   models.QuestWinStreak,
+		Game:   models.Coinflip,
+		Target: 3,
+	}
+	progress := int64(0)
+	for _, profit := range []int64{1, 1, -1, 1, 1, 1} {
+		progress = evaluateProgress(
+			streakQuest,
+			progress,
+			QuestEvent{Type: models.Coinflip, Profit: profit},
+		)
+		if profit < 0 && progress != 0 {
+			t.Fatalf("streak should be reset on loss: %d", progress)
+		}
+	}
+	if progress != streakQuest.Target {
+		t.Fatalf("streak should reach target: %d", progress)
+	}
+
+	levelQuest := models.Quest{
+		Kind:   models.QuestReachLevel,
+		Game:   models.Dreamtower,
+		Target: 6,
+	}
+	if progress := evaluateProgress(
+		levelQuest,
+		0,
+		QuestEvent{Type: models.Dreamtower, Level: 5},
+	); progress != 0 {
+		t.Fatalf("should not complete under target level: %d", progress)
+	}
+	if progress := evaluateProgress(
+		levelQuest,
+		0,
+		QuestEvent{Type: models.Dreamtower, Level: 6},
+	); progress != levelQuest.Target {
+		t.Fatalf("should complete on target level: %d", progress)
+	}
+}
+
+func TestPeriodAndRotation(t *testing.T) {
+	wednesday := time.Date(2023, 5, 17, 15, 30, 0, 0, time.Local)
+	if daily := getPeriodStartedAt(
+		models.QuestDaily,
+		wednesday,
+	); !daily.Equal(time.Date(2023, 5, 17, 0, 0, 0, 0, time.Local)) {
+		t.Fatalf("invalid daily period start: %v", daily)
+	}
+	weekly := getPeriodStartedAt(models.QuestWeekly, wednesday)
+	if !weekly.Equal(time.Date(2023, 5, 15, 0, 0, 0, 0, time.Local)) {
+		t.Fatalf("invalid weekly period start: %v", weekly)
+	}
+	if end := getPeriodEndAt(
+		models.QuestWeekly,
+		weekly,
+	); !end.Equal(time.Date(2023, 5, 22, 0, 0, 0, 0, time.Local)) {
+		t.Fatalf("invalid weekly period end: %v", end)
+	}
+
+	pool := []models.Quest{}
+	for i := 1; i <= 10; i++ {
+		pool = append(pool, models.Quest{Model: gorm.Model{ID: uint(i)}})
+	}
+	first := pickActiveQuests(pool, weekly, 3)
+	second := pickActiveQuests(pool, weekly, 3)
+	if len(first) != 3 {
+		t.Fatalf("should pick 3 quests: %d", len(first))
+	}
+	for i := range first {
+		if first[i].ID != second[i].ID {
+			t.Fatalf("rotation should be deterministic for the period")
+		}
+		if i > 0 && first[i-1].ID >= first[i].ID {
+			t.Fatalf("rotated quests should be sorted by id")
+		}
+	}
+	if all := pickActiveQuests(pool[:2], weekly, 3); len(all) != 2 {
+		t.Fatalf("should pick whole pool when smaller than count: %d", len(all))
+	}
+}
+
+func TestBuildQuestFromRequest(t *testing.T) {
+	if quest, err := buildQuestFromRequest(QuestRequest{
+		Title:      "Cash out above 5x",
+		Kind:       models.QuestCashOutMultiplier,
+		Game:       models.Crash,
+		Period:     models.QuestDaily,
+		Target:     5,
+		RewardType: models.QuestRewardChip,
+		Reward:     1,
+	}); err != nil || quest.Target != 500 || quest.Reward != config.ONE_CHIP_WITH_DECIMALS {
+		t.Fatalf("failed to build cash out quest: %v, %v", quest, err)
+	}
+	if _, err := buildQuestFromRequest(QuestRequest{
+		Title:      "Reach level 6",
+		Kind:       models.QuestReachLevel,
+		Game:       models.Crash,
+		Period:     models.QuestWeekly,
+		Target:     6,
+		RewardType: models.QuestRewardCoupon,
+		Reward:     1,
+	}); err == nil {
+		t.Fatalf("reach-level quest should be only for dreamtower")
+	}
+}
+
+func TestQueueWagerWhenFull(t *testing.T) {
+	defer func(queue chan []QuestEvent) {
+		wagerQueue = queue
+	}(wagerQueue)
+	wagerQueue = make(chan []QuestEvent, 1)
+
+	done := make(chan struct{})
+	go func() {
+		QueueWager([]QuestEvent{{UserID: 1}})
+		QueueWager([]QuestEvent{{UserID: 2}})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("queueing to full queue should not block")
+	}
+	if events := <-wagerQueue; len(events) != 1 || events[0].UserID != 1 {
+		t.Fatalf("first events should be queued: %v", events)
+	}
+}
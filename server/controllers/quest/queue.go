@@ -0,0 +1,54 @@
+package quest
+
+import (
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/sirupsen/logrus"
+)
+
+/**
+* @Internal
+* Wager events waiting to be applied to quest progresses.
+* A single worker drains the queue so that events of a user are
+* applied in the order they happened, which streak quests rely on.
+ */
+var wagerQueue chan []QuestEvent
+
+/**
+* @Internal
+* Starts the worker applying queued wager events.
+ */
+func initWagerQueue() {
+	wagerQueue = make(chan []QuestEvent, config.QUEST_WAGER_QUEUE_SIZE)
+	go func() {
+		for events := range wagerQueue {
+			HandleWager(events)
+		}
+	}()
+}
+
+/**
+* @External
+* Queues wager events to update quest progresses without blocking
+* the game. Handled in place if the queue is not initialized.
+* Events are dropped and logged when the queue is full, as quest
+* progress must not hold up bets.
+ */
+func QueueWager(events []QuestEvent) {
+	if wagerQueue == nil {
+		HandleWager(events)
+		return
+	}
+	select {
+	case wagerQueue <- events:
+	default:
+		log.LogMessage(
+			"quest_QueueWager",
+			"wager queue is full, dropped events",
+			"error",
+			logrus.Fields{
+				"events": events,
+			},
+		)
+	}
+}
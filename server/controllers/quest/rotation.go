@@ -0,0 +1,135 @@
+package quest
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/models"
+)
+
+// activeQuests caches rotated quests per period so that picking
+// does not hit db on every wager.
+var activeQuests = map[models.QuestPeriod]activeQuestsCache{}
+var activeQuestsMutex sync.Mutex
+
+/**
+* @Internal
+* Returns the start time of the period which contains `now`.
+* Daily period starts at 00:00 and weekly period starts at Monday 00:00.
+ */
+func getPeriodStartedAt(
+	period models.QuestPeriod,
+	now time.Time,
+) time.Time {
+	today := time.Date(
+		now.Year(),
+		now.Month(),
+		now.Day(),
+		0, 0, 0, 0,
+		time.Local,
+	)
+	if period == models.QuestWeekly {
+		offset := (int(today.Weekday()) + 6) % 7
+		return today.AddDate(0, 0, -offset)
+	}
+	return today
+}
+
+/**
+* @Internal
+* Returns the end time of the period started at `startedAt`.
+ */
+func getPeriodEndAt(
+	period models.QuestPeriod,
+	startedAt time.Time,
+) time.Time {
+	if period == models.QuestWeekly {
+		return startedAt.AddDate(0, 0, 7)
+	}
+	return startedAt.AddDate(0, 0, 1)
+}
+
+/**
+* @Internal
+* Returns rotation count for the period.
+ */
+func getRotationCount(period models.QuestPeriod) uint {
+	if period == models.QuestWeekly {
+		return config.QUEST_WEEKLY_COUNT
+	}
+	return config.QUEST_DAILY_COUNT
+}
+
+/**
+* @Internal
+* Picks `count` quests from the pool for the period.
+* The pick is seeded by the period start time, so every server
+* instance and every restart results in the same rotation.
+ */
+func pickActiveQuests(
+	pool []models.Quest,
+	periodStartedAt time.Time,
+	count uint,
+) []models.Quest {
+	result := []models.Quest{}
+	if len(pool) <= int(count) {
+		result = append(result, pool...)
+	} else {
+		random := rand.New(rand.NewSource(periodStartedAt.Unix()))
+		for _, i := range random.Perm(len(pool))[:count] {
+			result = append(result, pool[i])
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+/**
+* @Internal
+* Returns rotated quests for the current period of the given type.
+* Enabled quests are retrieved from db once per period.
+ */
+func getActiveQuests(period models.QuestPeriod) []models.Quest {
+	periodStartedAt := getPeriodStartedAt(period, time.Now())
+
+	activeQuestsMutex.Lock()
+	defer activeQuestsMutex.Unlock()
+
+	if cached, prs := activeQuests[period]; prs &&
+		cached.periodStartedAt.Equal(periodStartedAt) {
+		return cached.quests
+	}
+
+	pool, err := getEnabledQuests(period)
+	if err != nil {
+		return nil
+	}
+
+	quests := pickActiveQuests(
+		pool,
+		periodStartedAt,
+		getRotationCount(period),
+	)
+	activeQuests[period] = activeQuestsCache{
+		periodStartedAt: periodStartedAt,
+		quests:          quests,
+	}
+	return quests
+}
+
+/**
+* @Internal
+* Clears rotated quests cache.
+* This function is called after quest definitions are updated by admin.
+ */
+func invalidateActiveQuests() {
+	activeQuestsMutex.Lock()
+	defer activeQuestsMutex.Unlock()
+
+	activeQuests = map[models.QuestPeriod]activeQuestsCache{}
+}
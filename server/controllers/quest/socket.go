@@ -0,0 +1,52 @@
+package quest
+
+import (
+	"encoding/json"
+
+	"github.com/Duelana-Team/duelana-v1/types"
+	"github.com/Duelana-Team/duelana-v1/utils"
+)
+
+var EventEmitter chan types.WSEvent
+
+/*
+* @Internal
+* Initialize socket event emitter.
+ */
+func initSocket(eventEmitter chan types.WSEvent) {
+	EventEmitter = eventEmitter
+}
+
+/**
+* @Internal
+* Sends quest progress websocket event to the user.
+ */
+func sendProgressEvent(userID uint, status QuestStatus) error {
+	if EventEmitter == nil {
+		return nil
+	}
+
+	eventType := "progress"
+	if status.Completed {
+		eventType = "completed"
+	}
+	b, err := json.Marshal(types.WSMessage{
+		Room:      "quest",
+		EventType: eventType,
+		Payload:   status,
+	})
+	if err != nil {
+		return utils.MakeError(
+			"quest_socket",
+			"sendProgressEvent",
+			"failed to marshal json",
+			err,
+		)
+	}
+
+	EventEmitter <- types.WSEvent{
+		Users:   []uint{userID},
+		Message: b,
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package quest
+
+import (
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/google/uuid"
+)
+
+/**
+* Game event which can drive quest progress.
+* `Multiplier` is the crash payout multiplier and `Level` is the
+* dreamtower level reached. Both are zero for unrelated games.
+ */
+type QuestEvent struct {
+	UserID     uint
+	Type       models.GameType
+	Bet        int64
+	Profit     int64
+	Multiplier float64
+	Level      uint
+}
+
+type QuestStatus struct {
+	ID          uint                   `json:"id"`
+	ProgressID  uint                   `json:"progressId"`
+	Title       string                 `json:"title"`
+	Description string                 `json:"description"`
+	Kind        models.QuestKind       `json:"kind"`
+	Game        models.GameType        `json:"game"`
+	Period      models.QuestPeriod     `json:"period"`
+	Target      int64                  `json:"target"`
+	Progress    int64                  `json:"progress"`
+	Completed   bool                   `json:"completed"`
+	Claimed     bool                   `json:"claimed"`
+	RewardType  models.QuestRewardType `json:"rewardType"`
+	Reward      int64                  `json:"reward"`
+	Remaining   uint                   `json:"remaining"`
+}
+
+type QuestClaimResult struct {
+	QuestID uint       `json:"questId"`
+	Chips   int64      `json:"chips"`
+	Coupon  *uuid.UUID `json:"coupon"`
+}
+
+type QuestRequest struct {
+	Title       string                 `json:"title"`
+	Description string                 `json:"description"`
+	Kind        models.QuestKind       `json:"kind"`
+	Game        models.GameType        `json:"game"`
+	Period      models.QuestPeriod     `json:"period"`
+	Target      float64                `json:"target"`
+	RewardType  models.QuestRewardType `json:"rewardType"`
+	Reward      int64                  `json:"reward"`
+	Enabled     bool                   `json:"enabled"`
+}
+
+type activeQuestsCache struct {
+	periodStartedAt time.Time
+	quests          []models.Quest
+}
+
+type questProgressUpdate struct {
+	quest    models.Quest
+	progress models.QuestProgress
+}
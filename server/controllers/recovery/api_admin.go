@@ -0,0 +1,25 @@
+package recovery
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+/**
+* Returns the last janitor report.
+* Pass `run=true` to sweep right away.
+ */
+func GetJanitorReportHandler(ctx *gin.Context) {
+	report := getLastReport()
+	if ctx.Query("run") == "true" {
+		report = sweep(time.Now())
+	}
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"report": report,
+		},
+	)
+}
@@ -0,0 +1,149 @@
+package recovery
+
+import (
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/controllers/coupon"
+	"github.com/Duelana-Team/duelana-v1/controllers/crash"
+	"github.com/Duelana-Team/duelana-v1/controllers/transaction"
+	"github.com/Duelana-Team/duelana-v1/controllers/transaction/db_aggregator"
+	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"github.com/sirupsen/logrus"
+)
+
+/**
+* @External
+* Closes state left by the previous process. Should be called
+* on boot, after transaction module and before games start.
+* 1. Confirms or declines pending game transactions.
+* 2. Refunds bets of crash rounds which never ended.
+ */
+func Run() (*Summary, error) {
+	bootedAt := time.Now()
+
+	// 1. Close every pending game transaction created before boot.
+	txCutoffs := map[models.TransactionType]time.Time{}
+	for _, txType := range getRecoverableTxTypes() {
+		txCutoffs[txType] = bootedAt
+	}
+	couponCutoffs := map[models.CouponTransactionType]time.Time{}
+	for couponTxType := range getCouponTxTypes() {
+		couponCutoffs[couponTxType] = bootedAt
+	}
+	summary, err := closePendingTxs("recovery", txCutoffs, couponCutoffs)
+	if err != nil {
+		return nil, utils.MakeError(
+			"recovery",
+			"Run",
+			"failed to close pending transactions",
+			err,
+		)
+	}
+
+	// 2. Refund abandoned crash rounds.
+	refunded, closed, err := crash.RefundAbandonedRounds()
+	summary.RefundedBets = refunded
+	summary.ClosedRounds = closed
+	if err != nil {
+		return summary, utils.MakeError(
+			"recovery",
+			"Run",
+			"failed to refund abandoned crash rounds",
+			err,
+		)
+	}
+
+	return summary, nil
+}
+
+/**
+* @Internal
+* Confirms or declines pending transactions and coupon transactions
+* created before cutoffs of their types, logging each as `caller`.
+ */
+func closePendingTxs(
+	caller string,
+	txCutoffs map[models.TransactionType]time.Time,
+	couponCutoffs map[models.CouponTransactionType]time.Time,
+) (*Summary, error) {
+	summary := Summary{}
+
+	// 1. Retrieve pending transactions.
+	txs, err := getPendingTxs(txCutoffs)
+	if err != nil {
+		return nil, err
+	}
+	couponTxs, err := getPendingCouponTxs(couponCutoffs)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Resolve and apply each group in id order.
+	for _, group := range [][]pendingTx{txs, couponTxs} {
+		resolutions, err := resolveAll(group, dbRecordFinder{})
+		if err != nil {
+			return nil, err
+		}
+		for i, tx := range group {
+			if err := apply(tx, resolutions[i]); err != nil {
+				summary.Failed++
+				log.LogMessage(
+					caller,
+					"failed to close pending transaction",
+					"error",
+					logrus.Fields{
+						"transaction": tx,
+						"resolution":  resolutions[i],
+						"error":       err.Error(),
+					},
+				)
+				continue
+			}
+			if resolutions[i].Decision == decisionConfirm {
+				summary.Confirmed++
+			} else {
+				summary.Declined++
+			}
+			observeResolution(caller, tx, resolutions[i])
+			log.LogMessage(
+				caller,
+				"closed pending transaction",
+				"success",
+				logrus.Fields{
+					"transaction": tx,
+					"resolution":  resolutions[i],
+				},
+			)
+		}
+	}
+
+	return &summary, nil
+}
+
+/**
+* @Internal
+* Confirms or declines `tx` as resolved.
+ */
+func apply(tx pendingTx, resolution resolution) error {
+	if tx.BalanceType == models.CouponBalanceForGame {
+		if resolution.Decision == decisionConfirm {
+			return coupon.Confirm(tx.ID)
+		}
+		return coupon.Decline(tx.ID)
+	}
+
+	if resolution.Decision == decisionConfirm {
+		return transaction.Confirm(transaction.ConfirmRequest{
+			Transaction: db_aggregator.Transaction(tx.ID),
+			OwnerID:     resolution.OwnerID,
+			OwnerType:   resolution.OwnerType,
+		})
+	}
+	return transaction.Decline(transaction.DeclineRequest{
+		Transaction: db_aggregator.Transaction(tx.ID),
+		OwnerID:     resolution.OwnerID,
+		OwnerType:   resolution.OwnerType,
+	})
+}
@@ -0,0 +1,328 @@
+package recovery
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/controllers/transaction/db_aggregator"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"gorm.io/gorm"
+)
+
+/**
+* @Internal
+* Retrieves pending transactions of each type created before
+* its cutoff, ordered by id.
+ */
+func getPendingTxs(cutoffs map[models.TransactionType]time.Time) ([]pendingTx, error) {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"recovery_db",
+			"getPendingTxs",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	txs := []pendingTx{}
+	for txType, cutoff := range cutoffs {
+		rows := []pendingTx{}
+		if err := session.Table(
+			"transactions t",
+		).Joins(
+			"left join wallets fw on fw.id = t.from_wallet",
+		).Joins(
+			"left join wallets tw on tw.id = t.to_wallet",
+		).Joins(
+			"left join balances b on b.owner_type = ? and b.owner_id = t.id",
+			models.InTransaction,
+		).Joins(
+			"left join chip_balances cb on cb.id = b.chip_balance_id",
+		).Where(
+			"t.deleted_at is null",
+		).Where(
+			"t.status = ?",
+			models.TransactionPending,
+		).Where(
+			"t.type = ? and t.created_at < ?",
+			txType, cutoff,
+		).Select(
+			"t.id as id, t.type as type, t.from_wallet as from_wallet, "+
+				"fw.user_id as from_user, tw.user_id as to_user, "+
+				"coalesce(cb.balance, 0) as amount, t.owner_id as owner_id, "+
+				"t.owner_type as owner_type, ? as balance_type, "+
+				"t.created_at as created_at",
+			models.ChipBalanceForGame,
+		).Scan(&rows).Error; err != nil {
+			return nil, utils.MakeError(
+				"recovery_db",
+				"getPendingTxs",
+				"failed to retrieve pending transactions",
+				fmt.Errorf("type: %s, err: %v", txType, err),
+			)
+		}
+		txs = append(txs, rows...)
+	}
+
+	sort.Slice(txs, func(i, j int) bool {
+		return txs[i].ID < txs[j].ID
+	})
+	return txs, nil
+}
+
+/**
+* @Internal
+* Coupon transaction types waiting for a game record,
+* mapped to real chip types of the same purpose.
+ */
+func getCouponTxTypes() map[models.CouponTransactionType]models.TransactionType {
+	return map[models.CouponTransactionType]models.TransactionType{
+		models.CpTxCoinflipBet:   models.TxCoinflipBet,
+		models.CpTxDreamtowerBet: models.TxDreamtowerBet,
+		models.CpTxCrashBet:      models.TxCrashBet,
+		models.CpTxCrashProfit:   models.TxCrashProfit,
+	}
+}
+
+/**
+* @Internal
+* Retrieves pending coupon transactions of each type created
+* before its cutoff, ordered by id.
+ */
+func getPendingCouponTxs(cutoffs map[models.CouponTransactionType]time.Time) ([]pendingTx, error) {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"recovery_db",
+			"getPendingCouponTxs",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	txTypes := getCouponTxTypes()
+	txs := []pendingTx{}
+	for couponTxType, cutoff := range cutoffs {
+		rows := []models.CouponTransaction{}
+		if err := session.Where(
+			"status = ?",
+			models.CouponTransactionPending,
+		).Where(
+			"type = ? and created_at < ?",
+			couponTxType, cutoff,
+		).Find(&rows).Error; err != nil {
+			return nil, utils.MakeError(
+				"recovery_db",
+				"getPendingCouponTxs",
+				"failed to retrieve pending coupon transactions",
+				fmt.Errorf("type: %s, err: %v", couponTxType, err),
+			)
+		}
+		for _, row := range rows {
+			userID := row.ClaimedUserID
+			txs = append(txs, pendingTx{
+				ID:          row.ID,
+				Type:        txTypes[couponTxType],
+				FromUser:    &userID,
+				ToUser:      &userID,
+				Amount:      row.TxBalance,
+				BalanceType: models.CouponBalanceForGame,
+				CreatedAt:   row.CreatedAt,
+			})
+		}
+	}
+
+	sort.Slice(txs, func(i, j int) bool {
+		return txs[i].ID < txs[j].ID
+	})
+	return txs, nil
+}
+
+// Looks up game records in main session.
+type dbRecordFinder struct{}
+
+/**
+* @Internal
+* Runs `query` for the first matching record id.
+ */
+func findFirstID(query func(session *gorm.DB) *gorm.DB) (uint, bool, error) {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return 0, false, err
+	}
+
+	ids := []uint{}
+	if err := query(session).Limit(1).Pluck("id", &ids).Error; err != nil {
+		return 0, false, err
+	}
+	if len(ids) == 0 {
+		return 0, false, nil
+	}
+	return ids[0], true, nil
+}
+
+/**
+* @Internal
+* Narrows `query` to records created around `tx`.
+ */
+func matchWindow(query *gorm.DB, column string, tx pendingTx) *gorm.DB {
+	return query.Where(
+		fmt.Sprintf("%s >= ? and %s < ?", column, column),
+		tx.CreatedAt, tx.CreatedAt.Add(config.RECOVERY_MATCH_WINDOW),
+	)
+}
+
+/**
+* @Internal
+* Narrows `query` to the round owning `tx`, if recorded.
+ */
+func matchOwner(query *gorm.DB, column string, tx pendingTx, ownerType models.TransactionOwnerType) *gorm.DB {
+	if tx.OwnerType != ownerType || tx.OwnerID == 0 {
+		return query
+	}
+	return query.Where(
+		fmt.Sprintf("%s = ?", column),
+		tx.OwnerID,
+	)
+}
+
+/**
+* @Internal
+* Excludes records already paid by a succeed real chip transaction
+* of the same type and payer created after `tx`.
+* `ownerColumn` is the owner id column of the record.
+ */
+func notPaidLater(query *gorm.DB, ownerColumn string, tx pendingTx, ownerType models.TransactionOwnerType) *gorm.DB {
+	if tx.BalanceType != models.ChipBalanceForGame || tx.FromWallet == nil {
+		return query
+	}
+	return query.Where(
+		fmt.Sprintf(
+			"not exists (select 1 from transactions p where p.deleted_at is null"+
+				" and p.status = ? and p.type = ? and p.from_wallet = ?"+
+				" and p.owner_type = ? and p.owner_id = %s and p.created_at >= ?)",
+			ownerColumn,
+		),
+		models.TransactionSucceed, tx.Type, *tx.FromWallet,
+		ownerType, tx.CreatedAt,
+	)
+}
+
+func (finder dbRecordFinder) findCrashBet(tx pendingTx) (uint, bool, error) {
+	if tx.FromUser == nil {
+		return 0, false, nil
+	}
+	return findFirstID(func(session *gorm.DB) *gorm.DB {
+		query := session.Model(
+			&models.CrashBet{},
+		).Where(
+			"user_id = ? and bet_amount = ? and paid_balance_type = ?",
+			*tx.FromUser, tx.Amount, tx.BalanceType,
+		)
+		query = matchWindow(query, "created_at", tx)
+		query = matchOwner(query, "round_id", tx, models.TransactionCrashRoundReferenced)
+		if tx.BalanceType == models.ChipBalanceForGame {
+			query = query.Where(
+				"not exists (select 1 from transactions p where p.deleted_at is null"+
+					" and p.status = ? and p.owner_type = ? and p.owner_id = crash_bets.id)",
+				models.TransactionSucceed, models.TransactionCrashBetReferencedForCashIn,
+			)
+		}
+		return query.Order("id")
+	})
+}
+
+func (finder dbRecordFinder) findCrashPayout(tx pendingTx) (uint, bool, error) {
+	if tx.ToUser == nil {
+		return 0, false, nil
+	}
+	return findFirstID(func(session *gorm.DB) *gorm.DB {
+		query := session.Model(
+			&models.CrashBet{},
+		).Where(
+			"user_id = ? and profit = ? and paid_balance_type = ?",
+			*tx.ToUser, tx.Amount, tx.BalanceType,
+		)
+		query = matchWindow(query, "updated_at", tx)
+		if tx.BalanceType == models.ChipBalanceForGame {
+			query = query.Where(
+				"not exists (select 1 from transactions p where p.deleted_at is null"+
+					" and p.status = ? and p.owner_type = ? and p.owner_id = crash_bets.id)",
+				models.TransactionSucceed, models.TransactionCrashBetReferencedForCashOut,
+			)
+		}
+		return query.Order("id")
+	})
+}
+
+func (finder dbRecordFinder) findCoinflipRound(tx pendingTx) (uint, bool, error) {
+	if tx.FromUser == nil {
+		return 0, false, nil
+	}
+	return findFirstID(func(session *gorm.DB) *gorm.DB {
+		query := session.Model(
+			&models.CoinflipRound{},
+		).Where(
+			"(heads_user_id = ? or tails_user_id = ?) and amount = ? and paid_balance_type = ?",
+			*tx.FromUser, *tx.FromUser, tx.Amount, tx.BalanceType,
+		)
+		query = matchWindow(query, "updated_at", tx)
+		query = matchOwner(query, "id", tx, models.TransactionCoinflipReferenced)
+		query = notPaidLater(query, "coinflip_rounds.id", tx, models.TransactionCoinflipReferenced)
+		return query.Order("id")
+	})
+}
+
+func (finder dbRecordFinder) findJackpotRound(tx pendingTx) (uint, bool, error) {
+	if tx.FromUser == nil {
+		return 0, false, nil
+	}
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return 0, false, err
+	}
+
+	query := session.Table(
+		"jackpot_bets b",
+	).Joins(
+		"join jackpot_players pl on pl.id = b.player_id",
+	).Where(
+		"b.deleted_at is null and pl.user_id = ? and b.usd_amount = ?",
+		*tx.FromUser, tx.Amount,
+	)
+	query = matchWindow(query, "b.created_at", tx)
+	query = matchOwner(query, "pl.round_id", tx, models.TransactionJackpotReferenced)
+	query = notPaidLater(query, "pl.round_id", tx, models.TransactionJackpotReferenced)
+
+	roundIDs := []uint{}
+	if err := query.Order(
+		"b.id",
+	).Limit(1).Pluck("pl.round_id", &roundIDs).Error; err != nil {
+		return 0, false, err
+	}
+	if len(roundIDs) == 0 {
+		return 0, false, nil
+	}
+	return roundIDs[0], true, nil
+}
+
+func (finder dbRecordFinder) findDreamtowerRound(tx pendingTx) (uint, bool, error) {
+	if tx.FromUser == nil {
+		return 0, false, nil
+	}
+	return findFirstID(func(session *gorm.DB) *gorm.DB {
+		query := session.Model(
+			&models.DreamTowerRound{},
+		).Where(
+			"user_id = ? and bet_amount = ? and paid_balance_type = ?",
+			*tx.FromUser, tx.Amount, tx.BalanceType,
+		)
+		query = matchWindow(query, "created_at", tx)
+		query = notPaidLater(query, fmt.Sprint(*tx.FromUser), tx, models.TransactionUserReferenced)
+		return query.Order("id")
+	})
+}
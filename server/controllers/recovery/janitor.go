@@ -0,0 +1,124 @@
+package recovery
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	cron "github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+)
+
+// Guards sweeps and the last report.
+var janitorMutex sync.Mutex
+var lastReport *JanitorReport
+
+/**
+* @External
+* Schedules the janitor closing pending transactions
+* older than their per type TTL.
+ */
+func StartJanitor() error {
+	if err := registerMetrics(); err != nil {
+		log.LogMessage(
+			"pending_tx_janitor",
+			"failed to register metrics",
+			"error",
+			logrus.Fields{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	c := cron.New(cron.WithLocation(time.UTC))
+	if _, err := c.AddFunc(
+		config.PENDING_TX_JANITOR_SCHEDULE,
+		func() { sweep(time.Now()) },
+	); err != nil {
+		return utils.MakeError(
+			"pending_tx_janitor",
+			"StartJanitor",
+			"failed to schedule janitor",
+			err,
+		)
+	}
+	c.Start()
+	return nil
+}
+
+/**
+* @Internal
+* Closes pending transactions older than their TTL at `now`.
+ */
+func sweep(now time.Time) *JanitorReport {
+	janitorMutex.Lock()
+	defer janitorMutex.Unlock()
+
+	report := JanitorReport{StartedAt: now}
+	summary, err := closePendingTxs(
+		"pending_tx_janitor",
+		getTxCutoffs(config.PENDING_TX_TTL, now),
+		getCouponTxCutoffs(config.PENDING_COUPON_TX_TTL, now),
+	)
+	report.FinishedAt = time.Now()
+	if err != nil {
+		report.Error = err.Error()
+		log.LogMessage(
+			"pending_tx_janitor",
+			"failed to sweep pending transactions",
+			"error",
+			logrus.Fields{
+				"error": err.Error(),
+			},
+		)
+	} else {
+		report.Summary = *summary
+		level := "info"
+		if summary.Failed > 0 {
+			level = "error"
+		}
+		log.LogMessage(
+			"pending_tx_janitor",
+			"swept pending transactions",
+			level,
+			logrus.Fields{
+				"summary": summary,
+			},
+		)
+	}
+
+	lastReport = &report
+	return &report
+}
+
+/**
+* @Internal
+* Returns the last sweep report, nil before the first sweep.
+ */
+func getLastReport() *JanitorReport {
+	janitorMutex.Lock()
+	defer janitorMutex.Unlock()
+	return lastReport
+}
+
+func getTxCutoffs(ttls map[models.TransactionType]time.Duration, now time.Time) map[models.TransactionType]time.Time {
+	cutoffs := map[models.TransactionType]time.Time{}
+	for txType, ttl := range ttls {
+		cutoffs[txType] = now.Add(-ttl)
+	}
+	return cutoffs
+}
+
+func getCouponTxCutoffs(ttls map[models.CouponTransactionType]time.Duration, now time.Time) map[models.CouponTransactionType]time.Time {
+	cutoffs := map[models.CouponTransactionType]time.Time{}
+	for couponTxType, ttl := range ttls {
+		if _, ok := getCouponTxTypes()[couponTxType]; !ok {
+			continue
+		}
+		cutoffs[couponTxType] = now.Add(-ttl)
+	}
+	return cutoffs
+}
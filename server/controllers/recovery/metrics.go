@@ -0,0 +1,29 @@
+package recovery
+
+import (
+	"github.com/Duelana-Team/duelana-v1/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Pending transactions closed by boot recovery and janitor.
+var closedTransactions = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: metrics.NAMESPACE,
+		Subsystem: "recovery",
+		Name:      "closed_transactions_total",
+		Help:      "Stale pending transactions confirmed or declined.",
+	},
+	[]string{"caller", "type", "decision"},
+)
+
+func registerMetrics() error {
+	return metrics.Register(closedTransactions)
+}
+
+func observeResolution(caller string, tx pendingTx, resolution resolution) {
+	closedTransactions.WithLabelValues(
+		caller,
+		string(tx.Type)+"/"+string(tx.BalanceType),
+		string(resolution.Decision),
+	).Inc()
+}
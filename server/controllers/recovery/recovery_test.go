@@ -0,0 +1,112 @@
+package recovery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/models"
+)
+
+// Finds records for transactions listed in `found`.
+type fakeFinder struct {
+	found map[uint]uint
+}
+
+func (finder fakeFinder) find(tx pendingTx) (uint, bool, error) {
+	ownerID, ok := finder.found[tx.ID]
+	return ownerID, ok, nil
+}
+
+func (finder fakeFinder) findCrashBet(tx pendingTx) (uint, bool, error) {
+	return finder.find(tx)
+}
+
+func (finder fakeFinder) findCrashPayout(tx pendingTx) (uint, bool, error) {
+	return finder.find(tx)
+}
+
+func (finder fakeFinder) findCoinflipRound(tx pendingTx) (uint, bool, error) {
+	return finder.find(tx)
+}
+
+func (finder fakeFinder) findJackpotRound(tx pendingTx) (uint, bool, error) {
+	return finder.find(tx)
+}
+
+func (finder fakeFinder) findDreamtowerRound(tx pendingTx) (uint, bool, error) {
+	return finder.find(tx)
+}
+
+func TestResolveAll(t *testing.T) {
+	user := uint(7)
+	temp := uint(1008)
+	wallet := uint(70)
+	txs := []pendingTx{
+		{ID: 1, Type: models.TxCrashBet, FromUser: &user, FromWallet: &wallet},
+		{ID: 2, Type: models.TxCoinflipBet, FromUser: &user, FromWallet: &wallet},
+		{ID: 3, Type: models.TxDreamtowerBet, FromUser: &user, FromWallet: &wallet},
+		{ID: 4, Type: models.TxDreamtowerFee, FromUser: &temp},
+		{ID: 5, Type: models.TxDreamtowerFee, FromUser: &temp},
+		{ID: 6, Type: models.TxCrashProfit, FromUser: &temp, ToUser: &user},
+	}
+	finder := fakeFinder{found: map[uint]uint{1: 11, 3: 33}}
+
+	resolutions, err := resolveAll(txs, finder)
+	if err != nil {
+		t.Fatalf("failed to resolve: %v", err)
+	}
+
+	expected := []resolution{
+		{Decision: decisionConfirm, OwnerID: 11, OwnerType: models.TransactionCrashBetReferencedForCashIn},
+		{Decision: decisionDecline, OwnerID: user, OwnerType: models.TransactionUserReferenced},
+		{Decision: decisionConfirm, OwnerID: user, OwnerType: models.TransactionUserReferenced},
+		{Decision: decisionConfirm, OwnerID: user, OwnerType: models.TransactionUserReferenced},
+		{Decision: decisionDecline, OwnerID: temp, OwnerType: models.TransactionUserReferenced},
+		{Decision: decisionDecline, OwnerID: user, OwnerType: models.TransactionUserReferenced},
+	}
+	for i, want := range expected {
+		got := resolutions[i]
+		if got.Decision != want.Decision ||
+			got.OwnerID != want.OwnerID ||
+			got.OwnerType != want.OwnerType {
+			t.Fatalf("tx %d: expected %v, got %v", txs[i].ID, want, got)
+		}
+	}
+}
+
+func TestDeclineWithoutUser(t *testing.T) {
+	wallet := uint(70)
+	result := decline(pendingTx{ID: 1, Type: models.TxJackpotBet, FromWallet: &wallet}, "test")
+	if result.OwnerID != wallet ||
+		result.OwnerType != models.TransactionWalletReferenced {
+		t.Fatalf("should reference paying wallet: %v", result)
+	}
+}
+
+func TestJanitorCutoffs(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	txCutoffs := getTxCutoffs(
+		map[models.TransactionType]time.Duration{
+			models.TxCrashBet: 10 * time.Minute,
+		},
+		now,
+	)
+	if !txCutoffs[models.TxCrashBet].Equal(now.Add(-10 * time.Minute)) {
+		t.Fatalf("unexpected cutoff: %v", txCutoffs[models.TxCrashBet])
+	}
+
+	couponCutoffs := getCouponTxCutoffs(
+		map[models.CouponTransactionType]time.Duration{
+			models.CpTxCrashBet:    time.Minute,
+			models.CpTxCrashRefund: time.Minute,
+		},
+		now,
+	)
+	if _, ok := couponCutoffs[models.CpTxCrashRefund]; ok {
+		t.Fatal("unmapped coupon type should be skipped")
+	}
+	if !couponCutoffs[models.CpTxCrashBet].Equal(now.Add(-time.Minute)) {
+		t.Fatalf("unexpected coupon cutoff: %v", couponCutoffs[models.CpTxCrashBet])
+	}
+}
@@ -0,0 +1,127 @@
+package recovery
+
+import (
+	"github.com/Duelana-Team/duelana-v1/models"
+)
+
+/**
+* @Internal
+* Transaction types left pending by game handlers between
+* debiting the player and committing the game record.
+ */
+func getRecoverableTxTypes() []models.TransactionType {
+	return []models.TransactionType{
+		models.TxCrashBet,
+		models.TxCrashProfit,
+		models.TxCoinflipBet,
+		models.TxJackpotBet,
+		models.TxGrandJackpotBet,
+		models.TxDreamtowerBet,
+		models.TxDreamtowerFee,
+	}
+}
+
+/**
+* @Internal
+* Decides how to close pending transactions, ordered by id.
+* A transaction is confirmed if the game record it paid for
+* was committed, otherwise declined so the payer is refunded.
+* Dreamtower fees follow the bet transferred right before them.
+ */
+func resolveAll(txs []pendingTx, finder recordFinder) ([]resolution, error) {
+	resolutions := make([]resolution, 0, len(txs))
+	var lastDreamtowerBet *resolution
+	for _, tx := range txs {
+		if tx.Type == models.TxDreamtowerFee {
+			if lastDreamtowerBet == nil {
+				resolutions = append(resolutions, decline(tx, "no dreamtower bet for fee"))
+				continue
+			}
+			fee := *lastDreamtowerBet
+			fee.Reason = "follows dreamtower bet"
+			resolutions = append(resolutions, fee)
+			lastDreamtowerBet = nil
+			continue
+		}
+
+		resolution, err := resolve(tx, finder)
+		if err != nil {
+			return nil, err
+		}
+		if tx.Type == models.TxDreamtowerBet {
+			lastDreamtowerBet = &resolution
+		}
+		resolutions = append(resolutions, resolution)
+	}
+	return resolutions, nil
+}
+
+/**
+* @Internal
+* Decides how to close a single pending transaction.
+ */
+func resolve(tx pendingTx, finder recordFinder) (resolution, error) {
+	var find func(pendingTx) (uint, bool, error)
+	var ownerType models.TransactionOwnerType
+	switch tx.Type {
+	case models.TxCrashBet:
+		find = finder.findCrashBet
+		ownerType = models.TransactionCrashBetReferencedForCashIn
+	case models.TxCrashProfit:
+		find = finder.findCrashPayout
+		ownerType = models.TransactionCrashBetReferencedForCashOut
+	case models.TxCoinflipBet:
+		find = finder.findCoinflipRound
+		ownerType = models.TransactionCoinflipReferenced
+	case models.TxJackpotBet, models.TxGrandJackpotBet:
+		find = finder.findJackpotRound
+		ownerType = models.TransactionJackpotReferenced
+	case models.TxDreamtowerBet:
+		find = finder.findDreamtowerRound
+		ownerType = models.TransactionUserReferenced
+	default:
+		return decline(tx, "not a recoverable type"), nil
+	}
+
+	ownerID, found, err := find(tx)
+	if err != nil {
+		return resolution{}, err
+	}
+	if !found {
+		return decline(tx, "game record not committed"), nil
+	}
+	// Dreamtower bets are owned by the player, same as the game does.
+	if ownerType == models.TransactionUserReferenced &&
+		tx.FromUser != nil {
+		ownerID = *tx.FromUser
+	}
+	return resolution{
+		Decision:  decisionConfirm,
+		OwnerID:   ownerID,
+		OwnerType: ownerType,
+		Reason:    "game record committed",
+	}, nil
+}
+
+/**
+* @Internal
+* Declines refunding the payer, referencing the player
+* if known, otherwise the paying wallet.
+ */
+func decline(tx pendingTx, reason string) resolution {
+	result := resolution{
+		Decision: decisionDecline,
+		Reason:   reason,
+	}
+	if tx.Type == models.TxCrashProfit && tx.ToUser != nil {
+		result.OwnerID = *tx.ToUser
+		result.OwnerType = models.TransactionUserReferenced
+	} else if tx.FromUser != nil {
+		result.OwnerID = *tx.FromUser
+		result.OwnerType = models.TransactionUserReferenced
+	} else if tx.FromWallet != nil {
+		result.OwnerID = *tx.FromWallet
+		result.OwnerType = models.TransactionWalletReferenced
+	}
+	return result
+}
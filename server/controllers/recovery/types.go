@@ -0,0 +1,63 @@
+package recovery
+
+import (
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/models"
+)
+
+type decision string
+
+const (
+	decisionConfirm decision = "confirm"
+	decisionDecline decision = "decline"
+)
+
+// Pending transaction left by an interrupted game handler.
+// Coupon transactions are mapped to the real chip type of the
+// same purpose with `BalanceType` of coupon.
+type pendingTx struct {
+	ID          uint
+	Type        models.TransactionType
+	FromWallet  *uint
+	FromUser    *uint
+	ToUser      *uint
+	Amount      int64
+	OwnerID     uint
+	OwnerType   models.TransactionOwnerType
+	BalanceType models.PaidBalanceForGame
+	CreatedAt   time.Time
+}
+
+// How a pending transaction is closed.
+type resolution struct {
+	Decision  decision
+	OwnerID   uint
+	OwnerType models.TransactionOwnerType
+	Reason    string
+}
+
+// Finds game records committed by interrupted handlers.
+// Each returns id of the record owning `tx` and whether it is found.
+type recordFinder interface {
+	findCrashBet(tx pendingTx) (uint, bool, error)
+	findCrashPayout(tx pendingTx) (uint, bool, error)
+	findCoinflipRound(tx pendingTx) (uint, bool, error)
+	findJackpotRound(tx pendingTx) (uint, bool, error)
+	findDreamtowerRound(tx pendingTx) (uint, bool, error)
+}
+
+type Summary struct {
+	Confirmed    int `json:"confirmed"`
+	Declined     int `json:"declined"`
+	Failed       int `json:"failed"`
+	RefundedBets int `json:"refundedBets"`
+	ClosedRounds int `json:"closedRounds"`
+}
+
+type JanitorReport struct {
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+	Summary    Summary   `json:"summary"`
+	Error      string    `json:"error,omitempty"`
+}
@@ -36,9 +36,23 @@ func treasuryKeyPair() *solana.PrivateKey {
 	return &treasuryKeyPair
 }
 
+// Treasury public key replacing the one of `_treasuryBs58` in
+// decoding, so that recorded transactions of other wallets can be
+// decoded as treasury ones in tests.
+var _treasuryPubKeyOverride *solana.PublicKey
+
+// @Internal
+// Setter for `_treasuryPubKeyOverride`, nil to reset.
+func setTreasuryPubKeyOverride(pubKey *solana.PublicKey) {
+	_treasuryPubKeyOverride = pubKey
+}
+
 // @Internal
 // Get PublicKey from `_treasuryBs58`.
 func treasuryPubKey() *solana.PublicKey {
+	if _treasuryPubKeyOverride != nil {
+		return _treasuryPubKeyOverride
+	}
 	treasuryKeyPair := treasuryKeyPair()
 	treasuryPubKey := treasuryKeyPair.PublicKey()
 	return &treasuryPubKey
@@ -122,62 +136,51 @@ func newClient() *rpc.Client {
 }
 
 // @Internal
-// Supported spl tokens.
-var supportedSplTokens = []SplTokenMeta{}
-
-// @Internal
-// Initialize supported spl tokens.
-func initializeSplTokens() {
-	supportedSplTokens = []SplTokenMeta{
+// Spl tokens supported before the token registry, used until
+// the registry is loaded.
+func defaultSplTokens() []SplTokenMeta {
+	tokens := []SplTokenMeta{
 		{
-			Type:                 SolSplToken,
-			MintAddress:          solana.MustPublicKeyFromBase58(config.SOL_SPL_ADDRESS),
-			TreasuryTokenAccount: *treasuryPubKey(),
-			Decimals:             9,
-			Keyword:              "SOL",
-			Image:                "https://duelana-bucket-prod.s3.us-east-2.amazonaws.com/coins/SOL.png",
+			MintAddress: solana.MustPublicKeyFromBase58(config.SOL_SPL_ADDRESS),
+			Decimals:    9,
+			Keyword:     "SOL",
+			Image:       "https://duelana-bucket-prod.s3.us-east-2.amazonaws.com/coins/SOL.png",
 		},
 		{
-			Type:                 BonkSplToken,
-			MintAddress:          solana.MustPublicKeyFromBase58(config.BONK_SPL_ADDRESS),
-			TreasuryTokenAccount: treasuryTokenAccount(solana.MustPublicKeyFromBase58(config.BONK_SPL_ADDRESS)),
-			Decimals:             5,
-			Keyword:              "Bonk",
-			Image:                "https://duelana-bucket-prod.s3.us-east-2.amazonaws.com/coins/Bonk.png",
+			MintAddress: solana.MustPublicKeyFromBase58(config.BONK_SPL_ADDRESS),
+			Decimals:    5,
+			Keyword:     "Bonk",
+			Image:       "https://duelana-bucket-prod.s3.us-east-2.amazonaws.com/coins/Bonk.png",
+			WithdrawFee: config.WITHDRAW_FEE_PER_SPL,
 		},
 		{
-			Type:                 UsdcSplToken,
-			MintAddress:          solana.MustPublicKeyFromBase58(config.USDC_SPL_ADDRESS),
-			TreasuryTokenAccount: treasuryTokenAccount(solana.MustPublicKeyFromBase58(config.USDC_SPL_ADDRESS)),
-			Decimals:             6,
-			Keyword:              "USDC",
-			Image:                "https://duelana-bucket-prod.s3.us-east-2.amazonaws.com/coins/USDC.png",
+			MintAddress: solana.MustPublicKeyFromBase58(config.USDC_SPL_ADDRESS),
+			Decimals:    6,
+			Keyword:     "USDC",
+			Image:       "https://duelana-bucket-prod.s3.us-east-2.amazonaws.com/coins/USDC.png",
+			WithdrawFee: config.WITHDRAW_FEE_PER_SPL,
 		},
 		{
-			Type:                 BokuSplToken,
-			MintAddress:          solana.MustPublicKeyFromBase58(config.BOKU_SPL_ADDRESS),
-			TreasuryTokenAccount: treasuryTokenAccount(solana.MustPublicKeyFromBase58(config.BOKU_SPL_ADDRESS)),
-			Decimals:             9,
-			Keyword:              "BOKU",
-			Image:                "https://duelana-bucket-prod.s3.us-east-2.amazonaws.com/coins/BOKU.png",
+			MintAddress: solana.MustPublicKeyFromBase58(config.BOKU_SPL_ADDRESS),
+			Decimals:    9,
+			Keyword:     "BOKU",
+			Image:       "https://duelana-bucket-prod.s3.us-east-2.amazonaws.com/coins/BOKU.png",
+			WithdrawFee: config.WITHDRAW_FEE_PER_SPL,
 		},
 	}
+	for i := range tokens {
+		tokens[i].DepositEnabled = true
+		tokens[i].WithdrawEnabled = true
+		tokens[i].MinWithdraw = config.WITHDRAW_MIN_LIMIT
+		tokens[i].PriceSource = config.SPL_TOKEN_DEFAULT_PRICE_SOURCE
+	}
+	return completeSplTokenMetas(tokens)
 }
 
 // @External
 // Returns whether spl token meta is for sol or spl.
 func isSolSplMeta(meta SplTokenMeta) bool {
-	if meta.Keyword == "SOL" &&
-		meta.MintAddress == solana.MustPublicKeyFromBase58(config.SOL_SPL_ADDRESS) {
-		return true
-	}
-	return false
-}
-
-// @External
-// Retrieve supported spl tokens.
-func supportedSpls() []SplTokenMeta {
-	return supportedSplTokens
+	return meta.MintAddress == solana.MustPublicKeyFromBase58(config.SOL_SPL_ADDRESS)
 }
 
 // @External
@@ -201,3 +204,9 @@ func initialize(param *InitParam) error {
 func makeError(category string, reason string, err error) error {
 	return utils.MakeError("solana-aggregator", category, reason, err)
 }
+
+// @Internal
+// Makes an error object with an error code to be matched by callers.
+func makeErrorWithCode(category string, reason string, code string, err error) error {
+	return utils.MakeErrorWithCode("solana-aggregator", category, reason, code, err)
+}
@@ -0,0 +1,5 @@
+package solana
+
+// Error code range: #116xxx
+const ErrCodeBase = "#116"
+const ErrCodeRpcFailure = ErrCodeBase + "000"
@@ -1,6 +1,9 @@
 package solana
 
-import "github.com/gagliardetto/solana-go/rpc"
+import (
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
 
 func Initialize(param *InitParam) error {
 	return initialize(param)
@@ -33,3 +36,43 @@ func GetTransactionResult(txHashBs58 string) (*rpc.GetTransactionResult, error)
 func IsSolSplMeta(meta SplTokenMeta) bool {
 	return isSolSplMeta(meta)
 }
+
+func SetSplTokenLoader(loader func() ([]SplTokenMeta, error)) {
+	setSplTokenLoader(loader)
+}
+
+func InvalidateSplTokens() {
+	invalidateSplTokens()
+}
+
+func GetSignaturesForAddress(address solana.PublicKey, before string, until string, limit int) ([]*rpc.TransactionSignature, error) {
+	return getSignaturesForAddress(address, before, until, limit)
+}
+
+func TreasuryWatchAddresses() []solana.PublicKey {
+	return treasuryWatchAddresses()
+}
+
+func SignWithdrawal(param *WithdrawalRequest, attempt uint) (*SignedWithdrawal, error) {
+	return signWithdrawal(param, attempt)
+}
+
+func BroadcastTx(tx *solana.Transaction) error {
+	return broadcastTx(tx)
+}
+
+func GetSignatureStatus(signature string) (*rpc.SignatureStatusesResult, error) {
+	return getSignatureStatus(signature)
+}
+
+func GetFinalizedBlockHeight() (uint64, error) {
+	return getFinalizedBlockHeight()
+}
+
+func TreasuryPubKey() solana.PublicKey {
+	return *treasuryPubKey()
+}
+
+func GetTokenHoldings(owner solana.PublicKey) ([]TokenHolding, error) {
+	return getTokenHoldings(owner)
+}
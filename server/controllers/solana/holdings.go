@@ -0,0 +1,85 @@
+package solana
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/metrics"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// Byte range of amount in token accounts of both token programs.
+const (
+	TOKEN_ACCOUNT_AMOUNT_OFFSET = 64
+	TOKEN_ACCOUNT_AMOUNT_END    = 72
+)
+
+// @Internal
+// Get balances of supported spl tokens held by owner in its
+// associated token accounts, lamports for sol, in a single call.
+func getTokenHoldings(owner solana.PublicKey) ([]TokenHolding, error) {
+	tokens := supportedSpls()
+	accounts := make([]solana.PublicKey, len(tokens))
+	for i, token := range tokens {
+		if isSolSplMeta(token) {
+			accounts[i] = owner
+		} else {
+			accounts[i] = associatedTokenAccount(owner, token.MintAddress, token.TokenProgram)
+		}
+	}
+
+	client := newClient()
+	start := time.Now()
+	out, err := client.GetMultipleAccountsWithOpts(
+		context.TODO(),
+		accounts,
+		&rpc.GetMultipleAccountsOpts{Commitment: rpc.CommitmentFinalized},
+	)
+	metrics.ObserveSolanaRpc("getMultipleAccounts", start, err)
+	if err != nil {
+		return nil, makeError("getTokenHoldings", "failed to get token accounts", err)
+	}
+
+	return tokenHoldingsFromAccounts(tokens, out.Value)
+}
+
+// @Internal
+// Reads balances of tokens from their accounts. Accounts which
+// do not exist hold nothing.
+func tokenHoldingsFromAccounts(tokens []SplTokenMeta, accounts []*rpc.Account) ([]TokenHolding, error) {
+	if len(tokens) != len(accounts) {
+		return nil, makeError(
+			"tokenHoldingsFromAccounts",
+			"mismatching accounts",
+			fmt.Errorf("tokens: %d, accounts: %d", len(tokens), len(accounts)),
+		)
+	}
+
+	holdings := make([]TokenHolding, len(tokens))
+	for i, token := range tokens {
+		holdings[i] = TokenHolding{Token: token}
+		account := accounts[i]
+		if account == nil {
+			continue
+		}
+		if isSolSplMeta(token) {
+			holdings[i].Amount = account.Lamports
+			continue
+		}
+		data := account.Data.GetBinary()
+		if len(data) < TOKEN_ACCOUNT_AMOUNT_END {
+			return nil, makeError(
+				"tokenHoldingsFromAccounts",
+				"invalid token account",
+				fmt.Errorf("%s: %d bytes", token.Keyword, len(data)),
+			)
+		}
+		holdings[i].Amount = binary.LittleEndian.Uint64(
+			data[TOKEN_ACCOUNT_AMOUNT_OFFSET:TOKEN_ACCOUNT_AMOUNT_END],
+		)
+	}
+	return holdings, nil
+}
@@ -0,0 +1,43 @@
+package solana
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestTokenHoldingsFromAccounts(t *testing.T) {
+	tokens := []SplTokenMeta{
+		{MintAddress: solana.MustPublicKeyFromBase58(config.SOL_SPL_ADDRESS), Keyword: "SOL"},
+		{MintAddress: solana.MustPublicKeyFromBase58(config.USDC_SPL_ADDRESS), Keyword: "USDC"},
+		{MintAddress: solana.MustPublicKeyFromBase58(config.BONK_SPL_ADDRESS), Keyword: "Bonk"},
+	}
+	tokenAccount := make([]byte, 165)
+	binary.LittleEndian.PutUint64(tokenAccount[TOKEN_ACCOUNT_AMOUNT_OFFSET:], 42500000)
+
+	holdings, err := tokenHoldingsFromAccounts(tokens, []*rpc.Account{
+		{Lamports: 3000000000},
+		{Lamports: 2039280, Data: rpc.DataBytesOrJSONFromBytes(tokenAccount)},
+		nil,
+	})
+	if err != nil {
+		t.Fatalf("failed to read holdings: %v", err)
+	}
+	for i, expected := range []uint64{3000000000, 42500000, 0} {
+		if holdings[i].Amount != expected || holdings[i].Token.Keyword != tokens[i].Keyword {
+			t.Fatalf("unexpected holding of %s: %+v", tokens[i].Keyword, holdings[i])
+		}
+	}
+
+	if _, err := tokenHoldingsFromAccounts(tokens, []*rpc.Account{nil}); err == nil {
+		t.Fatal("mismatching accounts should fail")
+	}
+	if _, err := tokenHoldingsFromAccounts(tokens[1:2], []*rpc.Account{
+		{Data: rpc.DataBytesOrJSONFromBytes(tokenAccount[:10])},
+	}); err == nil {
+		t.Fatal("truncated token account should fail")
+	}
+}
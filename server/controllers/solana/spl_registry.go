@@ -0,0 +1,122 @@
+package solana
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/gagliardetto/solana-go"
+)
+
+// @Internal
+// Supported spl tokens, cached from the token registry.
+var supportedSplTokens = []SplTokenMeta{}
+var splTokensLoadedAt time.Time
+var splTokensMutex sync.RWMutex
+
+// @Internal
+// Loads spl tokens from the token registry. Set by `SetSplTokenLoader`,
+// defaults are kept while unset.
+var splTokenLoader func() ([]SplTokenMeta, error)
+
+// @Internal
+// Initialize supported spl tokens with defaults.
+func initializeSplTokens() {
+	splTokensMutex.Lock()
+	defer splTokensMutex.Unlock()
+	supportedSplTokens = defaultSplTokens()
+	splTokensLoadedAt = time.Time{}
+}
+
+// @Internal
+// Sets loader of the token registry, loaded on next retrieval.
+func setSplTokenLoader(loader func() ([]SplTokenMeta, error)) {
+	splTokensMutex.Lock()
+	defer splTokensMutex.Unlock()
+	splTokenLoader = loader
+	splTokensLoadedAt = time.Time{}
+}
+
+// @Internal
+// Drops cached spl tokens, reloaded on next retrieval.
+func invalidateSplTokens() {
+	splTokensMutex.Lock()
+	defer splTokensMutex.Unlock()
+	splTokensLoadedAt = time.Time{}
+}
+
+// @Internal
+// Fills in type, token program and treasury token account of
+// loaded tokens. Token program defaults to the token program.
+func completeSplTokenMetas(tokens []SplTokenMeta) []SplTokenMeta {
+	completed := make([]SplTokenMeta, len(tokens))
+	for i, meta := range tokens {
+		meta.Type = SplTokenType(strings.ToLower(meta.Keyword) + "_spl")
+		if meta.TokenProgram.IsZero() {
+			meta.TokenProgram = solana.TokenProgramID
+		}
+		if isSolSplMeta(meta) {
+			meta.TreasuryTokenAccount = *treasuryPubKey()
+		} else {
+			meta.TreasuryTokenAccount = associatedTokenAccount(
+				*treasuryPubKey(),
+				meta.MintAddress,
+				meta.TokenProgram,
+			)
+		}
+		completed[i] = meta
+	}
+	return completed
+}
+
+// @External
+// Retrieve supported spl tokens, reloading the registry once
+// cached tokens get older than `config.SPL_TOKEN_CACHE_TTL`.
+// Cached tokens are kept when reload fails.
+func supportedSpls() []SplTokenMeta {
+	splTokensMutex.RLock()
+	tokens := supportedSplTokens
+	fresh := splTokenLoader == nil ||
+		time.Since(splTokensLoadedAt) < config.SPL_TOKEN_CACHE_TTL
+	splTokensMutex.RUnlock()
+	if fresh {
+		return tokens
+	}
+
+	splTokensMutex.Lock()
+	defer splTokensMutex.Unlock()
+	if time.Since(splTokensLoadedAt) < config.SPL_TOKEN_CACHE_TTL {
+		return supportedSplTokens
+	}
+	splTokensLoadedAt = time.Now()
+	loaded, err := splTokenLoader()
+	if err != nil || len(loaded) == 0 {
+		return supportedSplTokens
+	}
+	supportedSplTokens = completeSplTokenMetas(loaded)
+	return supportedSplTokens
+}
+
+// @Internal
+// Meta of native SOL, from defaults when missing in the registry.
+func solSplMeta() *SplTokenMeta {
+	for _, meta := range supportedSpls() {
+		if isSolSplMeta(meta) {
+			return &meta
+		}
+	}
+	defaults := defaultSplTokens()
+	return &defaults[0]
+}
+
+// @Internal
+// Meta of supported spl token of mint, nil if not supported.
+func splTokenMetaOf(mint solana.PublicKey) *SplTokenMeta {
+	for _, meta := range supportedSpls() {
+		if meta.MintAddress.Equals(mint) {
+			return &meta
+		}
+	}
+	return nil
+}
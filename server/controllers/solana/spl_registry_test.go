@@ -0,0 +1,59 @@
+package solana
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/gagliardetto/solana-go"
+)
+
+func TestSupportedSplsFromRegistry(t *testing.T) {
+	if err := initialize(&InitParam{
+		TreasuryBs58: solana.NewWallet().PrivateKey.String(),
+		Cluster:      ClusterDevNet,
+	}); err != nil {
+		t.Fatalf("failed to initialize: %v", err)
+	}
+	defer setSplTokenLoader(nil)
+	ttl := config.SPL_TOKEN_CACHE_TTL
+	config.SPL_TOKEN_CACHE_TTL = time.Hour
+	defer func() { config.SPL_TOKEN_CACHE_TTL = ttl }()
+
+	// Defaults without registry.
+	if tokens := supportedSpls(); len(tokens) != 4 ||
+		!isSolSplMeta(tokens[0]) ||
+		!tokens[0].TreasuryTokenAccount.Equals(*treasuryPubKey()) {
+		t.Fatalf("unexpected default tokens: %v", tokens)
+	}
+
+	// Loaded once per cache lifetime, with treasury accounts.
+	mint := solana.NewWallet().PublicKey()
+	loads := 0
+	setSplTokenLoader(func() ([]SplTokenMeta, error) {
+		loads++
+		return []SplTokenMeta{
+			{MintAddress: mint, Keyword: "Test", Decimals: 6, DepositEnabled: true},
+		}, nil
+	})
+	supportedSpls()
+	tokens := supportedSpls()
+	if loads != 1 || len(tokens) != 1 ||
+		tokens[0].Type != "test_spl" ||
+		!tokens[0].TreasuryTokenAccount.Equals(treasuryTokenAccount(mint)) {
+		t.Fatalf("unexpected registry tokens after %d loads: %v", loads, tokens)
+	}
+	if meta := solSplMeta(); !isSolSplMeta(*meta) {
+		t.Fatalf("sol meta should fall back to defaults: %v", meta)
+	}
+
+	// Cached tokens are kept on failed reload.
+	setSplTokenLoader(func() ([]SplTokenMeta, error) {
+		loads++
+		return nil, errors.New("registry unavailable")
+	})
+	if tokens := supportedSpls(); loads != 2 || len(tokens) != 1 {
+		t.Fatalf("cached tokens should be kept after %d loads: %v", loads, tokens)
+	}
+}
@@ -0,0 +1,403 @@
+package solana
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/metrics"
+	"github.com/gagliardetto/solana-go"
+	solana_token "github.com/gagliardetto/solana-go/programs/token"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// Token-2022 program, sharing instruction layout of token program.
+var Token2022ProgramID = solana.MustPublicKeyFromBase58(config.TOKEN_2022_PROGRAM_ADDRESS)
+
+// Metaplex program enforcing rule sets of programmable NFTs.
+var TokenAuthRulesProgramID = solana.MustPublicKeyFromBase58("auth9SigNpDKz4sJJ1DfCTuZrZNSAgh9sFD3rboVmgg")
+
+// Token metadata `Transfer` instruction and its `V1` args variant.
+const (
+	METADATA_TRANSFER_DISCRIMINATOR = byte(49)
+	METADATA_TRANSFER_ARGS_V1       = byte(0)
+)
+
+// Token standards of token metadata.
+const (
+	TokenStandardNonFungible                    = uint8(0)
+	TokenStandardProgrammableNonFungible        = uint8(4)
+	TokenStandardProgrammableNonFungibleEdition = uint8(5)
+)
+
+// @Internal
+// Check whether the program is token or Token-2022 program.
+func isTokenProgram(progKey solana.PublicKey) bool {
+	return progKey.Equals(solana.TokenProgramID) ||
+		progKey.Equals(Token2022ProgramID)
+}
+
+// @Internal
+// Get associated token address of owner under token program.
+func associatedTokenAccount(owner solana.PublicKey, mint solana.PublicKey, tokenProgram solana.PublicKey) solana.PublicKey {
+	address, _, _ := solana.FindProgramAddress(
+		[][]byte{
+			owner[:],
+			tokenProgram[:],
+			mint[:],
+		},
+		solana.SPLAssociatedTokenAccountProgramID,
+	)
+	return address
+}
+
+// @Internal
+// Builds instruction creating associated token account of
+// wallet under token program.
+func createAssociatedTokenAccountInst(
+	payer solana.PublicKey,
+	wallet solana.PublicKey,
+	mint solana.PublicKey,
+	tokenProgram solana.PublicKey,
+) solana.Instruction {
+	return solana.NewInstruction(
+		solana.SPLAssociatedTokenAccountProgramID,
+		solana.AccountMetaSlice{
+			solana.Meta(payer).SIGNER().WRITE(),
+			solana.Meta(associatedTokenAccount(wallet, mint, tokenProgram)).WRITE(),
+			solana.Meta(wallet),
+			solana.Meta(mint),
+			solana.Meta(solana.SystemProgramID),
+			solana.Meta(tokenProgram),
+		},
+		[]byte{},
+	)
+}
+
+// @Internal
+// Builds transfer checked instruction of token program. Token-2022
+// shares layout of the instruction with token program.
+func transferCheckedInst(
+	tokenProgram solana.PublicKey,
+	amount uint64,
+	decimals uint8,
+	source solana.PublicKey,
+	mint solana.PublicKey,
+	destination solana.PublicKey,
+	owner solana.PublicKey,
+) (solana.Instruction, error) {
+	inst := solana_token.NewTransferCheckedInstruction(
+		amount,
+		decimals,
+		source,
+		mint,
+		destination,
+		owner,
+		[]solana.PublicKey{},
+	).Build()
+	data, err := inst.Data()
+	if err != nil {
+		return nil, makeError("transferCheckedInst", "failed to encode transfer checked", err)
+	}
+	return solana.NewInstruction(tokenProgram, inst.Accounts(), data), nil
+}
+
+// @Internal
+// Get master edition address of mint.
+func masterEditionAddress(mint solana.PublicKey) solana.PublicKey {
+	address, _, _ := solana.FindProgramAddress(
+		[][]byte{
+			[]byte("metadata"),
+			solana.TokenMetadataProgramID[:],
+			mint[:],
+			[]byte("edition"),
+		},
+		solana.TokenMetadataProgramID,
+	)
+	return address
+}
+
+// @Internal
+// Get token record address of a programmable NFT token account.
+func tokenRecordAddress(mint solana.PublicKey, token solana.PublicKey) solana.PublicKey {
+	address, _, _ := solana.FindProgramAddress(
+		[][]byte{
+			[]byte("metadata"),
+			solana.TokenMetadataProgramID[:],
+			mint[:],
+			[]byte("token_record"),
+			token[:],
+		},
+		solana.TokenMetadataProgramID,
+	)
+	return address
+}
+
+// @Internal
+// Builds token metadata `TransferV1` instruction moving a
+// programmable NFT from owner to destination. Destination token
+// account is created by token metadata program if not exists.
+// Optional accounts not provided are set to the program itself.
+func pnftTransferInst(asset *NftAsset, owner solana.PublicKey, to solana.PublicKey) solana.Instruction {
+	source := associatedTokenAccount(owner, asset.Mint, asset.TokenProgram)
+	destination := associatedTokenAccount(to, asset.Mint, asset.TokenProgram)
+	metadata, _, _ := solana.FindTokenMetadataAddress(asset.Mint)
+
+	authRulesProgram := solana.TokenMetadataProgramID
+	authRules := solana.TokenMetadataProgramID
+	if asset.RuleSet != nil {
+		authRulesProgram = TokenAuthRulesProgramID
+		authRules = *asset.RuleSet
+	}
+
+	data := make([]byte, 11)
+	data[0] = METADATA_TRANSFER_DISCRIMINATOR
+	data[1] = METADATA_TRANSFER_ARGS_V1
+	binary.LittleEndian.PutUint64(data[2:], 1)
+	data[10] = 0 // No authorization data
+
+	return solana.NewInstruction(
+		solana.TokenMetadataProgramID,
+		solana.AccountMetaSlice{
+			solana.Meta(source).WRITE(),
+			solana.Meta(owner),
+			solana.Meta(destination).WRITE(),
+			solana.Meta(to),
+			solana.Meta(asset.Mint),
+			solana.Meta(metadata).WRITE(),
+			solana.Meta(masterEditionAddress(asset.Mint)),
+			solana.Meta(tokenRecordAddress(asset.Mint, source)).WRITE(),
+			solana.Meta(tokenRecordAddress(asset.Mint, destination)).WRITE(),
+			solana.Meta(owner).SIGNER(),
+			solana.Meta(owner).SIGNER().WRITE(),
+			solana.Meta(solana.SystemProgramID),
+			solana.Meta(solana.SysVarInstructionsPubkey),
+			solana.Meta(asset.TokenProgram),
+			solana.Meta(solana.SPLAssociatedTokenAccountProgramID),
+			solana.Meta(authRulesProgram),
+			solana.Meta(authRules),
+		},
+		data,
+	)
+}
+
+// @Internal
+// Decodes token metadata `TransferV1` instruction, nil if the
+// instruction is any other one.
+func decodeNftTransferV1(accounts []*solana.AccountMeta, data []byte) *NftTransferV1 {
+	if len(data) < 10 ||
+		data[0] != METADATA_TRANSFER_DISCRIMINATOR ||
+		data[1] != METADATA_TRANSFER_ARGS_V1 ||
+		len(accounts) < 5 {
+		return nil
+	}
+	return &NftTransferV1{
+		Source:           accounts[0].PublicKey,
+		SourceOwner:      accounts[1].PublicKey,
+		Destination:      accounts[2].PublicKey,
+		DestinationOwner: accounts[3].PublicKey,
+		Mint:             accounts[4].PublicKey,
+		Amount:           binary.LittleEndian.Uint64(data[2:10]),
+	}
+}
+
+// Reads borsh encoded token metadata account.
+type metadataReader struct {
+	data   []byte
+	offset int
+}
+
+func (reader *metadataReader) skip(n int) error {
+	if n < 0 || reader.offset+n > len(reader.data) {
+		return errors.New("unexpected end of metadata")
+	}
+	reader.offset += n
+	return nil
+}
+
+func (reader *metadataReader) u8() (uint8, error) {
+	if err := reader.skip(1); err != nil {
+		return 0, err
+	}
+	return reader.data[reader.offset-1], nil
+}
+
+func (reader *metadataReader) string() error {
+	if err := reader.skip(4); err != nil {
+		return err
+	}
+	length := binary.LittleEndian.Uint32(reader.data[reader.offset-4:])
+	return reader.skip(int(length))
+}
+
+// Skips borsh option of fixed size value, returns whether present.
+func (reader *metadataReader) option(size int) (bool, error) {
+	flag, err := reader.u8()
+	if err != nil || flag == 0 {
+		return false, err
+	}
+	return true, reader.skip(size)
+}
+
+func (reader *metadataReader) eof() bool {
+	return reader.offset >= len(reader.data)
+}
+
+// @Internal
+// Parses token standard and rule set of token metadata account.
+// Metadata written before a field was introduced ends early,
+// in which case the field is left empty.
+func parseTokenMetadata(data []byte) (*TokenMetadata, error) {
+	reader := metadataReader{data: data}
+	metadata := TokenMetadata{}
+
+	// 1. Key, update authority, mint.
+	if err := reader.skip(1 + 32 + 32); err != nil {
+		return nil, makeError("parseTokenMetadata", "failed to read header", err)
+	}
+
+	// 2. Name, symbol, uri, seller fee basis points, creators.
+	for i := 0; i < 3; i++ {
+		if err := reader.string(); err != nil {
+			return nil, makeError("parseTokenMetadata", "failed to read data", err)
+		}
+	}
+	if err := reader.skip(2); err != nil {
+		return nil, makeError("parseTokenMetadata", "failed to read data", err)
+	}
+	hasCreators, err := reader.u8()
+	if err != nil {
+		return nil, makeError("parseTokenMetadata", "failed to read creators", err)
+	}
+	if hasCreators != 0 {
+		if err := reader.skip(4); err != nil {
+			return nil, makeError("parseTokenMetadata", "failed to read creators", err)
+		}
+		count := binary.LittleEndian.Uint32(data[reader.offset-4:])
+		if err := reader.skip(int(count) * 34); err != nil {
+			return nil, makeError("parseTokenMetadata", "failed to read creators", err)
+		}
+	}
+
+	// 3. Primary sale happened, is mutable, edition nonce.
+	if err := reader.skip(2); err != nil {
+		return nil, makeError("parseTokenMetadata", "failed to read flags", err)
+	}
+	if _, err := reader.option(1); err != nil {
+		return nil, makeError("parseTokenMetadata", "failed to read edition nonce", err)
+	}
+
+	// 4. Token standard.
+	if reader.eof() {
+		return &metadata, nil
+	}
+	hasStandard, err := reader.u8()
+	if err != nil {
+		return nil, makeError("parseTokenMetadata", "failed to read token standard", err)
+	}
+	if hasStandard != 0 {
+		standard, err := reader.u8()
+		if err != nil {
+			return nil, makeError("parseTokenMetadata", "failed to read token standard", err)
+		}
+		metadata.TokenStandard = &standard
+	}
+
+	// 5. Collection, uses, collection details.
+	for _, size := range []int{33, 17, 9} {
+		if reader.eof() {
+			return &metadata, nil
+		}
+		if _, err := reader.option(size); err != nil {
+			return nil, makeError("parseTokenMetadata", "failed to read optional fields", err)
+		}
+	}
+
+	// 6. Programmable config.
+	if reader.eof() {
+		return &metadata, nil
+	}
+	hasConfig, err := reader.u8()
+	if err != nil || hasConfig == 0 {
+		return &metadata, nil
+	}
+	if err := reader.skip(1); err != nil { // Config version
+		return nil, makeError("parseTokenMetadata", "failed to read programmable config", err)
+	}
+	hasRuleSet, err := reader.u8()
+	if err != nil {
+		return nil, makeError("parseTokenMetadata", "failed to read rule set", err)
+	}
+	if hasRuleSet != 0 {
+		if err := reader.skip(32); err != nil {
+			return nil, makeError("parseTokenMetadata", "failed to read rule set", err)
+		}
+		ruleSet := solana.PublicKeyFromBytes(data[reader.offset-32 : reader.offset])
+		metadata.RuleSet = &ruleSet
+	}
+	return &metadata, nil
+}
+
+// @Internal
+// Builds NFT asset from mint account owner and metadata.
+func nftAssetFromAccounts(mint solana.PublicKey, mintOwner solana.PublicKey, metadataData []byte) (*NftAsset, error) {
+	if !isTokenProgram(mintOwner) {
+		return nil, makeError(
+			"nftAssetFromAccounts",
+			"mint is not owned by token program",
+			fmt.Errorf("mint: %v, owner: %v", mint, mintOwner),
+		)
+	}
+
+	asset := NftAsset{
+		Mint:         mint,
+		TokenProgram: mintOwner,
+	}
+	if len(metadataData) == 0 {
+		return &asset, nil
+	}
+	metadata, err := parseTokenMetadata(metadataData)
+	if err != nil {
+		return nil, makeError("nftAssetFromAccounts", "failed to parse metadata", err)
+	}
+	if metadata.TokenStandard != nil &&
+		(*metadata.TokenStandard == TokenStandardProgrammableNonFungible ||
+			*metadata.TokenStandard == TokenStandardProgrammableNonFungibleEdition) {
+		asset.Programmable = true
+		asset.RuleSet = metadata.RuleSet
+	}
+	return &asset, nil
+}
+
+// @Internal
+// Get token program and token standard of NFT mint.
+func getNftAsset(mint solana.PublicKey) (*NftAsset, error) {
+	metadata, _, err := solana.FindTokenMetadataAddress(mint)
+	if err != nil {
+		return nil, makeError("getNftAsset", "failed to find metadata address", err)
+	}
+
+	client := newClient()
+	start := time.Now()
+	out, err := client.GetMultipleAccountsWithOpts(
+		context.TODO(),
+		[]solana.PublicKey{mint, metadata},
+		&rpc.GetMultipleAccountsOpts{Commitment: rpc.CommitmentFinalized},
+	)
+	metrics.ObserveSolanaRpc("getMultipleAccounts", start, err)
+	if err != nil {
+		return nil, makeError("getNftAsset", "failed to get mint accounts", err)
+	}
+	if len(out.Value) != 2 || out.Value[0] == nil {
+		return nil, makeError("getNftAsset", "mint not found", fmt.Errorf("mint: %v", mint))
+	}
+
+	metadataData := []byte{}
+	if out.Value[1] != nil {
+		metadataData = out.Value[1].Data.GetBinary()
+	}
+	return nftAssetFromAccounts(mint, out.Value[0].Owner, metadataData)
+}
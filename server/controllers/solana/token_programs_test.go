@@ -0,0 +1,324 @@
+package solana
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// Treasury of hand-built transaction fixtures in testdata. Mainnet
+// transactions recorded with `TestRecordTxFixtures` replace them with
+// the wallet they were sent to as `treasury` of their expectation.
+const fixtureTreasuryBs58 = "4Z7NKeds1vApJHSjNvYEEHr2XB8LcCJkN4k6ji4uZoW1HFoVJmxY8XfRDE4rU1zduT84925MF8F4G2NMxDFzFNCw"
+const fixtureUser = "QWXk6znZhBtDTD9JLFnJyUcJC6LzfVYCqd1nzpKEhPG"
+
+// Expected decoding of a transaction fixture.
+type txFixtureExpectation struct {
+	// Wallet the recorded transaction was sent to or from, decoded
+	// as treasury. Empty for hand-built fixtures.
+	treasury string
+	decoded  DecodedTransaction
+}
+
+// Loads `getTransaction` result from testdata.
+func loadTxFixture(t *testing.T, name string) *rpc.GetTransactionResult {
+	t.Helper()
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatalf("failed to read fixture %s: %v", name, err)
+	}
+	var txOut rpc.GetTransactionResult
+	if err := json.Unmarshal(data, &txOut); err != nil {
+		t.Fatalf("failed to parse fixture %s: %v", name, err)
+	}
+	return &txOut
+}
+
+// Decodes transaction fixture with treasury of its expectation.
+func decodeTxFixture(t *testing.T, name string, expected txFixtureExpectation) *DecodedTransaction {
+	t.Helper()
+	if expected.treasury != "" {
+		treasury := solana.MustPublicKeyFromBase58(expected.treasury)
+		setTreasuryPubKeyOverride(&treasury)
+		defer setTreasuryPubKeyOverride(nil)
+	}
+	decoded, err := decodeTransactionResult(loadTxFixture(t, name))
+	if err != nil {
+		t.Fatalf("%s: failed to decode: %v", name, err)
+	}
+	return decoded
+}
+
+// Records `getTransaction` results into testdata in the fixture
+// format, base64 encoded and indented by two spaces.
+// Skipped unless `SOLANA_FIXTURE_RPC` is set with comma separated
+// `name=signature` pairs in `SOLANA_FIXTURE_SIGNATURES`, e.g.
+// `pnft_deposit.json=<signature>,pnft_withdraw.json=<signature>`.
+func TestRecordTxFixtures(t *testing.T) {
+	endpoint := os.Getenv("SOLANA_FIXTURE_RPC")
+	signatures := os.Getenv("SOLANA_FIXTURE_SIGNATURES")
+	if endpoint == "" || signatures == "" {
+		t.Skip("SOLANA_FIXTURE_RPC or SOLANA_FIXTURE_SIGNATURES is not set")
+	}
+
+	client := rpc.New(endpoint)
+	for _, pair := range strings.Split(signatures, ",") {
+		name, signature, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || name == "" || signature == "" {
+			t.Fatalf("invalid fixture pair: %s", pair)
+		}
+
+		var result json.RawMessage
+		if err := client.RPCCallForInto(
+			context.Background(),
+			&result,
+			"getTransaction",
+			[]interface{}{
+				signature,
+				map[string]interface{}{
+					"encoding":                       "base64",
+					"commitment":                     "confirmed",
+					"maxSupportedTransactionVersion": 0,
+				},
+			},
+		); err != nil {
+			t.Fatalf("failed to get transaction %s: %v", signature, err)
+		}
+		if string(result) == "null" {
+			t.Fatalf("transaction not found: %s", signature)
+		}
+
+		var fixture bytes.Buffer
+		if err := json.Indent(&fixture, result, "", "  "); err != nil {
+			t.Fatalf("failed to indent transaction %s: %v", signature, err)
+		}
+		fixture.WriteString("\n")
+		if err := os.WriteFile("testdata/"+name, fixture.Bytes(), 0644); err != nil {
+			t.Fatalf("failed to write fixture %s: %v", name, err)
+		}
+	}
+}
+
+func TestDecodeTokenProgramTransactions(t *testing.T) {
+	if err := initialize(&InitParam{
+		TreasuryBs58: fixtureTreasuryBs58,
+		Cluster:      ClusterDevNet,
+	}); err != nil {
+		t.Fatalf("failed to initialize: %v", err)
+	}
+	spl2022 := solana.MustPublicKeyFromBase58("2DLpVcq3KwGBBXLejjiwjMewEfKTn81yhNJRh3XAMser")
+	setSplTokenLoader(func() ([]SplTokenMeta, error) {
+		return []SplTokenMeta{
+			{MintAddress: spl2022, Keyword: "T22", Decimals: 6, TokenProgram: Token2022ProgramID},
+		}, nil
+	})
+	defer setSplTokenLoader(nil)
+
+	for name, expectation := range map[string]txFixtureExpectation{
+		"pnft_deposit.json": {decoded: DecodedTransaction{
+			TransactionType: TransactionNftDeposit,
+			Participant:     fixtureUser,
+			Nfts:            []string{"BrtrcWAEBe1p9xmsvWd5y3oeokV7EQGxnykR9UQmdHnA"},
+		}},
+		"pnft_withdraw.json": {decoded: DecodedTransaction{
+			TransactionType: TransactionNftWithdraw,
+			Participant:     fixtureUser,
+			Nfts:            []string{"BrtrcWAEBe1p9xmsvWd5y3oeokV7EQGxnykR9UQmdHnA"},
+		}},
+		"token2022_nft_deposit.json": {decoded: DecodedTransaction{
+			TransactionType: TransactionNftDeposit,
+			Participant:     fixtureUser,
+			Nfts:            []string{"7PyivPRvbqXAobs4kG6SGTiizaggwLsZxhTGMybKNPkB"},
+		}},
+		"token2022_spl_deposit.json": {decoded: DecodedTransaction{
+			TransactionType: TransactionSplDeposit,
+			Participant:     fixtureUser,
+			Lamports:        2500000,
+		}},
+	} {
+		expected := expectation.decoded
+		decoded := decodeTxFixture(t, name, expectation)
+		if decoded.TransactionType != expected.TransactionType ||
+			decoded.Participant != expected.Participant ||
+			decoded.Lamports != expected.Lamports {
+			t.Fatalf("%s: unexpected decoded transaction: %+v", name, decoded)
+		}
+		if len(decoded.Nfts) != len(expected.Nfts) ||
+			(len(expected.Nfts) > 0 && decoded.Nfts[0] != expected.Nfts[0]) {
+			t.Fatalf("%s: unexpected nfts: %v", name, decoded.Nfts)
+		}
+		if expected.TransactionType == TransactionSplDeposit &&
+			(decoded.SplToken == nil || !decoded.SplToken.MintAddress.Equals(spl2022)) {
+			t.Fatalf("%s: unexpected spl token: %v", name, decoded.SplToken)
+		}
+	}
+}
+
+// Recorded fixtures are decoded against their own treasury.
+func TestDecodeTxFixtureTreasury(t *testing.T) {
+	if err := initialize(&InitParam{
+		TreasuryBs58: fixtureTreasuryBs58,
+		Cluster:      ClusterDevNet,
+	}); err != nil {
+		t.Fatalf("failed to initialize: %v", err)
+	}
+
+	treasury := solana.MustPrivateKeyFromBase58(fixtureTreasuryBs58).PublicKey()
+	decoded := decodeTxFixture(t, "pnft_deposit.json", txFixtureExpectation{
+		treasury: treasury.String(),
+	})
+	if decoded.TransactionType != TransactionNftDeposit {
+		t.Fatalf("deposit to the treasury should be decoded: %+v", decoded)
+	}
+
+	decoded = decodeTxFixture(t, "pnft_deposit.json", txFixtureExpectation{
+		treasury: solana.NewWallet().PublicKey().String(),
+	})
+	if decoded.TransactionType != TransactionNothing {
+		t.Fatalf("deposit to another wallet should not be decoded: %+v", decoded)
+	}
+	if !treasuryPubKey().Equals(treasury) {
+		t.Fatalf("treasury should be reset after decoding: %v", treasuryPubKey())
+	}
+}
+
+// Encodes token metadata account up to programmable config.
+func encodeTestMetadata(tokenStandard *uint8, ruleSet *solana.PublicKey, legacy bool) []byte {
+	data := []byte{4}
+	data = append(data, make([]byte, 64)...)
+	for _, field := range []string{"Test #1", "TEST", "https://example.com/1.json"} {
+		data = binary.LittleEndian.AppendUint32(data, uint32(len(field)))
+		data = append(data, field...)
+	}
+	data = append(data, 0xf4, 0x01)          // Seller fee basis points
+	data = append(data, 1, 1, 0, 0, 0)       // One creator
+	data = append(data, make([]byte, 34)...) // Creator
+	data = append(data, 1, 1, 1, 255)        // Primary sale, mutable, edition nonce
+	if legacy {
+		return data
+	}
+	if tokenStandard == nil {
+		data = append(data, 0)
+	} else {
+		data = append(data, 1, *tokenStandard)
+	}
+	data = append(data, 1) // Collection
+	data = append(data, make([]byte, 33)...)
+	data = append(data, 0, 0) // Uses, collection details
+	if ruleSet == nil {
+		data = append(data, 0)
+	} else {
+		data = append(data, 1, 0, 1)
+		data = append(data, ruleSet[:]...)
+	}
+	return append(data, make([]byte, 64)...) // Padding
+}
+
+func TestParseTokenMetadata(t *testing.T) {
+	ruleSet := solana.NewWallet().PublicKey()
+	mint := solana.NewWallet().PublicKey()
+	pnft := TokenStandardProgrammableNonFungible
+	nft := TokenStandardNonFungible
+
+	asset, err := nftAssetFromAccounts(mint, solana.TokenProgramID, encodeTestMetadata(&pnft, &ruleSet, false))
+	if err != nil {
+		t.Fatalf("failed to parse pnft metadata: %v", err)
+	}
+	if !asset.Programmable || asset.RuleSet == nil || !asset.RuleSet.Equals(ruleSet) {
+		t.Fatalf("unexpected pnft asset: %+v", asset)
+	}
+
+	asset, err = nftAssetFromAccounts(mint, solana.TokenProgramID, encodeTestMetadata(&nft, nil, false))
+	if err != nil || asset.Programmable || asset.RuleSet != nil {
+		t.Fatalf("unexpected nft asset: %+v, %v", asset, err)
+	}
+
+	asset, err = nftAssetFromAccounts(mint, Token2022ProgramID, encodeTestMetadata(nil, nil, true))
+	if err != nil || asset.Programmable || !asset.TokenProgram.Equals(Token2022ProgramID) {
+		t.Fatalf("unexpected legacy asset: %+v, %v", asset, err)
+	}
+
+	if _, err := nftAssetFromAccounts(mint, solana.SystemProgramID, nil); err == nil {
+		t.Fatal("expected error of mint not owned by token program")
+	}
+	if _, err := parseTokenMetadata([]byte{4, 1, 2}); err == nil {
+		t.Fatal("expected error of truncated metadata")
+	}
+}
+
+func TestNftAssetTransferInsts(t *testing.T) {
+	setTreasuryBs58(fixtureTreasuryBs58)
+	to := solana.NewWallet().PublicKey()
+	mint := solana.NewWallet().PublicKey()
+
+	// Token program NFT.
+	insts, err := nftAssetTransferInsts(&NftAsset{Mint: mint, TokenProgram: solana.TokenProgramID}, to, true)
+	if err != nil || len(*insts) != 2 ||
+		!(*insts)[0].ProgramID().Equals(solana.SPLAssociatedTokenAccountProgramID) ||
+		!(*insts)[1].ProgramID().Equals(solana.TokenProgramID) {
+		t.Fatalf("unexpected token program insts: %v, %v", insts, err)
+	}
+
+	// Token-2022 NFT.
+	insts, err = nftAssetTransferInsts(&NftAsset{Mint: mint, TokenProgram: Token2022ProgramID}, to, true)
+	if err != nil || len(*insts) != 2 ||
+		!(*insts)[1].ProgramID().Equals(Token2022ProgramID) {
+		t.Fatalf("unexpected token-2022 insts: %v, %v", insts, err)
+	}
+	accounts := (*insts)[0].Accounts()
+	if !accounts[1].PublicKey.Equals(associatedTokenAccount(to, mint, Token2022ProgramID)) ||
+		!accounts[5].PublicKey.Equals(Token2022ProgramID) {
+		t.Fatalf("unexpected token-2022 account creation: %v", accounts)
+	}
+	data, _ := (*insts)[1].Data()
+	if data[0] != 12 || data[9] != 0 {
+		t.Fatalf("expected transfer checked of 0 decimals: %v", data)
+	}
+
+	// Programmable NFT with rule set.
+	ruleSet := solana.NewWallet().PublicKey()
+	insts, err = nftAssetTransferInsts(&NftAsset{
+		Mint:         mint,
+		TokenProgram: solana.TokenProgramID,
+		Programmable: true,
+		RuleSet:      &ruleSet,
+	}, to, true)
+	if err != nil || len(*insts) != 1 ||
+		!(*insts)[0].ProgramID().Equals(solana.TokenMetadataProgramID) {
+		t.Fatalf("unexpected pnft insts: %v, %v", insts, err)
+	}
+	accounts = (*insts)[0].Accounts()
+	source := associatedTokenAccount(*treasuryPubKey(), mint, solana.TokenProgramID)
+	if len(accounts) != 17 ||
+		!accounts[7].PublicKey.Equals(tokenRecordAddress(mint, source)) ||
+		!accounts[15].PublicKey.Equals(TokenAuthRulesProgramID) ||
+		!accounts[16].PublicKey.Equals(ruleSet) {
+		t.Fatalf("unexpected pnft accounts: %v", accounts)
+	}
+	decoded := decodeNftTransferV1(accounts, mustData(t, (*insts)[0]))
+	if decoded == nil || !decoded.Mint.Equals(mint) ||
+		!decoded.DestinationOwner.Equals(to) || decoded.Amount != 1 {
+		t.Fatalf("unexpected decoded pnft transfer: %+v", decoded)
+	}
+
+	if limit := computeUnitLimit(*insts); limit != config.WITHDRAW_PNFT_COMPUTE_UNITS {
+		t.Fatalf("expected pnft compute units, got %d", limit)
+	}
+}
+
+func mustData(t *testing.T, inst solana.Instruction) []byte {
+	t.Helper()
+	data, err := inst.Data()
+	if err != nil {
+		t.Fatalf("failed to encode instruction: %v", err)
+	}
+	return data
+}
@@ -0,0 +1,94 @@
+package solana
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// @Internal
+// Resolve account metas of a transaction message.
+// Versioned messages reference accounts of address lookup tables
+// which are not part of the message itself. Those are taken from
+// the loaded addresses of transaction meta and placed after static
+// keys, writable ones first, same as the runtime orders them.
+func resolveMessageAccounts(tx *solana.Transaction, loaded rpc.LoadedAddresses) (solana.AccountMetaSlice, error) {
+	if tx == nil {
+		return nil, makeError("resolveMessageAccounts", "invalid parameter", errors.New("parameter (tx) is nil pointer"))
+	}
+
+	message := &tx.Message
+	numLookups := 0
+	numWritableLookups := 0
+	if message.IsVersioned() {
+		numLookups = message.GetAddressTableLookups().NumLookups()
+		numWritableLookups = message.GetAddressTableLookups().NumWritableLookups()
+	}
+	if numLookups != len(loaded.Writable)+len(loaded.ReadOnly) ||
+		numWritableLookups != len(loaded.Writable) {
+		return nil, makeError(
+			"resolveMessageAccounts",
+			"mismatching loaded addresses",
+			fmt.Errorf(
+				"lookups: %d (%d writable), loaded: %d writable, %d readonly",
+				numLookups,
+				numWritableLookups,
+				len(loaded.Writable),
+				len(loaded.ReadOnly),
+			),
+		)
+	}
+
+	header := message.Header
+	numSigners := int(header.NumRequiredSignatures)
+	numWritableSigners := numSigners - int(header.NumReadonlySignedAccounts)
+	numWritableStatic := len(message.AccountKeys) - int(header.NumReadonlyUnsignedAccounts)
+
+	accounts := make(solana.AccountMetaSlice, 0, len(message.AccountKeys)+numLookups)
+	for i, key := range message.AccountKeys {
+		isSigner := i < numSigners
+		isWritable := i < numWritableStatic
+		if isSigner {
+			isWritable = i < numWritableSigners
+		}
+		accounts = append(accounts, solana.NewAccountMeta(key, isWritable, isSigner))
+	}
+	for _, key := range loaded.Writable {
+		accounts = append(accounts, solana.NewAccountMeta(key, true, false))
+	}
+	for _, key := range loaded.ReadOnly {
+		accounts = append(accounts, solana.NewAccountMeta(key, false, false))
+	}
+	return accounts, nil
+}
+
+// @Internal
+// Resolve program and accounts of a compiled instruction.
+// Inner instructions index the same accounts as top level ones.
+func resolveInstructionAccounts(
+	accounts solana.AccountMetaSlice,
+	instruction solana.CompiledInstruction,
+) (solana.PublicKey, []*solana.AccountMeta, error) {
+	if int(instruction.ProgramIDIndex) >= len(accounts) {
+		return solana.PublicKey{}, nil, makeError(
+			"resolveInstructionAccounts",
+			"invalid program index",
+			fmt.Errorf("index %d out of %d accounts", instruction.ProgramIDIndex, len(accounts)),
+		)
+	}
+
+	result := make([]*solana.AccountMeta, len(instruction.Accounts))
+	for i, index := range instruction.Accounts {
+		if int(index) >= len(accounts) {
+			return solana.PublicKey{}, nil, makeError(
+				"resolveInstructionAccounts",
+				"invalid account index",
+				fmt.Errorf("index %d out of %d accounts", index, len(accounts)),
+			)
+		}
+		result[i] = accounts[index]
+	}
+	return accounts[instruction.ProgramIDIndex].PublicKey, result, nil
+}
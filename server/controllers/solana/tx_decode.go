@@ -3,8 +3,10 @@ package solana
 import (
 	"context"
 	"errors"
-	"fmt"
+	"strings"
+	"time"
 
+	"github.com/Duelana-Team/duelana-v1/metrics"
 	bin "github.com/gagliardetto/binary"
 	"github.com/gagliardetto/solana-go"
 	solana_token_account "github.com/gagliardetto/solana-go/programs/associated-token-account"
@@ -31,6 +33,7 @@ func getTransaction(txHashBs58 string) (*rpc.GetTransactionResult, error) {
 
 	client := newClient()
 	maxSupportedTxVersion := uint64(0)
+	start := time.Now()
 	txOut, err := client.GetTransaction(
 		context.TODO(),
 		txHash,
@@ -39,8 +42,9 @@ func getTransaction(txHashBs58 string) (*rpc.GetTransactionResult, error) {
 			MaxSupportedTransactionVersion: &maxSupportedTxVersion,
 		},
 	)
+	metrics.ObserveSolanaRpc("getTransaction", start, err)
 	if err != nil {
-		return nil, makeError("getTransaction", "failed to get transaction result", err)
+		return nil, makeErrorWithCode("getTransaction", "failed to get transaction result", ErrCodeRpcFailure, err)
 	}
 
 	return txOut, nil
@@ -58,87 +62,188 @@ func getDecodedTransaction(txOut *rpc.GetTransactionResult) (*solana.Transaction
 }
 
 // @Internal
-// Decode a instruction.
-func decodeTransactionInstructions(tx *solana.Transaction) (*[]DecodedInstruction, error) {
+// Decode top level instructions of a transaction.
+func decodeTransactionInstructions(tx *solana.Transaction, accounts solana.AccountMetaSlice) (*[]DecodedInstruction, error) {
 	if tx == nil {
 		return nil, makeError("decodeTransactionInstructions", "Invalid parameter", errors.New("parameter (tx) is nil pointer"))
 	}
 	result := make([]DecodedInstruction, len(tx.Message.Instructions))
 	for i, instruction := range tx.Message.Instructions {
-		progKey, err := tx.ResolveProgramIDIndex(instruction.ProgramIDIndex)
-		if err != nil {
-			result[i] = DecodedInstruction{
-				InstructionType: InstructionUnknown,
-				Meta:            nil,
-			}
-			continue
+		result[i] = decodeInstruction(accounts, instruction)
+	}
+
+	return &result, nil
+}
+
+// @Internal
+// Decode instructions invoked by top level ones through CPI,
+// in the order they were executed.
+func decodeInnerInstructions(accounts solana.AccountMetaSlice, innerInstructions []rpc.InnerInstruction) []DecodedInstruction {
+	result := []DecodedInstruction{}
+	for _, inner := range innerInstructions {
+		for _, instruction := range inner.Instructions {
+			result = append(result, decodeInstruction(accounts, instruction))
 		}
+	}
+	return result
+}
 
-		accounts, err := instruction.ResolveInstructionAccounts(&tx.Message)
-		if err != nil {
-			result[i] = DecodedInstruction{
-				InstructionType: InstructionUnknown,
-				Meta:            nil,
+// @Internal
+// Decode a instruction.
+func decodeInstruction(accounts solana.AccountMetaSlice, instruction solana.CompiledInstruction) DecodedInstruction {
+	unknown := DecodedInstruction{
+		InstructionType: InstructionUnknown,
+		Meta:            nil,
+	}
+	progKey, instAccounts, err := resolveInstructionAccounts(accounts, instruction)
+	if err != nil {
+		return unknown
+	}
+	unknown.Accounts = instAccounts
+
+	if isMemoProgram(progKey) {
+		return DecodedInstruction{
+			InstructionType: InstructionMemo,
+			Meta:            string(instruction.Data),
+			Accounts:        instAccounts,
+		}
+	}
+	if progKey.Equals(solana.ComputeBudget) {
+		return DecodedInstruction{
+			InstructionType: InstructionComputeBudget,
+			Meta:            nil,
+			Accounts:        instAccounts,
+		}
+	}
+
+	if progKey.Equals(solana.TokenMetadataProgramID) {
+		if transfer := decodeNftTransferV1(instAccounts, instruction.Data); transfer != nil {
+			return DecodedInstruction{
+				InstructionType: InstructionNftTransferV1,
+				Meta:            transfer,
+				Accounts:        instAccounts,
 			}
-			continue
 		}
-		decodedInstruction, err := solana.DecodeInstruction(
+		return unknown
+	}
+
+	var decodedInstruction interface{}
+	if progKey.Equals(Token2022ProgramID) {
+		decodedInstruction, err = solana_token.DecodeInstruction(instAccounts, instruction.Data)
+	} else {
+		decodedInstruction, err = solana.DecodeInstruction(
 			progKey,
-			accounts,
+			instAccounts,
 			instruction.Data,
 		)
-		if err != nil {
-			result[i] = DecodedInstruction{
-				InstructionType: InstructionUnknown,
-				Meta:            nil,
+	}
+	if err != nil {
+		return unknown
+	}
+
+	if tokenInstrucion, ok := decodedInstruction.(*solana_token.Instruction); ok {
+		if instructionMeta, ok := tokenInstrucion.Impl.(*solana_token.Transfer); ok {
+			return DecodedInstruction{
+				InstructionType: InstructionTokenTransfer,
+				Meta:            instructionMeta,
+				Accounts:        instAccounts,
 			}
-			continue
 		}
-
-		if tokenInstrucion, ok := decodedInstruction.(*solana_token.Instruction); ok {
-			if instructionMeta, ok := tokenInstrucion.Impl.(*solana_token.Transfer); ok {
-				result[i] = DecodedInstruction{
-					InstructionType: InstructionTokenTransfer,
-					Meta:            instructionMeta,
-				}
-				continue
+		if instructionMeta, ok := tokenInstrucion.Impl.(*solana_token.TransferChecked); ok {
+			return DecodedInstruction{
+				InstructionType: InstructionTokenTransferChecked,
+				Meta:            instructionMeta,
+				Accounts:        instAccounts,
 			}
-			if instructionMeta, ok := tokenInstrucion.Impl.(*solana_token.TransferChecked); ok {
-				result[i] = DecodedInstruction{
-					InstructionType: InstructionTokenTransferChecked,
-					Meta:            instructionMeta,
-				}
-				continue
+		}
+	} else if tokenAccountInstruction, ok := decodedInstruction.(*solana_token_account.Instruction); ok {
+		if instructionMeta, ok := tokenAccountInstruction.Impl.(*solana_token_account.Create); ok {
+			return DecodedInstruction{
+				InstructionType: InstructionTokenCreateAccount,
+				Meta:            instructionMeta,
+				Accounts:        instAccounts,
 			}
-		} else if tokenAccountInstruction, ok := decodedInstruction.(*solana_token_account.Instruction); ok {
-			if instructionMeta, ok := tokenAccountInstruction.Impl.(*solana_token_account.Create); ok {
-				result[i] = DecodedInstruction{
-					InstructionType: InstructionTokenCreateAccount,
-					Meta:            instructionMeta,
-				}
-				continue
+		}
+	} else if systemInstruction, ok := decodedInstruction.(*solana_system.Instruction); ok {
+		if instructionMeta, ok := systemInstruction.Impl.(*solana_system.Transfer); ok {
+			return DecodedInstruction{
+				InstructionType: InstructionSolTransfer,
+				Meta:            instructionMeta,
+				Accounts:        instAccounts,
 			}
-		} else if systemInstruction, ok := decodedInstruction.(*solana_system.Instruction); ok {
-			if instructionMeta, ok := systemInstruction.Impl.(*solana_system.Transfer); ok {
-				result[i] = DecodedInstruction{
-					InstructionType: InstructionSolTransfer,
-					Meta:            instructionMeta,
-				}
-				continue
+		}
+	}
+
+	return unknown
+}
+
+// Memo program ids, legacy one included.
+var memoProgramIDs = []solana.PublicKey{
+	solana.MemoProgramID,
+	solana.MustPublicKeyFromBase58("Memo1UhkJRfHyvLMcVucJwxXeuD728EqVDDwQDxFMNo"),
+}
+
+// @Internal
+// Check whether a program is a memo program.
+func isMemoProgram(progKey solana.PublicKey) bool {
+	for _, memoProgramID := range memoProgramIDs {
+		if progKey.Equals(memoProgramID) {
+			return true
+		}
+	}
+	return false
+}
+
+// @Internal
+// Collects memo and Solana Pay reference keys of a transaction.
+// References are read-only non-signer accounts of instructions,
+// which also includes program accounts like the system program.
+// Those never match a user's reference.
+func decodeDepositReference(instArray *[]DecodedInstruction) DepositReference {
+	reference := DepositReference{References: []string{}}
+	if instArray == nil {
+		return reference
+	}
+
+	added := map[solana.PublicKey]bool{}
+	for _, inst := range *instArray {
+		if inst.InstructionType == InstructionMemo {
+			if memo, ok := inst.Meta.(string); ok && reference.Memo == "" {
+				reference.Memo = strings.TrimSpace(memo)
 			}
+			continue
 		}
 
-		result[i] = DecodedInstruction{
-			InstructionType: InstructionUnknown,
-			Meta:            nil,
+		for _, account := range inst.Accounts {
+			if account.IsSigner ||
+				account.IsWritable ||
+				added[account.PublicKey] {
+				continue
+			}
+			added[account.PublicKey] = true
+			reference.References = append(reference.References, account.PublicKey.String())
 		}
 	}
+	return reference
+}
 
-	return &result, nil
+// @Internal
+// Drops memo and compute budget instructions, which do not
+// change the type of transfer.
+func withoutIgnoredInstructions(instArray *[]DecodedInstruction) *[]DecodedInstruction {
+	result := []DecodedInstruction{}
+	for _, inst := range *instArray {
+		if inst.InstructionType != InstructionMemo &&
+			inst.InstructionType != InstructionComputeBudget {
+			result = append(result, inst)
+		}
+	}
+	return &result
 }
 
 // @Internal
 // Analyse nft transfer from transaction result.
+// Sender and receiver are left empty when no nft is transferred.
 func analyseNftTransfer(txOut *rpc.GetTransactionResult) (*AnalyseNftTransferResult, error) {
 	postBalances := &txOut.Meta.PostTokenBalances
 	nfts := make([]string, len(*postBalances)/2)
@@ -173,6 +278,9 @@ func analyseNftTransfer(txOut *rpc.GetTransactionResult) (*AnalyseNftTransferRes
 			}
 		}
 	}
+	if from == nil || to == nil {
+		return &AnalyseNftTransferResult{Nfts: []string{}}, nil
+	}
 
 	return &AnalyseNftTransferResult{
 		From: *from,
@@ -203,7 +311,8 @@ func isNftTransferInstArray(instArray *[]DecodedInstruction) bool {
 		if i != 0 &&
 			inst.InstructionType != InstructionTokenCreateAccount &&
 			inst.InstructionType != InstructionTokenTransfer &&
-			inst.InstructionType != InstructionTokenTransferChecked {
+			inst.InstructionType != InstructionTokenTransferChecked &&
+			inst.InstructionType != InstructionNftTransferV1 {
 			return false
 		}
 	}
@@ -230,8 +339,8 @@ func isSplTransferInstArray(instArray *[]DecodedInstruction) (*DecodedTransactio
 	}
 
 	if transferTx, ok := (*instArray)[len(*instArray)-1].Meta.(*solana_token.Transfer); ok {
-		for i, supportedSpl := range supportedSplTokens {
-			if i == 0 { // skip sol
+		for _, supportedSpl := range supportedSpls() {
+			if isSolSplMeta(supportedSpl) {
 				continue
 			}
 
@@ -255,8 +364,8 @@ func isSplTransferInstArray(instArray *[]DecodedInstruction) (*DecodedTransactio
 	}
 
 	if transferCheckedTx, ok := (*instArray)[len(*instArray)-1].Meta.(*solana_token.TransferChecked); ok {
-		for i, supportedSpl := range supportedSplTokens {
-			if i == 0 { // skip sol
+		for _, supportedSpl := range supportedSpls() {
+			if isSolSplMeta(supportedSpl) {
 				continue
 			}
 
@@ -282,38 +391,154 @@ func isSplTransferInstArray(instArray *[]DecodedInstruction) (*DecodedTransactio
 	return nil, false
 }
 
+// @Internal
+// Get supported spl token whose treasury token account is the given one.
+func treasuryTokenAccountSpl(account solana.PublicKey) *SplTokenMeta {
+	for _, supportedSpl := range supportedSpls() {
+		if !isSolSplMeta(supportedSpl) &&
+			supportedSpl.TreasuryTokenAccount.Equals(account) {
+			return &supportedSpl
+		}
+	}
+	return nil
+}
+
+// @Internal
+// Sums up transfers crediting treasury among top level and inner
+// instructions, which catches deposits made through other programs
+// via CPI. Participant is the sending wallet when it signed the
+// transaction, and the fee payer otherwise, as program owned accounts
+// can not sign. Returns nil when treasury is not credited or sends
+// funds itself, and fails on credits of several tokens or from several
+// wallets, which can not be attributed to a single deposit.
+func decodeTreasuryDeposit(instArray []DecodedInstruction, payer solana.PublicKey) (*DecodedTransaction, error) {
+	var deposit *DecodedTransaction
+	credit := func(splToken *SplTokenMeta, amount uint64, sender *solana.AccountMeta) error {
+		participant := payer
+		if sender.IsSigner {
+			participant = sender.PublicKey
+		}
+		if deposit == nil {
+			deposit = &DecodedTransaction{
+				TransactionType: TransactionSplDeposit,
+				SplToken:        splToken,
+				Participant:     participant.String(),
+			}
+		} else if !deposit.SplToken.MintAddress.Equals(splToken.MintAddress) {
+			return makeError("decodeTreasuryDeposit", "ambiguous deposit", errors.New("multiple tokens credited to treasury"))
+		} else if deposit.Participant != participant.String() {
+			return makeError("decodeTreasuryDeposit", "ambiguous deposit", errors.New("multiple wallets credited treasury"))
+		}
+		deposit.Lamports += amount
+		return nil
+	}
+
+	for _, inst := range instArray {
+		switch meta := inst.Meta.(type) {
+		case *solana_system.Transfer:
+			if len(meta.AccountMetaSlice) < 2 || meta.Lamports == nil {
+				continue
+			}
+			from, to := meta.AccountMetaSlice[0], meta.AccountMetaSlice[1]
+			if equalPubKeyToTreasury(from.PublicKey) {
+				return nil, nil
+			}
+			if !equalPubKeyToTreasury(to.PublicKey) {
+				continue
+			}
+			solMeta := solSplMeta()
+			if solMeta == nil {
+				continue
+			}
+			if err := credit(solMeta, *meta.Lamports, from); err != nil {
+				return nil, err
+			}
+		case *solana_token.Transfer:
+			if len(meta.Accounts) < 3 || meta.Amount == nil {
+				continue
+			}
+			if treasuryTokenAccountSpl(meta.Accounts[0].PublicKey) != nil {
+				return nil, nil
+			}
+			if splToken := treasuryTokenAccountSpl(meta.Accounts[1].PublicKey); splToken != nil {
+				if err := credit(splToken, *meta.Amount, meta.Accounts[2]); err != nil {
+					return nil, err
+				}
+			}
+		case *solana_token.TransferChecked:
+			if len(meta.Accounts) < 4 || meta.Amount == nil {
+				continue
+			}
+			if treasuryTokenAccountSpl(meta.Accounts[0].PublicKey) != nil {
+				return nil, nil
+			}
+			if splToken := treasuryTokenAccountSpl(meta.Accounts[2].PublicKey); splToken != nil {
+				if err := credit(splToken, *meta.Amount, meta.Accounts[3]); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return deposit, nil
+}
+
 // @External
 // Analyse transaction type.
 func decodeTransactionType(txHashBs58 string) (*DecodedTransaction, error) {
 	txOut, err := getTransaction(txHashBs58)
+	if err != nil {
+		return &DecodedTransaction{
+			TransactionType: TransactionNothing,
+		}, makeError("decodeTransactionType", "failed to get transaction", err)
+	}
+	decoded, err := decodeTransactionResult(txOut)
+	if decoded != nil && txOut.BlockTime != nil {
+		decoded.BlockTime = txOut.BlockTime.Time()
+	}
+	return decoded, err
+}
+
+// @Internal
+// Analyse transaction type of transaction result.
+func decodeTransactionResult(txOut *rpc.GetTransactionResult) (*DecodedTransaction, error) {
 	nothing := DecodedTransaction{
 		TransactionType: TransactionNothing,
 	}
-	if err != nil {
-		return &nothing, makeError("decodeTransactionType", "failed to get transaction", err)
+	if txOut == nil || txOut.Meta == nil || txOut.Transaction == nil {
+		return &nothing, makeError("decodeTransactionResult", "invalid parameter", errors.New("parameter (txOut): incomplete result"))
 	}
 
 	if !isSucceedTransaction(txOut) {
 		return &DecodedTransaction{
 			TransactionType: TransactionNothing,
 			Failed:          true,
-		}, makeError("decodeTransactionType", "failed transaction", errors.New("provided transaction is a failed one"))
+		}, makeError("decodeTransactionResult", "failed transaction", errors.New("provided transaction is a failed one"))
 	}
 
 	tx, err := getDecodedTransaction(txOut)
 	if err != nil {
-		return &nothing, makeError("decodeTransactionType", "failed to decode transaction", err)
+		return &nothing, makeError("decodeTransactionResult", "failed to decode transaction", err)
+	}
+	accounts, err := resolveMessageAccounts(tx, txOut.Meta.LoadedAddresses)
+	if err != nil {
+		return &nothing, makeError("decodeTransactionResult", "failed to resolve accounts", err)
 	}
 
-	decodedInsts, err := decodeTransactionInstructions(tx)
+	decodedInsts, err := decodeTransactionInstructions(tx, accounts)
 	if err != nil {
-		return &nothing, makeError("decodeTransactionType", "failed to decode instructions", err)
+		return &nothing, makeError("decodeTransactionResult", "failed to decode instructions", err)
 	}
+	reference := decodeDepositReference(decodedInsts)
+	allInsts := append(
+		append([]DecodedInstruction{}, *decodedInsts...),
+		decodeInnerInstructions(accounts, txOut.Meta.InnerInstructions)...,
+	)
+	decodedInsts = withoutIgnoredInstructions(decodedInsts)
 
 	if isSolTransferInstArray(decodedInsts) {
 		solTransfer, ok := (*decodedInsts)[0].Meta.(*solana_system.Transfer)
 		if !ok {
-			return &nothing, makeError("decodeTransactionType", "failed to get system transfer meta", errors.New("failed to parse assigned meta"))
+			return &nothing, makeError("decodeTransactionResult", "failed to get system transfer meta", errors.New("failed to parse assigned meta"))
 		}
 
 		var from *solana.PublicKey = solTransfer.AccountMetaSlice[0].PublicKey.ToPointer()
@@ -327,7 +552,7 @@ func decodeTransactionType(txHashBs58 string) (*DecodedTransaction, error) {
 				TransactionType: TransactionSplWithdraw,
 				Lamports:        *solTransfer.Lamports,
 				Participant:     to.String(),
-				SplToken:        &supportedSplTokens[0],
+				SplToken:        solSplMeta(),
 			}, nil
 		}
 
@@ -336,42 +561,52 @@ func decodeTransactionType(txHashBs58 string) (*DecodedTransaction, error) {
 				TransactionType: TransactionSplDeposit,
 				Lamports:        *solTransfer.Lamports,
 				Participant:     from.String(),
-				SplToken:        &supportedSplTokens[0],
+				SplToken:        solSplMeta(),
+				Reference:       reference,
 			}, nil
 		}
 
 		return &nothing, nil
 	}
 
-	fmt.Printf("decode insts: %v", decodedInsts)
-
 	if decodedTx, ok := isSplTransferInstArray(decodedInsts); ok {
+		if decodedTx.TransactionType == TransactionSplDeposit {
+			decodedTx.Reference = reference
+		}
 		return decodedTx, nil
 	}
 
+	var nftErr error
 	if isNftTransferInstArray(decodedInsts) {
 		nftTransfer, err := analyseNftTransfer(txOut)
 		if err != nil {
-			return &nothing, makeError("decodeTransactionType", "failed to analyse nft transfer", err)
-		}
-
-		if equalPubKeyToTreasury(nftTransfer.From) {
+			nftErr = makeError("decodeTransactionResult", "failed to analyse nft transfer", err)
+		} else if equalPubKeyToTreasury(nftTransfer.From) {
 			return &DecodedTransaction{
 				TransactionType: TransactionNftWithdraw,
 				Nfts:            nftTransfer.Nfts,
 				Participant:     nftTransfer.To.String(),
 			}, nil
-		}
-
-		if equalPubKeyToTreasury(nftTransfer.To) {
+		} else if equalPubKeyToTreasury(nftTransfer.To) {
 			return &DecodedTransaction{
 				TransactionType: TransactionNftDeposit,
 				Nfts:            nftTransfer.Nfts,
 				Participant:     nftTransfer.From.String(),
+				Reference:       reference,
 			}, nil
 		}
+	}
 
-		return &nothing, nil
+	deposit, err := decodeTreasuryDeposit(allInsts, accounts[0].PublicKey)
+	if err != nil {
+		return &nothing, makeError("decodeTransactionResult", "failed to decode treasury deposit", err)
+	}
+	if deposit != nil {
+		deposit.Reference = reference
+		return deposit, nil
+	}
+	if nftErr != nil {
+		return &nothing, nftErr
 	}
 
 	return &nothing, nil
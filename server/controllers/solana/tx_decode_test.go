@@ -0,0 +1,193 @@
+package solana
+
+import (
+	"testing"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/gagliardetto/solana-go"
+	solana_system "github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestDecodeDepositReference(t *testing.T) {
+	initializeTxDecode()
+	from := solana.NewWallet().PublicKey()
+	to := solana.NewWallet().PublicKey()
+	reference := solana.NewWallet().PublicKey()
+
+	transfer := solana_system.NewTransferInstruction(1000, from, to).Build()
+	data, err := transfer.Data()
+	if err != nil {
+		t.Fatalf("failed to encode transfer: %v", err)
+	}
+	accounts := append(transfer.Accounts(), solana.Meta(reference))
+
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{
+			solana.NewInstruction(solana.SystemProgramID, accounts, data),
+			solana.NewInstruction(solana.MemoProgramID, solana.AccountMetaSlice{}, []byte(" ab12cd ")),
+		},
+		solana.Hash{},
+		solana.TransactionPayer(from),
+	)
+	if err != nil {
+		t.Fatalf("failed to build transaction: %v", err)
+	}
+
+	messageAccounts, err := resolveMessageAccounts(tx, rpc.LoadedAddresses{})
+	if err != nil {
+		t.Fatalf("failed to resolve accounts: %v", err)
+	}
+	decodedInsts, err := decodeTransactionInstructions(tx, messageAccounts)
+	if err != nil {
+		t.Fatalf("failed to decode instructions: %v", err)
+	}
+	if (*decodedInsts)[1].InstructionType != InstructionMemo {
+		t.Fatalf("memo instruction not recognised: %v", (*decodedInsts)[1])
+	}
+
+	decoded := decodeDepositReference(decodedInsts)
+	if decoded.Memo != "ab12cd" {
+		t.Fatalf("unexpected memo: %q", decoded.Memo)
+	}
+	found := false
+	for _, candidate := range decoded.References {
+		if candidate == from.String() || candidate == to.String() {
+			t.Fatalf("writable accounts should not be references: %v", decoded.References)
+		}
+		found = found || candidate == reference.String()
+	}
+	if !found {
+		t.Fatalf("reference not found: %v", decoded.References)
+	}
+
+	if !isSolTransferInstArray(withoutIgnoredInstructions(decodedInsts)) {
+		t.Fatal("memo should not change sol transfer shape")
+	}
+}
+
+// Decodes versioned and CPI deposits from testdata. The fixtures
+// are built by hand like the token program ones, and are replaced by
+// mainnet v0, lookup table and CPI transactions recorded with
+// `TestRecordTxFixtures`, naming the wallet they were sent to as
+// `treasury` of their expectation.
+func TestDecodeVersionedTransactions(t *testing.T) {
+	if err := initialize(&InitParam{
+		TreasuryBs58: fixtureTreasuryBs58,
+		Cluster:      ClusterDevNet,
+	}); err != nil {
+		t.Fatalf("failed to initialize: %v", err)
+	}
+	usdc := solana.MustPublicKeyFromBase58("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v")
+	setSplTokenLoader(func() ([]SplTokenMeta, error) {
+		return []SplTokenMeta{
+			{MintAddress: solana.MustPublicKeyFromBase58(config.SOL_SPL_ADDRESS), Keyword: "SOL", Decimals: 9},
+			{MintAddress: usdc, Keyword: "USDC", Decimals: 6},
+		}, nil
+	})
+	defer setSplTokenLoader(nil)
+
+	for name, expectation := range map[string]txFixtureExpectation{
+		"v0_sol_deposit.json": {decoded: DecodedTransaction{
+			TransactionType: TransactionSplDeposit,
+			Participant:     fixtureUser,
+			Lamports:        150000000,
+			SplToken:        &SplTokenMeta{Keyword: "SOL"},
+			Reference: DepositReference{
+				Memo:       "9f2c41",
+				References: []string{"RefXjwAW3dJ5GAdHZkvnG4iAT9nMjhGmK8usmvGCs1z"},
+			},
+		}},
+		"v0_spl_deposit.json": {decoded: DecodedTransaction{
+			TransactionType: TransactionSplDeposit,
+			Participant:     fixtureUser,
+			Lamports:        42500000,
+			SplToken:        &SplTokenMeta{Keyword: "USDC"},
+		}},
+		"cpi_sol_deposit.json": {decoded: DecodedTransaction{
+			TransactionType: TransactionSplDeposit,
+			Participant:     fixtureUser,
+			Lamports:        80000000,
+			SplToken:        &SplTokenMeta{Keyword: "SOL"},
+		}},
+		// Swap output is sent by the pool, attributed to the fee payer.
+		"cpi_spl_deposit.json": {decoded: DecodedTransaction{
+			TransactionType: TransactionSplDeposit,
+			Participant:     fixtureUser,
+			Lamports:        71250000,
+			SplToken:        &SplTokenMeta{Keyword: "USDC"},
+		}},
+		"v0_swap.json": {decoded: DecodedTransaction{
+			TransactionType: TransactionNothing,
+		}},
+	} {
+		expected := expectation.decoded
+		decoded := decodeTxFixture(t, name, expectation)
+		if decoded.TransactionType != expected.TransactionType ||
+			decoded.Participant != expected.Participant ||
+			decoded.Lamports != expected.Lamports {
+			t.Fatalf("%s: unexpected decoded transaction: %+v", name, decoded)
+		}
+		if expected.SplToken != nil &&
+			(decoded.SplToken == nil || decoded.SplToken.Keyword != expected.SplToken.Keyword) {
+			t.Fatalf("%s: unexpected spl token: %v", name, decoded.SplToken)
+		}
+		if decoded.Reference.Memo != expected.Reference.Memo {
+			t.Fatalf("%s: unexpected memo: %q", name, decoded.Reference.Memo)
+		}
+		for _, reference := range expected.Reference.References {
+			found := false
+			for _, candidate := range decoded.Reference.References {
+				found = found || candidate == reference
+			}
+			if !found {
+				t.Fatalf("%s: reference %s not found: %v", name, reference, decoded.Reference.References)
+			}
+		}
+	}
+}
+
+func TestResolveMessageAccounts(t *testing.T) {
+	txOut := loadTxFixture(t, "cpi_spl_deposit.json")
+	tx, err := getDecodedTransaction(txOut)
+	if err != nil {
+		t.Fatalf("failed to decode transaction: %v", err)
+	}
+
+	if _, err := resolveMessageAccounts(tx, rpc.LoadedAddresses{}); err == nil {
+		t.Fatal("lookups without loaded addresses should fail")
+	}
+
+	loaded := txOut.Meta.LoadedAddresses
+	accounts, err := resolveMessageAccounts(tx, loaded)
+	if err != nil {
+		t.Fatalf("failed to resolve accounts: %v", err)
+	}
+	numStatic := len(tx.Message.AccountKeys)
+	if len(accounts) != numStatic+len(loaded.Writable)+len(loaded.ReadOnly) {
+		t.Fatalf("unexpected number of accounts: %d", len(accounts))
+	}
+	if !accounts[0].IsSigner || !accounts[0].IsWritable {
+		t.Fatalf("fee payer should be a writable signer: %+v", accounts[0])
+	}
+	for i, key := range loaded.Writable {
+		account := accounts[numStatic+i]
+		if !account.PublicKey.Equals(key) || !account.IsWritable || account.IsSigner {
+			t.Fatalf("unexpected writable lookup account: %+v", account)
+		}
+	}
+	for i, key := range loaded.ReadOnly {
+		account := accounts[numStatic+len(loaded.Writable)+i]
+		if !account.PublicKey.Equals(key) || account.IsWritable || account.IsSigner {
+			t.Fatalf("unexpected readonly lookup account: %+v", account)
+		}
+	}
+
+	inner := decodeInnerInstructions(accounts, txOut.Meta.InnerInstructions)
+	if len(inner) != 3 ||
+		inner[0].InstructionType != InstructionUnknown ||
+		inner[1].InstructionType != InstructionTokenTransfer ||
+		inner[2].InstructionType != InstructionTokenTransferChecked {
+		t.Fatalf("unexpected inner instructions: %+v", inner)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/metrics"
 	"github.com/gagliardetto/solana-go"
 	solana_token_account "github.com/gagliardetto/solana-go/programs/associated-token-account"
 	solana_system "github.com/gagliardetto/solana-go/programs/system"
@@ -44,7 +45,9 @@ var SEND_TRANSACTION_CONDITIONS = []string{
 // Returns solana recent blockhash. Try once.
 func getRecentBlockHashOnce() (*solana.Hash, error) {
 	client := newClient()
+	start := time.Now()
 	recent, err := client.GetRecentBlockhash(context.TODO(), rpc.CommitmentFinalized)
+	metrics.ObserveSolanaRpc("getRecentBlockhash", start, err)
 	if err != nil {
 		return nil, makeError("getRecentBlockHashOnce", "failing to get recent block hash...", err)
 	}
@@ -78,13 +81,7 @@ func buildSendLamportsTx(to solana.PublicKey, lamports uint64) (*solana.Transact
 		return nil, makeError("buildSendLamportsTx", "failed to get recent blockhash", err)
 	}
 	tx, err := solana.NewTransaction(
-		[]solana.Instruction{
-			solana_system.NewTransferInstruction(
-				lamports,
-				*treasuryPubKey(),
-				to,
-			).Build(),
-		},
+		lamportsTransferInsts(to, lamports),
 		*recentBlockHash,
 		solana.TransactionPayer(*treasuryPubKey()),
 	)
@@ -95,6 +92,18 @@ func buildSendLamportsTx(to solana.PublicKey, lamports uint64) (*solana.Transact
 	return tx, nil
 }
 
+// @Internal
+// Instructions to send lamports from treasury.
+func lamportsTransferInsts(to solana.PublicKey, lamports uint64) []solana.Instruction {
+	return []solana.Instruction{
+		solana_system.NewTransferInstruction(
+			lamports,
+			*treasuryPubKey(),
+			to,
+		).Build(),
+	}
+}
+
 // @Internal
 // Get associated token address of treasury.
 func treasuryTokenAccount(mint solana.PublicKey) solana.PublicKey {
@@ -120,11 +129,32 @@ func buildSendSplTokensTx(to solana.PublicKey, mint solana.PublicKey, amount uin
 		return nil, makeError("buildSendSplTokensTx", "failed to get recent blockhash", err)
 	}
 
+	insts, err := splTransferInsts(to, mint, amount)
+	if err != nil {
+		return nil, makeError("buildSendSplTokensTx", "failed to get transfer instructions", err)
+	}
+
+	tx, err := solana.NewTransaction(
+		insts,
+		*recentBlockHash,
+		solana.TransactionPayer(*treasuryPubKey()),
+	)
+	if err != nil {
+		return nil, makeError("buildSendSplTokensTx", "failed to build transaction object", err)
+	}
+
+	return tx, nil
+}
+
+// @Internal
+// Instructions to send spl tokens from treasury, creating
+// destination token account if not exists.
+func splTransferInsts(to solana.PublicKey, mint solana.PublicKey, amount uint64) ([]solana.Instruction, error) {
 	insts := []solana.Instruction{}
 	destTokenAccount := tokenAccount(to, mint)
 	balance, err := getBalanceRetry(destTokenAccount)
 	if err != nil {
-		return nil, makeError("buildSendSplTokensTx", "failed to get balance of dest token account", err)
+		return nil, makeError("splTransferInsts", "failed to get balance of dest token account", err)
 	}
 	if balance == 0 {
 		insts = append(
@@ -147,16 +177,45 @@ func buildSendSplTokensTx(to solana.PublicKey, mint solana.PublicKey, amount uin
 		).Build(),
 	)
 
-	tx, err := solana.NewTransaction(
-		insts,
-		*recentBlockHash,
-		solana.TransactionPayer(*treasuryPubKey()),
+	return insts, nil
+}
+
+// @Internal
+// Instructions to send Token-2022 spl tokens from treasury,
+// creating destination token account if not exists.
+func token2022TransferInsts(to solana.PublicKey, meta *SplTokenMeta, amount uint64) ([]solana.Instruction, error) {
+	insts := []solana.Instruction{}
+	destTokenAccount := associatedTokenAccount(to, meta.MintAddress, meta.TokenProgram)
+	balance, err := getBalanceRetry(destTokenAccount)
+	if err != nil {
+		return nil, makeError("token2022TransferInsts", "failed to get balance of dest token account", err)
+	}
+	if balance == 0 {
+		insts = append(
+			insts,
+			createAssociatedTokenAccountInst(
+				*treasuryPubKey(),
+				to,
+				meta.MintAddress,
+				meta.TokenProgram,
+			),
+		)
+	}
+	transfer, err := transferCheckedInst(
+		meta.TokenProgram,
+		amount,
+		uint8(meta.Decimals),
+		meta.TreasuryTokenAccount,
+		meta.MintAddress,
+		destTokenAccount,
+		*treasuryPubKey(),
 	)
 	if err != nil {
-		return nil, makeError("buildSendSplTokensTx", "failed to build transaction object", err)
+		return nil, makeError("token2022TransferInsts", "failed to build transfer checked", err)
 	}
+	insts = append(insts, transfer)
 
-	return tx, nil
+	return insts, nil
 }
 
 // @Internal
@@ -177,7 +236,9 @@ func signAndSendTx(tx *solana.Transaction) (*solana.Signature, error) {
 	}
 
 	client := newClient()
+	start := time.Now()
 	sig, err := client.SendTransaction(context.TODO(), tx)
+	metrics.ObserveSolanaRpc("sendTransaction", start, err)
 	if err != nil {
 		return nil, makeError("signAndSendTx", "failed to send transaction", err)
 	}
@@ -324,26 +385,69 @@ func getBalanceRetry(account solana.PublicKey) (uint64, error) {
 // @Internal
 // Gets instructions to transfer mint to account.
 func getInstsForTransfer(to solana.PublicKey, mint solana.PublicKey) (*[]solana.Instruction, error) {
-	instructions := []solana.Instruction{}
-	toTokenAccount, _, err := solana.FindAssociatedTokenAddress(to, mint)
+	asset, err := getNftAsset(mint)
 	if err != nil {
-		return nil, makeError("getInstsForTransfer", "failed to get associated token address of dest", err)
+		return nil, makeError("getInstsForTransfer", "failed to get nft asset", err)
 	}
-	fromTokenAccount, _, err := solana.FindAssociatedTokenAddress(*treasuryPubKey(), mint)
-	if err != nil {
-		return nil, makeError("getInstsForTransfer", "failed to get associated token address of source", err)
+	if asset.Programmable {
+		return nftAssetTransferInsts(asset, to, false)
 	}
 
-	lamports, err := getBalanceRetry(toTokenAccount)
+	lamports, err := getBalanceRetry(associatedTokenAccount(to, mint, asset.TokenProgram))
 	if err != nil {
 		return nil, makeError("getInstsForTransfer", "failed to get balance of the token accoutn", err)
 	}
+	return nftAssetTransferInsts(asset, to, lamports == 0)
+}
 
-	if lamports == 0 {
+// @Internal
+// Instructions to transfer NFT asset from treasury to account.
+// Programmable NFTs are moved by token metadata `TransferV1`,
+// which creates destination token account by itself. Token-2022
+// NFTs are moved by transfer checked, others by token transfer.
+func nftAssetTransferInsts(asset *NftAsset, to solana.PublicKey, createDestination bool) (*[]solana.Instruction, error) {
+	if asset == nil {
+		return nil, makeError("nftAssetTransferInsts", "invalid parameter", errors.New("parameter (asset): null pointer"))
+	}
+	if asset.Programmable {
+		return &[]solana.Instruction{
+			pnftTransferInst(asset, *treasuryPubKey(), to),
+		}, nil
+	}
+
+	instructions := []solana.Instruction{}
+	toTokenAccount := associatedTokenAccount(to, asset.Mint, asset.TokenProgram)
+	fromTokenAccount := associatedTokenAccount(*treasuryPubKey(), asset.Mint, asset.TokenProgram)
+	if asset.TokenProgram.Equals(Token2022ProgramID) {
+		if createDestination {
+			instructions = append(instructions, createAssociatedTokenAccountInst(
+				*treasuryPubKey(),
+				to,
+				asset.Mint,
+				asset.TokenProgram,
+			))
+		}
+		transfer, err := transferCheckedInst(
+			asset.TokenProgram,
+			1,
+			0,
+			fromTokenAccount,
+			asset.Mint,
+			toTokenAccount,
+			*treasuryPubKey(),
+		)
+		if err != nil {
+			return nil, makeError("nftAssetTransferInsts", "failed to build transfer checked", err)
+		}
+		instructions = append(instructions, transfer)
+		return &instructions, nil
+	}
+
+	if createDestination {
 		instructions = append(instructions, solana_token_account.Create{
 			Payer:  *treasuryPubKey(),
 			Wallet: to,
-			Mint:   mint,
+			Mint:   asset.Mint,
 		}.Build())
 	}
 
@@ -361,14 +465,9 @@ func getInstsForTransfer(to solana.PublicKey, mint solana.PublicKey) (*[]solana.
 // @Internal
 // Builds send nft transaction.
 func buildSendNftsTx(to solana.PublicKey, mints *[]solana.PublicKey) (*solana.Transaction, error) {
-	instructions := []solana.Instruction{}
-	for _, mint := range *mints {
-		insts, err := getInstsForTransfer(to, mint)
-		if err != nil {
-			return nil, makeError("buildSendNftsTx", fmt.Sprintf("failed to get insts for mint(%v) to account(%v)", mint.String(), to.String()), err)
-		}
-
-		instructions = append(instructions, *insts...)
+	instructions, err := nftTransferInsts(to, mints)
+	if err != nil {
+		return nil, makeError("buildSendNftsTx", "failed to get transfer instructions", err)
 	}
 
 	recentBlockHash, err := getRecentBlockHashRetry()
@@ -388,6 +487,21 @@ func buildSendNftsTx(to solana.PublicKey, mints *[]solana.PublicKey) (*solana.Tr
 	return tx, nil
 }
 
+// @Internal
+// Instructions to send nfts from treasury.
+func nftTransferInsts(to solana.PublicKey, mints *[]solana.PublicKey) ([]solana.Instruction, error) {
+	instructions := []solana.Instruction{}
+	for _, mint := range *mints {
+		insts, err := getInstsForTransfer(to, mint)
+		if err != nil {
+			return nil, makeError("nftTransferInsts", fmt.Sprintf("failed to get insts for mint(%v) to account(%v)", mint.String(), to.String()), err)
+		}
+
+		instructions = append(instructions, *insts...)
+	}
+	return instructions, nil
+}
+
 // @Internal
 // Convert string array to pubkey array.
 func convertBs58sToPubKeys(bs58s *[]string) (*[]solana.PublicKey, error) {
@@ -0,0 +1,284 @@
+package solana
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/metrics"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// Compute budget program instruction discriminators.
+const (
+	COMPUTE_BUDGET_SET_UNIT_LIMIT = byte(2)
+	COMPUTE_BUDGET_SET_UNIT_PRICE = byte(3)
+)
+
+// Compute unit limit of a transaction.
+const MAX_COMPUTE_UNIT_LIMIT = uint32(1400000)
+
+// @Internal
+// Builds compute budget instruction setting compute unit limit.
+func computeUnitLimitInst(units uint32) solana.Instruction {
+	data := make([]byte, 5)
+	data[0] = COMPUTE_BUDGET_SET_UNIT_LIMIT
+	binary.LittleEndian.PutUint32(data[1:], units)
+	return solana.NewInstruction(solana.ComputeBudget, solana.AccountMetaSlice{}, data)
+}
+
+// @Internal
+// Builds compute budget instruction setting priority fee
+// in micro lamports per compute unit.
+func computeUnitPriceInst(microLamports uint64) solana.Instruction {
+	data := make([]byte, 9)
+	data[0] = COMPUTE_BUDGET_SET_UNIT_PRICE
+	binary.LittleEndian.PutUint64(data[1:], microLamports)
+	return solana.NewInstruction(solana.ComputeBudget, solana.AccountMetaSlice{}, data)
+}
+
+// @Internal
+// Get priority fees paid in recent slots by transactions
+// writing treasury, in micro lamports per compute unit.
+func getRecentPriorityFees() ([]uint64, error) {
+	var out []struct {
+		Slot              uint64 `json:"slot"`
+		PrioritizationFee uint64 `json:"prioritizationFee"`
+	}
+	client := newClient()
+	start := time.Now()
+	err := client.RPCCallForInto(
+		context.TODO(),
+		&out,
+		"getRecentPrioritizationFees",
+		[]interface{}{[]string{treasuryPubKey().String()}},
+	)
+	metrics.ObserveSolanaRpc("getRecentPrioritizationFees", start, err)
+	if err != nil {
+		return nil, makeError("getRecentPriorityFees", "failed to get recent prioritization fees", err)
+	}
+
+	fees := make([]uint64, len(out))
+	for i, fee := range out {
+		fees[i] = fee.PrioritizationFee
+	}
+	return fees, nil
+}
+
+// @Internal
+// Priority fee for the `attempt`th broadcast, starting from 0.
+// Percentile of recent fees, escalated per rebroadcast and
+// clamped to configured bounds.
+func priorityFee(recentFees []uint64, attempt uint) uint64 {
+	fee := uint64(0)
+	if len(recentFees) > 0 {
+		sorted := append([]uint64{}, recentFees...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		index := (len(sorted) - 1) * config.WITHDRAW_PRIORITY_FEE_PERCENTILE / 100
+		fee = sorted[index]
+	}
+	if fee < config.WITHDRAW_PRIORITY_FEE_MIN {
+		fee = config.WITHDRAW_PRIORITY_FEE_MIN
+	}
+	for i := uint(0); i < attempt && fee < config.WITHDRAW_PRIORITY_FEE_MAX; i++ {
+		fee *= config.WITHDRAW_PRIORITY_FEE_ESCALATION
+	}
+	if fee > config.WITHDRAW_PRIORITY_FEE_MAX {
+		fee = config.WITHDRAW_PRIORITY_FEE_MAX
+	}
+	return fee
+}
+
+// @Internal
+// Returns latest blockhash with its last valid block height. Retries.
+func getLatestBlockhashRetry() (*rpc.LatestBlockhashResult, error) {
+	var finalError error = errors.New("")
+	client := newClient()
+	for i := 0; i < GET_RECENT_BLOCKHASH_RETRY; i = i + 1 {
+		start := time.Now()
+		latest, err := client.GetLatestBlockhash(context.TODO(), rpc.CommitmentFinalized)
+		metrics.ObserveSolanaRpc("getLatestBlockhash", start, err)
+		if err == nil && latest.Value != nil {
+			return latest.Value, nil
+		}
+		if err == nil {
+			err = errors.New("empty latest blockhash")
+		}
+		finalError = fmt.Errorf("%v\n\r%v", finalError.Error(), err.Error())
+		if i < GET_RECENT_BLOCKHASH_RETRY-1 {
+			time.Sleep(GET_RECENT_BLOCKHASH_WAIT * time.Second)
+		}
+	}
+
+	return nil, makeError("getLatestBlockhashRetry", "failing to get latest blockhash over several retries", finalError)
+}
+
+// @Internal
+// Instructions of a withdrawal, without compute budget.
+func withdrawalInsts(param *WithdrawalRequest) ([]solana.Instruction, error) {
+	to, err := solana.PublicKeyFromBase58(param.To)
+	if err != nil {
+		return nil, makeError("withdrawalInsts", "failed to get public key from base58", err)
+	}
+
+	if len(param.Nfts) > 0 {
+		mints, err := convertBs58sToPubKeys(&param.Nfts)
+		if err != nil {
+			return nil, makeError("withdrawalInsts", "failed to convert bs58 mints to pubkeys", err)
+		}
+		return nftTransferInsts(to, mints)
+	}
+
+	if param.Mint == config.SOL_SPL_ADDRESS {
+		return lamportsTransferInsts(to, param.Amount), nil
+	}
+
+	mint, err := solana.PublicKeyFromBase58(param.Mint)
+	if err != nil {
+		return nil, makeError("withdrawalInsts", "failed to get public key from base58 of mint address", err)
+	}
+	if meta := splTokenMetaOf(mint); meta != nil &&
+		meta.TokenProgram.Equals(Token2022ProgramID) {
+		return token2022TransferInsts(to, meta, param.Amount)
+	}
+	return splTransferInsts(to, mint, param.Amount)
+}
+
+// @Internal
+// Compute unit limit of instructions. Programmable NFT transfers
+// validate rule sets and cost far more than token transfers.
+func computeUnitLimit(insts []solana.Instruction) uint32 {
+	unitLimit := uint32(0)
+	for _, inst := range insts {
+		if inst.ProgramID().Equals(solana.TokenMetadataProgramID) {
+			unitLimit += config.WITHDRAW_PNFT_COMPUTE_UNITS
+		} else {
+			unitLimit += config.WITHDRAW_COMPUTE_UNITS_PER_INSTRUCTION
+		}
+	}
+	if unitLimit > MAX_COMPUTE_UNIT_LIMIT {
+		unitLimit = MAX_COMPUTE_UNIT_LIMIT
+	}
+	return unitLimit
+}
+
+// @Internal
+// Builds and signs a withdrawal with priority fee for the
+// `attempt`th broadcast. Signature is known before broadcast,
+// so that callers persist it first and never lose track of a
+// transaction which may land.
+func signWithdrawal(param *WithdrawalRequest, attempt uint) (*SignedWithdrawal, error) {
+	if param == nil {
+		return nil, makeError("signWithdrawal", "invalid parameter", errors.New("parameter (param): null pointer"))
+	}
+
+	// 1. Build transfer instructions.
+	insts, err := withdrawalInsts(param)
+	if err != nil {
+		return nil, makeError("signWithdrawal", "failed to build withdrawal instructions", err)
+	}
+
+	// 2. Prepend compute budget.
+	recentFees, err := getRecentPriorityFees()
+	if err != nil {
+		recentFees = []uint64{}
+	}
+	fee := priorityFee(recentFees, attempt)
+	unitLimit := computeUnitLimit(insts)
+	insts = append(
+		[]solana.Instruction{
+			computeUnitLimitInst(unitLimit),
+			computeUnitPriceInst(fee),
+		},
+		insts...,
+	)
+
+	// 3. Build and sign transaction.
+	latest, err := getLatestBlockhashRetry()
+	if err != nil {
+		return nil, makeError("signWithdrawal", "failed to get latest blockhash", err)
+	}
+	tx, err := solana.NewTransaction(
+		insts,
+		latest.Blockhash,
+		solana.TransactionPayer(*treasuryPubKey()),
+	)
+	if err != nil {
+		return nil, makeError("signWithdrawal", "failed to build transaction object", err)
+	}
+	if _, err := tx.Sign(
+		func(key solana.PublicKey) *solana.PrivateKey {
+			if equalPubKeyToTreasury(key) {
+				return treasuryKeyPair()
+			}
+			return nil
+		},
+	); err != nil {
+		return nil, makeError("signWithdrawal", "failed to sign transaction", err)
+	}
+
+	return &SignedWithdrawal{
+		Tx:                   tx,
+		Signature:            convertSigToBs58(tx.Signatures[0]),
+		LastValidBlockHeight: latest.LastValidBlockHeight,
+		PriorityFee:          fee,
+	}, nil
+}
+
+// @Internal
+// Broadcasts a signed transaction.
+func broadcastTx(tx *solana.Transaction) error {
+	if tx == nil {
+		return makeError("broadcastTx", "invalid parameter", errors.New("parameter (tx): null pointer"))
+	}
+	client := newClient()
+	start := time.Now()
+	_, err := client.SendTransaction(context.TODO(), tx)
+	metrics.ObserveSolanaRpc("sendTransaction", start, err)
+	if err != nil {
+		return makeError("broadcastTx", "failed to send transaction", err)
+	}
+	return nil
+}
+
+// @Internal
+// Get status of a signature, nil if the cluster does not know it.
+func getSignatureStatus(signature string) (*rpc.SignatureStatusesResult, error) {
+	sig, err := solana.SignatureFromBase58(signature)
+	if err != nil {
+		return nil, makeError("getSignatureStatus", "invalid signature", err)
+	}
+
+	client := newClient()
+	start := time.Now()
+	out, err := client.GetSignatureStatuses(context.TODO(), true, sig)
+	metrics.ObserveSolanaRpc("getSignatureStatuses", start, err)
+	if errors.Is(err, rpc.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, makeError("getSignatureStatus", "failed to get signature statuses", err)
+	}
+	if len(out.Value) == 0 {
+		return nil, nil
+	}
+	return out.Value[0], nil
+}
+
+// @Internal
+// Get finalized block height.
+func getFinalizedBlockHeight() (uint64, error) {
+	client := newClient()
+	start := time.Now()
+	height, err := client.GetBlockHeight(context.TODO(), rpc.CommitmentFinalized)
+	metrics.ObserveSolanaRpc("getBlockHeight", start, err)
+	if err != nil {
+		return 0, makeError("getFinalizedBlockHeight", "failed to get block height", err)
+	}
+	return height, nil
+}
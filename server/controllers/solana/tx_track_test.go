@@ -0,0 +1,71 @@
+package solana
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/gagliardetto/solana-go"
+)
+
+func TestComputeBudgetInsts(t *testing.T) {
+	limit := computeUnitLimitInst(80000)
+	data, err := limit.Data()
+	if err != nil {
+		t.Fatalf("failed to encode unit limit: %v", err)
+	}
+	if !limit.ProgramID().Equals(solana.ComputeBudget) ||
+		len(data) != 5 ||
+		data[0] != COMPUTE_BUDGET_SET_UNIT_LIMIT ||
+		binary.LittleEndian.Uint32(data[1:]) != 80000 {
+		t.Fatalf("unexpected unit limit instruction: %v", data)
+	}
+
+	price := computeUnitPriceInst(12345)
+	data, err = price.Data()
+	if err != nil {
+		t.Fatalf("failed to encode unit price: %v", err)
+	}
+	if !price.ProgramID().Equals(solana.ComputeBudget) ||
+		len(data) != 9 ||
+		data[0] != COMPUTE_BUDGET_SET_UNIT_PRICE ||
+		binary.LittleEndian.Uint64(data[1:]) != 12345 {
+		t.Fatalf("unexpected unit price instruction: %v", data)
+	}
+}
+
+func TestPriorityFee(t *testing.T) {
+	min, max := config.WITHDRAW_PRIORITY_FEE_MIN, config.WITHDRAW_PRIORITY_FEE_MAX
+	percentile := config.WITHDRAW_PRIORITY_FEE_PERCENTILE
+	escalation := config.WITHDRAW_PRIORITY_FEE_ESCALATION
+	defer func() {
+		config.WITHDRAW_PRIORITY_FEE_MIN, config.WITHDRAW_PRIORITY_FEE_MAX = min, max
+		config.WITHDRAW_PRIORITY_FEE_PERCENTILE = percentile
+		config.WITHDRAW_PRIORITY_FEE_ESCALATION = escalation
+	}()
+	config.WITHDRAW_PRIORITY_FEE_MIN = 100
+	config.WITHDRAW_PRIORITY_FEE_MAX = 10000
+	config.WITHDRAW_PRIORITY_FEE_PERCENTILE = 75
+	config.WITHDRAW_PRIORITY_FEE_ESCALATION = 2
+
+	recentFees := []uint64{5000, 0, 1000, 2000, 3000}
+	for _, test := range []struct {
+		fees    []uint64
+		attempt uint
+		fee     uint64
+	}{
+		{fees: []uint64{}, attempt: 0, fee: 100},
+		{fees: []uint64{0, 10}, attempt: 0, fee: 100},
+		{fees: recentFees, attempt: 0, fee: 3000},
+		{fees: recentFees, attempt: 1, fee: 6000},
+		{fees: recentFees, attempt: 2, fee: 10000},
+		{fees: recentFees, attempt: 64, fee: 10000},
+	} {
+		if fee := priorityFee(test.fees, test.attempt); fee != test.fee {
+			t.Fatalf("priority fee of %v at attempt %d: expected %d, got %d", test.fees, test.attempt, test.fee, fee)
+		}
+	}
+	if recentFees[0] != 5000 {
+		t.Fatalf("recent fees should be left unsorted")
+	}
+}
@@ -0,0 +1,75 @@
+package solana
+
+import (
+	"context"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/metrics"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// @Internal
+// Get finalized signatures of transactions referencing `address`,
+// newest first. Pages backwards from `before` and stops at `until`,
+// both optional.
+func getSignaturesForAddress(
+	address solana.PublicKey,
+	before string,
+	until string,
+	limit int,
+) ([]*rpc.TransactionSignature, error) {
+	opts := &rpc.GetSignaturesForAddressOpts{
+		Limit:      &limit,
+		Commitment: rpc.CommitmentFinalized,
+	}
+	if before != "" {
+		signature, err := solana.SignatureFromBase58(before)
+		if err != nil {
+			return nil, makeError("getSignaturesForAddress", "invalid before signature", err)
+		}
+		opts.Before = signature
+	}
+	if until != "" {
+		signature, err := solana.SignatureFromBase58(until)
+		if err != nil {
+			return nil, makeError("getSignaturesForAddress", "invalid until signature", err)
+		}
+		opts.Until = signature
+	}
+
+	client := newClient()
+	start := time.Now()
+	signatures, err := client.GetSignaturesForAddressWithOpts(
+		context.TODO(),
+		address,
+		opts,
+	)
+	metrics.ObserveSolanaRpc("getSignaturesForAddress", start, err)
+	if err != nil {
+		return nil, makeError("getSignaturesForAddress", "failed to get signatures", err)
+	}
+
+	return signatures, nil
+}
+
+// @Internal
+// Treasury wallet and its token accounts of supported spl tokens.
+// NFT transfers into already existing treasury token accounts
+// reference none of them and are only caught by the webhook.
+func treasuryWatchAddresses() []solana.PublicKey {
+	addresses := []solana.PublicKey{*treasuryPubKey()}
+	for _, meta := range supportedSpls() {
+		duplicated := false
+		for _, address := range addresses {
+			if address.Equals(meta.TreasuryTokenAccount) {
+				duplicated = true
+				break
+			}
+		}
+		if !duplicated {
+			addresses = append(addresses, meta.TreasuryTokenAccount)
+		}
+	}
+	return addresses
+}
@@ -1,6 +1,10 @@
 package solana
 
-import "github.com/gagliardetto/solana-go"
+import (
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
 
 type InitParam struct {
 	TreasuryBs58 string
@@ -44,6 +48,15 @@ type SplTokenMeta struct {
 	Decimals             int              `json:"decimals"`
 	Keyword              string           `json:"keyword"`
 	Image                string           `json:"image"`
+	DepositEnabled       bool             `json:"depositEnabled"`
+	WithdrawEnabled      bool             `json:"withdrawEnabled"`
+	MinDeposit           uint64           `json:"minDeposit"`  // In token base units
+	MinWithdraw          int64            `json:"minWithdraw"` // In chips
+	WithdrawFee          int64            `json:"withdrawFee"` // In chips
+	PriceSource          string           `json:"priceSource"`
+	TokenProgram         solana.PublicKey `json:"tokenProgram"` // Token or Token-2022 program
+	HotWalletMin         uint64           `json:"hotWalletMin"` // In token base units, 0 for no threshold
+	HotWalletMax         uint64           `json:"hotWalletMax"` // In token base units, 0 for no threshold
 }
 
 type DecodedTransaction struct {
@@ -53,6 +66,15 @@ type DecodedTransaction struct {
 	Nfts            []string
 	Failed          bool
 	SplToken        *SplTokenMeta
+	Reference       DepositReference
+	BlockTime       time.Time
+}
+
+// Memo and Solana Pay reference keys attached to a deposit,
+// attributing it to a user other than the sending wallet.
+type DepositReference struct {
+	Memo       string
+	References []string
 }
 
 type InstructionType string
@@ -62,12 +84,40 @@ const (
 	InstructionTokenTransfer        InstructionType = "instruction-token-transfer"
 	InstructionTokenTransferChecked InstructionType = "instruction-token-transfer-checked"
 	InstructionTokenCreateAccount   InstructionType = "instruction-token-create-account"
+	InstructionNftTransferV1        InstructionType = "instruction-nft-transfer-v1"
+	InstructionMemo                 InstructionType = "instruction-memo"
+	InstructionComputeBudget        InstructionType = "instruction-compute-budget"
 	InstructionUnknown              InstructionType = "instruction-unknown"
 )
 
 type DecodedInstruction struct {
 	InstructionType InstructionType
 	Meta            interface{}
+	Accounts        []*solana.AccountMeta // Resolved, lookup table ones included
+}
+
+// Token metadata `TransferV1` of a programmable NFT.
+type NftTransferV1 struct {
+	Source           solana.PublicKey
+	SourceOwner      solana.PublicKey
+	Destination      solana.PublicKey
+	DestinationOwner solana.PublicKey
+	Mint             solana.PublicKey
+	Amount           uint64
+}
+
+// Token metadata fields deciding how an NFT is transferred.
+type TokenMetadata struct {
+	TokenStandard *uint8
+	RuleSet       *solana.PublicKey // Of programmable NFTs
+}
+
+// NFT mint along with how it is transferred.
+type NftAsset struct {
+	Mint         solana.PublicKey
+	TokenProgram solana.PublicKey
+	Programmable bool
+	RuleSet      *solana.PublicKey
 }
 
 type AnalyseNftTransferResult struct {
@@ -91,3 +141,25 @@ type SendSplTokenRequest struct {
 	Mint   string
 	Amount uint64
 }
+
+type WithdrawalRequest struct {
+	To     string
+	Mint   string // Sol mint for lamports
+	Amount uint64
+	Nfts   []string
+}
+
+// Signed, not yet broadcast, withdrawal transaction.
+type SignedWithdrawal struct {
+	Tx                   *solana.Transaction
+	Signature            string
+	LastValidBlockHeight uint64
+	PriorityFee          uint64 // Micro lamports per compute unit
+}
+
+// Balance of a supported spl token held by a wallet,
+// lamports for sol.
+type TokenHolding struct {
+	Token  SplTokenMeta
+	Amount uint64 // In token base units
+}
@@ -0,0 +1,101 @@
+package token_registry
+
+import (
+	"net/http"
+
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"github.com/gin-gonic/gin"
+)
+
+func GetTokensHandler(ctx *gin.Context) {
+	tokens, err := getTokens()
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to retrieve tokens",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"tokens": tokens,
+		},
+	)
+}
+
+func SaveTokenHandler(ctx *gin.Context) {
+	var params struct {
+		Mint            string `json:"mint"`
+		Keyword         string `json:"keyword"`
+		Decimals        int    `json:"decimals"`
+		Image           string `json:"image"`
+		DepositEnabled  bool   `json:"depositEnabled"`
+		WithdrawEnabled bool   `json:"withdrawEnabled"`
+		MinDeposit      uint64 `json:"minDeposit"`
+		MinWithdraw     int64  `json:"minWithdraw"`
+		WithdrawFee     int64  `json:"withdrawFee"`
+		PriceSource     string `json:"priceSource"`
+		Token2022       bool   `json:"token2022"`
+		HotWalletMin    uint64 `json:"hotWalletMin"`
+		HotWalletMax    uint64 `json:"hotWalletMax"`
+	}
+	if err := ctx.BindJSON(&params); err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid parameter",
+			},
+		)
+		return
+	}
+
+	token := models.SplToken{
+		Mint:            params.Mint,
+		Keyword:         params.Keyword,
+		Decimals:        params.Decimals,
+		Image:           params.Image,
+		DepositEnabled:  params.DepositEnabled,
+		WithdrawEnabled: params.WithdrawEnabled,
+		MinDeposit:      params.MinDeposit,
+		MinWithdraw:     params.MinWithdraw,
+		WithdrawFee:     params.WithdrawFee,
+		PriceSource:     params.PriceSource,
+		Token2022:       params.Token2022,
+		HotWalletMin:    params.HotWalletMin,
+		HotWalletMax:    params.HotWalletMax,
+	}
+	err := saveToken(&token)
+	if utils.IsErrorCode(err, ErrCodeInvalidParameter) {
+		ctx.AbortWithStatusJSON(
+			http.StatusBadRequest,
+			gin.H{
+				"message": "invalid token",
+				"error":   err.Error(),
+			},
+		)
+		return
+	} else if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to save token",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"message": "successfully saved token",
+			"token":   token,
+		},
+	)
+}
@@ -0,0 +1,78 @@
+package token_registry
+
+import (
+	"github.com/Duelana-Team/duelana-v1/controllers/transaction/db_aggregator"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"gorm.io/gorm/clause"
+)
+
+/**
+* @Internal
+* Returns every registered spl token in registration order.
+ */
+func getTokens() ([]models.SplToken, error) {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"token_registry_db",
+			"getTokens",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	tokens := []models.SplToken{}
+	if err := session.Order("id").Find(&tokens).Error; err != nil {
+		return nil, utils.MakeError(
+			"token_registry_db",
+			"getTokens",
+			"failed to retrieve tokens",
+			err,
+		)
+	}
+	return tokens, nil
+}
+
+/**
+* @Internal
+* Creates the token or updates one with the same mint.
+ */
+func upsertToken(token *models.SplToken) error {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return utils.MakeError(
+			"token_registry_db",
+			"upsertToken",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	if err := session.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "mint"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"updated_at",
+			"keyword",
+			"decimals",
+			"image",
+			"deposit_enabled",
+			"withdraw_enabled",
+			"min_deposit",
+			"min_withdraw",
+			"withdraw_fee",
+			"price_source",
+			"token2022",
+			"hot_wallet_min",
+			"hot_wallet_max",
+		}),
+	}).Create(token).Error; err != nil {
+		return utils.MakeError(
+			"token_registry_db",
+			"upsertToken",
+			"failed to save token",
+			err,
+		)
+	}
+	return nil
+}
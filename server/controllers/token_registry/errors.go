@@ -0,0 +1,5 @@
+package token_registry
+
+// Error code range: #112xxx
+const ErrCodeBase = "#112"
+const ErrCodeInvalidParameter = ErrCodeBase + "000"
@@ -0,0 +1,118 @@
+package token_registry
+
+import (
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/controllers/solana"
+	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	solanaGo "github.com/gagliardetto/solana-go"
+	"github.com/sirupsen/logrus"
+)
+
+/**
+* @External
+* Makes supported spl tokens of solana module follow the registry.
+ */
+func Initialize() {
+	solana.SetSplTokenLoader(loadSplTokens)
+}
+
+/**
+* @Internal
+* Loads registered tokens as spl token metas.
+* Tokens with invalid record are skipped.
+ */
+func loadSplTokens() ([]solana.SplTokenMeta, error) {
+	tokens, err := getTokens()
+	if err != nil {
+		log.LogMessage(
+			"token_registry_loadSplTokens",
+			"failed to load tokens, keeping cached ones",
+			"error",
+			logrus.Fields{
+				"error": err.Error(),
+			},
+		)
+		return nil, err
+	}
+
+	metas := []solana.SplTokenMeta{}
+	for _, token := range tokens {
+		meta, err := toSplTokenMeta(&token)
+		if err != nil {
+			log.LogMessage(
+				"token_registry_loadSplTokens",
+				"skipped invalid token",
+				"error",
+				logrus.Fields{
+					"mint":  token.Mint,
+					"error": err.Error(),
+				},
+			)
+			continue
+		}
+		metas = append(metas, *meta)
+	}
+	return metas, nil
+}
+
+/**
+* @Internal
+* Converts registry record to spl token meta. Treasury token
+* account is filled in by solana module.
+ */
+func toSplTokenMeta(token *models.SplToken) (*solana.SplTokenMeta, error) {
+	if err := validateToken(token); err != nil {
+		return nil, utils.MakeErrorWithCode(
+			"token_registry",
+			"toSplTokenMeta",
+			"invalid token",
+			ErrCodeInvalidParameter,
+			err,
+		)
+	}
+	tokenProgram := solanaGo.TokenProgramID
+	if token.Token2022 {
+		tokenProgram = solana.Token2022ProgramID
+	}
+	return &solana.SplTokenMeta{
+		MintAddress:     solanaGo.MustPublicKeyFromBase58(token.Mint),
+		Decimals:        token.Decimals,
+		Keyword:         token.Keyword,
+		Image:           token.Image,
+		DepositEnabled:  token.DepositEnabled,
+		WithdrawEnabled: token.WithdrawEnabled,
+		MinDeposit:      token.MinDeposit,
+		MinWithdraw:     token.MinWithdraw,
+		WithdrawFee:     token.WithdrawFee,
+		PriceSource:     token.PriceSource,
+		TokenProgram:    tokenProgram,
+		HotWalletMin:    token.HotWalletMin,
+		HotWalletMax:    token.HotWalletMax,
+	}, nil
+}
+
+/**
+* @Internal
+* Validates and saves the token, then reloads supported tokens.
+ */
+func saveToken(token *models.SplToken) error {
+	if token.PriceSource == "" {
+		token.PriceSource = config.SPL_TOKEN_DEFAULT_PRICE_SOURCE
+	}
+	if err := validateToken(token); err != nil {
+		return utils.MakeErrorWithCode(
+			"token_registry",
+			"saveToken",
+			"invalid token",
+			ErrCodeInvalidParameter,
+			err,
+		)
+	}
+	if err := upsertToken(token); err != nil {
+		return err
+	}
+	solana.InvalidateSplTokens()
+	return nil
+}
@@ -0,0 +1,56 @@
+package token_registry
+
+import (
+	"testing"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+)
+
+func TestToSplTokenMeta(t *testing.T) {
+	token := models.SplToken{
+		Mint:            config.USDC_SPL_ADDRESS,
+		Keyword:         "USDC",
+		Decimals:        6,
+		DepositEnabled:  true,
+		WithdrawEnabled: false,
+		MinDeposit:      1000000,
+		MinWithdraw:     5 * config.ONE_CHIP_WITH_DECIMALS,
+		WithdrawFee:     config.WITHDRAW_FEE_PER_SPL,
+		PriceSource:     config.SPL_TOKEN_DEFAULT_PRICE_SOURCE,
+		HotWalletMin:    1000000000,
+		HotWalletMax:    5000000000,
+	}
+	meta, err := toSplTokenMeta(&token)
+	if err != nil {
+		t.Fatalf("failed to convert valid token: %v", err)
+	}
+	if meta.MintAddress.String() != token.Mint ||
+		meta.Decimals != 6 ||
+		!meta.DepositEnabled ||
+		meta.WithdrawEnabled ||
+		meta.MinDeposit != token.MinDeposit ||
+		meta.MinWithdraw != token.MinWithdraw ||
+		meta.WithdrawFee != token.WithdrawFee ||
+		meta.HotWalletMin != token.HotWalletMin ||
+		meta.HotWalletMax != token.HotWalletMax {
+		t.Fatalf("unexpected meta: %v", meta)
+	}
+
+	for name, invalid := range map[string]func(token *models.SplToken){
+		"mint":         func(token *models.SplToken) { token.Mint = "invalid" },
+		"keyword":      func(token *models.SplToken) { token.Keyword = " " },
+		"decimals":     func(token *models.SplToken) { token.Decimals = config.SPL_TOKEN_MAX_DECIMALS + 1 },
+		"min withdraw": func(token *models.SplToken) { token.MinWithdraw = -1 },
+		"withdraw fee": func(token *models.SplToken) { token.WithdrawFee = -1 },
+		"price source": func(token *models.SplToken) { token.PriceSource = "" },
+		"hot wallet":   func(token *models.SplToken) { token.HotWalletMin = token.HotWalletMax + 1 },
+	} {
+		record := token
+		invalid(&record)
+		if _, err := toSplTokenMeta(&record); !utils.IsErrorCode(err, ErrCodeInvalidParameter) {
+			t.Fatalf("token with invalid %s should be rejected: %v", name, err)
+		}
+	}
+}
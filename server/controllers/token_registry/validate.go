@@ -0,0 +1,49 @@
+package token_registry
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/controllers/price_oracle"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/gagliardetto/solana-go"
+)
+
+/**
+* @Internal
+* Validates spl token registry record.
+ */
+func validateToken(token *models.SplToken) error {
+	if _, err := solana.PublicKeyFromBase58(token.Mint); err != nil {
+		return fmt.Errorf("invalid mint: %s", token.Mint)
+	}
+	if strings.TrimSpace(token.Keyword) == "" ||
+		len(token.Keyword) > 20 {
+		return fmt.Errorf("keyword should be 1 ~ 20 characters: %s", token.Keyword)
+	}
+	if token.Decimals < 0 ||
+		token.Decimals > config.SPL_TOKEN_MAX_DECIMALS {
+		return fmt.Errorf(
+			"decimals should be in 0 ~ %d: %d",
+			config.SPL_TOKEN_MAX_DECIMALS, token.Decimals,
+		)
+	}
+	if token.MinWithdraw < 0 {
+		return fmt.Errorf("min withdraw should not be negative: %d", token.MinWithdraw)
+	}
+	if token.WithdrawFee < 0 {
+		return fmt.Errorf("withdraw fee should not be negative: %d", token.WithdrawFee)
+	}
+	if token.HotWalletMax != 0 &&
+		token.HotWalletMin > token.HotWalletMax {
+		return fmt.Errorf(
+			"hot wallet min should not exceed max: %d > %d",
+			token.HotWalletMin, token.HotWalletMax,
+		)
+	}
+	if err := price_oracle.ValidatePriceSource(token.PriceSource); err != nil {
+		return err
+	}
+	return nil
+}
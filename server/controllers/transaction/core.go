@@ -102,7 +102,8 @@ func addWithdrawFeeToRequest(transactionRequest *TransactionRequest) (int64, err
 	if !isWithdrawTransaction(transactionRequest.Type) {
 		return 0, nil
 	}
-	if transactionRequest.Type == models.TxWithdrawSol {
+	if transactionRequest.Type == models.TxWithdrawSol &&
+		transactionRequest.WithdrawFee == nil {
 		return 0, nil
 	}
 
@@ -111,8 +112,12 @@ func addWithdrawFeeToRequest(transactionRequest *TransactionRequest) (int64, err
 		chipTransferWithFee = *transactionRequest.Balance.ChipBalance
 	}
 	withdrawFee := int64(0)
-	if transactionRequest.Type == models.TxWithdrawSpl {
+	if transactionRequest.Type == models.TxWithdrawSol ||
+		transactionRequest.Type == models.TxWithdrawSpl {
 		withdrawFee = config.WITHDRAW_FEE_PER_SPL
+		if transactionRequest.WithdrawFee != nil {
+			withdrawFee = *transactionRequest.WithdrawFee
+		}
 	} else if transactionRequest.Type == models.TxWithdrawNft {
 		if transactionRequest.Balance.NftBalance == nil {
 			return 0, utils.MakeError(
@@ -309,6 +314,8 @@ func transfer(transactionRequest *TransactionRequest) (*db_aggregator.Transactio
 		return nil, err
 	}
 
+	// Owner of a pending transaction is the round it is waiting for,
+	// replaced on confirm or decline.
 	transaction, err := db_aggregator.RecordTransaction(&db_aggregator.TransactionLoad{
 		FromWallet:       fromWallet,
 		ToWallet:         toWallet,
@@ -316,6 +323,8 @@ func transfer(transactionRequest *TransactionRequest) (*db_aggregator.Transactio
 		Type:             transactionRequest.Type,
 		FromWalletPrevID: transferResult.FromPrevBalance,
 		FromWalletNextID: transferResult.FromNextBalance,
+		OwnerID:          transactionRequest.OwnerID,
+		OwnerType:        transactionRequest.OwnerType,
 	}, sessionId)
 	if err != nil {
 		return nil, err
@@ -547,6 +556,24 @@ func rain(rainRequest *RainRequest) (*db_aggregator.Transaction, error) {
 		db_aggregator.RemoveSession(sessionId)
 	}(sessionId)
 
+	transaction, err := rainInSession(rainRequest, sessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db_aggregator.CommitSession(sessionId); err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// @Internal
+// Handles rain request in the session, left uncommitted
+func rainInSession(
+	rainRequest *RainRequest,
+	sessionId db_aggregator.UUID,
+) (*db_aggregator.Transaction, error) {
 	rainResult, err := db_aggregator.Rain(
 		rainRequest.FromUser,
 		rainRequest.ToUsers,
@@ -593,9 +620,5 @@ func rain(rainRequest *RainRequest) (*db_aggregator.Transaction, error) {
 		return nil, err
 	}
 
-	if err := db_aggregator.CommitSession(sessionId); err != nil {
-		return nil, err
-	}
-
 	return transaction, nil
 }
@@ -20,6 +20,12 @@ func initialize(db *gorm.DB) error {
 
 	sessions.Store(UUID_NIL, db)
 
+	if err := registerMetrics(); err != nil {
+		return utils.MakeError("db_aggregator", "initialize", "failed to register metrics", err)
+	}
+
+	leakDetectorOnce.Do(startLeakDetector)
+
 	return nil
 }
 
@@ -939,6 +945,8 @@ func recordTransaction(transactionLoad *TransactionLoad, sessionId ...UUID) (*Tr
 		FromWalletPrevID: (*uint)(transactionLoad.FromWalletPrevID),
 		FromWalletNextID: (*uint)(transactionLoad.FromWalletNextID),
 		Receipients:      receipients,
+		OwnerID:          transactionLoad.OwnerID,
+		OwnerType:        transactionLoad.OwnerType,
 	}
 	if transactionLoad.Balance.ChipBalance != nil {
 		transactionInfo.Balance.ChipBalance = &models.ChipBalance{
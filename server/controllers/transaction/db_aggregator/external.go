@@ -1,6 +1,7 @@
 package db_aggregator
 
 import (
+	"context"
 	"errors"
 
 	"github.com/Duelana-Team/duelana-v1/models"
@@ -28,6 +29,18 @@ func RemoveSession(session UUID) error {
 	return removeSession(session)
 }
 
+func WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return withTx(ctx, fn)
+}
+
+func GetTx(ctx context.Context) (*gorm.DB, error) {
+	return getTx(ctx)
+}
+
+func SessionIdFromContext(ctx context.Context) UUID {
+	return sessionIdFromContext(ctx)
+}
+
 func GetBalance(balance *Balance, sessionId ...UUID) (*BalanceLoad, error) {
 	return getBalance(balance, sessionId...)
 }
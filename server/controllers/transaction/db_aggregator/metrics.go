@@ -0,0 +1,106 @@
+package db_aggregator
+
+import (
+	"github.com/Duelana-Team/duelana-v1/metrics"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var pendingTransactionsDesc = prometheus.NewDesc(
+	metrics.NAMESPACE+"_ledger_pending_transactions",
+	"Transactions waiting for confirmation per type.",
+	[]string{"type"},
+	nil,
+)
+
+// Units of work retried after deadlock or serialization failure.
+var txRetries = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: metrics.NAMESPACE,
+		Subsystem: "db",
+		Name:      "tx_retries_total",
+		Help:      "Units of work retried after deadlock or serialization failure.",
+	},
+)
+
+// Sessions open longer than the leak thresholds.
+var leakedSessions = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: metrics.NAMESPACE,
+		Subsystem: "db",
+		Name:      "leaked_sessions_total",
+		Help:      "Sessions open for too long, reported or rolled back.",
+	},
+	[]string{"action"},
+)
+
+// @Internal
+// Reads pending transaction counts on every scrape.
+type pendingTransactionsCollector struct{}
+
+func (collector pendingTransactionsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- pendingTransactionsDesc
+}
+
+func (collector pendingTransactionsCollector) Collect(ch chan<- prometheus.Metric) {
+	session, err := getSession()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(pendingTransactionsDesc, err)
+		return
+	}
+
+	var counts []struct {
+		Type  models.TransactionType
+		Count int64
+	}
+	if err := session.Model(&models.Transaction{}).
+		Select("type, count(*) as count").
+		Where("status = ?", models.TransactionPending).
+		Group("type").
+		Scan(&counts).Error; err != nil {
+		ch <- prometheus.NewInvalidMetric(pendingTransactionsDesc, err)
+		return
+	}
+
+	for _, count := range counts {
+		ch <- prometheus.MustNewConstMetric(
+			pendingTransactionsDesc,
+			prometheus.GaugeValue,
+			float64(count.Count),
+			string(count.Type),
+		)
+	}
+}
+
+// @Internal
+// Counts open sessions except the main one.
+func countOpenSessions() float64 {
+	count := 0
+	sessions.Range(func(key, value any) bool {
+		if key.(UUID) != UUID_NIL {
+			count++
+		}
+		return true
+	})
+	return float64(count)
+}
+
+// @Internal
+// Registers ledger collectors.
+func registerMetrics() error {
+	if err := metrics.RegisterGaugeFunc(
+		"db",
+		"open_sessions",
+		"Open db_aggregator transaction sessions.",
+		countOpenSessions,
+	); err != nil {
+		return err
+	}
+	if err := metrics.Register(txRetries); err != nil {
+		return err
+	}
+	if err := metrics.Register(leakedSessions); err != nil {
+		return err
+	}
+	return metrics.Register(pendingTransactionsCollector{})
+}
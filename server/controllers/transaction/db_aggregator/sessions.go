@@ -2,6 +2,8 @@ package db_aggregator
 
 import (
 	"errors"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/sync/syncmap"
@@ -22,6 +24,18 @@ var UUID_NIL = UUID(uuid.Nil)
 // The main session pointer is indexed by UUID_NIL key.
 var sessions = syncmap.Map{}
 
+// sessionStartedAt is a mapping from UUID to begin time of the
+// session, used for detecting leaked sessions.
+var sessionStartedAt = syncmap.Map{}
+
+// unitOfWorkSessions holds the deadlines of the session ids registered
+// by `withTx`. Only these are rolled back by the leak detector, as legacy
+// sessions are owned by callers which commit them later.
+var unitOfWorkSessions = syncmap.Map{}
+
+// Leak detector is started once, on first initialization.
+var leakDetectorOnce sync.Once
+
 // Returns main session pointer id.
 func MainSessionId() UUID {
 	return UUID_NIL
@@ -74,6 +88,7 @@ func startSession() (UUID, error) {
 
 	new_uuid := generateUUID()
 	sessions.Store(new_uuid, mainSession.Begin())
+	sessionStartedAt.Store(new_uuid, time.Now())
 
 	return new_uuid, nil
 }
@@ -88,6 +103,7 @@ func commitSession(sessionId UUID) error {
 	}
 
 	sessions.Delete(sessionId)
+	sessionStartedAt.Delete(sessionId)
 	return session.Commit().Error
 }
 
@@ -104,5 +120,6 @@ func removeSession(sessionId UUID) error {
 
 	result := (session.(*gorm.DB)).Rollback()
 	sessions.Delete(sessionId)
+	sessionStartedAt.Delete(sessionId)
 	return result.Error
 }
@@ -0,0 +1,294 @@
+package db_aggregator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Postgres error codes worth retrying the whole unit of work.
+var retryableSQLStates = []string{
+	"40P01", // deadlock_detected
+	"40001", // serialization_failure
+}
+
+// unitOfWork is carried by the context passed to `WithTx` callbacks.
+// The transaction is also registered in the session map, so that
+// functions still taking `sessionId ...UUID` join it.
+type unitOfWork struct {
+	db      *gorm.DB
+	session UUID
+}
+
+type unitOfWorkKey struct{}
+
+// @Internal
+// Returns the unit of work carried by `ctx`, nil if none.
+func getUnitOfWork(ctx context.Context) *unitOfWork {
+	if ctx == nil {
+		return nil
+	}
+	uow, _ := ctx.Value(unitOfWorkKey{}).(*unitOfWork)
+	return uow
+}
+
+// @Internal
+// Runs `fn` inside a db transaction carried by its context.
+// The transaction commits when `fn` returns nil and rolls back
+// on error or panic. Nested calls join the outer transaction.
+// Deadlocks and serialization failures retry the whole `fn`,
+// so `fn` must not have side effects outside the db.
+func withTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if getUnitOfWork(ctx) != nil {
+		return fn(ctx)
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.DB_TX_TIMEOUT)
+		defer cancel()
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := runTx(ctx, fn)
+		if err == nil ||
+			!isRetryableTxError(err) ||
+			attempt > config.DB_TX_MAX_RETRIES {
+			return err
+		}
+
+		txRetries.Inc()
+		log.LogContext(
+			ctx,
+			"db_aggregator_withTx",
+			"retrying unit of work",
+			"info",
+			logrus.Fields{
+				"attempt": attempt,
+				"error":   err.Error(),
+			},
+		)
+		select {
+		case <-ctx.Done():
+			return utils.MakeError(
+				"db_aggregator",
+				"withTx",
+				"context done before retry",
+				fmt.Errorf("%v, last error: %v", ctx.Err(), err),
+			)
+		case <-time.After(config.DB_TX_RETRY_BACKOFF * time.Duration(attempt)):
+		}
+	}
+}
+
+// @Internal
+// Runs a single attempt of `fn` in a new transaction.
+func runTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	// 1. Begin transaction bound to ctx.
+	mainSession, err := getSession()
+	if err != nil {
+		return utils.MakeError(
+			"db_aggregator",
+			"runTx",
+			"failed to get main session",
+			err,
+		)
+	}
+	tx := mainSession.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return utils.MakeError(
+			"db_aggregator",
+			"runTx",
+			"failed to begin transaction",
+			tx.Error,
+		)
+	}
+
+	// 2. Register session for legacy callers.
+	uow := &unitOfWork{db: tx, session: generateUUID()}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(config.DB_TX_TIMEOUT)
+	}
+	sessions.Store(uow.session, tx)
+	sessionStartedAt.Store(uow.session, time.Now())
+	unitOfWorkSessions.Store(uow.session, deadline)
+	defer func() {
+		sessions.Delete(uow.session)
+		sessionStartedAt.Delete(uow.session)
+		unitOfWorkSessions.Delete(uow.session)
+	}()
+
+	// 3. Roll back and re-panic on panic.
+	committed := false
+	defer func() {
+		if committed {
+			return
+		}
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	// 4. Limit statement duration.
+	if err := tx.Exec(fmt.Sprintf(
+		"SET LOCAL statement_timeout = %d",
+		config.DB_TX_STATEMENT_TIMEOUT.Milliseconds(),
+	)).Error; err != nil {
+		tx.Rollback()
+		return utils.MakeError(
+			"db_aggregator",
+			"runTx",
+			"failed to set statement timeout",
+			err,
+		)
+	}
+
+	// 5. Run and commit.
+	if err := fn(context.WithValue(ctx, unitOfWorkKey{}, uow)); err != nil {
+		tx.Rollback()
+		return err
+	}
+	committed = true
+	if err := tx.Commit().Error; err != nil {
+		return utils.MakeError(
+			"db_aggregator",
+			"runTx",
+			"failed to commit transaction",
+			err,
+		)
+	}
+	return nil
+}
+
+// @Internal
+// Checks whether the error is a deadlock or serialization failure.
+// Errors wrapped by `utils.MakeError` lose their type, so the
+// SQLSTATE printed by the driver is matched as well.
+func isRetryableTxError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var stateErr interface{ SQLState() string }
+	if errors.As(err, &stateErr) {
+		for _, state := range retryableSQLStates {
+			if stateErr.SQLState() == state {
+				return true
+			}
+		}
+	}
+
+	message := err.Error()
+	for _, state := range retryableSQLStates {
+		if strings.Contains(message, "SQLSTATE "+state) {
+			return true
+		}
+	}
+	return false
+}
+
+// @Internal
+// Returns the transaction carried by `ctx`, or the main
+// session bound to `ctx` outside of a unit of work.
+func getTx(ctx context.Context) (*gorm.DB, error) {
+	if uow := getUnitOfWork(ctx); uow != nil {
+		return uow.db, nil
+	}
+	mainSession, err := getSession()
+	if err != nil {
+		return nil, err
+	}
+	if ctx == nil {
+		return mainSession, nil
+	}
+	return mainSession.WithContext(ctx), nil
+}
+
+// @Internal
+// Returns the legacy session id of the transaction carried
+// by `ctx`, or the main session id outside of a unit of work.
+func sessionIdFromContext(ctx context.Context) UUID {
+	if uow := getUnitOfWork(ctx); uow != nil {
+		return uow.session
+	}
+	return MainSessionId()
+}
+
+// @Internal
+// Reports legacy sessions open longer than the leak threshold and
+// units of work past their deadline, `DB_TX_TIMEOUT` by default.
+// Units of work still open `DB_SESSION_LEAK_ROLLBACK` past their
+// deadline are rolled back, releasing their row locks in case the
+// driver did not on the deadline. Their remaining statements and
+// commit then fail as the transaction is already rolled back.
+// Legacy sessions are only reported, as their owners may
+// still be using them.
+func checkLeakedSessions(now time.Time) {
+	sessionStartedAt.Range(func(key, value any) bool {
+		sessionId := key.(UUID)
+		age := now.Sub(value.(time.Time))
+		deadline, isUnitOfWork := unitOfWorkSessions.Load(sessionId)
+		if (isUnitOfWork && now.Before(deadline.(time.Time))) ||
+			(!isUnitOfWork && age < config.DB_SESSION_LEAK_THRESHOLD) {
+			return true
+		}
+
+		if !isUnitOfWork ||
+			now.Before(deadline.(time.Time).Add(config.DB_SESSION_LEAK_ROLLBACK)) {
+			leakedSessions.WithLabelValues("reported").Inc()
+			log.LogMessage(
+				"db_aggregator_checkLeakedSessions",
+				"session is open for too long",
+				"error",
+				logrus.Fields{
+					"session":    uuid.UUID(sessionId).String(),
+					"age":        age.String(),
+					"unitOfWork": isUnitOfWork,
+				},
+			)
+			return true
+		}
+
+		leakedSessions.WithLabelValues("rolled_back").Inc()
+		err := removeSession(sessionId)
+		fields := logrus.Fields{
+			"session": uuid.UUID(sessionId).String(),
+			"age":     age.String(),
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+		}
+		log.LogMessage(
+			"db_aggregator_checkLeakedSessions",
+			"rolled back leaked session",
+			"error",
+			fields,
+		)
+		return true
+	})
+}
+
+// @Internal
+// Checks leaked sessions periodically.
+func startLeakDetector() {
+	go func() {
+		ticker := time.NewTicker(config.DB_SESSION_LEAK_CHECK_INTERVAL)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			checkLeakedSessions(now)
+		}
+	}()
+}
@@ -0,0 +1,157 @@
+package db_aggregator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/tests"
+	"gorm.io/gorm"
+)
+
+type mockSQLStateError struct {
+	state string
+}
+
+func (err mockSQLStateError) Error() string {
+	return "mock sql state error"
+}
+
+func (err mockSQLStateError) SQLState() string {
+	return err.state
+}
+
+func TestIsRetryableTxError(t *testing.T) {
+	if !isRetryableTxError(mockSQLStateError{state: "40P01"}) {
+		t.Fatal("deadlock should be retryable")
+	}
+	if !isRetryableTxError(fmt.Errorf("wrapped: %w", mockSQLStateError{state: "40001"})) {
+		t.Fatal("wrapped serialization failure should be retryable")
+	}
+	if !isRetryableTxError(errors.New("failed:\n\r ERROR: deadlock detected (SQLSTATE 40P01)")) {
+		t.Fatal("printed deadlock should be retryable")
+	}
+	if isRetryableTxError(mockSQLStateError{state: "23505"}) {
+		t.Fatal("unique violation should not be retryable")
+	}
+	if isRetryableTxError(nil) {
+		t.Fatal("nil should not be retryable")
+	}
+}
+
+func TestWithTx(t *testing.T) {
+	database := tests.InitMockDB(true, true)
+	if err := initialize(database); err != nil {
+		t.Fatalf("failed to initialize transaction db: %v", err)
+	}
+
+	countUsers := func() int64 {
+		var count int64
+		database.Model(&models.User{}).Count(&count)
+		return count
+	}
+
+	// Commits on success, nested call and legacy session join.
+	if err := withTx(context.Background(), func(ctx context.Context) error {
+		tx, err := getTx(ctx)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(&models.User{Name: "uow1", WalletAddress: "uow1"}).Error; err != nil {
+			return err
+		}
+		return withTx(ctx, func(ctx context.Context) error {
+			session, err := getSession(sessionIdFromContext(ctx))
+			if err != nil {
+				return err
+			}
+			return session.Create(&models.User{Name: "uow2", WalletAddress: "uow2"}).Error
+		})
+	}); err != nil {
+		t.Fatalf("failed to run unit of work: %v", err)
+	}
+	if count := countUsers(); count != 2 {
+		t.Fatalf("expected 2 users, got %d", count)
+	}
+
+	// Rolls back on error.
+	if err := withTx(context.Background(), func(ctx context.Context) error {
+		tx, _ := getTx(ctx)
+		tx.Create(&models.User{Name: "uow3", WalletAddress: "uow3"})
+		return errors.New("mock error")
+	}); err == nil {
+		t.Fatal("error should be returned")
+	}
+	if count := countUsers(); count != 2 {
+		t.Fatalf("error should roll back, got %d users", count)
+	}
+
+	// Rolls back and re-panics on panic.
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatal("panic should be propagated")
+			}
+		}()
+		withTx(context.Background(), func(ctx context.Context) error {
+			tx, _ := getTx(ctx)
+			tx.Create(&models.User{Name: "uow4", WalletAddress: "uow4"})
+			panic("mock panic")
+		})
+	}()
+	if count := countUsers(); count != 2 {
+		t.Fatalf("panic should roll back, got %d users", count)
+	}
+
+	// Sessions are released.
+	if openSessions := countOpenSessions(); openSessions != 0 {
+		t.Fatalf("expected no open sessions, got %v", openSessions)
+	}
+}
+
+func TestCheckLeakedSessionsKeepsLegacySessions(t *testing.T) {
+	sessionId := generateUUID()
+	sessions.Store(sessionId, &gorm.DB{})
+	sessionStartedAt.Store(sessionId, time.Now().Add(-2*config.DB_SESSION_LEAK_ROLLBACK))
+	defer func() {
+		sessions.Delete(sessionId)
+		sessionStartedAt.Delete(sessionId)
+	}()
+
+	checkLeakedSessions(time.Now())
+
+	if _, ok := sessions.Load(sessionId); !ok {
+		t.Fatal("legacy session should only be reported")
+	}
+}
+
+func TestCheckLeakedSessionsRollsBackUnitsOfWorkPastDeadline(t *testing.T) {
+	now := time.Now()
+	for _, test := range []struct {
+		name       string
+		deadline   time.Time
+		rolledBack bool
+	}{
+		{"before deadline", now.Add(time.Second), false},
+		{"just past deadline", now.Add(-time.Second), false},
+		{"long past deadline", now.Add(-config.DB_SESSION_LEAK_ROLLBACK - time.Second), true},
+	} {
+		sessionId := generateUUID()
+		sessions.Store(sessionId, &gorm.DB{Config: &gorm.Config{}, Statement: &gorm.Statement{}})
+		sessionStartedAt.Store(sessionId, now.Add(-config.DB_TX_TIMEOUT))
+		unitOfWorkSessions.Store(sessionId, test.deadline)
+
+		checkLeakedSessions(now)
+
+		if _, ok := sessions.Load(sessionId); ok == test.rolledBack {
+			t.Fatalf("%s: unexpected rollback, rolled back: %v", test.name, !ok)
+		}
+		sessions.Delete(sessionId)
+		sessionStartedAt.Delete(sessionId)
+		unitOfWorkSessions.Delete(sessionId)
+	}
+}
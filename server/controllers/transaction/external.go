@@ -65,3 +65,7 @@ func DeactivateAffiliateCode(user db_aggregator.User, code string) error {
 func Rain(request *RainRequest) (*db_aggregator.Transaction, error) {
 	return rain(request)
 }
+
+func RainInSession(request *RainRequest, sessionId db_aggregator.UUID) (*db_aggregator.Transaction, error) {
+	return rainInSession(request, sessionId)
+}
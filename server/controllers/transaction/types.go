@@ -16,6 +16,7 @@ type TransactionRequest struct {
 	OwnerID           uint
 	BatchHouseFeeMeta []HouseFeeMeta
 	EdgeDetails       EdgeDetailsInTransactionRequest
+	WithdrawFee       *int64 // Overrides default fee of spl and sol withdrawals
 }
 
 type EdgeDetailsInTransactionRequest struct {
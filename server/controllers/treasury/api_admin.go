@@ -0,0 +1,50 @@
+package treasury
+
+import (
+	"net/http"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/gin-gonic/gin"
+)
+
+func GetReportHandler(ctx *gin.Context) {
+	report, err := GetReport()
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to build treasury report",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"report": report,
+		},
+	)
+}
+
+func GetSweepsHandler(ctx *gin.Context) {
+	sweeps, err := getSweeps(config.TREASURY_SWEEP_HISTORY_LIMIT)
+	if err != nil {
+		ctx.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			gin.H{
+				"message": "failed to retrieve sweeps",
+				"error":   err.Error(),
+			},
+		)
+		return
+	}
+
+	ctx.JSON(
+		http.StatusOK,
+		gin.H{
+			"sweeps": sweeps,
+		},
+	)
+}
@@ -0,0 +1,183 @@
+package treasury
+
+import (
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/controllers/transaction/db_aggregator"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+)
+
+/**
+* @Internal
+* Returns sweeps which may still land.
+ */
+func getSweepsInFlight() ([]models.TreasurySweep, error) {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"treasury_db",
+			"getSweepsInFlight",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	sweeps := []models.TreasurySweep{}
+	if err := session.Where(
+		"status IN ?",
+		[]models.WithdrawalStatus{
+			models.WithdrawalSent,
+			models.WithdrawalConfirmed,
+		},
+	).Order("id").Find(&sweeps).Error; err != nil {
+		return nil, utils.MakeError(
+			"treasury_db",
+			"getSweepsInFlight",
+			"failed to retrieve sweeps",
+			err,
+		)
+	}
+	return sweeps, nil
+}
+
+/**
+* @Internal
+* Creates or updates the sweep.
+ */
+func saveSweep(sweep *models.TreasurySweep) error {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return utils.MakeError(
+			"treasury_db",
+			"saveSweep",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	if err := session.Save(sweep).Error; err != nil {
+		return utils.MakeError(
+			"treasury_db",
+			"saveSweep",
+			"failed to save sweep",
+			err,
+		)
+	}
+	return nil
+}
+
+/**
+* @Internal
+* Returns latest sweeps, newest first.
+ */
+func getSweeps(limit int) ([]models.TreasurySweep, error) {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return nil, utils.MakeError(
+			"treasury_db",
+			"getSweeps",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	sweeps := []models.TreasurySweep{}
+	if err := session.Order("id desc").Limit(limit).Find(&sweeps).Error; err != nil {
+		return nil, utils.MakeError(
+			"treasury_db",
+			"getSweeps",
+			"failed to retrieve sweeps",
+			err,
+		)
+	}
+	return sweeps, nil
+}
+
+/**
+* @Internal
+* Sums chip balances of user wallets, house users excluded.
+ */
+func getUserChips() (int64, error) {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return 0, utils.MakeError(
+			"treasury_db",
+			"getUserChips",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	var chips int64
+	if err := session.Table(
+		"wallets w",
+	).Joins(
+		"join balances b on b.owner_type = ? and b.owner_id = w.id",
+		models.InWallet,
+	).Joins(
+		"join chip_balances cb on cb.id = b.chip_balance_id",
+	).Where(
+		"w.deleted_at is null",
+	).Where(
+		"w.user_id not in ?",
+		config.GetHouseUserIDs(),
+	).Select(
+		"coalesce(sum(cb.balance), 0)",
+	).Scan(&chips).Error; err != nil {
+		return 0, utils.MakeError(
+			"treasury_db",
+			"getUserChips",
+			"failed to sum user chips",
+			err,
+		)
+	}
+	return chips, nil
+}
+
+/**
+* @Internal
+* Sums chips of pending withdrawals. Those have left user
+* wallets but not yet the treasury.
+ */
+func getPendingWithdrawalChips() (int64, error) {
+	session, err := db_aggregator.GetSession()
+	if err != nil {
+		return 0, utils.MakeError(
+			"treasury_db",
+			"getPendingWithdrawalChips",
+			"failed to retrieve main session",
+			err,
+		)
+	}
+
+	var chips int64
+	if err := session.Table(
+		"transactions t",
+	).Joins(
+		"join balances b on b.owner_type = ? and b.owner_id = t.id",
+		models.InTransaction,
+	).Joins(
+		"join chip_balances cb on cb.id = b.chip_balance_id",
+	).Where(
+		"t.deleted_at is null",
+	).Where(
+		"t.type in ?",
+		[]models.TransactionType{
+			models.TxWithdrawSol,
+			models.TxWithdrawSpl,
+		},
+	).Where(
+		"t.status = ?",
+		models.TransactionPending,
+	).Select(
+		"coalesce(sum(cb.balance), 0)",
+	).Scan(&chips).Error; err != nil {
+		return 0, utils.MakeError(
+			"treasury_db",
+			"getPendingWithdrawalChips",
+			"failed to sum pending withdrawals",
+			err,
+		)
+	}
+	return chips, nil
+}
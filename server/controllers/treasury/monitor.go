@@ -0,0 +1,141 @@
+package treasury
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/controllers/admin"
+	"github.com/Duelana-Team/duelana-v1/controllers/solana"
+	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	cron "github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+)
+
+// Guards monitor runs.
+var monitorMutex sync.Mutex
+
+/**
+* @External
+* Schedules checking hot wallet against its thresholds.
+* Game controller should be initialized before, as low
+* liquidity pauses withdrawals through it.
+ */
+func Initialize() error {
+	c := cron.New(cron.WithLocation(time.UTC))
+	if _, err := c.AddFunc(
+		config.TREASURY_MONITOR_SCHEDULE,
+		runMonitor,
+	); err != nil {
+		return utils.MakeError(
+			"treasury_monitor",
+			"Initialize",
+			"failed to schedule treasury monitor",
+			err,
+		)
+	}
+	c.Start()
+	return nil
+}
+
+/**
+* @Internal
+* Settles previous sweeps, pauses withdrawals when hot wallet
+* runs low on any token and sweeps excess to cold wallet.
+ */
+func runMonitor() {
+	monitorMutex.Lock()
+	defer monitorMutex.Unlock()
+
+	// 1. Settle previous sweeps.
+	inFlight, err := pollSweeps()
+	if err != nil {
+		log.LogMessage(
+			"treasury_monitor_runMonitor",
+			"failed to poll sweeps, skipped sweeping",
+			"error",
+			logrus.Fields{
+				"error": err.Error(),
+			},
+		)
+	}
+
+	// 2. Read hot wallet.
+	holdings, err := solana.GetTokenHoldings(solana.TreasuryPubKey())
+	if err != nil {
+		log.LogMessage(
+			"treasury_monitor_runMonitor",
+			"failed to get hot wallet holdings",
+			"error",
+			logrus.Fields{
+				"error": err.Error(),
+			},
+		)
+		return
+	}
+
+	// 3. Pause withdrawals on low liquidity.
+	checkLiquidity(holdings)
+
+	// 4. Sweep excess, unless previous sweeps are unknown.
+	coldWallet := strings.TrimSpace(config.Get().ColdWalletPubKey)
+	if inFlight == nil || coldWallet == "" {
+		return
+	}
+	for _, holding := range holdings {
+		amount := sweepAmount(holding.Amount, holding.Token)
+		if amount == 0 ||
+			inFlight[holding.Token.MintAddress.String()] {
+			continue
+		}
+		if err := sweep(holding.Token, amount, coldWallet); err != nil {
+			log.LogMessage(
+				"treasury_monitor_runMonitor",
+				"failed to sweep hot wallet excess",
+				"error",
+				logrus.Fields{
+					"token":  holding.Token.Keyword,
+					"amount": amount,
+					"error":  err.Error(),
+				},
+			)
+		}
+	}
+}
+
+/**
+* @Internal
+* Alerts and pauses withdrawals when any token of hot wallet is
+* below its min threshold. Withdrawals are resumed by admins once
+* the hot wallet is refilled.
+ */
+func checkLiquidity(holdings []solana.TokenHolding) {
+	low := logrus.Fields{}
+	for _, holding := range holdings {
+		if hotWalletStatus(holding.Amount, holding.Token) == HotWalletLow {
+			low[holding.Token.Keyword] = holding.Amount
+		}
+	}
+	if len(low) == 0 {
+		return
+	}
+
+	log.LogMessage(
+		"treasury_monitor_checkLiquidity",
+		"critical: hot wallet liquidity is low, pausing withdrawals",
+		"error",
+		low,
+	)
+	if err := admin.BlockGame(admin.GAME_CONTROLLER_WITHDRAW); err != nil {
+		log.LogMessage(
+			"treasury_monitor_checkLiquidity",
+			"critical: failed to pause withdrawals",
+			"error",
+			logrus.Fields{
+				"error": err.Error(),
+			},
+		)
+	}
+}
@@ -0,0 +1,147 @@
+package treasury
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/controllers/price_oracle"
+	"github.com/Duelana-Team/duelana-v1/controllers/solana"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	solanaGo "github.com/gagliardetto/solana-go"
+)
+
+// Prices a token, price oracle aside in tests.
+type pricer func(token *solana.SplTokenMeta) (*price_oracle.Price, error)
+
+/**
+* @Internal
+* Chips worth `amount` base units of a token with `decimals`
+* at `price` in USD.
+ */
+func holdingChips(amount uint64, decimals int, price float64) int64 {
+	return int64(float64(amount) * price * math.Pow10(config.BALANCE_DECIMALS) / math.Pow10(decimals))
+}
+
+/**
+* @Internal
+* Values hot and cold holdings and compares them with chips owed
+* to users. Cold holdings are nil without cold wallet. Tokens which
+* fail to price are reported with zero value.
+ */
+func buildReport(
+	hot []solana.TokenHolding,
+	cold []solana.TokenHolding,
+	userChips int64,
+	pendingWithdrawalChips int64,
+	getPrice pricer,
+	now time.Time,
+) Report {
+	coldAmounts := map[solanaGo.PublicKey]uint64{}
+	for _, holding := range cold {
+		coldAmounts[holding.Token.MintAddress] = holding.Amount
+	}
+
+	report := Report{
+		Tokens:                 []TokenReport{},
+		UserChips:              userChips,
+		PendingWithdrawalChips: pendingWithdrawalChips,
+		LiabilityChips:         userChips + pendingWithdrawalChips,
+		Complete:               true,
+		At:                     now,
+	}
+	for _, holding := range hot {
+		token := holding.Token
+		tokenReport := TokenReport{
+			Mint:         token.MintAddress.String(),
+			Keyword:      token.Keyword,
+			Decimals:     token.Decimals,
+			HotAmount:    holding.Amount,
+			ColdAmount:   coldAmounts[token.MintAddress],
+			HotWalletMin: token.HotWalletMin,
+			HotWalletMax: token.HotWalletMax,
+			Status:       hotWalletStatus(holding.Amount, token),
+		}
+		price, err := getPrice(&token)
+		if err != nil {
+			tokenReport.PriceError = err.Error()
+			report.Complete = false
+		} else {
+			tokenReport.Price = price.Price
+			tokenReport.Chips = holdingChips(
+				tokenReport.HotAmount+tokenReport.ColdAmount,
+				token.Decimals,
+				price.Price,
+			)
+		}
+		report.HoldingChips += tokenReport.Chips
+		report.Tokens = append(report.Tokens, tokenReport)
+	}
+
+	report.SurplusChips = report.HoldingChips - report.LiabilityChips
+	if report.LiabilityChips > 0 {
+		report.CoverageBps = int64(
+			float64(report.HoldingChips) * 10000 / float64(report.LiabilityChips),
+		)
+	}
+	return report
+}
+
+/**
+* @External
+* Reports on chain holdings of hot and cold wallets against
+* chip liabilities.
+ */
+func GetReport() (*Report, error) {
+	hot, err := solana.GetTokenHoldings(solana.TreasuryPubKey())
+	if err != nil {
+		return nil, utils.MakeError(
+			"treasury_report",
+			"GetReport",
+			"failed to get hot wallet holdings",
+			err,
+		)
+	}
+	cold := []solana.TokenHolding{}
+	coldWallet := strings.TrimSpace(config.Get().ColdWalletPubKey)
+	if coldWallet != "" {
+		coldPubKey, err := solanaGo.PublicKeyFromBase58(coldWallet)
+		if err != nil {
+			return nil, utils.MakeError(
+				"treasury_report",
+				"GetReport",
+				"invalid cold wallet",
+				err,
+			)
+		}
+		if cold, err = solana.GetTokenHoldings(coldPubKey); err != nil {
+			return nil, utils.MakeError(
+				"treasury_report",
+				"GetReport",
+				"failed to get cold wallet holdings",
+				err,
+			)
+		}
+	}
+
+	userChips, err := getUserChips()
+	if err != nil {
+		return nil, err
+	}
+	pendingWithdrawalChips, err := getPendingWithdrawalChips()
+	if err != nil {
+		return nil, err
+	}
+
+	report := buildReport(
+		hot,
+		cold,
+		userChips,
+		pendingWithdrawalChips,
+		price_oracle.GetPrice,
+		time.Now(),
+	)
+	report.ColdWallet = coldWallet
+	return &report, nil
+}
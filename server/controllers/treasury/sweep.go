@@ -0,0 +1,211 @@
+package treasury
+
+import (
+	"fmt"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/controllers/solana"
+	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/sirupsen/logrus"
+)
+
+/**
+* @Internal
+* Status of hot wallet `amount` against thresholds of the token.
+* A zero threshold is not checked.
+ */
+func hotWalletStatus(amount uint64, token solana.SplTokenMeta) HotWalletStatus {
+	if token.HotWalletMin != 0 && amount < token.HotWalletMin {
+		return HotWalletLow
+	}
+	if token.HotWalletMax != 0 && amount > token.HotWalletMax {
+		return HotWalletExcess
+	}
+	return HotWalletNormal
+}
+
+/**
+* @Internal
+* Amount to sweep from hot wallet holding `amount` of the token.
+* Leaves the sweep target between min and max thresholds, so that
+* the next deposits do not trigger another sweep right away.
+ */
+func sweepAmount(amount uint64, token solana.SplTokenMeta) uint64 {
+	if hotWalletStatus(amount, token) != HotWalletExcess {
+		return 0
+	}
+	target := token.HotWalletMin + uint64(
+		float64(token.HotWalletMax-token.HotWalletMin)*
+			float64(config.TREASURY_SWEEP_TARGET)/10000,
+	)
+	return amount - target
+}
+
+/**
+* @Internal
+* Status of a sweep given its signature status, nil if the
+* cluster does not know the signature, and finalized block height.
+ */
+func nextSweepStatus(
+	sweep *models.TreasurySweep,
+	status *rpc.SignatureStatusesResult,
+	blockHeight uint64,
+) models.WithdrawalStatus {
+	if status == nil {
+		if blockHeight > sweep.LastValidBlockHeight {
+			return models.WithdrawalExpired
+		}
+		return sweep.Status
+	}
+	if status.Err != nil {
+		return models.WithdrawalFailed
+	}
+	switch status.ConfirmationStatus {
+	case rpc.ConfirmationStatusFinalized:
+		return models.WithdrawalFinalized
+	case rpc.ConfirmationStatusConfirmed:
+		return models.WithdrawalConfirmed
+	}
+	return sweep.Status
+}
+
+/**
+* @Internal
+* Moves sweeps in flight forward by their signature status.
+* Returns mints of sweeps still in flight, which are not swept
+* again until they settle.
+ */
+func pollSweeps() (map[string]bool, error) {
+	// 1. Block height first, so that a signature unknown
+	// afterwards can no longer land.
+	blockHeight, err := solana.GetFinalizedBlockHeight()
+	if err != nil {
+		return nil, utils.MakeError(
+			"treasury_sweep",
+			"pollSweeps",
+			"failed to get block height",
+			err,
+		)
+	}
+
+	// 2. Check sweeps in flight.
+	sweeps, err := getSweepsInFlight()
+	if err != nil {
+		return nil, err
+	}
+	inFlight := map[string]bool{}
+	for i := range sweeps {
+		sweep := &sweeps[i]
+		status, err := solana.GetSignatureStatus(sweep.Signature)
+		if err != nil {
+			inFlight[sweep.Mint] = true
+			log.LogMessage(
+				"treasury_sweep_pollSweeps",
+				"failed to get signature status",
+				"error",
+				logrus.Fields{
+					"sweep": sweep.ID,
+					"tx":    sweep.Signature,
+					"error": err.Error(),
+				},
+			)
+			continue
+		}
+
+		next := nextSweepStatus(sweep, status, blockHeight)
+		if next == models.WithdrawalSent ||
+			next == models.WithdrawalConfirmed {
+			inFlight[sweep.Mint] = true
+		}
+		if next == sweep.Status {
+			continue
+		}
+		sweep.Status = next
+		if next == models.WithdrawalFailed {
+			sweep.Error = fmt.Sprint(status.Err)
+		}
+		if err := saveSweep(sweep); err != nil {
+			return nil, err
+		}
+		log.LogMessage(
+			"treasury_sweep_pollSweeps",
+			"sweep status changed",
+			"info",
+			logrus.Fields{
+				"sweep":  sweep.ID,
+				"tx":     sweep.Signature,
+				"status": sweep.Status,
+			},
+		)
+	}
+	return inFlight, nil
+}
+
+/**
+* @Internal
+* Sends `amount` of the token from hot wallet to `coldWallet`.
+* Sweep is recorded before broadcast, so that a transaction which
+* may land is always tracked.
+ */
+func sweep(token solana.SplTokenMeta, amount uint64, coldWallet string) error {
+	// 1. Sign.
+	signed, err := solana.SignWithdrawal(
+		&solana.WithdrawalRequest{
+			To:     coldWallet,
+			Mint:   token.MintAddress.String(),
+			Amount: amount,
+		},
+		0,
+	)
+	if err != nil {
+		return utils.MakeError(
+			"treasury_sweep",
+			"sweep",
+			"failed to sign sweep",
+			err,
+		)
+	}
+
+	// 2. Record.
+	record := models.TreasurySweep{
+		Mint:                 token.MintAddress.String(),
+		To:                   coldWallet,
+		Amount:               amount,
+		Signature:            signed.Signature,
+		LastValidBlockHeight: signed.LastValidBlockHeight,
+		Status:               models.WithdrawalSent,
+	}
+	if err := saveSweep(&record); err != nil {
+		return err
+	}
+
+	// 3. Broadcast.
+	if err := solana.BroadcastTx(signed.Tx); err != nil {
+		log.LogMessage(
+			"treasury_sweep_sweep",
+			"failed to broadcast, tracking until expiry",
+			"error",
+			logrus.Fields{
+				"sweep": record.ID,
+				"tx":    record.Signature,
+				"error": err.Error(),
+			},
+		)
+		return nil
+	}
+	log.LogMessage(
+		"treasury_sweep_sweep",
+		"swept hot wallet excess",
+		"success",
+		logrus.Fields{
+			"sweep":  record.ID,
+			"tx":     record.Signature,
+			"token":  token.Keyword,
+			"amount": amount,
+		},
+	)
+	return nil
+}
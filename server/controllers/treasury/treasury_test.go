@@ -0,0 +1,140 @@
+package treasury
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/controllers/price_oracle"
+	"github.com/Duelana-Team/duelana-v1/controllers/solana"
+	"github.com/Duelana-Team/duelana-v1/models"
+	solanaGo "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestSweepAmount(t *testing.T) {
+	token := solana.SplTokenMeta{
+		Keyword:      "SOL",
+		HotWalletMin: 100,
+		HotWalletMax: 300,
+	}
+	for amount, expected := range map[uint64]HotWalletStatus{
+		99:  HotWalletLow,
+		100: HotWalletNormal,
+		300: HotWalletNormal,
+		301: HotWalletExcess,
+	} {
+		if status := hotWalletStatus(amount, token); status != expected {
+			t.Fatalf("unexpected status of %d: %s", amount, status)
+		}
+	}
+	if status := hotWalletStatus(0, solana.SplTokenMeta{}); status != HotWalletNormal {
+		t.Fatalf("token without thresholds should be normal: %s", status)
+	}
+
+	target := config.TREASURY_SWEEP_TARGET
+	config.TREASURY_SWEEP_TARGET = 5000
+	defer func() { config.TREASURY_SWEEP_TARGET = target }()
+	for amount, expected := range map[uint64]uint64{
+		50:   0,
+		300:  0,
+		301:  101,
+		1000: 800,
+	} {
+		if swept := sweepAmount(amount, token); swept != expected {
+			t.Fatalf("unexpected sweep of %d: %d", amount, swept)
+		}
+	}
+	if swept := sweepAmount(1000, solana.SplTokenMeta{HotWalletMin: 100}); swept != 0 {
+		t.Fatalf("token without max should not be swept: %d", swept)
+	}
+}
+
+func TestNextSweepStatus(t *testing.T) {
+	sweep := models.TreasurySweep{
+		Status:               models.WithdrawalSent,
+		LastValidBlockHeight: 1000,
+	}
+	for name, c := range map[string]struct {
+		status      *rpc.SignatureStatusesResult
+		blockHeight uint64
+		expected    models.WithdrawalStatus
+	}{
+		"unknown":   {nil, 1000, models.WithdrawalSent},
+		"expired":   {nil, 1001, models.WithdrawalExpired},
+		"failed":    {&rpc.SignatureStatusesResult{Err: "InstructionError"}, 900, models.WithdrawalFailed},
+		"confirmed": {&rpc.SignatureStatusesResult{ConfirmationStatus: rpc.ConfirmationStatusConfirmed}, 900, models.WithdrawalConfirmed},
+		"finalized": {&rpc.SignatureStatusesResult{ConfirmationStatus: rpc.ConfirmationStatusFinalized}, 1001, models.WithdrawalFinalized},
+	} {
+		if next := nextSweepStatus(&sweep, c.status, c.blockHeight); next != c.expected {
+			t.Fatalf("%s: unexpected status: %s", name, next)
+		}
+	}
+}
+
+func TestBuildReport(t *testing.T) {
+	sol := solana.SplTokenMeta{
+		MintAddress:  solanaGo.MustPublicKeyFromBase58(config.SOL_SPL_ADDRESS),
+		Keyword:      "SOL",
+		Decimals:     9,
+		HotWalletMin: 10000000000,
+	}
+	usdc := solana.SplTokenMeta{
+		MintAddress: solanaGo.MustPublicKeyFromBase58(config.USDC_SPL_ADDRESS),
+		Keyword:     "USDC",
+		Decimals:    6,
+	}
+	bonk := solana.SplTokenMeta{
+		MintAddress: solanaGo.MustPublicKeyFromBase58(config.BONK_SPL_ADDRESS),
+		Keyword:     "Bonk",
+		Decimals:    5,
+	}
+	getPrice := func(token *solana.SplTokenMeta) (*price_oracle.Price, error) {
+		switch token.Keyword {
+		case "SOL":
+			return &price_oracle.Price{Price: 150}, nil
+		case "USDC":
+			return &price_oracle.Price{Price: 1}, nil
+		}
+		return nil, errors.New("unsafe price")
+	}
+	now := time.Now()
+
+	report := buildReport(
+		[]solana.TokenHolding{
+			{Token: sol, Amount: 4000000000},  // 4 SOL
+			{Token: usdc, Amount: 250000000},  // 250 USDC
+			{Token: bonk, Amount: 1000000000}, // Not priced
+		},
+		[]solana.TokenHolding{
+			{Token: sol, Amount: 6000000000}, // 6 SOL
+		},
+		1500*config.ONE_CHIP_WITH_DECIMALS,
+		250*config.ONE_CHIP_WITH_DECIMALS,
+		getPrice,
+		now,
+	)
+	if len(report.Tokens) != 3 ||
+		report.Tokens[0].Status != HotWalletLow ||
+		report.Tokens[0].ColdAmount != 6000000000 ||
+		report.Tokens[0].Chips != 1500*config.ONE_CHIP_WITH_DECIMALS ||
+		report.Tokens[1].Chips != 250*config.ONE_CHIP_WITH_DECIMALS ||
+		report.Tokens[2].Chips != 0 ||
+		report.Tokens[2].PriceError == "" {
+		t.Fatalf("unexpected token reports: %+v", report.Tokens)
+	}
+	if report.Complete ||
+		report.HoldingChips != 1750*config.ONE_CHIP_WITH_DECIMALS ||
+		report.LiabilityChips != 1750*config.ONE_CHIP_WITH_DECIMALS ||
+		report.SurplusChips != 0 ||
+		report.CoverageBps != 10000 ||
+		!report.At.Equal(now) {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	if empty := buildReport(nil, nil, 0, 0, getPrice, now); empty.CoverageBps != 0 ||
+		!empty.Complete {
+		t.Fatalf("unexpected report without liabilities: %+v", empty)
+	}
+}
@@ -0,0 +1,42 @@
+package treasury
+
+import "time"
+
+// Hot wallet balance of a token against its thresholds.
+type HotWalletStatus string
+
+const (
+	HotWalletLow    HotWalletStatus = "low"
+	HotWalletNormal HotWalletStatus = "normal"
+	HotWalletExcess HotWalletStatus = "excess"
+)
+
+// On chain holdings of a token, valued in chips.
+type TokenReport struct {
+	Mint         string          `json:"mint"`
+	Keyword      string          `json:"keyword"`
+	Decimals     int             `json:"decimals"`
+	HotAmount    uint64          `json:"hotAmount"`  // In token base units
+	ColdAmount   uint64          `json:"coldAmount"` // In token base units
+	HotWalletMin uint64          `json:"hotWalletMin"`
+	HotWalletMax uint64          `json:"hotWalletMax"`
+	Status       HotWalletStatus `json:"status"`
+	Price        float64         `json:"price"` // In USD, 0 when not priced
+	Chips        int64           `json:"chips"` // Of hot and cold amounts
+	PriceError   string          `json:"priceError,omitempty"`
+}
+
+// Treasury holdings compared with chips owed to users.
+// Holdings are understated while any token is not priced.
+type Report struct {
+	Tokens                 []TokenReport `json:"tokens"`
+	ColdWallet             string        `json:"coldWallet"`
+	HoldingChips           int64         `json:"holdingChips"`
+	UserChips              int64         `json:"userChips"`
+	PendingWithdrawalChips int64         `json:"pendingWithdrawalChips"`
+	LiabilityChips         int64         `json:"liabilityChips"`
+	SurplusChips           int64         `json:"surplusChips"` // Negative when undercollateralized
+	CoverageBps            int64         `json:"coverageBps"`  // Of liabilities, 0 without liabilities
+	Complete               bool          `json:"complete"`     // Whether every token is priced
+	At                     time.Time     `json:"at"`
+}
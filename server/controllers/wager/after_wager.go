@@ -3,9 +3,11 @@ package wager
 import (
 	"errors"
 
+	"github.com/Duelana-Team/duelana-v1/controllers/quest"
 	"github.com/Duelana-Team/duelana-v1/controllers/redis"
 	"github.com/Duelana-Team/duelana-v1/controllers/weekly_raffle"
 	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/metrics"
 	"github.com/Duelana-Team/duelana-v1/models"
 	"github.com/Duelana-Team/duelana-v1/utils"
 	"github.com/sirupsen/logrus"
@@ -18,6 +20,12 @@ func AfterWager(params PerformAfterWagerParams) error {
 	// Set recently-wagered redis zset.
 	setRecentlyWagered(&params)
 
+	// Record bets and payouts telemetry.
+	observeWagerMetrics(&params)
+
+	// Update quest progresses.
+	quest.QueueWager(buildQuestEvents(&params))
+
 	if isHouseGame(&params) {
 		// Set for weekly raffle.
 		for _, player := range params.Players {
@@ -69,6 +77,21 @@ func isHouseGame(params *PerformAfterWagerParams) bool {
 				params.IsHouseGame))
 }
 
+func buildQuestEvents(params *PerformAfterWagerParams) []quest.QuestEvent {
+	events := []quest.QuestEvent{}
+	for _, player := range params.Players {
+		events = append(events, quest.QuestEvent{
+			UserID:     player.UserID,
+			Type:       params.Type,
+			Bet:        player.Bet,
+			Profit:     player.Profit,
+			Multiplier: player.Multiplier,
+			Level:      player.Level,
+		})
+	}
+	return events
+}
+
 func setUserStatistics(params *PerformAfterWagerParams) error {
 	for _, player := range params.Players {
 		if player.Profit > 0 {
@@ -90,6 +113,20 @@ func setUserStatistics(params *PerformAfterWagerParams) error {
 	return nil
 }
 
+func observeWagerMetrics(params *PerformAfterWagerParams) {
+	for _, player := range params.Players {
+		payout := int64(0)
+		if player.Profit > 0 {
+			payout = player.Bet + player.Profit
+		}
+		metrics.ObserveWager(
+			string(params.Type),
+			player.Bet,
+			payout,
+		)
+	}
+}
+
 func setRecentlyWagered(params *PerformAfterWagerParams) error {
 	errStr := ""
 	for _, player := range params.Players {
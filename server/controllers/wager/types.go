@@ -3,9 +3,11 @@ package wager
 import "github.com/Duelana-Team/duelana-v1/models"
 
 type PlayerInPerformAfterWagerParams struct {
-	UserID uint
-	Bet    int64
-	Profit int64
+	UserID     uint
+	Bet        int64
+	Profit     int64
+	Multiplier float64 // Crash payout multiplier.
+	Level      uint    // Dreamtower level reached.
 }
 
 type PerformAfterWagerParams struct {
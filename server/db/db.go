@@ -6,7 +6,6 @@ import (
 	"time"
 
 	Logger "github.com/Duelana-Team/duelana-v1/log"
-	"github.com/Duelana-Team/duelana-v1/models"
 	"github.com/sirupsen/logrus"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -36,45 +35,6 @@ func ConnectDB(DBUrl string) *gorm.DB {
 
 	Logger.LogMessage("connect db", "success", "info", logrus.Fields{})
 
-	err = db.AutoMigrate(
-		&models.Payment{},
-		&models.NftCollection{},
-		&models.User{},
-		&models.Statistics{},
-		&models.Wallet{},
-		&models.Transaction{},
-		&models.ChipBalance{},
-		&models.NftBalance{},
-		&models.Balance{},
-		&models.DepositedNft{},
-		&models.JackpotRound{}, &models.JackpotPlayer{}, &models.JackpotBet{},
-		&models.CoinflipRound{},
-		&models.NftInGame{},
-		&models.ClientSeed{}, &models.ServerSeed{}, &models.SeedPair{},
-		&models.DreamTowerRound{},
-		&models.DuelBot{},
-		&models.Rakeback{},
-		&models.ServerConfig{},
-		&models.Affiliate{},
-		&models.ActiveAffiliate{},
-		&models.AffiliateLifetime{},
-		&models.Coupon{},
-		&models.ClaimedCoupon{},
-		&models.CouponTransaction{},
-		&models.CrashRound{},
-		&models.CrashBet{},
-		&models.SelfExclusion{},
-		&models.DailyRaceRewards{},
-		&models.CouponShortcut{},
-		&models.WeeklyRaffleTicket{},
-		&models.WeeklyRaffle{},
-	)
-
-	if err != nil {
-		Logger.LogMessage("migrate db", "failed", "error", logrus.Fields{"error": err.Error()})
-		logrus.Fatal(err.Error())
-	}
-
 	return db
 }
 
@@ -0,0 +1,8 @@
+package migrate
+
+// Error code range: #111xxx
+const ErrCodeBase = "#111"
+const ErrCodeInvalidMigrations = ErrCodeBase + "000"
+const ErrCodePendingMigrations = ErrCodeBase + "001"
+const ErrCodeUnknownMigrations = ErrCodeBase + "002"
+const ErrCodeIrreversibleMigration = ErrCodeBase + "003"
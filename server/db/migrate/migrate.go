@@ -0,0 +1,369 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Postgres advisory lock key preventing concurrent migrators.
+const MIGRATION_LOCK_KEY = int64(20240101)
+
+/**
+* @Internal
+* Validates migrations are ordered by unique version and complete.
+ */
+func validate(migrations []Migration) error {
+	for i, migration := range migrations {
+		if migration.Version == 0 ||
+			migration.Name == "" ||
+			migration.Up == nil {
+			return utils.MakeErrorWithCode(
+				"migrate",
+				"validate",
+				"incomplete migration",
+				ErrCodeInvalidMigrations,
+				fmt.Errorf(
+					"version: %d, name: %s",
+					migration.Version, migration.Name,
+				),
+			)
+		}
+		if i > 0 && migration.Version <= migrations[i-1].Version {
+			return utils.MakeErrorWithCode(
+				"migrate",
+				"validate",
+				"migrations should be ordered by unique version",
+				ErrCodeInvalidMigrations,
+				fmt.Errorf(
+					"prev: %d, version: %d",
+					migrations[i-1].Version, migration.Version,
+				),
+			)
+		}
+	}
+	return nil
+}
+
+/**
+* @Internal
+* Builds status of registered and applied migrations ordered by version.
+ */
+func buildStatus(
+	migrations []Migration,
+	applied []models.SchemaMigration,
+) []MigrationStatus {
+	appliedAt := map[uint64]time.Time{}
+	for _, record := range applied {
+		appliedAt[record.Version] = record.AppliedAt
+	}
+
+	result := []MigrationStatus{}
+	known := map[uint64]bool{}
+	for _, migration := range migrations {
+		known[migration.Version] = true
+		status := MigrationStatus{
+			Version: migration.Version,
+			Name:    migration.Name,
+			Known:   true,
+		}
+		if at, ok := appliedAt[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = &at
+		}
+		result = append(result, status)
+	}
+
+	for _, record := range applied {
+		if known[record.Version] {
+			continue
+		}
+		at := record.AppliedAt
+		status := MigrationStatus{
+			Version:   record.Version,
+			Name:      record.Name,
+			Applied:   true,
+			AppliedAt: &at,
+		}
+		i := 0
+		for i < len(result) && result[i].Version < status.Version {
+			i++
+		}
+		result = append(result[:i], append([]MigrationStatus{status}, result[i:]...)...)
+	}
+	return result
+}
+
+/**
+* @Internal
+* Creates schema table if missing and returns applied migrations.
+ */
+func getApplied(db *gorm.DB) ([]models.SchemaMigration, error) {
+	if db == nil {
+		return nil, utils.MakeError(
+			"migrate",
+			"getApplied",
+			"invalid db pointer",
+			errors.New("db is nil"),
+		)
+	}
+
+	if err := db.AutoMigrate(&models.SchemaMigration{}); err != nil {
+		return nil, utils.MakeError(
+			"migrate",
+			"getApplied",
+			"failed to create schema table",
+			err,
+		)
+	}
+
+	applied := []models.SchemaMigration{}
+	if err := db.Order(
+		"version",
+	).Find(&applied).Error; err != nil {
+		return nil, utils.MakeError(
+			"migrate",
+			"getApplied",
+			"failed to retrieve applied migrations",
+			err,
+		)
+	}
+	return applied, nil
+}
+
+/**
+* @External
+* Returns status of every registered and applied migration.
+ */
+func GetStatus(db *gorm.DB, migrations []Migration) ([]MigrationStatus, error) {
+	if err := validate(migrations); err != nil {
+		return nil, err
+	}
+	applied, err := getApplied(db)
+	if err != nil {
+		return nil, err
+	}
+	return buildStatus(migrations, applied), nil
+}
+
+/**
+* @External
+* Refuses to serve with pending migrations, or with migrations
+* applied by a newer binary.
+* Returns error on
+*  - Pending migrations. `ErrCodePendingMigrations`
+*  - Unknown applied migrations. `ErrCodeUnknownMigrations`
+ */
+func CheckPending(db *gorm.DB, migrations []Migration) error {
+	statuses, err := GetStatus(db, migrations)
+	if err != nil {
+		return err
+	}
+
+	pending, unknown := []uint64{}, []uint64{}
+	for _, status := range statuses {
+		if !status.Known {
+			unknown = append(unknown, status.Version)
+		} else if !status.Applied {
+			pending = append(pending, status.Version)
+		}
+	}
+	if len(pending) > 0 {
+		return utils.MakeErrorWithCode(
+			"migrate",
+			"CheckPending",
+			"pending migrations, run `migrate up` first",
+			ErrCodePendingMigrations,
+			fmt.Errorf("versions: %v", pending),
+		)
+	}
+	if len(unknown) > 0 {
+		return utils.MakeErrorWithCode(
+			"migrate",
+			"CheckPending",
+			"database has migrations unknown to this binary",
+			ErrCodeUnknownMigrations,
+			fmt.Errorf("versions: %v", unknown),
+		)
+	}
+	return nil
+}
+
+/**
+* @Internal
+* Runs a migration step and records it in one transaction,
+* holding the migration lock.
+ */
+func runStep(
+	db *gorm.DB,
+	step func(tx *gorm.DB) error,
+	record func(tx *gorm.DB) error,
+) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(
+			"select pg_advisory_xact_lock(?)",
+			MIGRATION_LOCK_KEY,
+		).Error; err != nil {
+			return err
+		}
+		if err := step(tx); err != nil {
+			return err
+		}
+		return record(tx)
+	})
+}
+
+/**
+* @External
+* Applies pending migrations in order, up to `target` version.
+* Applies every pending migration when `target` is zero.
+ */
+func Up(
+	db *gorm.DB,
+	migrations []Migration,
+	target uint64,
+) ([]Migration, error) {
+	statuses, err := GetStatus(db, migrations)
+	if err != nil {
+		return nil, err
+	}
+	applied := map[uint64]bool{}
+	for _, status := range statuses {
+		applied[status.Version] = status.Applied
+	}
+
+	performed := []Migration{}
+	for _, migration := range migrations {
+		if applied[migration.Version] {
+			continue
+		}
+		if target != 0 && migration.Version > target {
+			break
+		}
+
+		if err := runStep(
+			db,
+			migration.Up,
+			func(tx *gorm.DB) error {
+				return tx.Create(&models.SchemaMigration{
+					Version:   migration.Version,
+					Name:      migration.Name,
+					AppliedAt: time.Now(),
+				}).Error
+			},
+		); err != nil {
+			return performed, utils.MakeError(
+				"migrate",
+				"Up",
+				"failed to apply migration",
+				fmt.Errorf(
+					"version: %d, name: %s, err: %v",
+					migration.Version, migration.Name, err,
+				),
+			)
+		}
+		performed = append(performed, migration)
+
+		log.LogMessage(
+			"migrate",
+			"applied migration",
+			"success",
+			logrus.Fields{
+				"version": migration.Version,
+				"name":    migration.Name,
+			},
+		)
+	}
+	return performed, nil
+}
+
+/**
+* @External
+* Rolls back last `steps` applied migrations in reverse order.
+* Returns error on
+*  - Irreversible migration. `ErrCodeIrreversibleMigration`
+*  - Unknown applied migration. `ErrCodeUnknownMigrations`
+ */
+func Down(
+	db *gorm.DB,
+	migrations []Migration,
+	steps int,
+) ([]Migration, error) {
+	statuses, err := GetStatus(db, migrations)
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[uint64]Migration{}
+	for _, migration := range migrations {
+		byVersion[migration.Version] = migration
+	}
+
+	performed := []Migration{}
+	for i := len(statuses) - 1; i >= 0 && len(performed) < steps; i-- {
+		status := statuses[i]
+		if !status.Applied {
+			continue
+		}
+		if !status.Known {
+			return performed, utils.MakeErrorWithCode(
+				"migrate",
+				"Down",
+				"cannot roll back unknown migration",
+				ErrCodeUnknownMigrations,
+				fmt.Errorf("version: %d", status.Version),
+			)
+		}
+		migration := byVersion[status.Version]
+		if migration.Down == nil {
+			return performed, utils.MakeErrorWithCode(
+				"migrate",
+				"Down",
+				"irreversible migration",
+				ErrCodeIrreversibleMigration,
+				fmt.Errorf(
+					"version: %d, name: %s",
+					migration.Version, migration.Name,
+				),
+			)
+		}
+
+		if err := runStep(
+			db,
+			migration.Down,
+			func(tx *gorm.DB) error {
+				return tx.Delete(
+					&models.SchemaMigration{},
+					migration.Version,
+				).Error
+			},
+		); err != nil {
+			return performed, utils.MakeError(
+				"migrate",
+				"Down",
+				"failed to roll back migration",
+				fmt.Errorf(
+					"version: %d, name: %s, err: %v",
+					migration.Version, migration.Name, err,
+				),
+			)
+		}
+		performed = append(performed, migration)
+
+		log.LogMessage(
+			"migrate",
+			"rolled back migration",
+			"success",
+			logrus.Fields{
+				"version": migration.Version,
+				"name":    migration.Name,
+			},
+		)
+	}
+	return performed, nil
+}
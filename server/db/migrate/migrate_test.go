@@ -0,0 +1,71 @@
+package migrate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"gorm.io/gorm"
+)
+
+func noop(tx *gorm.DB) error {
+	return nil
+}
+
+func TestValidate(t *testing.T) {
+	if err := validate([]Migration{
+		{Version: 1, Name: "first", Up: noop},
+		{Version: 2, Name: "second", Up: noop, Down: noop},
+	}); err != nil {
+		t.Fatalf("ordered migrations should be valid: %v", err)
+	}
+	if err := validate([]Migration{
+		{Version: 2, Name: "second", Up: noop},
+		{Version: 1, Name: "first", Up: noop},
+	}); !utils.IsErrorCode(err, ErrCodeInvalidMigrations) {
+		t.Fatalf("unordered migrations should be invalid: %v", err)
+	}
+	if err := validate([]Migration{
+		{Version: 1, Name: "first", Up: noop},
+		{Version: 1, Name: "duplicated", Up: noop},
+	}); !utils.IsErrorCode(err, ErrCodeInvalidMigrations) {
+		t.Fatalf("duplicated versions should be invalid: %v", err)
+	}
+	if err := validate([]Migration{
+		{Version: 1, Name: "first"},
+	}); !utils.IsErrorCode(err, ErrCodeInvalidMigrations) {
+		t.Fatalf("migration without up should be invalid: %v", err)
+	}
+}
+
+func TestBuildStatus(t *testing.T) {
+	now := time.Now()
+	statuses := buildStatus(
+		[]Migration{
+			{Version: 10, Name: "first", Up: noop},
+			{Version: 30, Name: "third", Up: noop},
+		},
+		[]models.SchemaMigration{
+			{Version: 10, Name: "first", AppliedAt: now},
+			{Version: 20, Name: "newer", AppliedAt: now},
+		},
+	)
+	if len(statuses) != 3 {
+		t.Fatalf("should include registered and unknown migrations: %v", statuses)
+	}
+	if statuses[0].Version != 10 ||
+		!statuses[0].Applied ||
+		!statuses[0].Known {
+		t.Fatalf("first should be applied: %v", statuses[0])
+	}
+	if statuses[1].Version != 20 ||
+		!statuses[1].Applied ||
+		statuses[1].Known {
+		t.Fatalf("unknown applied migration should be ordered by version: %v", statuses[1])
+	}
+	if statuses[2].Version != 30 ||
+		statuses[2].Applied {
+		t.Fatalf("third should be pending: %v", statuses[2])
+	}
+}
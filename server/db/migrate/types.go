@@ -0,0 +1,31 @@
+package migrate
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+/**
+* Versioned schema or data migration.
+* `Version` orders migrations, formatted as YYYYMMDDHHMM.
+* `Down` is nil for irreversible migrations.
+ */
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+/**
+* Status of a migration against the database.
+* `Known` is false for versions applied by a newer binary.
+ */
+type MigrationStatus struct {
+	Version   uint64     `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"appliedAt"`
+	Known     bool       `json:"known"`
+}
@@ -0,0 +1,86 @@
+package log
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Field names attached to entries.
+const (
+	CALLER_FIELD     = "caller"
+	OUTCOME_FIELD    = "outcome"
+	REQUEST_ID_FIELD = "requestId"
+	ROUND_ID_FIELD   = "roundId"
+)
+
+// REQUEST_ID_KEY is also the gin context key, so *gin.Context
+// resolves it through ctx.Value without a request context.
+const REQUEST_ID_KEY = "duelana-request-id"
+const REQUEST_ID_HEADER = "X-Request-ID"
+
+type roundIDKey struct{}
+
+/**
+* @External
+* Returns a copy of `ctx` carrying `requestID`.
+ */
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, REQUEST_ID_KEY, requestID)
+}
+
+/**
+* @External
+* Returns the request id carried by `ctx`, or empty string.
+ */
+func GetRequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(REQUEST_ID_KEY).(string)
+	return requestID
+}
+
+/**
+* @External
+* Returns a copy of `ctx` carrying the round correlation id
+* of `game` round `roundID`.
+ */
+func WithRound(ctx context.Context, game string, roundID interface{}) context.Context {
+	return context.WithValue(ctx, roundIDKey{}, RoundCorrelationID(game, roundID))
+}
+
+/**
+* @External
+* Returns the round correlation id carried by `ctx`, or empty string.
+ */
+func GetRoundID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	roundID, _ := ctx.Value(roundIDKey{}).(string)
+	return roundID
+}
+
+/**
+* @External
+* Round correlation id shared by every log line of a round,
+* e.g. "crash-1024".
+ */
+func RoundCorrelationID(game string, roundID interface{}) string {
+	return fmt.Sprintf("%s-%v", game, roundID)
+}
+
+/**
+* @External
+* Returns `fields` with the round correlation id attached.
+* A nil `fields` is allocated.
+ */
+func WithRoundID(fields logrus.Fields, game string, roundID interface{}) logrus.Fields {
+	if fields == nil {
+		fields = logrus.Fields{}
+	}
+	fields[ROUND_ID_FIELD] = RoundCorrelationID(game, roundID)
+	return fields
+}
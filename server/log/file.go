@@ -0,0 +1,141 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const ROTATED_FILE_DAY_LAYOUT = "2006-01-02"
+
+/**
+* @Internal
+* Appends to `path`. On the first write of a new UTC day the
+* current file is renamed to `<name>.<previous day><ext>` and
+* rotated files older than `maxAgeDays` are removed.
+ */
+type rotatingFile struct {
+	mutex      sync.Mutex
+	path       string
+	maxAgeDays int
+	file       *os.File
+	closed     bool
+	day        string
+	now        func() time.Time
+}
+
+func newRotatingFile(path string, maxAgeDays int) (*rotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	r := &rotatingFile{
+		path:       path,
+		maxAgeDays: maxAgeDays,
+		now:        time.Now,
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	r.file = file
+	r.day = r.now().UTC().Format(ROTATED_FILE_DAY_LAYOUT)
+
+	// Pick up a file left over from a previous day.
+	if info, err := file.Stat(); err == nil && info.Size() > 0 {
+		r.day = info.ModTime().UTC().Format(ROTATED_FILE_DAY_LAYOUT)
+	}
+	return nil
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	// Late writes from a replaced logger are dropped.
+	if r.closed {
+		return len(p), nil
+	}
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+	if today := r.now().UTC().Format(ROTATED_FILE_DAY_LAYOUT); today != r.day {
+		if err := r.rotate(today); err != nil {
+			return 0, err
+		}
+	}
+	return r.file.Write(p)
+}
+
+func (r *rotatingFile) rotate(today string) error {
+	// 1. Close and rename current file.
+	r.file.Close()
+	r.file = nil
+	if err := os.Rename(r.path, r.rotatedPath(r.day)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	// 2. Open a fresh file for today.
+	if err := r.open(); err != nil {
+		return err
+	}
+	r.day = today
+
+	// 3. Remove expired rotations.
+	r.removeExpired()
+	return nil
+}
+
+func (r *rotatingFile) rotatedPath(day string) string {
+	ext := filepath.Ext(r.path)
+	return strings.TrimSuffix(r.path, ext) + "." + day + ext
+}
+
+func (r *rotatingFile) removeExpired() {
+	if r.maxAgeDays <= 0 {
+		return
+	}
+	ext := filepath.Ext(r.path)
+	prefix := strings.TrimSuffix(filepath.Base(r.path), ext) + "."
+	cutoff := r.now().UTC().AddDate(0, 0, -r.maxAgeDays).Format(ROTATED_FILE_DAY_LAYOUT)
+
+	entries, err := os.ReadDir(filepath.Dir(r.path))
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		day := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext)
+		if _, err := time.Parse(ROTATED_FILE_DAY_LAYOUT, day); err != nil {
+			continue
+		}
+		if day < cutoff {
+			os.Remove(filepath.Join(filepath.Dir(r.path), name))
+		}
+	}
+}
+
+func (r *rotatingFile) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.closed = true
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
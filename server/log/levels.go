@@ -0,0 +1,103 @@
+package log
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+)
+
+// Module levels, from most to least verbose.
+const (
+	LEVEL_INFO  = 1 // info, success and error
+	LEVEL_ERROR = 2 // error only
+	LEVEL_OFF   = 3 // nothing
+)
+
+var levelNames = map[string]int{
+	"info":    LEVEL_INFO,
+	"success": LEVEL_INFO,
+	"error":   LEVEL_ERROR,
+	"off":     LEVEL_OFF,
+}
+
+type moduleLevel struct {
+	prefix string
+	level  int
+}
+
+/**
+* @Internal
+* Minimum level per caller prefix. Modules are sorted by
+* prefix length descending so the longest match wins.
+ */
+type levelTable struct {
+	defaultLevel int
+	modules      []moduleLevel
+}
+
+/**
+* @Internal
+* Parses LOG_LEVELS such as "info,crash=error,payment=off".
+* An entry without `=` (or `default=`) sets the default level.
+* Module names are matched as case insensitive caller prefixes.
+ */
+func parseLevels(raw string) (levelTable, error) {
+	table := levelTable{}
+	defaultLevel, ok := levelNames[config.LOG_DEFAULT_LEVEL]
+	if !ok {
+		defaultLevel = LEVEL_INFO
+	}
+	table.defaultLevel = defaultLevel
+
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		module, levelName, hasModule := strings.Cut(item, "=")
+		if !hasModule {
+			module, levelName = "default", item
+		}
+		module = strings.ToLower(strings.TrimSpace(module))
+		level, ok := levelNames[strings.ToLower(strings.TrimSpace(levelName))]
+		if !ok {
+			return levelTable{}, fmt.Errorf("unknown log level: %s", item)
+		}
+		if module == "" {
+			return levelTable{}, fmt.Errorf("empty log module: %s", item)
+		}
+		if module == "default" {
+			table.defaultLevel = level
+			continue
+		}
+		table.modules = append(table.modules, moduleLevel{
+			prefix: module,
+			level:  level,
+		})
+	}
+
+	sort.SliceStable(table.modules, func(i, j int) bool {
+		return len(table.modules[i].prefix) > len(table.modules[j].prefix)
+	})
+	return table, nil
+}
+
+func (table levelTable) levelOf(caller string) int {
+	caller = strings.ToLower(caller)
+	for _, module := range table.modules {
+		if strings.HasPrefix(caller, module.prefix) {
+			return module.level
+		}
+	}
+	return table.defaultLevel
+}
+
+func (table levelTable) enabled(caller string, level string) bool {
+	messageLevel, ok := levelNames[level]
+	if !ok || messageLevel == LEVEL_OFF {
+		messageLevel = LEVEL_INFO
+	}
+	return messageLevel >= table.levelOf(caller)
+}
@@ -1,24 +1,38 @@
 package log
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/Duelana-Team/duelana-v1/config"
 	"github.com/TwiN/go-color"
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/credentials"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
-	logrus_cloudwatchlogs "github.com/kdar/logrus-cloudwatchlogs"
 	cron "github.com/robfig/cron/v3"
 	"github.com/sirupsen/logrus"
 )
 
+// Logger receives every accepted message and fans it out to the
+// configured sinks through hooks. Its own output is discarded.
 var Logger *logrus.Logger
 
+var loggerMutex sync.RWMutex
+var moduleLevels = levelTable{defaultLevel: LEVEL_INFO}
+
+func init() {
+	Logger = newLogger([]sink{newConsoleSink()})
+}
+
+/**
+* @External
+* Builds sinks and per module levels from config.
+* Sinks which fail to start are reported and skipped
+* so that one broken sink does not silence the others.
+ */
 func Init() {
 	Refresh()
 	c := cron.New(cron.WithLocation(time.UTC))
@@ -26,69 +40,230 @@ func Init() {
 	c.Start()
 }
 
+/**
+* @External
+* Rebuilds the logger. Called daily so that file and
+* CloudWatch sinks roll over to a new file / stream.
+ */
 func Refresh() {
-	config := config.Get()
-
-	sess := session.Must(session.NewSession(&aws.Config{
-		Region:      aws.String(config.AWSRegion),
-		Credentials: credentials.NewStaticCredentials(config.AWSAccessID, config.AWSSecretKey, ""),
-	}))
+	conf := config.Get()
 
-	streamName, err := getLogStream(sess, config.CloudWatchLogGroup)
+	// 1. Parse module levels.
+	levels, err := parseLevels(conf.LogLevels)
 	if err != nil {
-		// LogMessage("log refresher", "failed to get log stream", "error", logrus.Fields{"error": err.Error()})
-		return
+		LogMessage(
+			"log refresher",
+			"failed to parse log levels, keeping previous levels",
+			"error",
+			logrus.Fields{
+				"levels": conf.LogLevels,
+				"error":  err.Error(),
+			},
+		)
+		levels = getLevels()
 	}
 
-	lgr := logrus.New()
+	// 2. Register config secrets for value redaction.
+	registerSecrets(
+		conf.MasterWalletPriKey,
+		conf.AWSSecretKey,
+		conf.AWSAccessID,
+		conf.DBPassword,
+		conf.RandomKey,
+		conf.TatumApiKey,
+		conf.TatumHmacSecret,
+		conf.HyperspaceApiKey,
+		conf.IpGeolocationApiKey,
+		conf.JupiterApiAccessKey,
+		conf.MatricaApiAccessToken,
+		conf.AdminApiAccessToken,
+		conf.RedisPwd,
+		conf.WeeklyRaffleRandomKey,
+		conf.MetricsAccessToken,
+	)
 
-	hook, _ := logrus_cloudwatchlogs.NewHook(config.CloudWatchLogGroup, streamName, sess)
+	// 3. Build sinks.
+	sinks := []sink{}
+	failed := map[string]string{}
+	for _, name := range parseSinkNames(conf.LogSinks) {
+		s, err := newSink(name, conf)
+		if err != nil {
+			failed[name] = err.Error()
+			continue
+		}
+		sinks = append(sinks, s)
+	}
+	if len(sinks) == 0 {
+		sinks = append(sinks, newConsoleSink())
+	}
+
+	// 4. Swap logger and levels.
+	loggerMutex.Lock()
+	prev := Logger
+	Logger = newLogger(sinks)
+	moduleLevels = levels
+	loggerMutex.Unlock()
+	closeSinks(prev)
 
-	lgr.Hooks.Add(hook)
+	for name, reason := range failed {
+		LogMessage(
+			"log refresher",
+			"failed to start log sink",
+			"error",
+			logrus.Fields{
+				"sink":  name,
+				"error": reason,
+			},
+		)
+	}
+}
+
+func newLogger(sinks []sink) *logrus.Logger {
+	lgr := logrus.New()
 	lgr.Out = io.Discard
-	lgr.Formatter = &logrus.TextFormatter{FullTimestamp: true,
-		TimestampFormat: "2006-01-02 15:04:05"}
-	Logger = lgr
+	lgr.Level = logrus.InfoLevel
+	lgr.Formatter = &logrus.JSONFormatter{
+		TimestampFormat: time.RFC3339Nano,
+	}
+	for _, s := range sinks {
+		lgr.Hooks.Add(s)
+	}
+	return lgr
 }
 
-func getLogStream(sess *session.Session, logGroupName string) (string, error) {
-	cwl := cloudwatchlogs.New(sess)
-	name := time.Now().String()[:10]
+func getLevels() levelTable {
+	loggerMutex.RLock()
+	defer loggerMutex.RUnlock()
+	return moduleLevels
+}
 
-	var descending = true
-	resp, err := cwl.DescribeLogStreams(&cloudwatchlogs.DescribeLogStreamsInput{LogGroupName: &logGroupName, Descending: &descending})
-	if err != nil {
-		return name, err
+func getLogger() *logrus.Logger {
+	loggerMutex.RLock()
+	defer loggerMutex.RUnlock()
+	return Logger
+}
+
+/**
+* @External
+* Logs a message of `level` ("error", "info" or "success")
+* for `caller`. Dropped when below the caller's module level.
+* Secrets in message and fields are redacted.
+ */
+func LogMessage(caller string, message string, level string, fields logrus.Fields) {
+	logEntry(caller, message, level, fields)
+}
+
+/**
+* @External
+* Same as LogMessage, additionally attaching correlation
+* ids carried by `ctx` (request id, round id).
+ */
+func LogContext(ctx context.Context, caller string, message string, level string, fields logrus.Fields) {
+	if ctx == nil {
+		logEntry(caller, message, level, fields)
+		return
+	}
+	merged := logrus.Fields{}
+	for key, value := range fields {
+		merged[key] = value
+	}
+	if requestID := GetRequestID(ctx); requestID != "" {
+		merged[REQUEST_ID_FIELD] = requestID
 	}
+	if roundID := GetRoundID(ctx); roundID != "" {
+		merged[ROUND_ID_FIELD] = roundID
+	}
+	logEntry(caller, message, level, merged)
+}
 
-	for _, logStream := range resp.LogStreams {
-		if *logStream.LogStreamName == name {
-			return name, nil
-		}
+func logEntry(caller string, message string, level string, fields logrus.Fields) {
+	if !getLevels().enabled(caller, level) {
+		return
 	}
 
-	_, err = cwl.CreateLogStream(&cloudwatchlogs.CreateLogStreamInput{
-		LogGroupName:  &logGroupName,
-		LogStreamName: &name,
-	})
+	redacted := redactFields(fields)
+	redacted[CALLER_FIELD] = caller
+	message = redactString(message)
 
-	return name, err
+	entry := getLogger().WithFields(redacted)
+	switch level {
+	case "error":
+		entry.Error(message)
+	case "success":
+		entry.WithField(OUTCOME_FIELD, "success").Info(message)
+	default:
+		entry.WithField(OUTCOME_FIELD, level).Info(message)
+	}
 }
 
-func LogMessage(caller string, message string, level string, fields logrus.Fields) {
+func closeSinks(lgr *logrus.Logger) {
+	if lgr == nil {
+		return
+	}
+	closed := map[sink]bool{}
+	for _, hooks := range lgr.Hooks {
+		for _, hook := range hooks {
+			if s, ok := hook.(sink); ok && !closed[s] {
+				closed[s] = true
+				s.Close()
+			}
+		}
+	}
+}
+
+func parseSinkNames(raw string) []string {
+	if strings.TrimSpace(raw) == "" {
+		raw = config.LOG_DEFAULT_SINKS
+	}
+	names := []string{}
+	seen := map[string]bool{}
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// Human readable single line format, kept from the original logger.
+func formatConsoleLine(entry *logrus.Entry) string {
+	fields := logrus.Fields{}
+	caller := ""
+	outcome := ""
+	for key, value := range entry.Data {
+		switch key {
+		case CALLER_FIELD:
+			caller = fmt.Sprint(value)
+		case OUTCOME_FIELD:
+			outcome = fmt.Sprint(value)
+		default:
+			fields[key] = value
+		}
+	}
 	jsonString, _ := json.Marshal(fields)
-	switch level {
-	case "error":
-		fmt.Println(color.Colorize(color.Red, "-- "+caller+" -> "+message+" -- "+string(jsonString)))
-		// Logger.WithFields(fields).Errorln(caller + " -> " + message + " -- " + string(jsonString))
-	case "info":
-		fmt.Println(color.Colorize(color.Cyan, "-- "+caller+" -> "+message+" -- "+string(jsonString)))
-		// Logger.WithFields(fields).Infoln(caller + " -> " + message + " -- " + string(jsonString))
+	line := "-- " + caller + " -> " + entry.Message + " -- " + string(jsonString)
+
+	if entry.Level <= logrus.ErrorLevel {
+		return color.Colorize(color.Red, line)
+	}
+	switch outcome {
 	case "success":
-		fmt.Println(color.Colorize(color.Green, "-- "+caller+" -> "+message+" -- "+string(jsonString)))
-		// Logger.WithFields(fields).Println(caller + " -> " + message + " -- " + string(jsonString))
+		return color.Colorize(color.Green, line)
+	case "info":
+		return color.Colorize(color.Cyan, line)
 	default:
-		fmt.Println(color.Colorize(color.Purple, "-- "+caller+" -> "+message+" : "+level))
-		break
+		return color.Colorize(color.Purple, line+" : "+outcome)
 	}
 }
+
+var stdoutMutex sync.Mutex
+
+func writeStdout(line []byte) error {
+	stdoutMutex.Lock()
+	defer stdoutMutex.Unlock()
+	_, err := os.Stdout.Write(line)
+	return err
+}
@@ -0,0 +1,136 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestParseLevels(t *testing.T) {
+	table, err := parseLevels("error, crash=info ,crash_controller_status=off")
+	if err != nil {
+		t.Fatalf("failed to parse levels: %v", err)
+	}
+
+	if table.enabled("coinflip controller", "info") {
+		t.Fatal("info should be dropped by default error level")
+	}
+	if !table.enabled("coinflip controller", "error") {
+		t.Fatal("error should pass default error level")
+	}
+	if !table.enabled("crash round data", "success") {
+		t.Fatal("success should pass crash info level")
+	}
+	if table.enabled("crash_controller_status", "error") {
+		t.Fatal("longest prefix should win and turn module off")
+	}
+
+	if _, err := parseLevels("crash=verbose"); err == nil {
+		t.Fatal("unknown level should be rejected")
+	}
+	if _, err := parseLevels("=info"); err == nil {
+		t.Fatal("empty module should be rejected")
+	}
+
+	table, err = parseLevels("")
+	if err != nil || !table.enabled("any", "info") {
+		t.Fatal("empty levels should default to info")
+	}
+}
+
+func TestRedactFields(t *testing.T) {
+	registerSecrets("short", "supersecretvalue")
+	defer registerSecrets()
+
+	type wallet struct {
+		PublicKey  string `json:"publicKey"`
+		PrivateKey string `json:"privateKey"`
+	}
+
+	redacted := redactFields(logrus.Fields{
+		"pri_key": "abc",
+		"api-key": "abc",
+		"amount":  100,
+		"error":   errors.New("dial with supersecretvalue failed"),
+		"wallet":  wallet{PublicKey: "pub", PrivateKey: "pri"},
+		"round":   map[string]interface{}{"seed": "unrevealed", "hashedSeed": "hash"},
+		"note":    "short words stay",
+	})
+
+	if redacted["pri_key"] != REDACTED || redacted["api-key"] != REDACTED {
+		t.Fatalf("secret keys not redacted: %v", redacted)
+	}
+	if redacted["amount"] != 100 {
+		t.Fatalf("numbers should be kept: %v", redacted["amount"])
+	}
+	if redacted["error"] != "dial with [REDACTED] failed" {
+		t.Fatalf("secret value not redacted: %v", redacted["error"])
+	}
+	w := redacted["wallet"].(map[string]interface{})
+	if w["privateKey"] != REDACTED || w["publicKey"] != "pub" {
+		t.Fatalf("nested secret key not redacted: %v", w)
+	}
+	r := redacted["round"].(map[string]interface{})
+	if r["seed"] != REDACTED || r["hashedSeed"] != "hash" {
+		t.Fatalf("seed not redacted exactly: %v", r)
+	}
+	if redacted["note"] != "short words stay" {
+		t.Fatalf("short secrets should not be registered: %v", redacted["note"])
+	}
+}
+
+func TestCorrelationIDs(t *testing.T) {
+	ctx := WithRequestID(context.Background(), "req-1")
+	ctx = WithRound(ctx, "crash", 12)
+
+	if GetRequestID(ctx) != "req-1" {
+		t.Fatalf("unexpected request id: %s", GetRequestID(ctx))
+	}
+	if GetRoundID(ctx) != "crash-12" {
+		t.Fatalf("unexpected round id: %s", GetRoundID(ctx))
+	}
+	if fields := WithRoundID(nil, "coinflip", uint(3)); fields[ROUND_ID_FIELD] != "coinflip-3" {
+		t.Fatalf("unexpected round fields: %v", fields)
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "duelana.log")
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	expired := filepath.Join(dir, "duelana.2026-09-01.log")
+	if err := os.WriteFile(expired, []byte("old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := newRotatingFile(path, 14)
+	if err != nil {
+		t.Fatalf("failed to open rotating file: %v", err)
+	}
+	r.now = func() time.Time { return now }
+	r.day = now.Format(ROTATED_FILE_DAY_LAYOUT)
+
+	r.Write([]byte("first\n"))
+	now = now.AddDate(0, 0, 1)
+	r.Write([]byte("second\n"))
+	r.Close()
+	r.Write([]byte("dropped\n"))
+
+	rotated, err := os.ReadFile(filepath.Join(dir, "duelana.2026-10-01.log"))
+	if err != nil || string(rotated) != "first\n" {
+		t.Fatalf("unexpected rotated file: %q, %v", rotated, err)
+	}
+	current, err := os.ReadFile(path)
+	if err != nil || string(current) != "second\n" {
+		t.Fatalf("unexpected current file: %q, %v", current, err)
+	}
+	if _, err := os.Stat(expired); !os.IsNotExist(err) {
+		t.Fatalf("expired rotation should be removed: %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/sirupsen/logrus"
+)
+
+const REDACTED = "[REDACTED]"
+
+// Secret values are at least this long to be replaced
+// inside arbitrary strings, avoiding masking short words.
+const MIN_SECRET_LENGTH = 8
+
+var secretsMutex sync.RWMutex
+var secrets = []string{}
+
+/**
+* @Internal
+* Registers secret values which are masked wherever they
+* appear in messages and field values.
+ */
+func registerSecrets(values ...string) {
+	registered := []string{}
+	for _, value := range values {
+		if len(value) >= MIN_SECRET_LENGTH {
+			registered = append(registered, value)
+		}
+	}
+
+	secretsMutex.Lock()
+	defer secretsMutex.Unlock()
+	secrets = registered
+}
+
+func redactString(value string) string {
+	secretsMutex.RLock()
+	defer secretsMutex.RUnlock()
+	for _, secret := range secrets {
+		if strings.Contains(value, secret) {
+			value = strings.ReplaceAll(value, secret, REDACTED)
+		}
+	}
+	return value
+}
+
+func isSecretKey(key string) bool {
+	normalized := strings.ToLower(key)
+	normalized = strings.ReplaceAll(normalized, "_", "")
+	normalized = strings.ReplaceAll(normalized, "-", "")
+	for _, exact := range config.LOG_REDACTED_EXACT_FIELD_KEYS {
+		if normalized == exact {
+			return true
+		}
+	}
+	for _, suffix := range config.LOG_REDACTED_FIELD_KEYS {
+		if strings.HasSuffix(normalized, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+/**
+* @Internal
+* Returns a copy of `fields` with secret keys masked at any
+* depth and registered secret values replaced. Structs,
+* maps and slices are flattened through JSON for inspection.
+ */
+func redactFields(fields logrus.Fields) logrus.Fields {
+	redacted := logrus.Fields{}
+	for key, value := range fields {
+		if isSecretKey(key) {
+			redacted[key] = REDACTED
+			continue
+		}
+		redacted[key] = redactValue(value)
+	}
+	return redacted
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return redactString(v)
+	case error:
+		return redactString(v.Error())
+	case fmt.Stringer:
+		return redactString(v.String())
+	case bool, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64,
+		float32, float64:
+		return v
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return redactString(fmt.Sprint(value))
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		return redactString(string(encoded))
+	}
+	return redactDecoded(decoded)
+}
+
+func redactDecoded(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return redactString(v)
+	case map[string]interface{}:
+		for key, item := range v {
+			if isSecretKey(key) {
+				v[key] = REDACTED
+				continue
+			}
+			v[key] = redactDecoded(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactDecoded(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,201 @@
+package log
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	logrus_cloudwatchlogs "github.com/kdar/logrus-cloudwatchlogs"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	SINK_CONSOLE    = "console"
+	SINK_JSON       = "json"
+	SINK_FILE       = "file"
+	SINK_CLOUDWATCH = "cloudwatch"
+)
+
+// A sink is a logrus hook owning its destination.
+type sink interface {
+	logrus.Hook
+	Close()
+}
+
+func newSink(name string, conf config.Config) (sink, error) {
+	switch name {
+	case SINK_CONSOLE:
+		return newConsoleSink(), nil
+	case SINK_JSON:
+		return newJSONSink(), nil
+	case SINK_FILE:
+		return newFileSink(conf.LogFilePath)
+	case SINK_CLOUDWATCH:
+		return newCloudWatchSink(conf)
+	default:
+		return nil, fmt.Errorf("unknown log sink: %s", name)
+	}
+}
+
+/**
+* @Internal
+* Colored text lines on stdout, for local development.
+ */
+type consoleSink struct{}
+
+func newConsoleSink() *consoleSink {
+	return &consoleSink{}
+}
+
+func (s *consoleSink) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (s *consoleSink) Fire(entry *logrus.Entry) error {
+	return writeStdout([]byte(formatConsoleLine(entry) + "\n"))
+}
+
+func (s *consoleSink) Close() {}
+
+/**
+* @Internal
+* One JSON object per line on stdout, for log collectors.
+ */
+type jsonSink struct {
+	formatter logrus.Formatter
+}
+
+func newJSONSink() *jsonSink {
+	return &jsonSink{
+		formatter: &logrus.JSONFormatter{
+			TimestampFormat: time.RFC3339Nano,
+		},
+	}
+}
+
+func (s *jsonSink) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (s *jsonSink) Fire(entry *logrus.Entry) error {
+	line, err := s.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	return writeStdout(line)
+}
+
+func (s *jsonSink) Close() {}
+
+/**
+* @Internal
+* JSON lines into a daily rotating file.
+ */
+type fileSink struct {
+	formatter logrus.Formatter
+	writer    *rotatingFile
+}
+
+func newFileSink(path string) (*fileSink, error) {
+	if path == "" {
+		path = config.LOG_FILE_DEFAULT_PATH
+	}
+	writer, err := newRotatingFile(
+		path,
+		config.LOG_FILE_MAX_AGE_DAYS,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &fileSink{
+		formatter: &logrus.JSONFormatter{
+			TimestampFormat: time.RFC3339Nano,
+		},
+		writer: writer,
+	}, nil
+}
+
+func (s *fileSink) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (s *fileSink) Fire(entry *logrus.Entry) error {
+	line, err := s.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	_, err = s.writer.Write(line)
+	return err
+}
+
+func (s *fileSink) Close() {
+	s.writer.Close()
+}
+
+/**
+* @Internal
+* Batched JSON events into a daily CloudWatch log stream.
+ */
+type cloudWatchSink struct {
+	*logrus_cloudwatchlogs.Hook
+}
+
+func newCloudWatchSink(conf config.Config) (*cloudWatchSink, error) {
+	if conf.CloudWatchLogGroup == "" {
+		return nil, fmt.Errorf("CLOUD_WATCH_LOG_GROUP_NAME is not set")
+	}
+
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String(conf.AWSRegion),
+		Credentials: credentials.NewStaticCredentials(conf.AWSAccessID, conf.AWSSecretKey, ""),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	streamName, err := getLogStream(sess, conf.CloudWatchLogGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	hook, err := logrus_cloudwatchlogs.NewBatchingHook(
+		conf.CloudWatchLogGroup,
+		streamName,
+		sess,
+		config.LOG_CLOUDWATCH_BATCH_INTERVAL,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &cloudWatchSink{Hook: hook}, nil
+}
+
+func (s *cloudWatchSink) Close() {}
+
+func getLogStream(sess *session.Session, logGroupName string) (string, error) {
+	cwl := cloudwatchlogs.New(sess)
+	name := time.Now().UTC().Format("2006-01-02")
+
+	var descending = true
+	resp, err := cwl.DescribeLogStreams(&cloudwatchlogs.DescribeLogStreamsInput{LogGroupName: &logGroupName, Descending: &descending})
+	if err != nil {
+		return name, err
+	}
+
+	for _, logStream := range resp.LogStreams {
+		if *logStream.LogStreamName == name {
+			return name, nil
+		}
+	}
+
+	_, err = cwl.CreateLogStream(&cloudwatchlogs.CreateLogStreamInput{
+		LogGroupName:  &logGroupName,
+		LogStreamName: &name,
+	})
+
+	return name, err
+}
@@ -1,20 +1,20 @@
 package main
 
 import (
-	"encoding/json"
-	"errors"
 	"fmt"
+	"os"
 	"runtime/debug"
 	"time"
 
 	"github.com/Duelana-Team/duelana-v1/config"
 	"github.com/Duelana-Team/duelana-v1/controllers/admin"
 	"github.com/Duelana-Team/duelana-v1/controllers/mixpanel"
-	"github.com/Duelana-Team/duelana-v1/controllers/payment"
 	"github.com/Duelana-Team/duelana-v1/controllers/prelude"
+	"github.com/Duelana-Team/duelana-v1/controllers/recovery"
 	"github.com/Duelana-Team/duelana-v1/controllers/redis"
 	"github.com/Duelana-Team/duelana-v1/controllers/solana"
 	"github.com/Duelana-Team/duelana-v1/controllers/transaction"
+	"github.com/Duelana-Team/duelana-v1/controllers/treasury"
 	"github.com/Duelana-Team/duelana-v1/db"
 	"github.com/Duelana-Team/duelana-v1/log"
 	"github.com/Duelana-Team/duelana-v1/models"
@@ -59,12 +59,10 @@ func initRoute(config *config.Config) {
 		})
 	}
 
-	if err := r.Run(fmt.Sprintf(":%v", config.AppPort)); err != nil {
+	if err := serve(r, fmt.Sprintf(":%v", config.AppPort)); err != nil {
 		log.LogMessage("main thread", fmt.Sprintf("failed to run server %v", err), "error", logrus.Fields{})
 		return
 	}
-
-	log.LogMessage("main thread", "initializing router done...", "success", logrus.Fields{})
 }
 
 func initSolana(config *config.Config) {
@@ -120,59 +118,6 @@ func initServerConfig() {
 	config.SetServerConfig(serverConfig)
 }
 
-func migrations() {
-	db := db.GetDB()
-	var serverConfig models.ServerConfig
-	if result := db.First(&serverConfig); result.Error != nil && errors.Is(result.Error, gorm.ErrRecordNotFound) {
-		serverConfig.ShouldNotReset = true
-		serverConfig.BaseRakeBackRate = config.BASE_RAKEBACK_RATE
-		serverConfig.AdditionalRakeBackRate = config.ADDITIONAL_RAKEBACK_RATE
-		serverConfig.NextGrandJackpotStartAt = config.GRAND_JACKPOT_NEXT_ROUND
-		byte, err := json.Marshal(config.API_RATE_LIMIT_CONFIGURATION)
-		if err != nil {
-			log.LogMessage("migrate server configs", "falied to msrshal api rate limit configuratioins", "error", logrus.Fields{"error": err.Error()})
-			return
-		}
-		serverConfig.ApiRateLimitConfiguration = string(byte)
-		byte, err = json.Marshal(config.WEBSOCKET_RATE_LIMIT_CONFIGURATION)
-		if err != nil {
-			log.LogMessage("migrate server configs", "falied to msrshal websocket rate limit configuratioins", "error", logrus.Fields{"error": err.Error()})
-			return
-		}
-		serverConfig.WebsocketRateLimitConfiguration = string(byte)
-		if result := db.Create(&serverConfig); result.Error != nil {
-			log.LogMessage("migrate server configs", "falied to create server config", "error", logrus.Fields{"error": err.Error()})
-			return
-		}
-		log.LogMessage("migrate server configs", "successfully created", "success", logrus.Fields{})
-		return
-	}
-	if !serverConfig.ShouldNotReset {
-		serverConfig.ShouldNotReset = true
-		serverConfig.BaseRakeBackRate = config.BASE_RAKEBACK_RATE
-		serverConfig.AdditionalRakeBackRate = config.ADDITIONAL_RAKEBACK_RATE
-		serverConfig.NextGrandJackpotStartAt = config.GRAND_JACKPOT_NEXT_ROUND
-		byte, err := json.Marshal(config.API_RATE_LIMIT_CONFIGURATION)
-		if err != nil {
-			log.LogMessage("migrate server configs", "falied to msrshal api rate limit configuratioins", "error", logrus.Fields{"error": err.Error()})
-			return
-		}
-		serverConfig.ApiRateLimitConfiguration = string(byte)
-		byte, err = json.Marshal(config.WEBSOCKET_RATE_LIMIT_CONFIGURATION)
-		if err != nil {
-			log.LogMessage("migrate server configs", "falied to msrshal websocket rate limit configuratioins", "error", logrus.Fields{"error": err.Error()})
-			return
-		}
-		serverConfig.WebsocketRateLimitConfiguration = string(byte)
-		if result := db.Save(&serverConfig); result.Error != nil {
-			log.LogMessage("migrate server configs", "falied to save server config", "error", logrus.Fields{"error": err.Error()})
-			return
-		}
-		log.LogMessage("migrate server configs", "successfully saved", "success", logrus.Fields{})
-	}
-	log.LogMessage("migrate server configs", "done...", "success", logrus.Fields{})
-}
-
 func initRedis() {
 	if err := redis.InitRedis(
 		config.Get().RedisUrl,
@@ -190,48 +135,49 @@ func initRedis() {
 	}
 }
 
+func initRecovery() {
+	summary, err := recovery.Run()
+	if err != nil {
+		log.LogMessage("recovery", "failed to recover previous state", "error", logrus.Fields{"summary": summary, "error": err.Error()})
+		return
+	}
+	log.LogMessage("main thread", "recovering previous state done...", "success", logrus.Fields{"summary": summary})
+}
+
+func initTreasury() {
+	if err := treasury.Initialize(); err != nil {
+		log.LogMessage("init treasury", "failed to start treasury monitor", "error", logrus.Fields{"error": err.Error()})
+		return
+	}
+	log.LogMessage("main thread", "initializing treasury monitor done...", "success", logrus.Fields{})
+}
+
 func initialize() {
 	// log.LogMessage("main thread", "initializing...", "info", logrus.Fields{})
 	config := initConfig()
 	initLog()
 	database := initDB(config)
+	initMigrationCheck(database)
 	initRedis()
 	if err := prelude.InitDuelMainUsers(database); err != nil {
 		log.LogMessage("init duel main users", "failed", "error", logrus.Fields{"error": err.Error()})
 	}
-//	if err := prelude.InitDuelBotsV2(database); err != nil {
-//		log.LogMessage("init duel bots", "failed", "error", logrus.Fields{"error": err.Error()})
-//	}
 	initTxModule(database)
-	// if err := prelude.PerformRemoveOdes(); err != nil {
-	// 	log.LogMessage("remove odes", "failed", "error", logrus.Fields{"error": err.Error()})
-	// }
-	// if err := prelude.MigrateRakebackRecords(); err != nil {
-	// 	log.LogMessage("migrate rakeback records", "failed", "error", logrus.Fields{"error": err.Error()})
-	// }
-	if err := prelude.InitAffiliateLifetime(); err != nil {
-		log.LogMessage(
-			"initialize affiliate lifetime",
-			"failed to initialize",
-			"error",
-			logrus.Fields{
-				"error": err.Error(),
-			},
-		)
-	}
 	initSolana(config)
 	// initMixpanel(config)
-	if err := payment.MigratePaymentModel(); err != nil {
-		log.LogMessage("payment migration", "failed", "error", logrus.Fields{"error": err.Error()})
-	}
-	migrations()
 	initServerConfig()
 	// initGlobalTimezone()
+	initRecovery()
 	admin.InitGameController()
+	initTreasury()
 	initRoute(config)
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrateCommand(os.Args[2:]))
+	}
+
 	defer func() {
 		if r := recover(); r != nil {
 			log.LogMessage(
@@ -0,0 +1,200 @@
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const NAMESPACE = "duelana"
+
+// Private registry, so that only collectors below and the
+// ones registered by modules through `Register` are exposed.
+var registry = prometheus.NewRegistry()
+
+var (
+	// Bets placed per game.
+	GameBets = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: NAMESPACE,
+			Subsystem: "game",
+			Name:      "bets_total",
+			Help:      "Number of settled bets per game.",
+		},
+		[]string{"game"},
+	)
+	// Wagered chips per game.
+	GameBetAmount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: NAMESPACE,
+			Subsystem: "game",
+			Name:      "bet_amount_total",
+			Help:      "Wagered chips (with decimals) per game.",
+		},
+		[]string{"game"},
+	)
+	// Paid out chips per game, bet amount included.
+	GamePayoutAmount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: NAMESPACE,
+			Subsystem: "game",
+			Name:      "payout_amount_total",
+			Help:      "Paid out chips (with decimals) per game, stake included.",
+		},
+		[]string{"game"},
+	)
+	// Time spent in each crash round phase.
+	CrashPhaseDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: NAMESPACE,
+			Subsystem: "crash",
+			Name:      "phase_duration_seconds",
+			Help:      "Time spent in each crash round phase.",
+			Buckets:   []float64{0.5, 1, 2, 5, 10, 15, 20, 30, 60, 120, 300},
+		},
+		[]string{"phase"},
+	)
+	// Delay between crash ticker firing and the tick being handled.
+	CrashTickLag = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: NAMESPACE,
+			Subsystem: "crash",
+			Name:      "tick_lag_seconds",
+			Help:      "Delay between crash ticker firing and the tick being handled.",
+			Buckets:   []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1},
+		},
+	)
+	// Websocket clients dropped for not draining their send queue.
+	HubDroppedClients = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: NAMESPACE,
+			Subsystem: "ws",
+			Name:      "dropped_slow_clients_total",
+			Help:      "Websocket clients dropped because their send buffer was full.",
+		},
+	)
+	// Solana rpc call latency.
+	SolanaRpcDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: NAMESPACE,
+			Subsystem: "solana",
+			Name:      "rpc_duration_seconds",
+			Help:      "Solana rpc call latency per method.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"method"},
+	)
+	// Solana rpc call failures.
+	SolanaRpcErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: NAMESPACE,
+			Subsystem: "solana",
+			Name:      "rpc_errors_total",
+			Help:      "Failed solana rpc calls per method.",
+		},
+		[]string{"method"},
+	)
+	// Requests rejected by api and websocket rate limiters.
+	RateLimitRejections = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: NAMESPACE,
+			Subsystem: "rate_limiter",
+			Name:      "rejections_total",
+			Help:      "Requests rejected by rate limiters.",
+		},
+		[]string{"kind", "caller"},
+	)
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		GameBets,
+		GameBetAmount,
+		GamePayoutAmount,
+		CrashPhaseDuration,
+		CrashTickLag,
+		HubDroppedClients,
+		SolanaRpcDuration,
+		SolanaRpcErrors,
+		RateLimitRejections,
+	)
+}
+
+/**
+* @External
+* Registers a module owned collector. A collector already
+* registered under the same descriptors is replaced, so that
+* re-initialized modules report their latest state.
+ */
+func Register(collector prometheus.Collector) error {
+	err := registry.Register(collector)
+	if err == nil {
+		return nil
+	}
+
+	var registered prometheus.AlreadyRegisteredError
+	if !errors.As(err, &registered) {
+		return err
+	}
+	registry.Unregister(registered.ExistingCollector)
+	return registry.Register(collector)
+}
+
+/**
+* @External
+* Registers a gauge evaluated on every scrape.
+ */
+func RegisterGaugeFunc(subsystem string, name string, help string, function func() float64) error {
+	return Register(prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Namespace: NAMESPACE,
+			Subsystem: subsystem,
+			Name:      name,
+			Help:      help,
+		},
+		function,
+	))
+}
+
+/**
+* @External
+* Records settled bets of a game round.
+ */
+func ObserveWager(game string, bet int64, payout int64) {
+	GameBets.WithLabelValues(game).Inc()
+	GameBetAmount.WithLabelValues(game).Add(float64(bet))
+	if payout > 0 {
+		GamePayoutAmount.WithLabelValues(game).Add(float64(payout))
+	}
+}
+
+/**
+* @External
+* Records latency and failure of a solana rpc call started at `start`.
+ */
+func ObserveSolanaRpc(method string, start time.Time, err error) {
+	SolanaRpcDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		SolanaRpcErrors.WithLabelValues(method).Inc()
+	}
+}
+
+/**
+* @External
+* Prometheus text exposition handler.
+ */
+func Handler() gin.HandlerFunc {
+	handler := promhttp.HandlerFor(
+		registry,
+		promhttp.HandlerOpts{},
+	)
+	return func(c *gin.Context) {
+		handler.ServeHTTP(c.Writer, c.Request)
+	}
+}
@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRegisterReplacesCollector(t *testing.T) {
+	if err := RegisterGaugeFunc("test", "value", "Test gauge.", func() float64 { return 1 }); err != nil {
+		t.Fatalf("failed to register gauge: %v", err)
+	}
+	if err := RegisterGaugeFunc("test", "value", "Test gauge.", func() float64 { return 2 }); err != nil {
+		t.Fatalf("failed to replace gauge: %v", err)
+	}
+
+	ObserveWager("crash", 100, 0)
+	ObserveWager("crash", 50, 120)
+	ObserveSolanaRpc("sendTransaction", time.Now(), errors.New("rpc down"))
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/metrics", Handler())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d", w.Code)
+	}
+	body := w.Body.String()
+	for _, expected := range []string{
+		"duelana_test_value 2",
+		`duelana_game_bets_total{game="crash"} 2`,
+		`duelana_game_bet_amount_total{game="crash"} 150`,
+		`duelana_game_payout_amount_total{game="crash"} 120`,
+		`duelana_solana_rpc_errors_total{method="sendTransaction"} 1`,
+	} {
+		if !strings.Contains(body, expected) {
+			t.Fatalf("missing %q in metrics output", expected)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package middlewares
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"slices"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/db"
+	Logger "github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const ADMIN_OPERATOR_KEY = "adminOperator"
+const ADMIN_AUDIT_OMIT_RESULT_KEY = "adminAuditOmitResult"
+const ROOT_ADMIN_OPERATOR_NAME = "root"
+
+type AdminOperatorIdentity struct {
+	ID   *uint
+	Name string
+	Role models.AdminOperatorRole
+}
+
+type auditResponseWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w auditResponseWriter) Write(b []byte) (int, error) {
+	if w.body.Len() < config.ADMIN_AUDIT_LOG_MAX_FIELD_LENGTH {
+		w.body.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+/**
+* @External
+* Returns hex encoded sha256 hash of operator api token.
+* Only hashes are persisted, so tokens can not be recovered from db.
+ */
+func HashAdminApiToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+/**
+* @External
+* Authenticates admin operator by `x-api-key` header and records an
+* audit log entry for every call including rejected ones.
+* The shared `rootToken` is accepted as the bootstrap super-admin.
+ */
+func AdminAuthMiddleware(rootToken string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parameters := c.Request.URL.RawQuery
+		if c.Request.Body != nil {
+			body, _ := io.ReadAll(c.Request.Body)
+			c.Request.Body = io.NopCloser(bytes.NewBuffer(body))
+			if len(body) > 0 {
+				parameters += " " + string(body)
+			}
+		}
+		writer := auditResponseWriter{
+			ResponseWriter: c.Writer,
+			body:           &bytes.Buffer{},
+		}
+		c.Writer = writer
+
+		operator := authenticateAdminOperator(c, rootToken)
+		if operator == nil {
+			utils.RespondWithError(c, 401, "Invalid API token")
+		} else {
+			c.Set(ADMIN_OPERATOR_KEY, *operator)
+			c.Next()
+		}
+
+		result := writer.body.String()
+		if c.GetBool(ADMIN_AUDIT_OMIT_RESULT_KEY) {
+			result = "omitted"
+		}
+		recordAdminAuditLog(c, operator, parameters, result)
+	}
+}
+
+/**
+* @External
+* Allows the request only for operators having one of the roles.
+* Super-admin is allowed for all routes.
+ */
+func AdminPermission(roles ...models.AdminOperatorRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		operator := GetAdminOperator(c)
+		if operator == nil ||
+			(operator.Role != models.AdminSuperRole &&
+				!slices.Contains(roles, operator.Role)) {
+			utils.RespondWithError(c, http.StatusForbidden, "Permission denied")
+			return
+		}
+		c.Next()
+	}
+}
+
+/**
+* @External
+* Returns authenticated admin operator of the request.
+ */
+func GetAdminOperator(c *gin.Context) *AdminOperatorIdentity {
+	value, prs := c.Get(ADMIN_OPERATOR_KEY)
+	if !prs {
+		return nil
+	}
+	operator, ok := value.(AdminOperatorIdentity)
+	if !ok {
+		return nil
+	}
+	return &operator
+}
+
+/**
+* @Internal
+* Resolves operator from api token in request header.
+ */
+func authenticateAdminOperator(c *gin.Context, rootToken string) *AdminOperatorIdentity {
+	token, prs := c.Request.Header[http.CanonicalHeaderKey("x-api-key")]
+	if !prs || len(token) == 0 || token[0] == "" {
+		return nil
+	}
+
+	if rootToken != "" &&
+		subtle.ConstantTimeCompare([]byte(token[0]), []byte(rootToken)) == 1 {
+		return &AdminOperatorIdentity{
+			Name: ROOT_ADMIN_OPERATOR_NAME,
+			Role: models.AdminSuperRole,
+		}
+	}
+
+	var operator models.AdminOperator
+	if err := db.GetDB().Where(
+		"token_hash = ?",
+		HashAdminApiToken(token[0]),
+	).Where(
+		"enabled = ?",
+		true,
+	).First(&operator).Error; err != nil {
+		return nil
+	}
+	return &AdminOperatorIdentity{
+		ID:   &operator.ID,
+		Name: operator.Name,
+		Role: operator.Role,
+	}
+}
+
+/**
+* @Internal
+* Appends audit log entry of the admin call.
+ */
+func recordAdminAuditLog(
+	c *gin.Context,
+	operator *AdminOperatorIdentity,
+	parameters string,
+	result string,
+) {
+	entry := models.AdminAuditLog{
+		Method:     c.Request.Method,
+		Route:      c.FullPath(),
+		Parameters: truncateAuditField(parameters),
+		StatusCode: c.Writer.Status(),
+		Result:     truncateAuditField(result),
+		ClientIP:   c.ClientIP(),
+	}
+	if entry.Route == "" {
+		entry.Route = c.Request.URL.Path
+	}
+	if operator != nil {
+		entry.OperatorID = operator.ID
+		entry.OperatorName = operator.Name
+		entry.Role = operator.Role
+	}
+
+	if err := db.GetDB().Create(&entry).Error; err != nil {
+		Logger.LogContext(
+			c,
+			"admin_audit",
+			"failed to record audit log",
+			"error",
+			logrus.Fields{
+				"route":    entry.Route,
+				"operator": entry.OperatorName,
+				"error":    err.Error(),
+			},
+		)
+	}
+}
+
+func truncateAuditField(value string) string {
+	if len(value) > config.ADMIN_AUDIT_LOG_MAX_FIELD_LENGTH {
+		return value[:config.ADMIN_AUDIT_LOG_MAX_FIELD_LENGTH]
+	}
+	return value
+}
@@ -0,0 +1,68 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Duelana-Team/duelana-v1/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestAdminPermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	perform := func(operator *AdminOperatorIdentity) int {
+		recorder := httptest.NewRecorder()
+		ctx, router := gin.CreateTestContext(recorder)
+		router.GET(
+			"/",
+			func(c *gin.Context) {
+				if operator != nil {
+					c.Set(ADMIN_OPERATOR_KEY, *operator)
+				}
+			},
+			AdminPermission(models.AdminFinanceRole),
+			func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			},
+		)
+		ctx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		router.HandleContext(ctx)
+		return recorder.Code
+	}
+
+	if code := perform(nil); code != http.StatusForbidden {
+		t.Fatalf("unauthenticated request should be forbidden: %d", code)
+	}
+	if code := perform(&AdminOperatorIdentity{
+		Name: "games",
+		Role: models.AdminGamesRole,
+	}); code != http.StatusForbidden {
+		t.Fatalf("operator without role should be forbidden: %d", code)
+	}
+	if code := perform(&AdminOperatorIdentity{
+		Name: "finance",
+		Role: models.AdminFinanceRole,
+	}); code != http.StatusOK {
+		t.Fatalf("operator with role should be allowed: %d", code)
+	}
+	if code := perform(&AdminOperatorIdentity{
+		Name: "super",
+		Role: models.AdminSuperRole,
+	}); code != http.StatusOK {
+		t.Fatalf("super-admin should be allowed: %d", code)
+	}
+}
+
+func TestHashAdminApiToken(t *testing.T) {
+	if HashAdminApiToken("token") != HashAdminApiToken("token") {
+		t.Fatalf("hash should be deterministic")
+	}
+	if HashAdminApiToken("token") == HashAdminApiToken("token2") {
+		t.Fatalf("different tokens should have different hashes")
+	}
+	if HashAdminApiToken("token") == "token" {
+		t.Fatalf("token should not be stored as is")
+	}
+}
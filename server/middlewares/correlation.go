@@ -0,0 +1,64 @@
+package middlewares
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	Logger "github.com/Duelana-Team/duelana-v1/log"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]+$`)
+
+/**
+* @External
+* Attaches a request correlation id to gin and request contexts
+* and the response header, reusing a sane incoming X-Request-ID.
+* Logs one access line per request once handlers finish.
+ */
+func RequestCorrelation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(Logger.REQUEST_ID_HEADER)
+		if !isValidRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+		c.Set(Logger.REQUEST_ID_KEY, requestID)
+		c.Request = c.Request.WithContext(
+			Logger.WithRequestID(c.Request.Context(), requestID),
+		)
+		c.Header(Logger.REQUEST_ID_HEADER, requestID)
+
+		start := time.Now()
+		c.Next()
+
+		path := c.FullPath()
+		if path == "" {
+			path = c.Request.URL.Path
+		}
+		level := "info"
+		if c.Writer.Status() >= 500 {
+			level = "error"
+		}
+		Logger.LogContext(
+			c,
+			"http",
+			"request served",
+			level,
+			logrus.Fields{
+				"method":    c.Request.Method,
+				"path":      path,
+				"status":    c.Writer.Status(),
+				"latencyMs": time.Since(start).Milliseconds(),
+			},
+		)
+	}
+}
+
+func isValidRequestID(requestID string) bool {
+	return len(requestID) > 0 &&
+		len(requestID) <= config.LOG_CORRELATION_ID_MAX_LENGTH &&
+		requestIDPattern.MatchString(requestID)
+}
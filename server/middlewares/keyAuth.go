@@ -1,7 +1,9 @@
 package middlewares
 
 import (
+	"crypto/subtle"
 	"net/http"
+	"strings"
 
 	"github.com/Duelana-Team/duelana-v1/utils"
 	"github.com/gin-gonic/gin"
@@ -17,7 +19,28 @@ func TokenAuthMiddleware(apiAccessToken string) gin.HandlerFunc {
 			return
 		}
 
-		if token[0] != apiAccessToken {
+		if subtle.ConstantTimeCompare([]byte(token[0]), []byte(apiAccessToken)) != 1 {
+			utils.RespondWithError(c, 401, "Invalid API token")
+			return
+		}
+
+		c.Next()
+	}
+}
+
+/**
+* @External
+* Bearer token check for scrapers, e.g. Prometheus `authorization`.
+ */
+func BearerTokenAuthMiddleware(accessToken string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !found || token == "" {
+			utils.RespondWithError(c, 401, "API token required")
+			return
+		}
+
+		if subtle.ConstantTimeCompare([]byte(token), []byte(accessToken)) != 1 {
 			utils.RespondWithError(c, 401, "Invalid API token")
 			return
 		}
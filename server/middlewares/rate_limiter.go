@@ -8,6 +8,7 @@ import (
 
 	"github.com/Duelana-Team/duelana-v1/config"
 	Logger "github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/metrics"
 	"github.com/Duelana-Team/duelana-v1/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/gomodule/redigo/redis"
@@ -128,6 +129,7 @@ func APIRateLimiter(caller string) gin.HandlerFunc {
 
 		Logger.LogMessage("RateLimiter", "take key", "info", logrus.Fields{"tokens": tokens, "remaining": remaining, "reset": reset, "ok": ok})
 		if !ok {
+			metrics.RateLimitRejections.WithLabelValues("api", caller).Inc()
 			var result interface{}
 			if rateLimit.Interval == time.Hour {
 				result = gin.H{"message": "Please try after an hour."}
@@ -168,6 +170,9 @@ func WebsocketRateLimiter(caller string, userID *uint, conn *websocket.Conn) (bo
 	if tokens != rateLimit.Tokens {
 		store.Set(context, key, rateLimit.Tokens, rateLimit.Interval)
 	}
+	if !ok {
+		metrics.RateLimitRejections.WithLabelValues("websocket", caller).Inc()
+	}
 
 	return ok, nil
 }
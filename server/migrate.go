@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Duelana-Team/duelana-v1/db/migrate"
+	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/migrations"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const migrateUsage = `usage: migrate <command>
+  status          show applied and pending migrations
+  up [version]    apply pending migrations, up to version if given
+  down [steps]    roll back last applied migrations, 1 step by default`
+
+/**
+* @Internal
+* Refuses to serve while migrations are pending or unknown.
+ */
+func initMigrationCheck(database *gorm.DB) {
+	if err := migrate.CheckPending(
+		database,
+		migrations.GetMigrations(),
+	); err != nil {
+		log.LogMessage("migrate check", "refused to serve", "error", logrus.Fields{"error": err.Error()})
+		logrus.Fatal(err.Error())
+	}
+	log.LogMessage("main thread", "checking migrations done...", "success", logrus.Fields{})
+}
+
+/**
+* @Internal
+* Runs `migrate` subcommand and returns process exit code.
+ */
+func runMigrateCommand(args []string) int {
+	if len(args) == 0 || len(args) > 2 {
+		fmt.Println(migrateUsage)
+		return 2
+	}
+
+	config := initConfig()
+	log.Init()
+	database := initDB(config)
+	registered := migrations.GetMigrations()
+
+	switch args[0] {
+	case "status":
+		statuses, err := migrate.GetStatus(database, registered)
+		if err != nil {
+			fmt.Println(err.Error())
+			return 1
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if !status.Known {
+				state += " (unknown to this binary)"
+			}
+			fmt.Printf("%d  %-32s %s\n", status.Version, status.Name, state)
+		}
+		return 0
+
+	case "up":
+		target := uint64(0)
+		if len(args) == 2 {
+			value, err := strconv.ParseUint(args[1], 10, 64)
+			if err != nil {
+				fmt.Println(migrateUsage)
+				return 2
+			}
+			target = value
+		}
+		performed, err := migrate.Up(database, registered, target)
+		for _, migration := range performed {
+			fmt.Printf("applied %d %s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			fmt.Println(err.Error())
+			return 1
+		}
+		return 0
+
+	case "down":
+		steps := 1
+		if len(args) == 2 {
+			value, err := strconv.Atoi(args[1])
+			if err != nil || value <= 0 {
+				fmt.Println(migrateUsage)
+				return 2
+			}
+			steps = value
+		}
+		performed, err := migrate.Down(database, registered, steps)
+		for _, migration := range performed {
+			fmt.Printf("rolled back %d %s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			fmt.Println(err.Error())
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Println(migrateUsage)
+	return 2
+}
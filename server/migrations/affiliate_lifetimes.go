@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"github.com/Duelana-Team/duelana-v1/db/migrate"
+	"github.com/Duelana-Team/duelana-v1/migrations/baseline"
+	"gorm.io/gorm"
+)
+
+/**
+* @Internal
+* Creates affiliate lifetimes from active affiliates.
+* Replaces `prelude.InitAffiliateLifetime`, which recorded completion in
+* `a_affiliate_lifetime_flag` table. Databases having that table are skipped.
+ */
+func affiliateLifetimes() migrate.Migration {
+	const LEGACY_FLAG_TABLE = "a_affiliate_lifetime_flag"
+	return migrate.Migration{
+		Version: 202610190030,
+		Name:    "affiliate_lifetimes",
+		Up: func(tx *gorm.DB) error {
+			if tx.Migrator().HasTable(LEGACY_FLAG_TABLE) {
+				return nil
+			}
+
+			activeAffiliates := []baseline.ActiveAffiliate{}
+			if err := tx.Find(&activeAffiliates).Error; err != nil {
+				return err
+			}
+
+			for _, activeAffiliate := range activeAffiliates {
+				if err := tx.Create(&baseline.AffiliateLifetime{
+					UserID:        activeAffiliate.UserID,
+					AffiliateID:   activeAffiliate.AffiliateID,
+					LastActivated: activeAffiliate.CreatedAt,
+					IsActive:      true,
+				}).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,17 @@
+package baseline
+
+import "gorm.io/gorm"
+
+type ChatIgnore struct {
+	gorm.Model
+	UserID        uint `gorm:"not null;uniqueIndex:idx_chat_ignore_user" json:"userId"`
+	IgnoredUserID uint `gorm:"not null;uniqueIndex:idx_chat_ignore_user" json:"ignoredUserId"`
+	IgnoredUser   User `gorm:"foreignKey:IgnoredUserID" json:"ignoredUser"`
+}
+
+type DirectMessage struct {
+	gorm.Model
+	SenderID    uint   `gorm:"not null;index" json:"senderId"`
+	RecipientID uint   `gorm:"not null;index" json:"recipientId"`
+	Message     string `gorm:"not null" json:"message"`
+}
@@ -0,0 +1,34 @@
+package baseline
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type DepositBonusCampaign struct {
+	gorm.Model
+	Name              string          `gorm:"not null;unique" json:"name"`
+	MatchPercent      uint            `gorm:"not null" json:"matchPercent"`
+	MaxBonus          int64           `gorm:"not null" json:"maxBonus"`
+	MinDeposit        int64           `gorm:"default:0" json:"minDeposit"`
+	StartAt           time.Time       `gorm:"not null;index" json:"startAt"`
+	EndAt             time.Time       `gorm:"not null;index" json:"endAt"`
+	ExcludeAffiliated bool            `json:"excludeAffiliated"`
+	Enabled           bool            `gorm:"index" json:"enabled"`
+	CouponCampaignID  uint            `gorm:"not null" json:"couponCampaignId"`
+	CouponCampaign    *CouponCampaign `gorm:"foreignKey:CouponCampaignID" json:"couponCampaign"`
+}
+
+type DepositBonusOptIn struct {
+	gorm.Model
+	CampaignID   uint                  `gorm:"uniqueIndex:deposit_bonus_campaign_user" json:"campaignId"`
+	Campaign     *DepositBonusCampaign `gorm:"foreignKey:CampaignID" json:"campaign"`
+	UserID       uint                  `gorm:"uniqueIndex:deposit_bonus_campaign_user;index" json:"userId"`
+	PaymentID    *uint                 `json:"paymentId"`
+	Deposit      int64                 `gorm:"default:0" json:"deposit"`
+	BonusBalance int64                 `gorm:"default:0" json:"bonusBalance"`
+	CouponCode   *uuid.UUID            `gorm:"type:uuid" json:"couponCode"`
+	AppliedAt    *time.Time            `gorm:"index" json:"appliedAt"`
+}
@@ -0,0 +1,7 @@
+/**
+* Models as they were when versioned migrations were introduced.
+* The baseline migration creates tables from these, so that replaying
+* history always creates the same schema whatever `models` became.
+* Frozen: never edit, later changes belong to new migrations.
+ */
+package baseline
@@ -0,0 +1,37 @@
+package baseline
+
+import (
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+type HouseRainStatus string
+
+const (
+	HouseRainPerformed HouseRainStatus = "performed"
+	HouseRainSkipped   HouseRainStatus = "skipped"
+	HouseRainFailed    HouseRainStatus = "failed"
+)
+
+type HouseRainSchedule struct {
+	gorm.Model
+	Name               string `gorm:"not null" json:"name"`
+	CronSpec           string `gorm:"not null" json:"cronSpec"`
+	Amount             int64  `gorm:"not null" json:"amount"`
+	SplitCount         uint   `gorm:"not null" json:"splitCount"`
+	MinWager           int64  `gorm:"default:0" json:"minWager"`
+	MinVipLevel        uint   `gorm:"default:0" json:"minVipLevel"`
+	CountdownInSeconds uint   `gorm:"default:0" json:"countdownInSeconds"`
+	Enabled            bool   `gorm:"index" json:"enabled"`
+}
+
+type HouseRain struct {
+	gorm.Model
+	ScheduleID    uint            `gorm:"index" json:"scheduleId"`
+	Amount        int64           `json:"amount"`
+	Recipients    pq.Int64Array   `gorm:"type:bigint[]" json:"recipients"`
+	TransactionID *uint           `json:"transactionId"`
+	Transaction   *Transaction    `gorm:"foreignKey:TransactionID" json:"transaction"`
+	Status        HouseRainStatus `gorm:"index" json:"status"`
+	Reason        string          `json:"reason"`
+}
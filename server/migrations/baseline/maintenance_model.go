@@ -0,0 +1,36 @@
+package baseline
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+/**
+* Persisted block state of games and features controlled by admin.
+ */
+type FeatureSwitch struct {
+	Name      string    `gorm:"primaryKey" json:"name"`
+	Blocked   bool      `gorm:"not null;default:false" json:"blocked"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type MaintenanceWindowStatus string
+
+const (
+	MaintenanceScheduled  MaintenanceWindowStatus = "scheduled"
+	MaintenanceInProgress MaintenanceWindowStatus = "in-progress"
+	MaintenanceFinished   MaintenanceWindowStatus = "finished"
+	MaintenanceCancelled  MaintenanceWindowStatus = "cancelled"
+)
+
+/**
+* Maintenance window. `EndAt` is nil for windows finished manually.
+ */
+type MaintenanceWindow struct {
+	gorm.Model
+	StartAt time.Time               `gorm:"not null" json:"startAt"`
+	EndAt   *time.Time              `json:"endAt"`
+	Reason  string                  `json:"reason"`
+	Status  MaintenanceWindowStatus `gorm:"not null;index" json:"status"`
+}
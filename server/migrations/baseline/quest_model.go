@@ -0,0 +1,56 @@
+package baseline
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type QuestKind string
+
+const (
+	QuestWagerAmount       QuestKind = "wager-amount"
+	QuestCashOutMultiplier QuestKind = "cash-out-multiplier"
+	QuestWinStreak         QuestKind = "win-streak"
+	QuestReachLevel        QuestKind = "reach-level"
+)
+
+type QuestPeriod string
+
+const (
+	QuestDaily  QuestPeriod = "daily"
+	QuestWeekly QuestPeriod = "weekly"
+)
+
+type QuestRewardType string
+
+const (
+	QuestRewardChip   QuestRewardType = "chip"
+	QuestRewardCoupon QuestRewardType = "coupon"
+)
+
+type Quest struct {
+	gorm.Model
+	Title       string          `gorm:"not null" json:"title"`
+	Description string          `json:"description"`
+	Kind        QuestKind       `gorm:"not null" json:"kind"`
+	Game        GameType        `json:"game"`
+	Period      QuestPeriod     `gorm:"not null;index" json:"period"`
+	Target      int64           `gorm:"not null" json:"target"`
+	RewardType  QuestRewardType `gorm:"not null;default:chip" json:"rewardType"`
+	Reward      int64           `gorm:"not null" json:"reward"`
+	Enabled     bool            `gorm:"index" json:"enabled"`
+}
+
+type QuestProgress struct {
+	gorm.Model
+	QuestID          uint           `gorm:"uniqueIndex:quest_user_period" json:"questId"`
+	Quest            Quest          `json:"quest"`
+	UserID           uint           `gorm:"uniqueIndex:quest_user_period;index" json:"userId"`
+	PeriodStartedAt  datatypes.Date `gorm:"type:date;uniqueIndex:quest_user_period" json:"periodStartedAt"`
+	Progress         int64          `json:"progress"`
+	Completed        bool           `gorm:"index" json:"completed"`
+	Claimed          int64          `gorm:"index" json:"claimed"`
+	ClaimTransaction *Transaction   `gorm:"polymorphic:Owner;polymorphicValue:tx_quest_progress_referenced" json:"claimTransaction"`
+	RewardCoupon     *uuid.UUID     `gorm:"type:uuid" json:"rewardCoupon"`
+}
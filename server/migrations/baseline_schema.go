@@ -0,0 +1,72 @@
+package migrations
+
+import (
+	"github.com/Duelana-Team/duelana-v1/db/migrate"
+	"github.com/Duelana-Team/duelana-v1/migrations/baseline"
+	"gorm.io/gorm"
+)
+
+/**
+* @Internal
+* Adopts schema previously created by startup AutoMigrate.
+* Idempotent on existing databases, creates every table on fresh ones.
+* Uses frozen `baseline` models, not the live ones.
+ */
+func baselineSchema() migrate.Migration {
+	return migrate.Migration{
+		Version: 202610190000,
+		Name:    "baseline_schema",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(
+				&baseline.Payment{},
+				&baseline.NftCollection{},
+				&baseline.User{},
+				&baseline.Statistics{},
+				&baseline.Wallet{},
+				&baseline.Transaction{},
+				&baseline.ChipBalance{},
+				&baseline.NftBalance{},
+				&baseline.Balance{},
+				&baseline.DepositedNft{},
+				&baseline.JackpotRound{}, &baseline.JackpotPlayer{}, &baseline.JackpotBet{},
+				&baseline.CoinflipRound{},
+				&baseline.NftInGame{},
+				&baseline.ClientSeed{}, &baseline.ServerSeed{}, &baseline.SeedPair{},
+				&baseline.DreamTowerRound{},
+				&baseline.DuelBot{},
+				&baseline.Rakeback{},
+				&baseline.ServerConfig{},
+				&baseline.Affiliate{},
+				&baseline.ActiveAffiliate{},
+				&baseline.AffiliateLifetime{},
+				&baseline.CouponCampaign{},
+				&baseline.Coupon{},
+				&baseline.DepositBonusCampaign{},
+				&baseline.DepositBonusOptIn{},
+				&baseline.ClaimedCoupon{},
+				&baseline.CouponTransaction{},
+				&baseline.CrashRound{},
+				&baseline.CrashBet{},
+				&baseline.SelfExclusion{},
+				&baseline.DailyRaceRewards{},
+				&baseline.CouponShortcut{},
+				&baseline.WeeklyRaffleTicket{},
+				&baseline.WeeklyRaffle{},
+				&baseline.Quest{},
+				&baseline.QuestProgress{},
+				&baseline.HouseRainSchedule{},
+				&baseline.HouseRain{},
+				&baseline.ChatIgnore{},
+				&baseline.DirectMessage{},
+				&baseline.AdminOperator{},
+				&baseline.AdminAuditLog{},
+				&baseline.FeatureSwitch{},
+				&baseline.MaintenanceWindow{},
+				&baseline.GameSettings{},
+				&baseline.DailyGameRevenue{},
+				&baseline.DailyRevenueDeduction{},
+				&baseline.DailyPaymentVolume{},
+			)
+		},
+	}
+}
@@ -0,0 +1,26 @@
+package migrations
+
+import (
+	"github.com/Duelana-Team/duelana-v1/db/migrate"
+	"gorm.io/gorm"
+)
+
+/**
+* @Internal
+* Creates per user deposit references.
+ */
+func depositReferences() migrate.Migration {
+	return migrate.Migration{
+		Version: 202610190050,
+		Name:    "deposit_references",
+		Up: func(tx *gorm.DB) error {
+			type DepositReference struct {
+				gorm.Model
+				UserID    uint   `gorm:"not null;uniqueIndex"`
+				Reference string `gorm:"type:varchar(50);not null;uniqueIndex"`
+				Memo      string `gorm:"type:varchar(20);not null;uniqueIndex"`
+			}
+			return tx.AutoMigrate(&DepositReference{})
+		},
+	}
+}
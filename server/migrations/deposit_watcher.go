@@ -0,0 +1,64 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/Duelana-Team/duelana-v1/db/migrate"
+	"github.com/Duelana-Team/duelana-v1/utils"
+	"gorm.io/gorm"
+)
+
+/**
+* @Internal
+* Creates deposit watcher cursors and makes deposit tx hashes
+* unique, so that a deposit is credited once whichever of
+* webhook and watcher handles it first.
+* Fails with the duplicated deposits if any, as each of them may
+* have been credited and should be reconciled by hand first.
+ */
+func depositWatcher() migrate.Migration {
+	const DEPOSIT_TX_HASH_INDEX = "idx_payments_deposit_tx_hash"
+	return migrate.Migration{
+		Version: 202610190040,
+		Name:    "deposit_watcher",
+		Up: func(tx *gorm.DB) error {
+			type DepositWatcherCursor struct {
+				gorm.Model
+				Address       string `gorm:"not null;uniqueIndex"`
+				LastSignature string `gorm:"type:varchar(100);not null"`
+				LastSlot      uint64 `gorm:"not null;default:0"`
+			}
+			if err := tx.AutoMigrate(&DepositWatcherCursor{}); err != nil {
+				return err
+			}
+
+			type duplicatedDeposit struct {
+				TxHash     string
+				PaymentIDs string
+			}
+			duplicated := []duplicatedDeposit{}
+			if err := tx.Raw(
+				"SELECT tx_hash, string_agg(id::text, ',' ORDER BY id) AS payment_ids" +
+					" FROM payments" +
+					" WHERE type LIKE 'deposit_%' AND tx_hash <> '' AND deleted_at IS NULL" +
+					" GROUP BY tx_hash HAVING count(*) > 1",
+			).Scan(&duplicated).Error; err != nil {
+				return err
+			}
+			if len(duplicated) > 0 {
+				return utils.MakeError(
+					"migrations",
+					"depositWatcher",
+					"duplicated deposit tx hashes, reconcile and soft delete extra payments",
+					fmt.Errorf("duplicated: %+v", duplicated),
+				)
+			}
+
+			return tx.Exec(
+				"CREATE UNIQUE INDEX IF NOT EXISTS " + DEPOSIT_TX_HASH_INDEX +
+					" ON payments (tx_hash)" +
+					" WHERE type LIKE 'deposit_%' AND tx_hash <> '' AND deleted_at IS NULL",
+			).Error
+		},
+	}
+}
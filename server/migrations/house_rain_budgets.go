@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"github.com/Duelana-Team/duelana-v1/db/migrate"
+	"gorm.io/gorm"
+)
+
+/**
+* @Internal
+* Creates daily budgets of house rains, persisting the budget and
+* serializing rains of a day on its row.
+ */
+func houseRainBudgets() migrate.Migration {
+	return migrate.Migration{
+		Version: 202610190140,
+		Name:    "house_rain_budgets",
+		Up: func(tx *gorm.DB) error {
+			type HouseRainBudget struct {
+				gorm.Model
+				Day    string `gorm:"type:varchar(10);not null;uniqueIndex"`
+				Budget int64  `gorm:"not null"`
+				Spent  int64  `gorm:"not null;default:0"`
+			}
+			return tx.AutoMigrate(&HouseRainBudget{})
+		},
+	}
+}
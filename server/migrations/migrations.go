@@ -0,0 +1,28 @@
+package migrations
+
+import "github.com/Duelana-Team/duelana-v1/db/migrate"
+
+/**
+* @External
+* Returns registered migrations ordered by version.
+* Append new migrations with a later version, never edit applied ones.
+ */
+func GetMigrations() []migrate.Migration {
+	return []migrate.Migration{
+		baselineSchema(),
+		serverConfigDefaults(),
+		paymentNftMints(),
+		affiliateLifetimes(),
+		depositWatcher(),
+		depositReferences(),
+		withdrawals(),
+		splTokens(),
+		priceOracle(),
+		nftCollections(),
+		nftValuation(),
+		token2022(),
+		treasury(),
+		queuedDepositTime(),
+		houseRainBudgets(),
+	}
+}
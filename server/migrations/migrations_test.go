@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"testing"
+)
+
+func TestMigrationsAreOrdered(t *testing.T) {
+	registered := GetMigrations()
+	for i, migration := range registered {
+		if migration.Name == "" || migration.Up == nil {
+			t.Fatalf("incomplete migration: %d", migration.Version)
+		}
+		if i == 0 {
+			continue
+		}
+		if registered[i].Version <= registered[i-1].Version {
+			t.Fatalf(
+				"migrations should be ordered by version: %d after %d",
+				registered[i].Version, registered[i-1].Version,
+			)
+		}
+	}
+}
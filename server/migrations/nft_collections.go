@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/db/migrate"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+/**
+* @Internal
+* Adds collection status, existing collections stay approved,
+* and floor price history.
+ */
+func nftCollections() migrate.Migration {
+	return migrate.Migration{
+		Version: 202610190090,
+		Name:    "nft_collections",
+		Up: func(tx *gorm.DB) error {
+			// Only columns added at this version.
+			type NftCollection struct {
+				FloorPriceUpdatedAt *time.Time
+				Status              string `gorm:"not null;default:approved;index"`
+			}
+			type NftFloorPrice struct {
+				gorm.Model
+				CollectionID uint    `gorm:"not null;index"`
+				RawPrice     int64   `gorm:"not null"`
+				Price        int64   `gorm:"not null"`
+				SolPrice     float64 `gorm:"not null"`
+				Floors       datatypes.JSON
+			}
+			return tx.AutoMigrate(
+				&NftCollection{},
+				&NftFloorPrice{},
+			)
+		},
+	}
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"github.com/Duelana-Team/duelana-v1/db/migrate"
+	"gorm.io/gorm"
+)
+
+/**
+* @Internal
+* Adds valuation details of NFTs in game. Existing NFTs were
+* valued at floor.
+ */
+func nftValuation() migrate.Migration {
+	return migrate.Migration{
+		Version: 202610190100,
+		Name:    "nft_valuation",
+		Up: func(tx *gorm.DB) error {
+			// Only columns added at this version.
+			type NftInGame struct {
+				FloorPrice      int64  `gorm:"not null;default:0"`
+				ValuationMethod string `gorm:"not null;default:floor"`
+				RarityRank      uint   `gorm:"not null;default:0"`
+			}
+			if err := tx.AutoMigrate(&NftInGame{}); err != nil {
+				return err
+			}
+			return tx.Exec(
+				"UPDATE nft_in_games SET floor_price = price WHERE floor_price = 0",
+			).Error
+		},
+	}
+}
@@ -0,0 +1,57 @@
+package migrations
+
+import (
+	"encoding/json"
+
+	"github.com/Duelana-Team/duelana-v1/db/migrate"
+	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/migrations/baseline"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+/**
+* @Internal
+* Moves nft deposits from json encoded `MintAddresses` to `Mints`.
+* Replaces `payment.MigratePaymentModel` ran on every boot.
+* Undecodable payments are logged and left as they are.
+ */
+func paymentNftMints() migrate.Migration {
+	const MIGRATE_DONE = "migrate done"
+	return migrate.Migration{
+		Version: 202610190020,
+		Name:    "payment_nft_mints",
+		Up: func(tx *gorm.DB) error {
+			payments := []baseline.Payment{}
+			if err := tx.Where(
+				"mint_addresses <> '' and mint_addresses <> ?",
+				MIGRATE_DONE,
+			).Find(&payments).Error; err != nil {
+				return err
+			}
+
+			for _, payment := range payments {
+				if err := json.Unmarshal(
+					[]byte(payment.NftDetail.MintAddresses),
+					&payment.NftDetail.Mints,
+				); err != nil {
+					log.LogMessage(
+						"migrations_paymentNftMints",
+						"failed to decode mint addresses",
+						"error",
+						logrus.Fields{
+							"payment": payment.ID,
+							"error":   err.Error(),
+						},
+					)
+					continue
+				}
+				payment.NftDetail.MintAddresses = MIGRATE_DONE
+				if err := tx.Save(&payment).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/db/migrate"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+/**
+* @Internal
+* Creates payment price snapshots, and prices registered tokens
+* from several sources. USDC is pegged as before.
+ */
+func priceOracle() migrate.Migration {
+	return migrate.Migration{
+		Version: 202610190080,
+		Name:    "price_oracle",
+		Up: func(tx *gorm.DB) error {
+			type PaymentPriceSnapshot struct {
+				gorm.Model
+				PaymentID uint    `gorm:"not null;index"`
+				Mint      string  `gorm:"type:varchar(50);not null"`
+				Price     float64 `gorm:"not null;default:0"`
+				PricedAt  time.Time
+				Quotes    datatypes.JSON
+				Accepted  bool   `gorm:"not null"`
+				Reason    string `gorm:"type:text"`
+			}
+			if err := tx.AutoMigrate(&PaymentPriceSnapshot{}); err != nil {
+				return err
+			}
+			if err := tx.Table("spl_tokens").
+				Where("mint = ?", config.USDC_SPL_ADDRESS).
+				Where("price_source IN ?", []string{"coingecko", "coingecko,jupiter"}).
+				Update("price_source", "usd_peg").Error; err != nil {
+				return err
+			}
+			return tx.Table("spl_tokens").
+				Where("price_source = ?", "coingecko").
+				Update("price_source", "coingecko,jupiter").Error
+		},
+	}
+}
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/db/migrate"
+	"gorm.io/gorm"
+)
+
+/**
+* @Internal
+* Adds block time of deposits to price snapshots, so that queued
+* deposits are priced at their time. Existing ones have none and
+* are priced at their payment creation.
+ */
+func queuedDepositTime() migrate.Migration {
+	return migrate.Migration{
+		Version: 202610190130,
+		Name:    "queued_deposit_time",
+		Up: func(tx *gorm.DB) error {
+			// Only columns added at this version.
+			type PaymentPriceSnapshot struct {
+				DepositedAt *time.Time
+			}
+			return tx.AutoMigrate(&PaymentPriceSnapshot{})
+		},
+	}
+}
@@ -0,0 +1,50 @@
+package migrations
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/db/migrate"
+	"github.com/Duelana-Team/duelana-v1/migrations/baseline"
+	"gorm.io/gorm"
+)
+
+/**
+* @Internal
+* Creates server config with defaults, or resets one which was never
+* reset before. Replaces the `ShouldNotReset` check ran on every boot.
+ */
+func serverConfigDefaults() migrate.Migration {
+	return migrate.Migration{
+		Version: 202610190010,
+		Name:    "server_config_defaults",
+		Up: func(tx *gorm.DB) error {
+			var serverConfig baseline.ServerConfig
+			err := tx.First(&serverConfig).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			if err == nil && serverConfig.ShouldNotReset {
+				return nil
+			}
+
+			apiRateLimit, err := json.Marshal(config.API_RATE_LIMIT_CONFIGURATION)
+			if err != nil {
+				return err
+			}
+			websocketRateLimit, err := json.Marshal(config.WEBSOCKET_RATE_LIMIT_CONFIGURATION)
+			if err != nil {
+				return err
+			}
+
+			serverConfig.ShouldNotReset = true
+			serverConfig.BaseRakeBackRate = config.BASE_RAKEBACK_RATE
+			serverConfig.AdditionalRakeBackRate = config.ADDITIONAL_RAKEBACK_RATE
+			serverConfig.NextGrandJackpotStartAt = config.GRAND_JACKPOT_NEXT_ROUND
+			serverConfig.ApiRateLimitConfiguration = string(apiRateLimit)
+			serverConfig.WebsocketRateLimitConfiguration = string(websocketRateLimit)
+			return tx.Save(&serverConfig).Error
+		},
+	}
+}
@@ -0,0 +1,83 @@
+package migrations
+
+import (
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/db/migrate"
+	"gorm.io/gorm"
+)
+
+/**
+* @Internal
+* Creates spl token registry with tokens supported before,
+* which were hard coded in `solana.initializeSplTokens`.
+ */
+func splTokens() migrate.Migration {
+	return migrate.Migration{
+		Version: 202610190070,
+		Name:    "spl_tokens",
+		Up: func(tx *gorm.DB) error {
+			type SplToken struct {
+				gorm.Model
+				Mint            string `gorm:"type:varchar(50);not null;uniqueIndex"`
+				Keyword         string `gorm:"type:varchar(20);not null;uniqueIndex"`
+				Decimals        int    `gorm:"not null"`
+				Image           string
+				DepositEnabled  bool   `gorm:"not null"`
+				WithdrawEnabled bool   `gorm:"not null"`
+				MinDeposit      uint64 `gorm:"not null;default:0"`
+				MinWithdraw     int64  `gorm:"not null;default:0"`
+				WithdrawFee     int64  `gorm:"not null;default:0"`
+				PriceSource     string `gorm:"type:varchar(20);not null"`
+			}
+			if err := tx.AutoMigrate(&SplToken{}); err != nil {
+				return err
+			}
+
+			var count int64
+			if err := tx.Model(&SplToken{}).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return nil
+			}
+
+			tokens := []SplToken{
+				{
+					Mint:        config.SOL_SPL_ADDRESS,
+					Keyword:     "SOL",
+					Decimals:    9,
+					Image:       "https://duelana-bucket-prod.s3.us-east-2.amazonaws.com/coins/SOL.png",
+					WithdrawFee: 0,
+				},
+				{
+					Mint:        config.BONK_SPL_ADDRESS,
+					Keyword:     "Bonk",
+					Decimals:    5,
+					Image:       "https://duelana-bucket-prod.s3.us-east-2.amazonaws.com/coins/Bonk.png",
+					WithdrawFee: config.WITHDRAW_FEE_PER_SPL,
+				},
+				{
+					Mint:        config.USDC_SPL_ADDRESS,
+					Keyword:     "USDC",
+					Decimals:    6,
+					Image:       "https://duelana-bucket-prod.s3.us-east-2.amazonaws.com/coins/USDC.png",
+					WithdrawFee: config.WITHDRAW_FEE_PER_SPL,
+				},
+				{
+					Mint:        config.BOKU_SPL_ADDRESS,
+					Keyword:     "BOKU",
+					Decimals:    9,
+					Image:       "https://duelana-bucket-prod.s3.us-east-2.amazonaws.com/coins/BOKU.png",
+					WithdrawFee: config.WITHDRAW_FEE_PER_SPL,
+				},
+			}
+			for i := range tokens {
+				tokens[i].DepositEnabled = true
+				tokens[i].WithdrawEnabled = true
+				tokens[i].MinWithdraw = config.WITHDRAW_MIN_LIMIT
+				tokens[i].PriceSource = "coingecko" // Default at this version.
+			}
+			return tx.Create(&tokens).Error
+		},
+	}
+}
@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"github.com/Duelana-Team/duelana-v1/db/migrate"
+	"gorm.io/gorm"
+)
+
+/**
+* @Internal
+* Adds token program of spl tokens, existing ones are minted by
+* token program.
+ */
+func token2022() migrate.Migration {
+	return migrate.Migration{
+		Version: 202610190110,
+		Name:    "token_2022",
+		Up: func(tx *gorm.DB) error {
+			// Only columns added at this version.
+			type SplToken struct {
+				Token2022 bool `gorm:"not null;default:false"`
+			}
+			return tx.AutoMigrate(&SplToken{})
+		},
+	}
+}
@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"github.com/Duelana-Team/duelana-v1/db/migrate"
+	"gorm.io/gorm"
+)
+
+/**
+* @Internal
+* Adds hot wallet thresholds of spl tokens and sweeps to cold
+* wallet. Existing tokens have no thresholds.
+ */
+func treasury() migrate.Migration {
+	return migrate.Migration{
+		Version: 202610190120,
+		Name:    "treasury",
+		Up: func(tx *gorm.DB) error {
+			// Only columns added at this version.
+			type SplToken struct {
+				HotWalletMin uint64 `gorm:"not null;default:0"`
+				HotWalletMax uint64 `gorm:"not null;default:0"`
+			}
+			type TreasurySweep struct {
+				gorm.Model
+				Mint                 string `gorm:"type:varchar(50);not null;index"`
+				To                   string `gorm:"type:varchar(50);not null"`
+				Amount               uint64 `gorm:"not null;default:0"`
+				Signature            string `gorm:"type:varchar(100);index"`
+				LastValidBlockHeight uint64 `gorm:"not null;default:0"`
+				Status               string `gorm:"not null;default:sent;index"`
+				Error                string `gorm:"type:text"`
+			}
+			return tx.AutoMigrate(
+				&SplToken{},
+				&TreasurySweep{},
+			)
+		},
+	}
+}
@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/Duelana-Team/duelana-v1/db/migrate"
+	"github.com/Duelana-Team/duelana-v1/migrations/baseline"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+/**
+* @Internal
+* Creates withdrawal lifecycle table. Withdrawals sent before
+* are left to `/admin/refund-withdrawals`.
+ */
+func withdrawals() migrate.Migration {
+	return migrate.Migration{
+		Version: 202610190060,
+		Name:    "withdrawals",
+		Up: func(tx *gorm.DB) error {
+			type Withdrawal struct {
+				gorm.Model
+				PaymentID            uint              `gorm:"not null;uniqueIndex"`
+				Payment              *baseline.Payment `gorm:"foreignKey:PaymentID"`
+				UserID               uint              `gorm:"not null;index"`
+				Status               string            `gorm:"not null;default:queued;index"`
+				To                   string            `gorm:"type:varchar(50);not null"`
+				Mint                 string            `gorm:"type:varchar(50)"`
+				Amount               uint64            `gorm:"not null;default:0"`
+				Nfts                 pq.StringArray    `gorm:"type:text[]"`
+				Signature            string            `gorm:"type:varchar(100);index"`
+				Signatures           pq.StringArray    `gorm:"type:text[]"`
+				LastValidBlockHeight uint64            `gorm:"not null;default:0"`
+				PriorityFee          uint64            `gorm:"not null;default:0"`
+				Broadcasts           uint              `gorm:"not null;default:0"`
+				SentAt               *time.Time
+				SettledAt            *time.Time
+				Error                string `gorm:"type:text"`
+			}
+			return tx.AutoMigrate(&Withdrawal{})
+		},
+	}
+}
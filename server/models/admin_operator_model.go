@@ -0,0 +1,54 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AdminOperatorRole string
+
+const (
+	AdminFinanceRole    AdminOperatorRole = "finance"
+	AdminGamesRole      AdminOperatorRole = "games"
+	AdminPromotionsRole AdminOperatorRole = "promotions"
+	AdminSupportRole    AdminOperatorRole = "support"
+	AdminSuperRole      AdminOperatorRole = "super-admin"
+)
+
+type AdminOperator struct {
+	gorm.Model
+	Name      string            `gorm:"not null;uniqueIndex" json:"name"`
+	Role      AdminOperatorRole `gorm:"not null" json:"role"`
+	TokenHash string            `gorm:"not null;uniqueIndex" json:"-"`
+	Enabled   bool              `gorm:"not null;default:true" json:"enabled"`
+}
+
+/**
+* Audit log entries are append-only. Updates and deletes are rejected
+* by model hooks so that records can not be altered through gorm.
+ */
+type AdminAuditLog struct {
+	ID           uint              `gorm:"primarykey" json:"id"`
+	CreatedAt    time.Time         `gorm:"index" json:"createdAt"`
+	OperatorID   *uint             `gorm:"index" json:"operatorId"`
+	OperatorName string            `gorm:"index" json:"operatorName"`
+	Role         AdminOperatorRole `json:"role"`
+	Method       string            `json:"method"`
+	Route        string            `gorm:"index" json:"route"`
+	Parameters   string            `json:"parameters"`
+	StatusCode   int               `json:"statusCode"`
+	Result       string            `json:"result"`
+	ClientIP     string            `json:"clientIp"`
+}
+
+var ErrAdminAuditLogImmutable = errors.New("admin audit log is immutable")
+
+func (log *AdminAuditLog) BeforeUpdate(tx *gorm.DB) error {
+	return ErrAdminAuditLogImmutable
+}
+
+func (log *AdminAuditLog) BeforeDelete(tx *gorm.DB) error {
+	return ErrAdminAuditLogImmutable
+}
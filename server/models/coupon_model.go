@@ -5,6 +5,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
+	"gorm.io/gorm"
 )
 
 type CouponType string
@@ -24,17 +25,35 @@ type Coupon struct {
 	BonusBalance          int64           `gorm:"not null" json:"bonusBalance"`
 	RequiredAffiliateCode *string         `json:"requiredAffiliateCode"`
 	RequiredAffiliate     *Affiliate      `gorm:"foreignKey:RequiredAffiliateCode;references:Code" json:"requiredAffiliate"`
+	CampaignID            *uint           `gorm:"index" json:"campaignId"`
+	Campaign              *CouponCampaign `gorm:"foreignKey:CampaignID" json:"campaign"`
+}
+
+type CouponCampaign struct {
+	gorm.Model
+	Name                   string         `gorm:"not null;unique" json:"name"`
+	CodeCount              uint           `gorm:"not null" json:"codeCount"`
+	BonusBalance           int64          `gorm:"not null" json:"bonusBalance"`
+	ExpiresAt              time.Time      `gorm:"not null;index" json:"expiresAt"`
+	WagerTimes             uint           `gorm:"not null" json:"wagerTimes"`
+	BalanceLifeTimeInHours uint           `gorm:"not null" json:"balanceLifeTimeInHours"`
+	MaxBetWhileActive      int64          `gorm:"default:0" json:"maxBetWhileActive"`
+	AllowedGames           pq.StringArray `gorm:"type:text[]" json:"allowedGames"`
+	ClaimLimitPerIP        uint           `gorm:"default:0" json:"claimLimitPerIp"`
+	Coupons                []Coupon       `gorm:"foreignKey:CampaignID" json:"coupons"`
 }
 
 type ClaimedCoupon struct {
-	CreatedAt     time.Time `gorm:"index"`
-	CouponID      uuid.UUID `gorm:"not null;primaryKey;autoIncrement:false" json:"couponId"`
-	Coupon        Coupon    `gorm:"foreignKey:CouponID" json:"coupon"`
-	ClaimedUserID uint      `gorm:"not null;index;primaryKey;autoIncrement:false" json:"claimedUserId"`
-	ClaimedUser   User      `gorm:"foreignKey:ClaimedUserID" json:"claimedUser"`
-	Wagered       int64     `gorm:"default:0" json:"wagered"`
-	Balance       int64     `gorm:"not null" json:"balance"`
-	Exchanged     int64     `gorm:"default:0;index" json:"exchanged"`
+	CreatedAt     time.Time  `gorm:"index"`
+	CouponID      uuid.UUID  `gorm:"not null;primaryKey;autoIncrement:false" json:"couponId"`
+	Coupon        Coupon     `gorm:"foreignKey:CouponID" json:"coupon"`
+	ClaimedUserID uint       `gorm:"not null;index;primaryKey;autoIncrement:false" json:"claimedUserId"`
+	ClaimedUser   User       `gorm:"foreignKey:ClaimedUserID" json:"claimedUser"`
+	Wagered       int64      `gorm:"default:0" json:"wagered"`
+	Balance       int64      `gorm:"not null" json:"balance"`
+	Exchanged     int64      `gorm:"default:0;index" json:"exchanged"`
+	ClaimedIP     string     `gorm:"index" json:"claimedIp"`
+	ExpiresAt     *time.Time `gorm:"index" json:"expiresAt"`
 }
 
 type CouponShortcut struct {
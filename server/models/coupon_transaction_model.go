@@ -15,6 +15,7 @@ const (
 	CpTxDreamtowerProfit CouponTransactionType = "cp-tx-dreamtower-profit"
 	CpTxCrashBet         CouponTransactionType = "cp-tx-crash-bet"
 	CpTxCrashProfit      CouponTransactionType = "cp-tx-crash-profit"
+	CpTxCrashRefund      CouponTransactionType = "cp-tx-crash-refund"
 	CpTxExchangeToChip   CouponTransactionType = "cp-tx-exchange-to-chip"
 )
 
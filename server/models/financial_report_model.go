@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PaymentDirection string
+
+const (
+	PaymentDeposit  PaymentDirection = "deposit"
+	PaymentWithdraw PaymentDirection = "withdraw"
+)
+
+/**
+* Nightly materialized gross gaming revenue of a game per UTC day.
+* `GGR` is `Wager` minus `Payout`, where both only count real users.
+ */
+type DailyGameRevenue struct {
+	gorm.Model
+	Day    time.Time `gorm:"type:date;not null;uniqueIndex:idx_daily_game_revenue" json:"day"`
+	Game   string    `gorm:"not null;uniqueIndex:idx_daily_game_revenue" json:"game"`
+	Wager  int64     `gorm:"not null;default:0" json:"wager"`
+	Payout int64     `gorm:"not null;default:0" json:"payout"`
+	Fee    int64     `gorm:"not null;default:0" json:"fee"`
+	GGR    int64     `gorm:"column:ggr;not null;default:0" json:"ggr"`
+}
+
+/**
+* Nightly materialized house cost per UTC day, subtracted from GGR for NGR.
+ */
+type DailyRevenueDeduction struct {
+	gorm.Model
+	Day      time.Time `gorm:"type:date;not null;uniqueIndex:idx_daily_revenue_deduction" json:"day"`
+	Category string    `gorm:"not null;uniqueIndex:idx_daily_revenue_deduction" json:"category"`
+	Amount   int64     `gorm:"not null;default:0" json:"amount"`
+}
+
+/**
+* Nightly materialized deposit and withdrawal volume per token and UTC day.
+* `TokenAmount` is in token decimals, `UsdAmount` in balance decimals.
+ */
+type DailyPaymentVolume struct {
+	gorm.Model
+	Day         time.Time        `gorm:"type:date;not null;uniqueIndex:idx_daily_payment_volume" json:"day"`
+	Direction   PaymentDirection `gorm:"not null;uniqueIndex:idx_daily_payment_volume" json:"direction"`
+	Token       string           `gorm:"not null;uniqueIndex:idx_daily_payment_volume" json:"token"`
+	Count       int64            `gorm:"not null;default:0" json:"count"`
+	TokenAmount int64            `gorm:"not null;default:0" json:"tokenAmount"`
+	UsdAmount   int64            `gorm:"not null;default:0" json:"usdAmount"`
+}
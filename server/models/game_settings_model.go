@@ -0,0 +1,18 @@
+package models
+
+import (
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+/**
+* Versioned runtime settings of a game.
+* Records are never updated. Latest version of each game is active.
+ */
+type GameSettings struct {
+	gorm.Model
+	Game     string         `gorm:"not null;uniqueIndex:idx_game_settings_version" json:"game"`
+	Version  uint           `gorm:"not null;uniqueIndex:idx_game_settings_version" json:"version"`
+	Settings datatypes.JSON `gorm:"not null" json:"settings"`
+	Operator string         `json:"operator"`
+}
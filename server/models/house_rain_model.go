@@ -0,0 +1,46 @@
+package models
+
+import (
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+type HouseRainStatus string
+
+const (
+	HouseRainPerformed HouseRainStatus = "performed"
+	HouseRainSkipped   HouseRainStatus = "skipped"
+	HouseRainFailed    HouseRainStatus = "failed"
+)
+
+type HouseRainSchedule struct {
+	gorm.Model
+	Name               string `gorm:"not null" json:"name"`
+	CronSpec           string `gorm:"not null" json:"cronSpec"`
+	Amount             int64  `gorm:"not null" json:"amount"`
+	SplitCount         uint   `gorm:"not null" json:"splitCount"`
+	MinWager           int64  `gorm:"default:0" json:"minWager"`
+	MinVipLevel        uint   `gorm:"default:0" json:"minVipLevel"`
+	CountdownInSeconds uint   `gorm:"default:0" json:"countdownInSeconds"`
+	Enabled            bool   `gorm:"index" json:"enabled"`
+}
+
+type HouseRain struct {
+	gorm.Model
+	ScheduleID    uint            `gorm:"index" json:"scheduleId"`
+	Amount        int64           `json:"amount"`
+	Recipients    pq.Int64Array   `gorm:"type:bigint[]" json:"recipients"`
+	TransactionID *uint           `json:"transactionId"`
+	Transaction   *Transaction    `gorm:"foreignKey:TransactionID" json:"transaction"`
+	Status        HouseRainStatus `gorm:"index" json:"status"`
+	Reason        string          `json:"reason"`
+}
+
+// Budget of house rains of a day, locked while a rain is performed.
+// Budget of a new day is carried over from the latest day.
+type HouseRainBudget struct {
+	gorm.Model
+	Day    string `gorm:"type:varchar(10);not null;uniqueIndex" json:"day"`
+	Budget int64  `gorm:"not null" json:"budget"`
+	Spent  int64  `gorm:"not null;default:0" json:"spent"`
+}
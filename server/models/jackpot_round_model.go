@@ -29,6 +29,9 @@ type NftInGame struct {
 	CollectionName  string        `gorm:"not null" json:"collectionName"`
 	CollectionImage string        `gorm:"not null" json:"collectionImage"`
 	Price           int64         `gorm:"not null" json:"price"`
+	FloorPrice      int64         `gorm:"not null;default:0" json:"floorPrice"`          // Collection floor when valued
+	ValuationMethod string        `gorm:"not null;default:floor" json:"valuationMethod"` // How `Price` was chosen
+	RarityRank      uint          `gorm:"not null;default:0" json:"rarityRank"`
 	Status          NftGameStatus `json:"status"`
 	BetID           uint          `gorm:"not null" json:"betId"`
 }
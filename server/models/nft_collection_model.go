@@ -1,18 +1,43 @@
 package models
 
 import (
+	"time"
+
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
+type NftCollectionStatus string
+
+const (
+	NftCollectionPending  NftCollectionStatus = "pending"
+	NftCollectionApproved NftCollectionStatus = "approved"
+	NftCollectionDisabled NftCollectionStatus = "disabled"
+)
+
 type NftCollection struct {
 	gorm.Model
-	Name       string         `gorm:"not null;varchar(50)" json:"name"`
-	MoonRank   string         `gorm:"varchar(50)" json:"moonRank"`
-	Solanart   string         `gorm:"varchar(50)" json:"solanart"`
-	MagicEden  string         `gorm:"varchar(50)" json:"magicEden"`
-	HyperSpace string         `gorm:"varchar(50)" json:"hyperSpace"`
-	HowRare    string         `gorm:"varchar(50)" json:"howRare"`
-	Image      string         `gorm:"not null" json:"image"`
-	FloorPrice int64          `gorm:"not null;default:0" json:"floorPrice"`
-	Nfts       []DepositedNft `gorm:"foreignKey:CollectionID" json:"nfts"`
+	Name                string              `gorm:"not null;varchar(50)" json:"name"`
+	MoonRank            string              `gorm:"varchar(50)" json:"moonRank"`
+	Solanart            string              `gorm:"varchar(50)" json:"solanart"`
+	MagicEden           string              `gorm:"varchar(50)" json:"magicEden"`
+	HyperSpace          string              `gorm:"varchar(50)" json:"hyperSpace"`
+	HowRare             string              `gorm:"varchar(50)" json:"howRare"`
+	Image               string              `gorm:"not null" json:"image"`
+	FloorPrice          int64               `gorm:"not null;default:0" json:"floorPrice"`
+	FloorPriceUpdatedAt *time.Time          `json:"floorPriceUpdatedAt"`
+	Status              NftCollectionStatus `gorm:"not null;default:approved;index" json:"status"`
+	Nfts                []DepositedNft      `gorm:"foreignKey:CollectionID" json:"nfts"`
+}
+
+// Floor price observation of a collection. `RawPrice` is the
+// median of marketplace floors, `Price` the floor applied after
+// TWAP and max change per update.
+type NftFloorPrice struct {
+	gorm.Model
+	CollectionID uint           `gorm:"not null;index" json:"collectionId"`
+	RawPrice     int64          `gorm:"not null" json:"rawPrice"`
+	Price        int64          `gorm:"not null" json:"price"`
+	SolPrice     float64        `gorm:"not null" json:"solPrice"`
+	Floors       datatypes.JSON `json:"floors"` // Lamports per marketplace
 }
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"github.com/lib/pq"
 	"gorm.io/gorm"
 )
@@ -34,8 +36,59 @@ type Payment struct {
 	Status        PaymentStatus `gorm:"not null;default:pending;index:status" json:"status"`
 	SolDetail     SolDetail     `gorm:"embedded"`
 	NftDetail     NftDetail     `gorm:"embedded"`
-	TxHash        string        `gorm:"type:varchar(100)" json:"txHash"`
+	TxHash        string        `gorm:"type:varchar(100);uniqueIndex:idx_payments_deposit_tx_hash,where:type LIKE 'deposit_%' AND tx_hash <> '' AND deleted_at IS NULL" json:"txHash"`
 	TransactionID *uint         `json:"transactionId"`
 	Transaction   *Transaction  `gorm:"foreignKey:TransactionID" json:"transaction"`
 	AdminDepositAmountDetail AdminDepositAmountDetail `gorm:"embedded"`
 }
+
+// Last signature handled by deposit watcher per watched address.
+type DepositWatcherCursor struct {
+	gorm.Model
+	Address       string `gorm:"not null;uniqueIndex" json:"address"`
+	LastSignature string `gorm:"type:varchar(100);not null" json:"lastSignature"`
+	LastSlot      uint64 `gorm:"not null;default:0" json:"lastSlot"`
+}
+
+// Deposit reference of a user. Deposits carrying the reference
+// key or the memo are credited to the user whatever the sender is.
+type DepositReference struct {
+	gorm.Model
+	UserID    uint   `gorm:"not null;uniqueIndex" json:"userId"`
+	Reference string `gorm:"type:varchar(50);not null;uniqueIndex" json:"reference"`
+	Memo      string `gorm:"type:varchar(20);not null;uniqueIndex" json:"memo"`
+}
+
+type WithdrawalStatus string
+
+const (
+	WithdrawalQueued    WithdrawalStatus = "queued"
+	WithdrawalSent      WithdrawalStatus = "sent"
+	WithdrawalConfirmed WithdrawalStatus = "confirmed"
+	WithdrawalFinalized WithdrawalStatus = "finalized"
+	WithdrawalExpired   WithdrawalStatus = "expired"
+	WithdrawalFailed    WithdrawalStatus = "failed"
+)
+
+// On chain lifecycle of a withdraw payment. Signature and last
+// valid block height are saved before broadcast, so that a
+// transaction which may land is always tracked.
+type Withdrawal struct {
+	gorm.Model
+	PaymentID            uint             `gorm:"not null;uniqueIndex" json:"paymentId"`
+	Payment              *Payment         `gorm:"foreignKey:PaymentID" json:"payment"`
+	UserID               uint             `gorm:"not null;index" json:"userId"`
+	Status               WithdrawalStatus `gorm:"not null;default:queued;index" json:"status"`
+	To                   string           `gorm:"type:varchar(50);not null" json:"to"`
+	Mint                 string           `gorm:"type:varchar(50)" json:"mint"`
+	Amount               uint64           `gorm:"not null;default:0" json:"amount"`
+	Nfts                 pq.StringArray   `gorm:"type:text[]" json:"nfts"`
+	Signature            string           `gorm:"type:varchar(100);index" json:"signature"`
+	Signatures           pq.StringArray   `gorm:"type:text[]" json:"signatures"`
+	LastValidBlockHeight uint64           `gorm:"not null;default:0" json:"lastValidBlockHeight"`
+	PriorityFee          uint64           `gorm:"not null;default:0" json:"priorityFee"`
+	Broadcasts           uint             `gorm:"not null;default:0" json:"broadcasts"`
+	SentAt               *time.Time       `json:"sentAt"`
+	SettledAt            *time.Time       `json:"settledAt"`
+	Error                string           `gorm:"type:text" json:"error"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+/**
+* Price a payment was valued at, with quotes it was aggregated
+* from. Rejected snapshots record why a deposit was queued, and
+* the block time the queued deposit is priced at later.
+ */
+type PaymentPriceSnapshot struct {
+	gorm.Model
+	PaymentID   uint           `gorm:"not null;index" json:"paymentId"`
+	Mint        string         `gorm:"type:varchar(50);not null" json:"mint"`
+	Price       float64        `gorm:"not null;default:0" json:"price"` // USD, zero when rejected
+	PricedAt    time.Time      `json:"pricedAt"`
+	DepositedAt *time.Time     `json:"depositedAt"` // Block time of deposits
+	Quotes      datatypes.JSON `json:"quotes"`
+	Accepted    bool           `gorm:"not null" json:"accepted"`
+	Reason      string         `gorm:"type:text" json:"reason"`
+}
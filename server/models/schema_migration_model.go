@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+/**
+* Applied schema and data migration, recorded by `db/migrate`.
+ */
+type SchemaMigration struct {
+	Version   uint64    `gorm:"primaryKey;autoIncrement:false" json:"version"`
+	Name      string    `gorm:"not null" json:"name"`
+	AppliedAt time.Time `gorm:"not null" json:"appliedAt"`
+}
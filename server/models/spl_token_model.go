@@ -0,0 +1,26 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+/**
+* Spl token accepted for deposits and withdrawals.
+* Mint of wrapped SOL stands for native lamports.
+ */
+type SplToken struct {
+	gorm.Model
+	Mint            string `gorm:"type:varchar(50);not null;uniqueIndex" json:"mint"`
+	Keyword         string `gorm:"type:varchar(20);not null;uniqueIndex" json:"keyword"`
+	Decimals        int    `gorm:"not null" json:"decimals"`
+	Image           string `json:"image"`
+	DepositEnabled  bool   `gorm:"not null" json:"depositEnabled"`
+	WithdrawEnabled bool   `gorm:"not null" json:"withdrawEnabled"`
+	MinDeposit      uint64 `gorm:"not null;default:0" json:"minDeposit"`  // In token base units
+	MinWithdraw     int64  `gorm:"not null;default:0" json:"minWithdraw"` // In chips
+	WithdrawFee     int64  `gorm:"not null;default:0" json:"withdrawFee"` // In chips
+	PriceSource     string `gorm:"type:varchar(20);not null" json:"priceSource"`
+	Token2022       bool   `gorm:"not null;default:false" json:"token2022"` // Minted by Token-2022 program
+	HotWalletMin    uint64 `gorm:"not null;default:0" json:"hotWalletMin"`  // Withdrawals pause below, in token base units
+	HotWalletMax    uint64 `gorm:"not null;default:0" json:"hotWalletMax"`  // Excess is swept to cold wallet, in token base units
+}
@@ -36,9 +36,12 @@ const (
 	TxCrashBet                TransactionType = "crash_bet"
 	TxCrashProfit             TransactionType = "crash_profit"
 	TxCrashFee                TransactionType = "crash_fee"
+	TxCrashRefund             TransactionType = "crash_refund"
 	TxClaimDailyRaceReward    TransactionType = "claim_daily_race_reward"
 	TxClaimWeeklyRaffleReward TransactionType = "claim_weekly_raffle_reward"
 	TxAdminUserDeposit        TransactionType = "admin_deposit_to_user"
+	TxClaimQuestReward        TransactionType = "claim_quest_reward"
+	TxHouseRain               TransactionType = "house_rain"
 )
 
 type TransactionStatus string
@@ -67,6 +70,7 @@ const (
 	TransactionDailyRaceRewardsReferenced   TransactionOwnerType = "tx_daily_race_rewards_referenced"
 	TransactionWeeklyRaffleRewardReferenced TransactionOwnerType = "tx_weekly_raffle_reward_referenced"
 	TransactionPaymentAdminUserBalanceUpdate TransactionOwnerType = "tx_admin_user_referenced"
+	TransactionQuestProgressReferenced      TransactionOwnerType = "tx_quest_progress_referenced"
 )
 
 type Transaction struct {
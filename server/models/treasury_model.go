@@ -0,0 +1,21 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+/**
+* Excess of hot treasury wallet sent to cold wallet. Sweeps share
+* the on chain lifecycle of withdrawals, but are never rebroadcast.
+* An expired sweep leaves funds in hot wallet for the next one.
+ */
+type TreasurySweep struct {
+	gorm.Model
+	Mint                 string           `gorm:"type:varchar(50);not null;index" json:"mint"`
+	To                   string           `gorm:"type:varchar(50);not null" json:"to"`
+	Amount               uint64           `gorm:"not null;default:0" json:"amount"` // In token base units
+	Signature            string           `gorm:"type:varchar(100);index" json:"signature"`
+	LastValidBlockHeight uint64           `gorm:"not null;default:0" json:"lastValidBlockHeight"`
+	Status               WithdrawalStatus `gorm:"not null;default:sent;index" json:"status"`
+	Error                string           `gorm:"type:text" json:"error"`
+}
@@ -2,45 +2,100 @@ package routes
 
 import (
 	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/controllers"
 	"github.com/Duelana-Team/duelana-v1/controllers/admin"
 	"github.com/Duelana-Team/duelana-v1/controllers/daily_race"
+	"github.com/Duelana-Team/duelana-v1/controllers/financial_report"
+	"github.com/Duelana-Team/duelana-v1/controllers/game_settings"
+	"github.com/Duelana-Team/duelana-v1/controllers/house_rain"
+	"github.com/Duelana-Team/duelana-v1/controllers/nft_collection"
+	"github.com/Duelana-Team/duelana-v1/controllers/quest"
+	"github.com/Duelana-Team/duelana-v1/controllers/recovery"
 	"github.com/Duelana-Team/duelana-v1/controllers/self_exclusion"
+	"github.com/Duelana-Team/duelana-v1/controllers/token_registry"
+	"github.com/Duelana-Team/duelana-v1/controllers/treasury"
 	"github.com/Duelana-Team/duelana-v1/controllers/weekly_raffle"
 	"github.com/Duelana-Team/duelana-v1/middlewares"
+	"github.com/Duelana-Team/duelana-v1/models"
 	"github.com/gin-gonic/gin"
 )
 
 func initAdminRoutes(rg *gin.RouterGroup) {
-	tokenAuthMiddleware := middlewares.TokenAuthMiddleware(config.Get().AdminApiAccessToken)
-
 	adminRoute := rg.Group("/admin")
-	adminRoute.Use(tokenAuthMiddleware)
-	adminRoute.GET("/pending-withdrawals", admin.GetPendingWithdrawals)
-	adminRoute.POST("/refund-withdrawals", admin.RefundFailedWithdrawals)
-	adminRoute.GET("/rakeback", admin.GetRakebackRate)
-	adminRoute.POST("/rakeback", admin.SetRakebackRate)
-	adminRoute.POST("/set-affiliate-custom-rate", admin.SetAffiliateCustomRate)
-	adminRoute.POST("/block-game", admin.BlockGameHandler)
-	adminRoute.POST("/start-game", admin.StartGameHandler)
-	adminRoute.GET("/get-game-status", admin.GetGameStatusHandler)
-	adminRoute.GET("/get-total-game-status", admin.GetAllGameStatusHandler)
-	adminRoute.GET("/get-user-loss", admin.GetUserLoss)
-	adminRoute.POST("/create-coupon", admin.CreateCouponHandler)
-	adminRoute.POST("/crash-salt", admin.DetermineCrashSalt)
-	adminRoute.POST("/crash-client-seed", admin.DetermineClientSeed)
-	adminRoute.POST("/crash-pause", admin.PauseCrash)
-	adminRoute.POST("/crash-start", admin.StartCrash)
-	adminRoute.POST("/remove-self-exclusion", self_exclusion.Remove)
-	adminRoute.POST("/create-coupon-shortcut", admin.CreateCouponShortcutHandler)
-	adminRoute.POST("/delete-coupon-shortcut", admin.DeleteCouponShortcutHandler)
-	adminRoute.GET("/get-daily-race-params", daily_race.GetParametersHandler)
-	adminRoute.POST("/set-daily-race-params", daily_race.SetParametersHandler)
-	adminRoute.POST("/perform-daily-race-prizing", daily_race.PerformDailyPrizingHandler)
-	adminRoute.GET("/get-unapproved-daily-race-rewards", daily_race.GetUnapprovedRewardsHandler)
-	adminRoute.POST("/approve-daily-race-rewards", daily_race.ApproveRewardsHandler)
-	adminRoute.POST("/set-affiliate-first-deposit", admin.SetAffiliateFirstDeposit)
-	adminRoute.GET("/get-weekly-raffle-prizes", weekly_raffle.GetPrizesHandler)
-	adminRoute.POST("/set-weekly-raffle-prizes", weekly_raffle.SetPrizesHandler)
-	adminRoute.POST("/perform-weekly-raffle-prizing", weekly_raffle.PerformweeklyRafflePrizingHandler)
-	adminRoute.POST("/update-user-balance", admin.UpdateUserBalances)
+	adminRoute.Use(middlewares.AdminAuthMiddleware(config.Get().AdminApiAccessToken))
+
+	financeRoute := adminRoute.Group("", middlewares.AdminPermission(models.AdminFinanceRole))
+	financeRoute.GET("/pending-withdrawals", admin.GetPendingWithdrawals)
+	financeRoute.POST("/refund-withdrawals", admin.RefundFailedWithdrawals)
+	financeRoute.GET("/rakeback", admin.GetRakebackRate)
+	financeRoute.POST("/rakeback", admin.SetRakebackRate)
+	financeRoute.POST("/set-affiliate-custom-rate", admin.SetAffiliateCustomRate)
+	financeRoute.POST("/set-affiliate-first-deposit", admin.SetAffiliateFirstDeposit)
+	financeRoute.POST("/update-user-balance", admin.UpdateUserBalances)
+	financeRoute.GET("/report-ggr", financial_report.GetGameRevenueHandler)
+	financeRoute.GET("/report-ngr", financial_report.GetNetRevenueHandler)
+	financeRoute.GET("/report-payments", financial_report.GetPaymentVolumeHandler)
+	financeRoute.POST("/rebuild-report", financial_report.RebuildReportHandler)
+	financeRoute.GET("/pending-tx-janitor", recovery.GetJanitorReportHandler)
+	financeRoute.POST("/backfill-deposits", controllers.Payment.BackfillDeposits)
+	financeRoute.GET("/spl-tokens", token_registry.GetTokensHandler)
+	financeRoute.POST("/save-spl-token", token_registry.SaveTokenHandler)
+	financeRoute.GET("/nft-collections", nft_collection.GetCollectionsHandler)
+	financeRoute.POST("/save-nft-collection", nft_collection.SaveCollectionHandler)
+	financeRoute.POST("/set-nft-collection-status", nft_collection.SetCollectionStatusHandler)
+	financeRoute.GET("/nft-floor-history", nft_collection.GetFloorHistoryHandler)
+	financeRoute.GET("/treasury-report", treasury.GetReportHandler)
+	financeRoute.GET("/treasury-sweeps", treasury.GetSweepsHandler)
+
+	gamesRoute := adminRoute.Group("", middlewares.AdminPermission(models.AdminGamesRole))
+	gamesRoute.POST("/block-game", admin.BlockGameHandler)
+	gamesRoute.POST("/start-game", admin.StartGameHandler)
+	gamesRoute.GET("/get-game-status", admin.GetGameStatusHandler)
+	gamesRoute.GET("/get-total-game-status", admin.GetAllGameStatusHandler)
+	gamesRoute.POST("/crash-salt", admin.DetermineCrashSalt)
+	gamesRoute.POST("/crash-client-seed", admin.DetermineClientSeed)
+	gamesRoute.POST("/crash-pause", admin.PauseCrash)
+	gamesRoute.POST("/crash-start", admin.StartCrash)
+	gamesRoute.GET("/game-settings", game_settings.GetSettingsHandler)
+	gamesRoute.POST("/save-game-settings", game_settings.SaveSettingsHandler)
+	gamesRoute.GET("/game-settings-history", game_settings.GetSettingsHistoryHandler)
+
+	promotionsRoute := adminRoute.Group("", middlewares.AdminPermission(models.AdminPromotionsRole))
+	promotionsRoute.POST("/create-coupon", admin.CreateCouponHandler)
+	promotionsRoute.POST("/create-coupon-shortcut", admin.CreateCouponShortcutHandler)
+	promotionsRoute.POST("/delete-coupon-shortcut", admin.DeleteCouponShortcutHandler)
+	promotionsRoute.POST("/create-coupon-campaign", admin.CreateCouponCampaignHandler)
+	promotionsRoute.GET("/get-coupon-campaign-codes", admin.GetCouponCampaignCodesHandler)
+	promotionsRoute.GET("/get-coupon-campaign-stats", admin.GetCouponCampaignStatsHandler)
+	promotionsRoute.POST("/create-deposit-bonus-campaign", admin.CreateDepositBonusCampaignHandler)
+	promotionsRoute.POST("/set-deposit-bonus-campaign-enabled", admin.SetDepositBonusCampaignEnabledHandler)
+	promotionsRoute.GET("/get-deposit-bonus-campaigns", admin.GetDepositBonusCampaignsHandler)
+	promotionsRoute.GET("/get-daily-race-params", daily_race.GetParametersHandler)
+	promotionsRoute.POST("/set-daily-race-params", daily_race.SetParametersHandler)
+	promotionsRoute.POST("/perform-daily-race-prizing", daily_race.PerformDailyPrizingHandler)
+	promotionsRoute.GET("/get-unapproved-daily-race-rewards", daily_race.GetUnapprovedRewardsHandler)
+	promotionsRoute.POST("/approve-daily-race-rewards", daily_race.ApproveRewardsHandler)
+	promotionsRoute.GET("/get-weekly-raffle-prizes", weekly_raffle.GetPrizesHandler)
+	promotionsRoute.POST("/set-weekly-raffle-prizes", weekly_raffle.SetPrizesHandler)
+	promotionsRoute.POST("/perform-weekly-raffle-prizing", weekly_raffle.PerformweeklyRafflePrizingHandler)
+	promotionsRoute.GET("/get-quests", quest.GetQuestsHandler)
+	promotionsRoute.POST("/save-quest", quest.SaveQuestHandler)
+	promotionsRoute.GET("/get-house-rain-schedules", house_rain.GetSchedulesHandler)
+	promotionsRoute.POST("/save-house-rain-schedule", house_rain.SaveScheduleHandler)
+	promotionsRoute.POST("/set-house-rain-daily-budget", house_rain.SetDailyBudgetHandler)
+	promotionsRoute.GET("/get-house-rain-history", house_rain.GetHistoryHandler)
+
+	supportRoute := adminRoute.Group("", middlewares.AdminPermission(models.AdminSupportRole))
+	supportRoute.POST("/remove-self-exclusion", self_exclusion.Remove)
+	adminRoute.GET(
+		"/get-user-loss",
+		middlewares.AdminPermission(models.AdminSupportRole, models.AdminFinanceRole),
+		admin.GetUserLoss,
+	)
+
+	superRoute := adminRoute.Group("", middlewares.AdminPermission(models.AdminSuperRole))
+	superRoute.GET("/operators", admin.GetAdminOperatorsHandler)
+	superRoute.POST("/create-operator", admin.CreateAdminOperatorHandler)
+	superRoute.POST("/update-operator", admin.UpdateAdminOperatorHandler)
+	superRoute.GET("/audit-logs", admin.GetAuditLogsHandler)
 }
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"github.com/Duelana-Team/duelana-v1/controllers"
+	"github.com/Duelana-Team/duelana-v1/middlewares"
+	"github.com/gin-gonic/gin"
+)
+
+func initChatRoutes(rg *gin.RouterGroup) {
+	chatRoute := rg.Group("/chat")
+	chatRoute.Use(middlewares.AuthMiddleware().MiddlewareFunc())
+
+	chatRoute.GET("/ignores", controllers.Chat.GetIgnoredUsersHandler)
+	chatRoute.POST(
+		"/ignore",
+		middlewares.APIRateLimiter("chat/ignore"),
+		controllers.Chat.IgnoreUserHandler,
+	)
+	chatRoute.POST(
+		"/unignore",
+		middlewares.APIRateLimiter("chat/ignore"),
+		controllers.Chat.UnignoreUserHandler,
+	)
+	chatRoute.GET("/direct-messages", controllers.Chat.GetDirectMessagesHandler)
+	chatRoute.POST(
+		"/direct-message",
+		middlewares.APIRateLimiter("chat/direct-message"),
+		controllers.Chat.SendDirectMessageHandler,
+	)
+}
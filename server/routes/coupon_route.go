@@ -21,4 +21,10 @@ func initCouponRoutes(rg *gin.RouterGroup) {
 		middlewares.APIRateLimiter("coupon/claim"),
 		coupon.ClaimHandler,
 	)
+	couponRoute.GET("/deposit-bonus", coupon.GetDepositBonusesHandler)
+	couponRoute.POST(
+		"/deposit-bonus/opt-in",
+		middlewares.APIRateLimiter("coupon/deposit-bonus/opt-in"),
+		coupon.OptInDepositBonusHandler,
+	)
 }
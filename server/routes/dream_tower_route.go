@@ -3,6 +3,7 @@ package routes
 import (
 	"github.com/Duelana-Team/duelana-v1/controllers"
 	"github.com/Duelana-Team/duelana-v1/controllers/admin"
+	"github.com/Duelana-Team/duelana-v1/controllers/maintenance"
 	"github.com/Duelana-Team/duelana-v1/middlewares"
 	"github.com/gin-gonic/gin"
 )
@@ -18,6 +19,7 @@ func initDreamTowerRoutes(rg *gin.RouterGroup) {
 	dreamTowerRoute.GET("/max-win", controllers.Dreamtower.MaxWinning)
 	dreamTowerRoute.POST("/bet",
 		admin.GameControllerMiddleware(admin.GAME_CONTROLLER_DREAMTOWER),
+		maintenance.BetMiddleware(),
 		middlewares.AuthMiddleware().MiddlewareFunc(),
 		middlewares.APIRateLimiter("dreamtower/bet"),
 		controllers.Dreamtower.Bet,
@@ -1,29 +1,37 @@
 package routes
 
 import (
+	"strings"
 	"time"
 
+	"github.com/Duelana-Team/duelana-v1/config"
 	"github.com/Duelana-Team/duelana-v1/controllers"
 	"github.com/Duelana-Team/duelana-v1/docs"
+	"github.com/Duelana-Team/duelana-v1/log"
 	"github.com/Duelana-Team/duelana-v1/middlewares"
 	"github.com/Duelana-Team/duelana-v1/socket"
 	"github.com/gin-contrib/gzip"
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 	swaggerfiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+// Hub of the running server, flushed on shutdown.
+var hub *socket.Hub
+
 func Init() *gin.Engine {
-	hub := socket.NewHub()
+	hub = socket.NewHub()
 	go hub.Run()
 
 	controllers.Init(hub.EventEmitter)
 
 	gin.SetMode(gin.ReleaseMode)
-	r := gin.Default()
+	r := gin.New()
+	initClientIP(r)
 	// r.Use(middlewares.LeakBucket())
+	r.Use(middlewares.RequestCorrelation())
 	r.Use(gzip.Gzip(gzip.DefaultCompression))
-	r.Use(gin.Logger())
 	r.Use(gin.Recovery())
 	middlewares.InitRateLimiter(10000, time.Hour)
 
@@ -45,9 +53,13 @@ func Init() *gin.Engine {
 	initCouponRoutes(api)
 	initDailyRaceRoutes(api)
 	initWeeklyRaffleRoutes(api)
+	initQuestRoutes(api)
+	initChatRoutes(api)
 
 	api.GET("/config", middlewares.SocketAuthMiddleware().MiddlewareFunc(), controllers.GetServerConfig)
 
+	initMetricsRoutes(r)
+
 	// programmatically set swagger info
 	docs.SwaggerInfo.Title = "Duelana APIs"
 	docs.SwaggerInfo.Description = "This shows duelana backend apis."
@@ -58,3 +70,47 @@ func Init() *gin.Engine {
 	r.GET("/docs/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
 	return r
 }
+
+// Client ip is taken from forwarded headers only for requests coming
+// through the trusted proxies, otherwise the peer address is used.
+// Outside dev the server refuses to start without trusted proxies, as
+// behind the load balancer every client would share its ip, breaking
+// geo-blocking and per ip account limits.
+func initClientIP(r *gin.Engine) {
+	trustedProxies := []string{}
+	for _, proxy := range strings.Split(config.Get().TrustedProxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trustedProxies = append(trustedProxies, proxy)
+		}
+	}
+	if len(trustedProxies) == 0 && config.Get().ENV != "dev" {
+		logrus.Fatal("TRUSTED_PROXIES is required outside dev to resolve client ip")
+	}
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		logrus.Fatal("Invalid TRUSTED_PROXIES: ", err)
+	}
+	if header := config.Get().ClientIPHeader; header != "" {
+		r.RemoteIPHeaders = []string{header}
+	}
+}
+
+// Stops games and flushes events queued for websocket clients.
+func Shutdown() {
+	controllers.Shutdown()
+	if hub == nil {
+		return
+	}
+	if !hub.Flush(
+		config.SHUTDOWN_EVENT_FLUSH_TIMEOUT,
+		config.SHUTDOWN_POLL_INTERVAL,
+	) {
+		log.LogMessage(
+			"routes_Shutdown",
+			"events left in hub queue",
+			"error",
+			logrus.Fields{
+				"queued": len(hub.EventEmitter),
+			},
+		)
+	}
+}
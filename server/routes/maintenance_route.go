@@ -4,14 +4,19 @@ import (
 	"github.com/Duelana-Team/duelana-v1/config"
 	"github.com/Duelana-Team/duelana-v1/controllers/maintenance"
 	"github.com/Duelana-Team/duelana-v1/middlewares"
+	"github.com/Duelana-Team/duelana-v1/models"
 	"github.com/gin-gonic/gin"
 )
 
 func initMaintenanceRoutes(rg *gin.RouterGroup) {
-	tokenAuthMiddleware := middlewares.TokenAuthMiddleware(config.Get().AdminApiAccessToken)
+	rg.GET("/maintenance-status", maintenance.GetMaintenanceStatus)
 
 	maintenanceRoute := rg.Group("/maintenance")
-	maintenanceRoute.Use(tokenAuthMiddleware)
+	maintenanceRoute.Use(middlewares.AdminAuthMiddleware(config.Get().AdminApiAccessToken))
+	maintenanceRoute.Use(middlewares.AdminPermission(models.AdminGamesRole))
 	maintenanceRoute.POST("/start-maintenance", maintenance.StartMaintenance)
 	maintenanceRoute.POST("/finish-maintenance", maintenance.FinishMaintenance)
+	maintenanceRoute.POST("/schedule-maintenance", maintenance.ScheduleMaintenance)
+	maintenanceRoute.POST("/cancel-maintenance", maintenance.CancelMaintenance)
+	maintenanceRoute.GET("/status", maintenance.GetMaintenanceStatus)
 }
@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/metrics"
+	"github.com/Duelana-Team/duelana-v1/middlewares"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// Mounted at the root, next to `/api`, as `/metrics` is the
+// Prometheus default. Not exposed without an access token.
+func initMetricsRoutes(r *gin.Engine) {
+	accessToken := config.Get().MetricsAccessToken
+	if accessToken == "" {
+		log.LogMessage(
+			"routes_initMetricsRoutes",
+			"METRICS_ACCESS_TOKEN is not set, /metrics is disabled",
+			"info",
+			logrus.Fields{},
+		)
+		return
+	}
+
+	r.GET(
+		"/metrics",
+		middlewares.BearerTokenAuthMiddleware(accessToken),
+		metrics.Handler(),
+	)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/Duelana-Team/duelana-v1/controllers"
 	"github.com/Duelana-Team/duelana-v1/controllers/admin"
 	"github.com/Duelana-Team/duelana-v1/middlewares"
+	"github.com/Duelana-Team/duelana-v1/models"
 	"github.com/gin-gonic/gin"
 )
 
@@ -21,7 +22,8 @@ func initPaymentRoutes(rg *gin.RouterGroup) {
 	)
 	paymentRoute.POST("/subscription-v2",
 		admin.GameControllerMiddleware(admin.GAME_CONTROLLER_DEPOSIT),
-		middlewares.TokenAuthMiddleware(config.Get().AdminApiAccessToken),
+		middlewares.AdminAuthMiddleware(config.Get().AdminApiAccessToken),
+		middlewares.AdminPermission(models.AdminFinanceRole),
 		controllers.Payment.ListnerV2,
 	)
 	paymentRoute.POST("/withdraw/sol",
@@ -37,8 +39,13 @@ func initPaymentRoutes(rg *gin.RouterGroup) {
 		controllers.Payment.WithdrawNfts,
 	)
 	paymentRoute.GET("/history", middlewares.AuthMiddleware().MiddlewareFunc(), controllers.Payment.History)
-	paymentRoute.GET("/latest-hash", middlewares.TokenAuthMiddleware(config.Get().AdminApiAccessToken), controllers.Payment.LatestTxHash)
-	paymentRoute.GET("/all-nfts", middlewares.TokenAuthMiddleware(config.Get().AdminApiAccessToken), controllers.Payment.AllNfts)
+	paymentRoute.GET("/deposit-reference", middlewares.AuthMiddleware().MiddlewareFunc(), controllers.Payment.DepositReference)
+
+	operatorRoute := paymentRoute.Group("")
+	operatorRoute.Use(middlewares.AdminAuthMiddleware(config.Get().AdminApiAccessToken))
+	operatorRoute.Use(middlewares.AdminPermission(models.AdminFinanceRole))
+	operatorRoute.GET("/latest-hash", controllers.Payment.LatestTxHash)
+	operatorRoute.GET("/all-nfts", controllers.Payment.AllNfts)
 
 	rg.GET("/token-prices", controllers.Payment.TokenPrices)
 	rg.GET("/tokens", controllers.Payment.Tokens)
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"github.com/Duelana-Team/duelana-v1/controllers/quest"
+	"github.com/Duelana-Team/duelana-v1/middlewares"
+	"github.com/gin-gonic/gin"
+)
+
+func initQuestRoutes(rg *gin.RouterGroup) {
+	questRoute := rg.Group("/quest")
+
+	questRoute.GET(
+		"/status",
+		middlewares.SocketAuthMiddleware().MiddlewareFunc(),
+		quest.GetQuestStatusHandler,
+	)
+	questRoute.GET(
+		"/rewards",
+		middlewares.AuthMiddleware().MiddlewareFunc(),
+		quest.GetQuestRewardsHandler,
+	)
+	questRoute.POST(
+		"/claim",
+		middlewares.AuthMiddleware().MiddlewareFunc(),
+		quest.ClaimQuestRewardsHandler,
+	)
+}
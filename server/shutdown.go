@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os/signal"
+	"syscall"
+
+	"github.com/Duelana-Team/duelana-v1/config"
+	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/routes"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+/**
+* @Internal
+* Serves `r` on `addr` till SIGINT or SIGTERM is received,
+* then shuts down gracefully.
+ */
+func serve(r *gin.Engine, addr string) error {
+	ctx, stop := signal.NotifyContext(
+		context.Background(),
+		syscall.SIGINT,
+		syscall.SIGTERM,
+	)
+	defer stop()
+
+	server := &http.Server{
+		Addr:    addr,
+		Handler: r,
+	}
+	served := make(chan error, 1)
+	go func() {
+		served <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-served:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.LogMessage("main thread", "shutting down...", "info", logrus.Fields{})
+	shutdown(server)
+	return nil
+}
+
+/**
+* @Internal
+* Shutdown order:
+* 1. Refuse new bets and let the running crash round finish.
+* 2. Flush events queued for websocket clients.
+* 3. Stop http server, waiting for in-flight requests.
+ */
+func shutdown(server *http.Server) {
+	routes.Shutdown()
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		config.SHUTDOWN_HTTP_TIMEOUT,
+	)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil &&
+		!errors.Is(err, http.ErrServerClosed) {
+		log.LogMessage(
+			"main thread",
+			"failed to shut down server gracefully",
+			"error",
+			logrus.Fields{
+				"error": err.Error(),
+			},
+		)
+		return
+	}
+	log.LogMessage("main thread", "shut down gracefully", "success", logrus.Fields{})
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/Duelana-Team/duelana-v1/controllers/coinflip"
 	"github.com/Duelana-Team/duelana-v1/controllers/crash"
 	"github.com/Duelana-Team/duelana-v1/controllers/jackpot"
+	"github.com/Duelana-Team/duelana-v1/controllers/maintenance"
 	"github.com/Duelana-Team/duelana-v1/controllers/user"
 	"github.com/Duelana-Team/duelana-v1/log"
 	"github.com/Duelana-Team/duelana-v1/middlewares"
@@ -68,6 +69,9 @@ func (c *Client) listenCoinflip(content string) error {
 			return fmt.Errorf("coinflip blocked by admin")
 		}
 		if eventParam.EventType == "bet" {
+			if !maintenance.AbleToBet() {
+				return fmt.Errorf("coinflip bet refused in maintenance")
+			}
 			if eventParam.Opponent == coinflip.Bot {
 				controllers.Coinflip.BetAgainstBot(*c.userID, eventParam)
 			} else {
@@ -85,7 +89,8 @@ func (c *Client) listenCoinflip(content string) error {
 }
 
 func (c *Client) listenJackpotLow(content string) {
-	if admin.GetGameBlocked(admin.GAME_CONTROLLER_JACKPOT) {
+	if admin.GetGameBlocked(admin.GAME_CONTROLLER_JACKPOT) ||
+		!maintenance.AbleToBet() {
 		return
 	}
 	var betParam struct {
@@ -112,7 +117,8 @@ func (c *Client) listenJackpotLow(content string) {
 }
 
 func (c *Client) listenJackpotMedium(content string) {
-	if admin.GetGameBlocked(admin.GAME_CONTROLLER_JACKPOT) {
+	if admin.GetGameBlocked(admin.GAME_CONTROLLER_JACKPOT) ||
+		!maintenance.AbleToBet() {
 		return
 	}
 	var betParam struct {
@@ -139,7 +145,8 @@ func (c *Client) listenJackpotMedium(content string) {
 }
 
 func (c *Client) listenJackpotWild(content string) {
-	if admin.GetGameBlocked(admin.GAME_CONTROLLER_JACKPOT) {
+	if admin.GetGameBlocked(admin.GAME_CONTROLLER_JACKPOT) ||
+		!maintenance.AbleToBet() {
 		return
 	}
 	var betParam struct {
@@ -166,7 +173,8 @@ func (c *Client) listenJackpotWild(content string) {
 }
 
 func (c *Client) listenGrandJackpot(content string) {
-	if admin.GetGameBlocked(admin.GAME_CONTROLLER_GRAND_JACKPOT) {
+	if admin.GetGameBlocked(admin.GAME_CONTROLLER_GRAND_JACKPOT) ||
+		!maintenance.AbleToBet() {
 		return
 	}
 	var betParam struct {
@@ -5,8 +5,12 @@
 package socket
 
 import (
+	"slices"
+	"time"
+
 	"github.com/Duelana-Team/duelana-v1/controllers"
 	"github.com/Duelana-Team/duelana-v1/log"
+	"github.com/Duelana-Team/duelana-v1/metrics"
 	"github.com/Duelana-Team/duelana-v1/types"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/syncmap"
@@ -31,13 +35,37 @@ type Hub struct {
 }
 
 func NewHub() *Hub {
-	return &Hub{
+	hub := &Hub{
 		EventEmitter: make(chan types.WSEvent, 4096),
 		register:     make(chan *Client, 256),
 		unregister:   make(chan *Client, 256),
 		users:        syncmap.Map{},
 		clients:      syncmap.Map{},
 	}
+	if err := metrics.Register(newHubCollector(hub)); err != nil {
+		log.LogMessage(
+			"hub",
+			"failed to register metrics collector",
+			"error",
+			logrus.Fields{
+				"error": err.Error(),
+			},
+		)
+	}
+	return hub
+}
+
+// Flush waits till queued events are handed over to clients.
+// Returns false if events are still queued after timeout.
+func (h *Hub) Flush(timeout time.Duration, interval time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for len(h.EventEmitter) > 0 {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(interval)
+	}
+	return true
 }
 
 func (h *Hub) Run() {
@@ -71,9 +99,14 @@ func (h *Hub) Run() {
 					if value.(*Client).room != wsEvent.Room && wsEvent.Room != types.Chat {
 						return true
 					}
+					if value.(*Client).userID != nil &&
+						slices.Contains(wsEvent.Excludes, *value.(*Client).userID) {
+						return true
+					}
 					select {
 					case value.(*Client).send <- wsEvent.Message:
 					default:
+						metrics.HubDroppedClients.Inc()
 						close(value.(*Client).send)
 						if value.(*Client).userID != nil {
 							h.users.Delete(value.(*Client).userID)
@@ -89,6 +122,7 @@ func (h *Hub) Run() {
 						select {
 						case user.(*Client).send <- wsEvent.Message:
 						default:
+							metrics.HubDroppedClients.Inc()
 							close(user.(*Client).send)
 							h.users.Delete(userID)
 							h.clients.Delete(user.(*Client).conn)
@@ -101,6 +135,7 @@ func (h *Hub) Run() {
 						select {
 						case client.(*Client).send <- wsEvent.Message:
 						default:
+							metrics.HubDroppedClients.Inc()
 							close(client.(*Client).send)
 							h.clients.Delete(conn)
 							if client.(*Client).userID != nil {
@@ -0,0 +1,79 @@
+package socket
+
+import (
+	"github.com/Duelana-Team/duelana-v1/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	hubQueueDepthDesc = prometheus.NewDesc(
+		metrics.NAMESPACE+"_ws_event_queue_depth",
+		"Events waiting in Hub.EventEmitter.",
+		nil,
+		nil,
+	)
+	hubConnectionsDesc = prometheus.NewDesc(
+		metrics.NAMESPACE+"_ws_connections",
+		"Open websocket connections per room.",
+		[]string{"room"},
+		nil,
+	)
+	hubConnectedUsersDesc = prometheus.NewDesc(
+		metrics.NAMESPACE+"_ws_connected_users",
+		"Authenticated websocket connections per room.",
+		[]string{"room"},
+		nil,
+	)
+)
+
+// Reads hub state on every scrape.
+type hubCollector struct {
+	hub *Hub
+}
+
+func newHubCollector(hub *Hub) *hubCollector {
+	return &hubCollector{hub: hub}
+}
+
+func (collector *hubCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- hubQueueDepthDesc
+	ch <- hubConnectionsDesc
+	ch <- hubConnectedUsersDesc
+}
+
+func (collector *hubCollector) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(
+		hubQueueDepthDesc,
+		prometheus.GaugeValue,
+		float64(len(collector.hub.EventEmitter)),
+	)
+
+	connections := map[string]int{}
+	users := map[string]int{}
+	collector.hub.clients.Range(func(key, value any) bool {
+		client := value.(*Client)
+		room := string(client.room)
+		if room == "" {
+			room = "none"
+		}
+		connections[room]++
+		if client.userID != nil {
+			users[room]++
+		}
+		return true
+	})
+	for room, count := range connections {
+		ch <- prometheus.MustNewConstMetric(
+			hubConnectionsDesc,
+			prometheus.GaugeValue,
+			float64(count),
+			room,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			hubConnectedUsersDesc,
+			prometheus.GaugeValue,
+			float64(users[room]),
+			room,
+		)
+	}
+}
@@ -103,7 +103,10 @@ func dropTables(db *gorm.DB) error {
 		&models.Affiliate{},
 		&models.ActiveAffiliate{},
 		&models.AffiliateLifetime{},
+		&models.CouponCampaign{},
 		&models.Coupon{},
+		&models.DepositBonusCampaign{},
+		&models.DepositBonusOptIn{},
 		&models.ClaimedCoupon{},
 		&models.CouponTransaction{},
 		&models.CrashRound{},
@@ -113,6 +116,27 @@ func dropTables(db *gorm.DB) error {
 		&models.CouponShortcut{},
 		&models.WeeklyRaffleTicket{},
 		&models.WeeklyRaffle{},
+		&models.Quest{},
+		&models.QuestProgress{},
+		&models.HouseRainSchedule{},
+		&models.HouseRain{},
+		&models.ChatIgnore{},
+		&models.DirectMessage{},
+		&models.AdminOperator{},
+		&models.AdminAuditLog{},
+		&models.FeatureSwitch{},
+		&models.MaintenanceWindow{},
+		&models.GameSettings{},
+		&models.DailyGameRevenue{},
+		&models.DailyRevenueDeduction{},
+		&models.DailyPaymentVolume{},
+		&models.DepositWatcherCursor{},
+		&models.DepositReference{},
+		&models.Withdrawal{},
+		&models.SplToken{},
+		&models.PaymentPriceSnapshot{},
+		&models.NftFloorPrice{},
+		&models.TreasurySweep{},
 	)
 }
 
@@ -139,7 +163,10 @@ func migrateTables(db *gorm.DB) error {
 		&models.Affiliate{},
 		&models.ActiveAffiliate{},
 		&models.AffiliateLifetime{},
+		&models.CouponCampaign{},
 		&models.Coupon{},
+		&models.DepositBonusCampaign{},
+		&models.DepositBonusOptIn{},
 		&models.ClaimedCoupon{},
 		&models.CouponTransaction{},
 		&models.CrashRound{},
@@ -149,5 +176,26 @@ func migrateTables(db *gorm.DB) error {
 		&models.CouponShortcut{},
 		&models.WeeklyRaffleTicket{},
 		&models.WeeklyRaffle{},
+		&models.Quest{},
+		&models.QuestProgress{},
+		&models.HouseRainSchedule{},
+		&models.HouseRain{},
+		&models.ChatIgnore{},
+		&models.DirectMessage{},
+		&models.AdminOperator{},
+		&models.AdminAuditLog{},
+		&models.FeatureSwitch{},
+		&models.MaintenanceWindow{},
+		&models.GameSettings{},
+		&models.DailyGameRevenue{},
+		&models.DailyRevenueDeduction{},
+		&models.DailyPaymentVolume{},
+		&models.DepositWatcherCursor{},
+		&models.DepositReference{},
+		&models.Withdrawal{},
+		&models.SplToken{},
+		&models.PaymentPriceSnapshot{},
+		&models.NftFloorPrice{},
+		&models.TreasurySweep{},
 	)
 }
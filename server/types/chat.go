@@ -18,3 +18,11 @@ type ChatCommand struct {
 	RegExp  string      `json:"regExp"`
 	Role    models.Role `json:"role"`
 }
+
+type DirectMessagePayload struct {
+	ID        uint   `json:"id"`
+	Sender    User   `json:"sender"`
+	Recipient User   `json:"recipient"`
+	Message   string `json:"message"`
+	Time      uint64 `json:"time"`
+}
@@ -0,0 +1,8 @@
+package types
+
+// Premium of NFTs ranked within the top `Top` bps of their
+// collection, valued at `Multiplier` bps of floor.
+type RarityTier struct {
+	Top        int64 `json:"top"`
+	Multiplier int64 `json:"multiplier"`
+}
@@ -126,6 +126,13 @@ type PaymentHistoryPayload struct {
 	TxID      string               `json:"txId"`
 }
 
+type WithdrawalStatusPayload struct {
+	PaymentID uint                    `json:"paymentId"`
+	Type      string                  `json:"type"`
+	Status    models.WithdrawalStatus `json:"status"`
+	TxID      string                  `json:"txId"`
+}
+
 // error
 type ErrorCode string
 
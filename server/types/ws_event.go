@@ -15,10 +15,11 @@ const (
 )
 
 type WSEvent struct {
-	Conns   []*websocket.Conn
-	Users   []uint
-	Room    Room
-	Message []byte
+	Conns    []*websocket.Conn
+	Users    []uint
+	Excludes []uint
+	Room     Room
+	Message  []byte
 }
 
 type WSMessage struct {